@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ptfpinho23/synthesis/pkg/systemd"
+)
+
+// runInstall handles `synthesis-agent install [agent flags...]`: it
+// writes a Type=notify systemd unit running this binary with the given
+// flags. The agent skips the strict sandbox — it drives the engine
+// CLI and inspects host paths.
+func runInstall(args []string) {
+	bin, err := os.Executable()
+	if err != nil {
+		log.Fatalf("install: resolving binary path: %v", err)
+	}
+	execStart := bin
+	if len(args) > 0 {
+		execStart += " " + strings.Join(args, " ")
+	}
+	unit := systemd.Unit{
+		Name:        "synthesis-agent",
+		Description: "Synthesis node agent",
+		ExecStart:   execStart,
+		WatchdogSec: 60,
+	}
+	path, err := unit.Install()
+	if err != nil {
+		log.Fatalf("install: %v", err)
+	}
+	fmt.Printf("wrote %s\n", path)
+	fmt.Printf("enable with: systemctl daemon-reload && systemctl enable --now %s\n", unit.Name)
+}
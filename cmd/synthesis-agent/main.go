@@ -0,0 +1,136 @@
+// Command synthesis-agent is the per-node daemon: it registers the node
+// and keeps its heartbeat lease renewed.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/agent"
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/logging"
+	"github.com/ptfpinho23/synthesis/pkg/runtime"
+)
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "install" {
+		runInstall(os.Args[2:])
+		return
+	}
+	hostname, _ := os.Hostname()
+	server := flag.String("server", "http://127.0.0.1:8420", "API server address")
+	nodeName := flag.String("node-name", hostname, "name to register this node as")
+	nodeIP := flag.String("node-ip", "", "address peers reach this node on (overlay mesh endpoint)")
+	staticPodsDir := flag.String("pod-manifests-dir", "",
+		"directory of pod manifests run as static pods, independent of the API server; empty disables")
+	orphanAfter := flag.Duration("orphan-after", 0,
+		"how long pods keep running while the server is unreachable before they are torn down; 0 keeps them forever")
+	startupBurst := flag.Int("startup-burst", 3,
+		"pods allowed to start containers per sync pass while restoring after a restart; 0 lifts the cap")
+	clusterDNS := flag.String("cluster-dns", "",
+		"resolver address for ClusterFirst pods; empty leaves pods on the engine default")
+	resyncInterval := flag.Duration("resync-interval", 5*time.Minute,
+		"how often the anti-entropy pass repairs drift between pod specs and the engine")
+	engineRestarts := flag.Bool("engine-restarts", false,
+		"let the container engine restart containers per the pod restartPolicy instead of the agent")
+	engineOpTimeout := flag.Duration("engine-op-timeout", time.Minute,
+		"deadline for each engine CLI call; 0 disables the bound")
+	engineNamespace := flag.String("engine-namespace", "",
+		"containerd namespace a nerdctl engine works in, e.g. synthesis; empty keeps the engine default")
+	engineSnapshotter := flag.String("engine-snapshotter", "",
+		"containerd snapshotter: overlayfs, stargz for lazy pulls, or zfs; empty keeps the engine default")
+	engineRuntime := flag.String("engine-runtime", "",
+		"OCI runtime handler containers are launched under; empty keeps the engine default")
+	registryMirrors := flag.String("registry-mirrors", "",
+		"registry=mirror pairs routing pulls through a mirror or pull-through cache, e.g. docker.io=mirror.internal:5000")
+	systemReserved := flag.String("system-reserved", "",
+		"cpu/memory held back for OS daemons, e.g. cpu=500m,memory=1Gi; empty keeps the built-in reserve")
+	kubeReserved := flag.String("kube-reserved", "",
+		"cpu/memory held back for the agent and engine, added to --system-reserved")
+	verbosity := flag.String("v", "info", "default log level: debug, info, warn or error")
+	flag.Parse()
+
+	level, err := logging.ParseLevel(*verbosity)
+	if err != nil {
+		log.Fatalf("--v: %v", err)
+	}
+	logging.SetDefaultLevel(level)
+
+	if *nodeName == "" {
+		log.Fatal("agent: --node-name is required when the hostname is unavailable")
+	}
+	a := agent.New(*server, *nodeName)
+	a.NodeIP = *nodeIP
+	a.StaticPodsDir = *staticPodsDir
+	a.OrphanAfter = *orphanAfter
+	a.StartupBurst = *startupBurst
+	a.ResyncInterval = *resyncInterval
+	if *systemReserved != "" || *kubeReserved != "" {
+		system, err := parseReserved(*systemReserved)
+		if err != nil {
+			log.Fatalf("--system-reserved: %v", err)
+		}
+		kube, err := parseReserved(*kubeReserved)
+		if err != nil {
+			log.Fatalf("--kube-reserved: %v", err)
+		}
+		a.Reserved = system.Add(kube)
+	}
+	if d, ok := a.Runtime.(*runtime.Docker); ok {
+		d.ClusterDNS = *clusterDNS
+		d.EngineRestarts = *engineRestarts
+		d.OpTimeout = *engineOpTimeout
+		d.RuntimeConfig = runtime.RuntimeConfig{
+			Namespace:      *engineNamespace,
+			Snapshotter:    *engineSnapshotter,
+			RuntimeHandler: *engineRuntime,
+		}
+		if *registryMirrors != "" {
+			mirrors, err := parseMirrors(*registryMirrors)
+			if err != nil {
+				log.Fatalf("--registry-mirrors: %v", err)
+			}
+			d.Mirrors = mirrors
+		}
+	}
+	if err := a.Run(); err != nil {
+		log.Fatalf("agent: %v", err)
+	}
+}
+
+// parseMirrors parses "docker.io=mirror.internal:5000" style lists.
+func parseMirrors(s string) (map[string]string, error) {
+	out := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		host, mirror, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || host == "" || mirror == "" {
+			return nil, fmt.Errorf("malformed mirror %q, want registry=mirror", pair)
+		}
+		out[host] = mirror
+	}
+	return out, nil
+}
+
+// parseReserved parses "cpu=500m,memory=1Gi" style reservation lists.
+func parseReserved(s string) (api.ResourceList, error) {
+	out := api.ResourceList{}
+	if s == "" {
+		return out, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		name, raw, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || name == "" || raw == "" {
+			return nil, fmt.Errorf("malformed reservation %q, want resource=quantity", pair)
+		}
+		qty, err := api.ParseQuantity(name, raw)
+		if err != nil {
+			return nil, err
+		}
+		out[name] = qty
+	}
+	return out, nil
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/logging"
+	"github.com/ptfpinho23/synthesis/pkg/yaml"
+)
+
+// serverConfig is the optional --config file: per-controller tuning so
+// heavily loaded or battery-powered edge deployments can trade
+// responsiveness for CPU. Flags keep their meaning; the file only
+// covers the reconcile loops.
+type serverConfig struct {
+	// LogLevel overrides the --v log level: debug, info, warn or
+	// error. Empty keeps the flag's value.
+	LogLevel string `json:"logLevel,omitempty"`
+
+	// Controllers tunes the named reconcile loops: nodelifecycle,
+	// podcidr, serviceaccount, recommender, workload and scheduler.
+	Controllers map[string]controllerTuning `json:"controllers,omitempty"`
+
+	// Defaults configures the mutating admission check that stamps
+	// site conventions onto every pod. Applied at startup; changing it
+	// needs a restart, not a SIGHUP.
+	Defaults *admissionDefaults `json:"defaults,omitempty"`
+}
+
+// admissionDefaults is the defaults section of the config file; see
+// admission.Defaults for what each field does.
+type admissionDefaults struct {
+	Registry        string            `json:"registry,omitempty"`
+	ImagePullPolicy string            `json:"imagePullPolicy,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	Annotations     map[string]string `json:"annotations,omitempty"`
+}
+
+// controllerTuning adjusts one reconcile loop.
+type controllerTuning struct {
+	// Disabled skips starting the loop entirely.
+	Disabled bool `json:"disabled,omitempty"`
+
+	// SyncInterval overrides how often the loop reconciles, as a Go
+	// duration string ("30s", "5m").
+	SyncInterval duration `json:"syncInterval,omitempty"`
+}
+
+// duration is a time.Duration that unmarshals from "30s"-style strings.
+type duration time.Duration
+
+func (d *duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = duration(parsed)
+	return nil
+}
+
+func (d duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// controllerNames are the loops the config file may address.
+var controllerNames = map[string]bool{
+	"nodelifecycle":  true,
+	"podcidr":        true,
+	"serviceaccount": true,
+	"recommender":    true,
+	"workload":       true,
+	"scheduler":      true,
+	"loadbalancer":   true,
+	"certificate":    true,
+	"descheduler":    true,
+}
+
+// loadServerConfig reads a YAML or JSON tuning file.
+func loadServerConfig(path string) (*serverConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(data)) > 0 && bytes.TrimSpace(data)[0] != '{' {
+		if data, err = yaml.ToJSON(data); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+	}
+	var cfg serverConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if cfg.LogLevel != "" {
+		if _, err := logging.ParseLevel(cfg.LogLevel); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	for name, tuning := range cfg.Controllers {
+		if !controllerNames[name] {
+			return nil, fmt.Errorf("%s: unknown controller %q", path, name)
+		}
+		if tuning.SyncInterval < 0 {
+			return nil, fmt.Errorf("%s: controller %q: syncInterval must not be negative", path, name)
+		}
+	}
+	if cfg.Defaults != nil {
+		switch api.PullPolicy(cfg.Defaults.ImagePullPolicy) {
+		case "", api.PullAlways, api.PullIfNotPresent, api.PullNever:
+		default:
+			return nil, fmt.Errorf("%s: unknown imagePullPolicy %q", path, cfg.Defaults.ImagePullPolicy)
+		}
+	}
+	return &cfg, nil
+}
+
+// applyLogLevel switches the process log level when the config sets
+// one; parse errors were already rejected at load time.
+func (c *serverConfig) applyLogLevel() {
+	if c.LogLevel == "" {
+		return
+	}
+	if level, err := logging.ParseLevel(c.LogLevel); err == nil {
+		logging.SetDefaultLevel(level)
+	}
+}
+
+// changes describes what differs between this config and next, one
+// entry per setting, for the reload log and event.
+func (c *serverConfig) changes(next *serverConfig) []string {
+	var out []string
+	if c.LogLevel != next.LogLevel {
+		out = append(out, fmt.Sprintf("logLevel %s -> %s",
+			levelOrDefault(c.LogLevel), levelOrDefault(next.LogLevel)))
+	}
+	names := make([]string, 0, len(controllerNames))
+	for name := range controllerNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		before, after := c.Controllers[name], next.Controllers[name]
+		if before.Disabled != after.Disabled {
+			out = append(out, fmt.Sprintf("%s disabled %t -> %t", name, before.Disabled, after.Disabled))
+		}
+		if before.SyncInterval != after.SyncInterval {
+			out = append(out, fmt.Sprintf("%s syncInterval %s -> %s",
+				name, intervalOrDefault(before.SyncInterval), intervalOrDefault(after.SyncInterval)))
+		}
+	}
+	return out
+}
+
+func levelOrDefault(s string) string {
+	if s == "" {
+		return "(default)"
+	}
+	return s
+}
+
+func intervalOrDefault(d duration) string {
+	if d == 0 {
+		return "(default)"
+	}
+	return time.Duration(d).String()
+}
+
+// enabled reports whether the named loop should run.
+func (c *serverConfig) enabled(name string) bool {
+	return !c.Controllers[name].Disabled
+}
+
+// interval overwrites d when the config names a period for the loop.
+func (c *serverConfig) interval(name string, d *time.Duration) {
+	if v := c.Controllers[name].SyncInterval; v > 0 {
+		*d = time.Duration(v)
+	}
+}
+
+// effective renders the tuning actually in force — file overrides
+// merged over defaults — for the system config endpoint.
+func (c *serverConfig) effective(intervals map[string]time.Duration) map[string]any {
+	controllers := make(map[string]any, len(intervals))
+	for name, d := range intervals {
+		controllers[name] = map[string]any{
+			"enabled":      c.enabled(name),
+			"syncInterval": d.String(),
+		}
+	}
+	return map[string]any{"controllers": controllers}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ptfpinho23/synthesis/pkg/systemd"
+)
+
+// runInstall handles `synthesis-server install [server flags...]`: it
+// writes a hardened Type=notify systemd unit running this binary with
+// the given flags, so nobody hand-rolls a unit file per cluster.
+func runInstall(args []string) {
+	bin, err := os.Executable()
+	if err != nil {
+		log.Fatalf("install: resolving binary path: %v", err)
+	}
+	execStart := bin
+	if len(args) > 0 {
+		execStart += " " + strings.Join(args, " ")
+	}
+	unit := systemd.Unit{
+		Name:        "synthesis-server",
+		Description: "Synthesis control plane",
+		ExecStart:   execStart,
+		WatchdogSec: 30,
+		FullSandbox: true,
+	}
+	path, err := unit.Install()
+	if err != nil {
+		log.Fatalf("install: %v", err)
+	}
+	fmt.Printf("wrote %s\n", path)
+	fmt.Printf("enable with: systemctl daemon-reload && systemctl enable --now %s\n", unit.Name)
+}
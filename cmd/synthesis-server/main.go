@@ -0,0 +1,661 @@
+// Command synthesis-server runs the Synthesis control plane: the REST
+// API plus the scheduling loop that binds pending pods to nodes.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/admission"
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/controller"
+	"github.com/ptfpinho23/synthesis/pkg/events"
+	"github.com/ptfpinho23/synthesis/pkg/kms"
+	"github.com/ptfpinho23/synthesis/pkg/logging"
+	"github.com/ptfpinho23/synthesis/pkg/scheduler"
+	"github.com/ptfpinho23/synthesis/pkg/server"
+	"github.com/ptfpinho23/synthesis/pkg/store"
+	"github.com/ptfpinho23/synthesis/pkg/systemd"
+	"github.com/ptfpinho23/synthesis/pkg/tlsutil"
+	"github.com/ptfpinho23/synthesis/pkg/token"
+)
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "install" {
+		runInstall(os.Args[2:])
+		return
+	}
+	// `synthesis-server start --role=...` reads like k3s; the start
+	// token is optional and everything stays flag-driven underneath.
+	if len(os.Args) > 1 && os.Args[1] == "start" {
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+	}
+	hostname, _ := os.Hostname()
+	listen := flag.String("listen", ":8420", "address the API server listens on")
+	role := flag.String("role", "server",
+		"server runs the control plane, agent only a worker, all both in one process")
+	join := flag.String("join", "",
+		"API server URL the agent role connects to; --role=all defaults to this process (required for TLS)")
+	nodeName := flag.String("node-name", hostname, "name the agent role registers its node as")
+	nodeIP := flag.String("node-ip", "", "address peers reach this node on (agent role)")
+	evictionTimeout := flag.Duration("node-eviction-timeout", 60*time.Second,
+		"how long a node may stay NotReady before its pods are rescheduled")
+	clusterCIDR := flag.String("cluster-cidr", "10.42.0.0/16", "range carved into per-node pod CIDRs")
+	descheduleThreshold := flag.Float64("deschedule-threshold", 0,
+		"requested fraction of allocatable above which pods are rebalanced off a node; 0 disables the descheduler")
+	lbPool := flag.String("lb-address-pool", "",
+		"CIDR or dash range handed to LoadBalancer services; empty leaves them unallocated")
+	acmeDirectory := flag.String("acme-directory", "",
+		"ACME directory URL for ingress certificates; empty disables issuance")
+	acmeEmail := flag.String("acme-email", "", "contact email for the ACME account")
+	dataDir := flag.String("data-dir", "",
+		"directory for persisted cluster state; empty keeps state in memory only")
+	raftID := flag.String("raft-id", "", "this member's name in an embedded-raft cluster")
+	raftListen := flag.String("raft-listen", ":8421", "address raft peers connect to")
+	raftPeers := flag.String("raft-peers", "",
+		"other members as id=http://host:port pairs, comma separated; empty disables raft")
+	tlsCert := flag.String("tls-cert", "", "PEM certificate for HTTPS; implies --tls-key")
+	tlsKey := flag.String("tls-key", "", "PEM private key for HTTPS")
+	tlsAuto := flag.Bool("tls-self-signed", false,
+		"serve HTTPS with an auto-generated self-signed CA under --tls-dir")
+	tlsDir := flag.String("tls-dir", "/var/lib/synthesis/pki", "where self-signed certificates are kept")
+	tokenFile := flag.String("token-auth-file", "", "CSV file of token,username pairs for bearer auth")
+	authzMode := flag.String("authorization-mode", "AlwaysAllow",
+		"AlwaysAllow or RBAC; RBAC enforces stored roles and bindings")
+	saKeyFile := flag.String("service-account-key-file", "/var/lib/synthesis/sa.key",
+		"symmetric key for signing service account tokens")
+	clientQPS := flag.Float64("client-qps", 50, "sustained requests/second allowed per client IP (0 disables)")
+	clientBurst := flag.Int("client-burst", 100, "burst requests absorbed per client IP")
+	maxBodyBytes := flag.Int64("max-request-bytes", 3<<20, "largest accepted request body (0 disables)")
+	maxInFlightReads := flag.Int("max-inflight-reads", 400, "concurrent read-only requests (0 disables)")
+	maxInFlightWrites := flag.Int("max-inflight-writes", 200, "concurrent mutating requests (0 disables)")
+	imageVerifyKey := flag.String("image-verify-key", "", "cosign public key; enables image signature admission")
+	imageVerifyIdentity := flag.String("image-verify-identity", "",
+		"certificate identity for keyless image verification (with --image-verify-issuer)")
+	imageVerifyIssuer := flag.String("image-verify-issuer", "", "OIDC issuer for keyless image verification")
+	kmsDir := flag.String("kms-keyring-dir", "/var/lib/synthesis/kms",
+		"local KMS keyring for secret envelope encryption; empty disables encryption at rest")
+	corsOrigins := flag.String("cors-allowed-origins", "",
+		"origins allowed to make cross-origin requests, comma separated; * allows any, empty disables CORS")
+	eventSinks := flag.String("event-sinks", "",
+		"YAML or JSON file of webhook/slack/nats sinks that receive cluster events")
+	eventBus := flag.String("event-bus", "",
+		"nats:// or mqtt:// URL that resource change events are published to; empty disables the bus")
+	metricsWindow := flag.Duration("metrics-window", 15*time.Minute,
+		"how much node and pod usage history the metrics API retains")
+	manifestsDir := flag.String("manifests-dir", "",
+		"directory of YAML manifests applied at startup and re-applied when files change; empty disables")
+	configFile := flag.String("config", "",
+		"YAML or JSON file tuning reconcile intervals and enabling/disabling controllers")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 30*time.Second,
+		"how long SIGTERM waits for in-flight requests and reconciles before exiting")
+	verbosity := flag.String("v", "info", "default log level: debug, info, warn or error")
+	flag.Parse()
+
+	level, err := logging.ParseLevel(*verbosity)
+	if err != nil {
+		log.Fatalf("--v: %v", err)
+	}
+	logging.SetDefaultLevel(level)
+	logger := logging.Component("server")
+
+	switch *role {
+	case "server", "all":
+	case "agent":
+		if *join == "" {
+			log.Fatal("--join is required with --role=agent")
+		}
+		runAgent(*join, *nodeName, *nodeIP)
+		return
+	default:
+		log.Fatalf("unknown --role %q", *role)
+	}
+
+	cfg := &serverConfig{}
+	if *configFile != "" {
+		if cfg, err = loadServerConfig(*configFile); err != nil {
+			log.Fatalf("loading config: %v", err)
+		}
+	}
+	cfg.applyLogLevel()
+
+	// ctx ends on SIGTERM/SIGINT; every loop below finishes its current
+	// pass and returns, tracked by loops so shutdown can wait for them.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var loops sync.WaitGroup
+	runLoop := func(run func(context.Context)) {
+		loops.Add(1)
+		go func() {
+			defer loops.Done()
+			run(ctx)
+		}()
+	}
+
+	// With raft enabled the three replicas replicate the store amongst
+	// themselves and raft's own leader gates the controllers. Without
+	// it, state lives in this process — on disk when --data-dir is set —
+	// and a lease-based elector covers the shared-backend case.
+	var st store.Store
+	var disk *store.DiskStore
+	var isLeader func() bool
+	if *raftPeers != "" {
+		peers, err := parseRaftPeers(*raftPeers)
+		if err != nil {
+			log.Fatalf("raft: %v", err)
+		}
+		if *raftID == "" {
+			log.Fatal("raft: --raft-id is required with --raft-peers")
+		}
+		rs := store.NewReplicatedStore(*raftID, peers)
+		rs.Start()
+		go func() {
+			if err := http.ListenAndServe(*raftListen, rs.Node().Handler()); err != nil {
+				log.Fatalf("raft listener: %v", err)
+			}
+		}()
+		st = rs
+		isLeader = rs.Node().IsLeader
+	} else if *dataDir != "" {
+		ds, err := store.NewDiskStore(*dataDir)
+		if err != nil {
+			log.Fatalf("opening data dir: %v", err)
+		}
+		ds.Start()
+		st = ds
+		disk = ds
+	} else {
+		st = store.NewMemoryStore()
+	}
+
+	srv := server.New(st)
+	srv.SetLimits(server.Limits{
+		PerClientQPS:      *clientQPS,
+		PerClientBurst:    *clientBurst,
+		MaxBodyBytes:      *maxBodyBytes,
+		MaxInFlightReads:  *maxInFlightReads,
+		MaxInFlightWrites: *maxInFlightWrites,
+	})
+	srv.SetMetricsWindow(*metricsWindow)
+	if *corsOrigins != "" {
+		srv.SetCORS(strings.Split(*corsOrigins, ","))
+	}
+	if *eventSinks != "" {
+		notifier, err := events.LoadConfig(*eventSinks)
+		if err != nil {
+			log.Fatalf("loading event sinks: %v", err)
+		}
+		srv.SetEventNotifier(notifier)
+		runLoop(notifier.Run)
+	}
+	if *eventBus != "" {
+		publisher, err := events.NewBusPublisher(*eventBus)
+		if err != nil {
+			log.Fatalf("event bus: %v", err)
+		}
+		srv.SetEventBus(publisher)
+		runLoop(publisher.Run)
+	}
+	// Defaults run first so later checks judge the pod as it will be
+	// stored; the configured site defaults come before the namespace
+	// limit defaults so both see the final image references.
+	var checks admission.Chain
+	if d := cfg.Defaults; d != nil {
+		checks = append(checks, &admission.Defaults{
+			Registry:    d.Registry,
+			PullPolicy:  api.PullPolicy(d.ImagePullPolicy),
+			Labels:      d.Labels,
+			Annotations: d.Annotations,
+		})
+	}
+	checks = append(checks, admission.NewLimitDefaults(st), admission.NewPodSecurity(st))
+	if *imageVerifyKey != "" || *imageVerifyIdentity != "" {
+		policy := admission.NewImagePolicy(st)
+		policy.KeyFile = *imageVerifyKey
+		policy.Identity = *imageVerifyIdentity
+		policy.Issuer = *imageVerifyIssuer
+		checks = append(checks, policy)
+	}
+	srv.SetAdmission(checks)
+	var kmsProvider kms.Provider
+	if *kmsDir != "" {
+		provider, err := kms.NewLocalProvider(*kmsDir)
+		if err != nil {
+			logger.Warn("secrets encryption at rest disabled", "err", err)
+		} else {
+			srv.SetKMSProvider(provider)
+			kmsProvider = provider
+		}
+	}
+	if key, err := token.LoadOrCreateKey(*saKeyFile); err != nil {
+		logger.Warn("service account token signing disabled", "err", err)
+	} else {
+		srv.SetTokenSigner(token.NewSigner(key))
+	}
+	switch *authzMode {
+	case "AlwaysAllow":
+	case "RBAC":
+		tokens := map[string]string{}
+		if *tokenFile != "" {
+			loaded, err := server.LoadTokenFile(*tokenFile)
+			if err != nil {
+				log.Fatalf("loading token file: %v", err)
+			}
+			tokens = loaded
+		}
+		srv.EnableRBAC(tokens)
+	default:
+		log.Fatalf("unknown --authorization-mode %q", *authzMode)
+	}
+	// The manifests directory is local to this process, so every
+	// replica applies its own copy; the PUTs are idempotent upserts.
+	if *manifestsDir != "" {
+		runLoop(func(ctx context.Context) { srv.RunManifestsDir(ctx, *manifestsDir) })
+	}
+	// Several replicas may share one backend; only the leader runs the
+	// reconcile loops, while every replica serves the API.
+	elector := controller.NewLeaderElector(st, fmt.Sprintf("%s-%d", hostname, os.Getpid()))
+	if isLeader == nil {
+		isLeader = elector.IsLeader
+		runLoop(elector.Run)
+	}
+
+	opts := controllerOptions{
+		evictionTimeout:     *evictionTimeout,
+		clusterCIDR:         *clusterCIDR,
+		lbPool:              *lbPool,
+		acmeDirectory:       *acmeDirectory,
+		acmeEmail:           *acmeEmail,
+		descheduleThreshold: *descheduleThreshold,
+		kms:                 kmsProvider,
+	}
+	loopCtx, cancelLoops := context.WithCancel(ctx)
+	if err := startControllers(loopCtx, &loops, srv, st, isLeader, cfg, opts); err != nil {
+		log.Fatalf("starting controllers: %v", err)
+	}
+
+	// SIGHUP rereads --config and applies the safe-to-change subset —
+	// log level, reconcile intervals, enable switches — by restarting
+	// the reconcile loops under fresh tuning. The API keeps serving
+	// throughout, so a reload never disrupts workloads.
+	if *configFile != "" {
+		reload := make(chan os.Signal, 1)
+		signal.Notify(reload, syscall.SIGHUP)
+		go func() {
+			current := cfg
+			for range reload {
+				next, err := loadServerConfig(*configFile)
+				if err != nil {
+					logger.Error("config reload failed, keeping previous config", "err", err)
+					continue
+				}
+				changes := current.changes(next)
+				if len(changes) == 0 {
+					logger.Info("config reloaded, nothing changed")
+					continue
+				}
+				next.applyLogLevel()
+				cancelLoops()
+				loopCtx, cancelLoops = context.WithCancel(ctx)
+				if err := startControllers(loopCtx, &loops, srv, st, isLeader, next, opts); err != nil {
+					logger.Error("restarting controllers failed", "err", err)
+					continue
+				}
+				current = next
+				msg := strings.Join(changes, "; ")
+				logger.Info("config reloaded", "changes", msg)
+				srv.RecordEvent(api.Event{
+					Reason:    "ConfigReloaded",
+					Message:   msg,
+					Regarding: api.ObjectRef{Kind: "Server", Name: hostname},
+				})
+			}
+		}()
+	}
+
+	// The listener is opened before readiness is signaled, so with a
+	// Type=notify unit nothing depends on the server until connects can
+	// at least queue.
+	ln, err := net.Listen("tcp", *listen)
+	if err != nil {
+		log.Fatalf("listening on %s: %v", *listen, err)
+	}
+	httpServer := &http.Server{Handler: srv}
+	serveErr := make(chan error, 1)
+	go func() {
+		switch {
+		case *tlsCert != "" || *tlsKey != "":
+			if *tlsCert == "" || *tlsKey == "" {
+				serveErr <- fmt.Errorf("--tls-cert and --tls-key must be set together")
+				return
+			}
+			logger.Info("listening", "addr", *listen, "tls", true)
+			serveErr <- httpServer.ServeTLS(ln, *tlsCert, *tlsKey)
+		case *tlsAuto:
+			cert, key, ca, genErr := tlsutil.EnsureServerCert(*tlsDir, []string{hostname, "localhost", "127.0.0.1"})
+			if genErr != nil {
+				serveErr <- fmt.Errorf("generating certificates: %w", genErr)
+				return
+			}
+			logger.Info("listening", "addr", *listen, "tls", true, "ca", ca)
+			serveErr <- httpServer.ServeTLS(ln, cert, key)
+		default:
+			logger.Info("listening", "addr", *listen, "tls", false)
+			serveErr <- httpServer.Serve(ln)
+		}
+	}()
+	systemd.Ready()
+	runLoop(systemd.Watchdog)
+
+	// The all role carries the worker in-process, registering against
+	// the API this process just started serving.
+	if *role == "all" {
+		target := *join
+		if target == "" {
+			target = localServerURL(*listen)
+		}
+		go runAgent(target, *nodeName, *nodeIP)
+	}
+
+	select {
+	case err := <-serveErr:
+		log.Fatalf("api server: %v", err)
+	case <-ctx.Done():
+	}
+
+	// Drain: refuse new writes, let in-flight requests and reconciles
+	// finish within the timeout, then flush anything the store has queued.
+	systemd.Stopping()
+	logger.Info("shutting down", "timeout", *shutdownTimeout)
+	srv.BeginDrain()
+	drainCtx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(drainCtx); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		logger.Warn("draining requests failed", "err", err)
+	}
+	drained := make(chan struct{})
+	go func() {
+		loops.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+	case <-drainCtx.Done():
+		logger.Warn("reconcile loops did not stop before the shutdown timeout")
+	}
+	if disk != nil {
+		if err := disk.Flush(); err != nil {
+			logger.Error("flushing state to disk failed", "err", err)
+		}
+	}
+	logger.Info("shutdown complete")
+}
+
+// parseRaftPeers turns "a=http://h1:8421,b=http://h2:8421" into a map.
+func parseRaftPeers(s string) (map[string]string, error) {
+	peers := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		id, addr, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || id == "" || addr == "" {
+			return nil, fmt.Errorf("malformed peer %q, want id=url", pair)
+		}
+		peers[id] = strings.TrimSuffix(addr, "/")
+	}
+	return peers, nil
+}
+
+// controllerOptions carries the flag-derived settings startControllers
+// needs alongside the reloadable tuning config.
+type controllerOptions struct {
+	evictionTimeout     time.Duration
+	clusterCIDR         string
+	lbPool              string
+	acmeDirectory       string
+	acmeEmail           string
+	descheduleThreshold float64
+	kms                 kms.Provider
+}
+
+// startControllers builds the scheduler and reconcile loops with the
+// given tuning and runs them until ctx is canceled. Config reload
+// restarts them under a fresh ctx; a pass in flight when the old ctx
+// ends finishes first, so two generations can briefly overlap — which
+// is harmless, every loop reconciling idempotently against the same
+// store.
+func startControllers(ctx context.Context, loops *sync.WaitGroup, srv *server.Server, st store.Store, isLeader func() bool, cfg *serverConfig, opts controllerOptions) error {
+	runLoop := func(run func(context.Context)) {
+		loops.Add(1)
+		go func() {
+			defer loops.Done()
+			run(ctx)
+		}()
+	}
+
+	lifecycle := controller.NewNodeLifecycleController(st)
+	lifecycle.EvictionTimeout = opts.evictionTimeout
+	lifecycle.Gate = isLeader
+	lifecycle.Record = srv.RecordEvent
+	cfg.interval("nodelifecycle", &lifecycle.MonitorInterval)
+
+	podcidr, err := controller.NewPodCIDRController(st, opts.clusterCIDR)
+	if err != nil {
+		return fmt.Errorf("network controller: %w", err)
+	}
+	podcidr.Gate = isLeader
+	cfg.interval("podcidr", &podcidr.SyncInterval)
+
+	saController := controller.NewServiceAccountController(st)
+	saController.Gate = isLeader
+	cfg.interval("serviceaccount", &saController.SyncInterval)
+
+	recommender := controller.NewRecommendationController(st, srv.PodUsageHistory)
+	recommender.Gate = isLeader
+	cfg.interval("recommender", &recommender.SyncInterval)
+
+	workloads := controller.NewWorkloadController(st)
+	workloads.Gate = isLeader
+	workloads.Record = srv.RecordEvent
+	cfg.interval("workload", &workloads.SyncInterval)
+
+	sched := scheduler.New(st)
+	bindInterval := 2 * time.Second
+	cfg.interval("scheduler", &bindInterval)
+
+	var lb *controller.LoadBalancerController
+	if opts.lbPool != "" {
+		lb, err = controller.NewLoadBalancerController(st, opts.lbPool)
+		if err != nil {
+			return fmt.Errorf("load balancer controller: %w", err)
+		}
+		lb.Gate = isLeader
+		lb.Record = srv.RecordEvent
+		cfg.interval("loadbalancer", &lb.SyncInterval)
+	}
+
+	var desched *controller.DeschedulerController
+	if opts.descheduleThreshold > 0 {
+		desched = controller.NewDeschedulerController(st)
+		desched.HighThreshold = opts.descheduleThreshold
+		desched.Gate = isLeader
+		desched.Record = srv.RecordEvent
+		cfg.interval("descheduler", &desched.SyncInterval)
+	}
+
+	var certs *controller.CertificateController
+	if opts.acmeDirectory != "" {
+		certs = controller.NewCertificateController(st, opts.acmeDirectory, opts.acmeEmail)
+		certs.Gate = isLeader
+		certs.Record = srv.RecordEvent
+		certs.Publish = srv.PublishACMEChallenge
+		certs.Unpublish = srv.RemoveACMEChallenge
+		certs.KMS = opts.kms
+		cfg.interval("certificate", &certs.SyncInterval)
+	}
+
+	// Disabled loops neither run nor register a health check, so
+	// /healthz stays green on deployments that turned them off.
+	if cfg.enabled("nodelifecycle") {
+		srv.AddHealthCheck("nodelifecycle", lifecycle.Healthy)
+		runLoop(lifecycle.Run)
+	}
+	if cfg.enabled("podcidr") {
+		srv.AddHealthCheck("podcidr", podcidr.Healthy)
+		runLoop(podcidr.Run)
+	}
+	if cfg.enabled("serviceaccount") {
+		srv.AddHealthCheck("serviceaccount", saController.Healthy)
+		runLoop(saController.Run)
+	}
+	if cfg.enabled("recommender") {
+		srv.AddHealthCheck("recommender", recommender.Healthy)
+		runLoop(recommender.Run)
+	}
+	if cfg.enabled("workload") {
+		srv.AddHealthCheck("workload", workloads.Healthy)
+		runLoop(workloads.Run)
+	}
+	if cfg.enabled("scheduler") {
+		runLoop(func(ctx context.Context) { bindLoop(ctx, st, sched, isLeader, bindInterval) })
+	}
+	if lb != nil && cfg.enabled("loadbalancer") {
+		srv.AddHealthCheck("loadbalancer", lb.Healthy)
+		runLoop(lb.Run)
+	}
+	if desched != nil && cfg.enabled("descheduler") {
+		srv.AddHealthCheck("descheduler", desched.Healthy)
+		runLoop(desched.Run)
+	}
+	if certs != nil && cfg.enabled("certificate") {
+		srv.AddHealthCheck("certificate", certs.Healthy)
+		runLoop(certs.Run)
+	}
+
+	intervals := map[string]time.Duration{
+		"nodelifecycle":  lifecycle.MonitorInterval,
+		"podcidr":        podcidr.SyncInterval,
+		"serviceaccount": saController.SyncInterval,
+		"recommender":    recommender.SyncInterval,
+		"workload":       workloads.SyncInterval,
+		"scheduler":      bindInterval,
+	}
+	if lb != nil {
+		intervals["loadbalancer"] = lb.SyncInterval
+	}
+	if desched != nil {
+		intervals["descheduler"] = desched.SyncInterval
+	}
+	if certs != nil {
+		intervals["certificate"] = certs.SyncInterval
+	}
+	srv.SetSystemConfig(cfg.effective(intervals))
+	return nil
+}
+
+// bindLoop assigns pending pods to nodes until ctx is canceled.
+// Failures are logged and retried on the next pass; an unschedulable
+// pod stays Pending.
+func bindLoop(ctx context.Context, st store.Store, sched *scheduler.Scheduler, gate func() bool, interval time.Duration) {
+	logger := logging.Component("scheduler")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if gate != nil && !gate() {
+			continue
+		}
+		pods, err := st.ListPods()
+		if err != nil {
+			logger.Error("listing pods failed", "err", err)
+			continue
+		}
+		classes, err := st.ListPriorityClasses()
+		if err != nil {
+			logger.Error("listing priority classes failed", "err", err)
+			continue
+		}
+		prios := scheduler.NewPriorities(classes)
+		pending := pending(pods)
+		// Highest priority schedules first, so the pods allowed to
+		// preempt get first pick of the free capacity.
+		sort.SliceStable(pending, func(i, j int) bool {
+			return prios.Of(pending[i]) > prios.Of(pending[j])
+		})
+		for _, pod := range pending {
+			nodeName, err := sched.Schedule(pod)
+			if err != nil {
+				var unsched *scheduler.UnschedulableError
+				if errors.As(err, &unsched) && prios.Of(pod) > 0 {
+					nodeName = preempt(st, sched, pod, prios, logger)
+				}
+				if nodeName == "" {
+					logger.Warn("pod not scheduled",
+						"namespace", pod.Meta.Namespace, "pod", pod.Meta.Name, "err", err)
+					continue
+				}
+			}
+			pod.Status.NodeName = nodeName
+			logger.Debug("bound pod",
+				"namespace", pod.Meta.Namespace, "pod", pod.Meta.Name, "node", nodeName)
+			if err := st.PutPod(pod); err != nil {
+				logger.Error("binding pod failed",
+					"namespace", pod.Meta.Namespace, "pod", pod.Meta.Name, "err", err)
+			}
+		}
+	}
+}
+
+// pending filters the pods that still need a node. Gated pods wait
+// until their scheduling gates are lifted.
+func pending(pods []*api.Pod) []*api.Pod {
+	var out []*api.Pod
+	for _, pod := range pods {
+		if pod.Status.NodeName == "" && pod.Spec.NodeName == "" && !pod.Spec.Gated() {
+			out = append(out, pod)
+		}
+	}
+	return out
+}
+
+// preempt makes room for a pod no node can currently hold by evicting
+// lower-priority pods, recording why on each victim. It returns the
+// freed node's name, or "" when preemption cannot help.
+func preempt(st store.Store, sched *scheduler.Scheduler, pod *api.Pod, prios *scheduler.Priorities, logger *slog.Logger) string {
+	nodeName, victims, err := sched.Preempt(pod, prios)
+	if err != nil {
+		logger.Warn("preemption failed",
+			"namespace", pod.Meta.Namespace, "pod", pod.Meta.Name, "err", err)
+		return ""
+	}
+	for _, victim := range victims {
+		logger.Info("preempting pod",
+			"namespace", victim.Meta.Namespace, "pod", victim.Meta.Name,
+			"node", nodeName, "for", pod.Meta.Name)
+		victim.Status.NodeName = ""
+		victim.Status.Phase = api.PodPending
+		victim.Status.PodIP = ""
+		victim.Status.Message = fmt.Sprintf("preempted by higher-priority pod %s", pod.Meta.Name)
+		if err := st.PutPod(victim); err != nil {
+			logger.Error("evicting preemption victim failed",
+				"namespace", victim.Meta.Namespace, "pod", victim.Meta.Name, "err", err)
+			return ""
+		}
+	}
+	return nodeName
+}
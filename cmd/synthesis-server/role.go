@@ -0,0 +1,32 @@
+package main
+
+import (
+	"log"
+	"net"
+
+	"github.com/ptfpinho23/synthesis/pkg/agent"
+)
+
+// runAgent runs the embedded node agent: the whole worker for
+// --role=agent, or the in-process worker a --role=all control plane
+// carries so a one-node cluster is a single binary and a single unit.
+func runAgent(serverURL, nodeName, nodeIP string) {
+	if nodeName == "" {
+		log.Fatal("agent role: --node-name is required when the hostname is unavailable")
+	}
+	a := agent.New(serverURL, nodeName)
+	a.NodeIP = nodeIP
+	if err := a.Run(); err != nil {
+		log.Fatalf("agent: %v", err)
+	}
+}
+
+// localServerURL is where an embedded agent reaches the API this
+// process serves, derived from the listen address.
+func localServerURL(listen string) string {
+	_, port, err := net.SplitHostPort(listen)
+	if err != nil || port == "" {
+		port = "8420"
+	}
+	return "http://127.0.0.1:" + port
+}
@@ -0,0 +1,12 @@
+// Command synthesis is the command-line client for a Synthesis cluster.
+package main
+
+import (
+	"os"
+
+	"github.com/ptfpinho23/synthesis/pkg/cli"
+)
+
+func main() {
+	os.Exit(cli.Run(os.Args[1:]))
+}
@@ -0,0 +1,3 @@
+module github.com/ptfpinho23/synthesis
+
+go 1.22
@@ -0,0 +1,353 @@
+// Package acme implements the small slice of RFC 8555 the certificate
+// controller needs: one ES256 account, HTTP-01 validation, and order
+// finalization. It speaks to any conforming directory, Let's Encrypt
+// included.
+package acme
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// pollInterval paces authorization and order status polling.
+const pollInterval = 2 * time.Second
+
+// pollTimeout bounds how long one validation may take before the order
+// is abandoned to the next reconcile pass.
+const pollTimeout = 2 * time.Minute
+
+// Client is an ACME account bound to one directory.
+type Client struct {
+	// Directory is the ACME directory URL.
+	Directory string
+
+	// Key is the ES256 account key.
+	Key *ecdsa.PrivateKey
+
+	// HTTP, when set, replaces http.DefaultClient.
+	HTTP *http.Client
+
+	dir   *directory
+	kid   string
+	nonce string
+}
+
+type directory struct {
+	NewNonce   string `json:"newNonce"`
+	NewAccount string `json:"newAccount"`
+	NewOrder   string `json:"newOrder"`
+}
+
+// Register creates (or re-attaches to) the account for the client's
+// key. It must be called once before Obtain.
+func (c *Client) Register(email string) error {
+	if err := c.discover(); err != nil {
+		return err
+	}
+	payload := map[string]any{"termsOfServiceAgreed": true}
+	if email != "" {
+		payload["contact"] = []string{"mailto:" + email}
+	}
+	resp, err := c.post(c.dir.NewAccount, payload)
+	if err != nil {
+		return fmt.Errorf("registering account: %w", err)
+	}
+	resp.Body.Close()
+	c.kid = resp.Header.Get("Location")
+	if c.kid == "" {
+		return fmt.Errorf("registering account: directory returned no account URL")
+	}
+	return nil
+}
+
+// Obtain runs one order for the given domains and returns the
+// certificate chain and its key, PEM-encoded. For each HTTP-01
+// challenge, solve is called with the token and key authorization to
+// publish, and cleanup afterwards.
+func (c *Client) Obtain(domains []string, solve func(token, keyAuth string) error, cleanup func(token string)) (certPEM, keyPEM []byte, err error) {
+	if c.kid == "" {
+		return nil, nil, fmt.Errorf("account not registered")
+	}
+	ids := make([]map[string]string, len(domains))
+	for i, d := range domains {
+		ids[i] = map[string]string{"type": "dns", "value": d}
+	}
+	resp, err := c.post(c.dir.NewOrder, map[string]any{"identifiers": ids})
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating order: %w", err)
+	}
+	orderURL := resp.Header.Get("Location")
+	var order struct {
+		Status         string   `json:"status"`
+		Authorizations []string `json:"authorizations"`
+		Finalize       string   `json:"finalize"`
+		Certificate    string   `json:"certificate"`
+	}
+	if err := decodeBody(resp, &order); err != nil {
+		return nil, nil, err
+	}
+	for _, authzURL := range order.Authorizations {
+		if err := c.authorize(authzURL, solve, cleanup); err != nil {
+			return nil, nil, err
+		}
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: domains[0]},
+		DNSNames: domains,
+	}, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err = c.post(order.Finalize, map[string]string{"csr": b64(csr)})
+	if err != nil {
+		return nil, nil, fmt.Errorf("finalizing order: %w", err)
+	}
+	resp.Body.Close()
+	deadline := time.Now().Add(pollTimeout)
+	for order.Certificate == "" {
+		if time.Now().After(deadline) {
+			return nil, nil, fmt.Errorf("order %s not issued in time", orderURL)
+		}
+		time.Sleep(pollInterval)
+		resp, err := c.post(orderURL, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := decodeBody(resp, &order); err != nil {
+			return nil, nil, err
+		}
+		if order.Status == "invalid" {
+			return nil, nil, fmt.Errorf("order %s became invalid", orderURL)
+		}
+	}
+	resp, err = c.post(order.Certificate, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("downloading certificate: %w", err)
+	}
+	certPEM, err = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+	return certPEM, keyPEM, nil
+}
+
+// authorize completes one authorization via its HTTP-01 challenge.
+func (c *Client) authorize(authzURL string, solve func(token, keyAuth string) error, cleanup func(token string)) error {
+	var authz struct {
+		Status     string `json:"status"`
+		Challenges []struct {
+			Type  string `json:"type"`
+			URL   string `json:"url"`
+			Token string `json:"token"`
+		} `json:"challenges"`
+	}
+	resp, err := c.post(authzURL, nil)
+	if err != nil {
+		return fmt.Errorf("fetching authorization: %w", err)
+	}
+	if err := decodeBody(resp, &authz); err != nil {
+		return err
+	}
+	if authz.Status == "valid" {
+		return nil
+	}
+	for _, chal := range authz.Challenges {
+		if chal.Type != "http-01" {
+			continue
+		}
+		keyAuth := chal.Token + "." + c.thumbprint()
+		if err := solve(chal.Token, keyAuth); err != nil {
+			return err
+		}
+		defer cleanup(chal.Token)
+		// An empty JSON object tells the CA to start validating.
+		resp, err := c.post(chal.URL, struct{}{})
+		if err != nil {
+			return fmt.Errorf("triggering challenge: %w", err)
+		}
+		resp.Body.Close()
+		deadline := time.Now().Add(pollTimeout)
+		for {
+			if time.Now().After(deadline) {
+				return fmt.Errorf("authorization %s not validated in time", authzURL)
+			}
+			time.Sleep(pollInterval)
+			resp, err := c.post(authzURL, nil)
+			if err != nil {
+				return err
+			}
+			if err := decodeBody(resp, &authz); err != nil {
+				return err
+			}
+			switch authz.Status {
+			case "valid":
+				return nil
+			case "invalid":
+				return fmt.Errorf("authorization %s failed validation", authzURL)
+			}
+		}
+	}
+	return fmt.Errorf("authorization %s offers no http-01 challenge", authzURL)
+}
+
+func (c *Client) discover() error {
+	if c.dir != nil {
+		return nil
+	}
+	resp, err := c.httpClient().Get(c.Directory)
+	if err != nil {
+		return fmt.Errorf("fetching directory: %w", err)
+	}
+	var dir directory
+	if err := decodeBody(resp, &dir); err != nil {
+		return err
+	}
+	c.dir = &dir
+	return nil
+}
+
+// post sends one signed request; a nil payload is a POST-as-GET. A
+// request burned by a stale nonce is retried once with a fresh one.
+func (c *Client) post(url string, payload any) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		body, err := c.sign(url, payload)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.httpClient().Post(url, "application/jose+json", strings.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		if n := resp.Header.Get("Replay-Nonce"); n != "" {
+			c.nonce = n
+		}
+		if resp.StatusCode < 300 {
+			return resp, nil
+		}
+		data, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if attempt == 0 && strings.Contains(string(data), "urn:ietf:params:acme:error:badNonce") {
+			continue
+		}
+		return nil, fmt.Errorf("%s: %s: %s", url, resp.Status, strings.TrimSpace(string(data)))
+	}
+}
+
+// sign wraps the payload in a JWS with this account's key: the
+// registered account URL when known, the bare public key before that.
+func (c *Client) sign(url string, payload any) (string, error) {
+	nonce, err := c.getNonce()
+	if err != nil {
+		return "", err
+	}
+	header := map[string]any{"alg": "ES256", "nonce": nonce, "url": url}
+	if c.kid != "" {
+		header["kid"] = c.kid
+	} else {
+		header["jwk"] = c.jwk()
+	}
+	protected, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	body := ""
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return "", err
+		}
+		body = b64(data)
+	}
+	input := b64(protected) + "." + body
+	digest := sha256.Sum256([]byte(input))
+	r, s, err := ecdsa.Sign(rand.Reader, c.Key, digest[:])
+	if err != nil {
+		return "", err
+	}
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	out, err := json.Marshal(map[string]string{
+		"protected": b64(protected),
+		"payload":   body,
+		"signature": b64(sig),
+	})
+	return string(out), err
+}
+
+func (c *Client) getNonce() (string, error) {
+	if c.nonce != "" {
+		n := c.nonce
+		c.nonce = ""
+		return n, nil
+	}
+	resp, err := c.httpClient().Head(c.dir.NewNonce)
+	if err != nil {
+		return "", fmt.Errorf("fetching nonce: %w", err)
+	}
+	resp.Body.Close()
+	n := resp.Header.Get("Replay-Nonce")
+	if n == "" {
+		return "", fmt.Errorf("directory returned no nonce")
+	}
+	return n, nil
+}
+
+// jwk is the account public key in the canonical form RFC 7638 hashes.
+func (c *Client) jwk() map[string]string {
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	c.Key.X.FillBytes(x)
+	c.Key.Y.FillBytes(y)
+	return map[string]string{"crv": "P-256", "kty": "EC", "x": b64(x), "y": b64(y)}
+}
+
+func (c *Client) thumbprint() string {
+	jwk := c.jwk()
+	// Thumbprints hash the JWK with its keys in lexical order, which
+	// is how encoding/json marshals string-keyed maps.
+	data, _ := json.Marshal(jwk)
+	digest := sha256.Sum256(data)
+	return b64(digest[:])
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTP != nil {
+		return c.HTTP
+	}
+	return http.DefaultClient
+}
+
+func decodeBody(resp *http.Response, out any) error {
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding %s response: %w", resp.Request.URL, err)
+	}
+	return nil
+}
+
+func b64(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -0,0 +1,30 @@
+// Package admission vets pods between decoding and storage. Checks run
+// in order; the first rejection wins, and warnings from every check are
+// collected for the client.
+package admission
+
+import "github.com/ptfpinho23/synthesis/pkg/api"
+
+// Check inspects (and may mutate) a pod about to be stored. It returns
+// human-readable warnings and an error when the pod must be rejected.
+type Check interface {
+	// Name identifies the check in errors and logs.
+	Name() string
+
+	Admit(pod *api.Pod) (warnings []string, err error)
+}
+
+// Chain runs checks in order.
+type Chain []Check
+
+// Admit runs every check, stopping at the first rejection.
+func (c Chain) Admit(pod *api.Pod) (warnings []string, err error) {
+	for _, check := range c {
+		w, err := check.Admit(pod)
+		warnings = append(warnings, w...)
+		if err != nil {
+			return warnings, err
+		}
+	}
+	return warnings, nil
+}
@@ -0,0 +1,72 @@
+package admission
+
+import (
+	"strings"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// Defaults stamps site-wide conventions onto every pod at admission:
+// a registry for bare image names, an image pull policy, and the
+// labels and annotations the organization requires on everything.
+// Values the pod already carries always win, so the check only fills
+// gaps — it never overrides an author's explicit choice.
+type Defaults struct {
+	// Registry is prepended to image references that name no registry
+	// host, so bare names like "nginx" resolve against the site mirror
+	// instead of Docker Hub.
+	Registry string
+
+	// PullPolicy is applied to containers that set none.
+	PullPolicy api.PullPolicy
+
+	// Labels and Annotations are added to every pod's metadata.
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// Name implements Check.
+func (d *Defaults) Name() string { return "Defaults" }
+
+// Admit implements Check; it only mutates and never rejects.
+func (d *Defaults) Admit(pod *api.Pod) ([]string, error) {
+	for _, list := range [][]api.Container{pod.Spec.InitContainers, pod.Spec.Containers, pod.Spec.EphemeralContainers} {
+		for i := range list {
+			c := &list[i]
+			if d.Registry != "" {
+				c.Image = defaultRegistry(d.Registry, c.Image)
+			}
+			if d.PullPolicy != "" && c.ImagePullPolicy == "" {
+				c.ImagePullPolicy = d.PullPolicy
+			}
+		}
+	}
+	pod.Meta.Labels = fillIn(pod.Meta.Labels, d.Labels)
+	pod.Meta.Annotations = fillIn(pod.Meta.Annotations, d.Annotations)
+	return nil, nil
+}
+
+// fillIn adds the defaults the map does not already have a key for.
+func fillIn(have, defaults map[string]string) map[string]string {
+	for k, v := range defaults {
+		if _, ok := have[k]; ok {
+			continue
+		}
+		if have == nil {
+			have = make(map[string]string, len(defaults))
+		}
+		have[k] = v
+	}
+	return have
+}
+
+// defaultRegistry prepends the registry to references that carry no
+// registry host. Docker's rule applies: the first path segment is a
+// host only when it contains a dot or colon or is "localhost".
+func defaultRegistry(registry, image string) string {
+	if first, _, found := strings.Cut(image, "/"); found &&
+		(strings.ContainsAny(first, ".:") || first == "localhost") {
+		return image
+	}
+	return strings.TrimSuffix(registry, "/") + "/" + image
+}
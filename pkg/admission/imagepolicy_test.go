@@ -0,0 +1,55 @@
+package admission
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/store"
+)
+
+// TestImagePolicyChecksAllContainerLists relies on the unconfigured
+// verifier rejecting everything: a pod whose only images live in init or
+// ephemeral containers must still hit verification.
+func TestImagePolicyChecksAllContainerLists(t *testing.T) {
+	cases := []struct {
+		name string
+		spec api.PodSpec
+	}{
+		{"containers", api.PodSpec{Containers: []api.Container{{Name: "c", Image: "app:v1"}}}},
+		{"initContainers", api.PodSpec{InitContainers: []api.Container{{Name: "init", Image: "setup:v1"}}}},
+		{"ephemeralContainers", api.PodSpec{EphemeralContainers: []api.Container{{Name: "debug", Image: "shell:v1"}}}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewImagePolicy(store.NewMemoryStore())
+			pod := &api.Pod{
+				Meta: api.ObjectMeta{Namespace: "default", Name: "p"},
+				Spec: tc.spec,
+			}
+			if _, err := p.Admit(pod); err == nil {
+				t.Fatalf("%s image was not verified", tc.name)
+			}
+		})
+	}
+}
+
+func TestImagePolicyExemptNamespace(t *testing.T) {
+	st := store.NewMemoryStore()
+	st.PutNamespace(&api.Namespace{Meta: api.ObjectMeta{
+		Name:   "dev",
+		Labels: map[string]string{ExemptLabel: "true"},
+	}})
+	p := NewImagePolicy(st)
+	pod := &api.Pod{
+		Meta: api.ObjectMeta{Namespace: "dev", Name: "p"},
+		Spec: api.PodSpec{InitContainers: []api.Container{{Name: "init", Image: "setup:v1"}}},
+	}
+	warnings, err := p.Admit(pod)
+	if err != nil {
+		t.Fatalf("exempt namespace was verified: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "exempt") {
+		t.Fatalf("warnings = %v, want one exemption notice", warnings)
+	}
+}
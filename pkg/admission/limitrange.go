@@ -0,0 +1,88 @@
+package admission
+
+import (
+	"fmt"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/store"
+)
+
+// Namespace labels configuring default container resources, as
+// Kubernetes-style quantities ("500m", "256Mi"). Defaults fill in only
+// what a container leaves unset.
+const (
+	DefaultCPURequestLabel    = "limits.synthesis.io/default-cpu-request"
+	DefaultMemoryRequestLabel = "limits.synthesis.io/default-memory-request"
+	DefaultCPULimitLabel      = "limits.synthesis.io/default-cpu"
+	DefaultMemoryLimitLabel   = "limits.synthesis.io/default-memory"
+)
+
+// LimitDefaults fills in container requests and limits from namespace
+// labels, the LimitRange counterpart: a namespace can guarantee every
+// pod lands with resource bounds even when its manifests say nothing,
+// so unbounded workloads cannot starve the node.
+type LimitDefaults struct {
+	store store.Store
+}
+
+// NewLimitDefaults returns the check backed by namespace labels in the
+// store.
+func NewLimitDefaults(s store.Store) *LimitDefaults { return &LimitDefaults{store: s} }
+
+// Name implements Check.
+func (l *LimitDefaults) Name() string { return "LimitDefaults" }
+
+// Admit applies the namespace's defaults to every container missing
+// the corresponding value. Unparseable labels reject the pod, since
+// silently skipping them would admit exactly the workloads the
+// namespace meant to bound.
+func (l *LimitDefaults) Admit(pod *api.Pod) ([]string, error) {
+	nsName := pod.Meta.Namespace
+	if nsName == "" {
+		nsName = "default"
+	}
+	ns, err := l.store.GetNamespace(nsName)
+	if err != nil {
+		return nil, nil
+	}
+	defaults := []struct {
+		label    string
+		resource string
+		limits   bool
+	}{
+		{DefaultCPURequestLabel, api.ResourceCPU, false},
+		{DefaultMemoryRequestLabel, api.ResourceMemory, false},
+		{DefaultCPULimitLabel, api.ResourceCPU, true},
+		{DefaultMemoryLimitLabel, api.ResourceMemory, true},
+	}
+	for _, d := range defaults {
+		raw := ns.Meta.Labels[d.label]
+		if raw == "" {
+			continue
+		}
+		qty, err := api.ParseQuantity(d.resource, raw)
+		if err != nil {
+			return nil, fmt.Errorf("namespace label %s: %v", d.label, err)
+		}
+		for i := range pod.Spec.Containers {
+			applyDefault(&pod.Spec.Containers[i], d.resource, qty, d.limits)
+		}
+		for i := range pod.Spec.InitContainers {
+			applyDefault(&pod.Spec.InitContainers[i], d.resource, qty, d.limits)
+		}
+	}
+	return nil, nil
+}
+
+func applyDefault(c *api.Container, resource string, qty int64, limits bool) {
+	list := &c.Resources.Requests
+	if limits {
+		list = &c.Resources.Limits
+	}
+	if *list == nil {
+		*list = api.ResourceList{}
+	}
+	if _, ok := (*list)[resource]; !ok {
+		(*list)[resource] = qty
+	}
+}
@@ -0,0 +1,96 @@
+package admission
+
+import (
+	"fmt"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/store"
+)
+
+// Namespace labels configuring pod security. The enforce label rejects
+// violating pods; the warn label only reports them.
+const (
+	EnforceLabel = "pod-security.synthesis.io/enforce"
+	WarnLabel    = "pod-security.synthesis.io/warn"
+)
+
+// Profiles, from loosest to strictest. Privileged allows everything;
+// baseline blocks the host-breakout vectors; restricted additionally
+// demands non-root, non-escalating containers.
+const (
+	ProfilePrivileged = "privileged"
+	ProfileBaseline   = "baseline"
+	ProfileRestricted = "restricted"
+)
+
+// PodSecurity enforces Pod Security Standards per namespace label.
+type PodSecurity struct {
+	store store.Store
+}
+
+// NewPodSecurity returns the check backed by namespace labels in the
+// store.
+func NewPodSecurity(s store.Store) *PodSecurity { return &PodSecurity{store: s} }
+
+// Name implements Check.
+func (p *PodSecurity) Name() string { return "PodSecurity" }
+
+// Admit applies the namespace's enforce profile as a rejection and its
+// warn profile as warnings. Namespaces without labels are privileged.
+func (p *PodSecurity) Admit(pod *api.Pod) ([]string, error) {
+	nsName := pod.Meta.Namespace
+	if nsName == "" {
+		nsName = "default"
+	}
+	enforce, warn := ProfilePrivileged, ProfilePrivileged
+	if ns, err := p.store.GetNamespace(nsName); err == nil {
+		if v := ns.Meta.Labels[EnforceLabel]; v != "" {
+			enforce = v
+		}
+		if v := ns.Meta.Labels[WarnLabel]; v != "" {
+			warn = v
+		}
+	}
+
+	if violations := violations(pod, enforce); len(violations) > 0 {
+		return nil, fmt.Errorf("pod violates the %q pod security profile: %s", enforce, violations[0])
+	}
+	var warnings []string
+	for _, v := range violations(pod, warn) {
+		warnings = append(warnings, fmt.Sprintf("would violate the %q pod security profile: %s", warn, v))
+	}
+	return warnings, nil
+}
+
+// violations lists how the pod falls short of the profile.
+func violations(pod *api.Pod, profile string) []string {
+	if profile == ProfilePrivileged || profile == "" {
+		return nil
+	}
+	var out []string
+	if pod.Spec.HostNetwork {
+		out = append(out, "hostNetwork is not allowed")
+	}
+	for _, vol := range pod.Spec.Volumes {
+		if vol.HostPath != nil {
+			out = append(out, fmt.Sprintf("hostPath volume %q is not allowed", vol.Name))
+		}
+	}
+	for _, list := range [][]api.Container{pod.Spec.InitContainers, pod.Spec.Containers, pod.Spec.EphemeralContainers} {
+		for _, c := range list {
+			sc := c.SecurityContext
+			if sc != nil && sc.Privileged {
+				out = append(out, fmt.Sprintf("container %q must not be privileged", c.Name))
+			}
+			if profile == ProfileRestricted {
+				if sc == nil || !sc.RunAsNonRoot {
+					out = append(out, fmt.Sprintf("container %q must set runAsNonRoot", c.Name))
+				}
+				if sc == nil || sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+					out = append(out, fmt.Sprintf("container %q must set allowPrivilegeEscalation: false", c.Name))
+				}
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,72 @@
+package admission
+
+import (
+	"testing"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/store"
+)
+
+func baselineStore(t *testing.T) *store.MemoryStore {
+	t.Helper()
+	st := store.NewMemoryStore()
+	st.PutNamespace(&api.Namespace{Meta: api.ObjectMeta{
+		Name:   "default",
+		Labels: map[string]string{EnforceLabel: ProfileBaseline},
+	}})
+	return st
+}
+
+// TestPodSecurityChecksAllContainerLists puts a privileged container in
+// each of the three lists; a baseline namespace must reject all of them.
+func TestPodSecurityChecksAllContainerLists(t *testing.T) {
+	privileged := []api.Container{{
+		Name:            "c",
+		Image:           "app:v1",
+		SecurityContext: &api.SecurityContext{Privileged: true},
+	}}
+	cases := []struct {
+		name string
+		spec api.PodSpec
+	}{
+		{"containers", api.PodSpec{Containers: privileged}},
+		{"initContainers", api.PodSpec{InitContainers: privileged}},
+		{"ephemeralContainers", api.PodSpec{EphemeralContainers: privileged}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewPodSecurity(baselineStore(t))
+			pod := &api.Pod{
+				Meta: api.ObjectMeta{Namespace: "default", Name: "p"},
+				Spec: tc.spec,
+			}
+			if _, err := p.Admit(pod); err == nil {
+				t.Fatalf("privileged %s passed the baseline profile", tc.name)
+			}
+		})
+	}
+}
+
+func TestPodSecurityWarnOnly(t *testing.T) {
+	st := store.NewMemoryStore()
+	st.PutNamespace(&api.Namespace{Meta: api.ObjectMeta{
+		Name:   "default",
+		Labels: map[string]string{WarnLabel: ProfileBaseline},
+	}})
+	p := NewPodSecurity(st)
+	pod := &api.Pod{
+		Meta: api.ObjectMeta{Namespace: "default", Name: "p"},
+		Spec: api.PodSpec{InitContainers: []api.Container{{
+			Name:            "init",
+			Image:           "setup:v1",
+			SecurityContext: &api.SecurityContext{Privileged: true},
+		}}},
+	}
+	warnings, err := p.Admit(pod)
+	if err != nil {
+		t.Fatalf("warn-only namespace rejected the pod: %v", err)
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("warnings = %v, want exactly one", warnings)
+	}
+}
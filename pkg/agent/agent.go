@@ -0,0 +1,299 @@
+// Package agent is the per-node daemon. It registers its node with the
+// API server and keeps a heartbeat lease renewed; pod lifecycle and
+// runtime plumbing hang off the same loop.
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	goruntime "runtime"
+	"sync"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/logging"
+	"github.com/ptfpinho23/synthesis/pkg/runtime"
+	"github.com/ptfpinho23/synthesis/pkg/systemd"
+	"github.com/ptfpinho23/synthesis/pkg/version"
+)
+
+// Agent runs on every node in the cluster.
+type Agent struct {
+	// Server is the base URL of the API server.
+	Server string
+
+	// NodeName is this node's registered name.
+	NodeName string
+
+	// NodeIP is the address other nodes reach this one on, used as the
+	// overlay mesh endpoint.
+	NodeIP string
+
+	// Reserved, when set, replaces the built-in reservation subtracted
+	// from capacity when computing allocatable, so operators can size
+	// the cut for their OS daemons and the agent itself.
+	Reserved api.ResourceList
+
+	// HeartbeatInterval is how often the lease is renewed. The lease
+	// duration is three intervals, so two missed beats keep the node
+	// Ready and the third marks it NotReady.
+	HeartbeatInterval time.Duration
+
+	// Runtime runs the pods bound to this node.
+	Runtime runtime.Runtime
+
+	// SyncInterval is how often bound pods are reconciled against the
+	// runtime's containers.
+	SyncInterval time.Duration
+
+	// StaticPodsDir, when set, is a directory of pod manifests this
+	// agent runs independently of the API server, mirrored into the API
+	// as read-only objects. Static pods bootstrap the node and keep
+	// critical workloads alive when the control plane is unreachable.
+	StaticPodsDir string
+
+	// StartupBurst caps how many cold pods may start containers in one
+	// sync pass while the node restores after a restart, spreading the
+	// image mounts and IO out on small devices. Zero lifts the cap.
+	StartupBurst int
+
+	// OrphanAfter is how long the agent keeps running its last known
+	// pods while the server is unreachable. Past the deadline the
+	// cached pods are torn down; zero, the default, keeps them running
+	// indefinitely, which is what flaky edge uplinks want.
+	OrphanAfter time.Duration
+
+	// ResyncInterval is how often the slow anti-entropy pass relists
+	// the engine and repairs drift from the stored specs (missing
+	// containers, wrong images, mutated env). It only runs when the
+	// runtime can check drift.
+	ResyncInterval time.Duration
+
+	// StatsInterval is how often usage samples are pushed to the API
+	// server for the metrics endpoints.
+	StatsInterval time.Duration
+
+	// PressureInterval is how often node memory and disk usage are
+	// checked against the eviction thresholds.
+	PressureInterval time.Duration
+
+	// MemoryPressureThreshold and DiskPressureThreshold are the used
+	// fractions above which the node reports pressure and starts
+	// evicting pods in QoS order.
+	MemoryPressureThreshold float64
+	DiskPressureThreshold   float64
+
+	// cache is the informer-style view of the runtime's containers
+	// shared by the sync and stats loops.
+	cache *runtime.ContainerCache
+
+	// pulls deduplicates and throttles image pulls when the runtime can
+	// pull explicitly; nil otherwise, leaving pulls implicit in SyncPod.
+	pulls *runtime.PullManager
+
+	// restarts tracks per-container restart counts and terminations for
+	// the pod statuses this agent reports.
+	restarts *restartTracker
+
+	// syncKick wakes the pod sync loop early when a runtime event makes
+	// the current state stale.
+	syncKick chan struct{}
+
+	// lastDesired is the node's pod set from the last successful list,
+	// run in place while the server is unreachable; lastContact is when
+	// that list succeeded, which the orphan deadline counts from.
+	lastDesired map[string]*api.Pod
+	lastContact time.Time
+
+	// pendingStatus queues status updates that could not be delivered
+	// while offline, flushed on the first pass after reconnecting.
+	pendingStatus map[string]*api.Pod
+
+	// evictMu guards evicting, the pods whose graceful teardown is in
+	// flight, so a stop waiting out its grace period is not restarted
+	// every sync pass.
+	evictMu  sync.Mutex
+	evicting map[string]bool
+
+	// restore tracks the post-restart restoration of previously running
+	// pods; restoreAssessed marks the one-time check that starts it.
+	restore         *restoreState
+	restoreAssessed bool
+
+	log *slog.Logger
+}
+
+// New returns an agent with default timings and the Docker runtime.
+func New(server, nodeName string) *Agent {
+	return &Agent{
+		Server:                  server,
+		NodeName:                nodeName,
+		HeartbeatInterval:       10 * time.Second,
+		Runtime:                 runtime.NewDocker(),
+		SyncInterval:            10 * time.Second,
+		StartupBurst:            3,
+		ResyncInterval:          5 * time.Minute,
+		StatsInterval:           10 * time.Second,
+		PressureInterval:        10 * time.Second,
+		MemoryPressureThreshold: 0.9,
+		DiskPressureThreshold:   0.9,
+		log:                     logging.Component("agent"),
+	}
+}
+
+// Run registers the node and then renews its lease until the process
+// exits. Registration retries forever so agents can start before the
+// control plane.
+func (a *Agent) Run() error {
+	a.cache = runtime.NewContainerCache(a.Runtime)
+	a.restarts = newRestartTracker()
+	a.syncKick = make(chan struct{}, 1)
+	a.pendingStatus = make(map[string]*api.Pod)
+	a.cache.OnEvent = a.onContainerEvent
+	if sc, ok := a.Runtime.(runtime.StrayCleaner); ok {
+		// Free any managed names held by unlabeled leftovers before the
+		// first sync tries to create containers under them.
+		if err := sc.RemoveStrayContainers(); err != nil {
+			a.log.Warn("cleaning up stray containers failed", "err", err)
+		}
+	}
+	if p, ok := a.Runtime.(runtime.ImagePuller); ok {
+		a.pulls = runtime.NewPullManager(p)
+	}
+	go a.cache.Run()
+	// Static pods must not wait for the control plane — the server may
+	// itself be one of them — so with a manifest directory configured
+	// the pod sync loop starts before registration.
+	if a.StaticPodsDir != "" {
+		go a.runPodSync()
+	}
+	for {
+		if err := a.register(); err != nil {
+			a.log.Warn("registering node failed, retrying", "err", err)
+			time.Sleep(a.HeartbeatInterval)
+			continue
+		}
+		break
+	}
+	a.log.Info("node registered", "node", a.NodeName, "server", a.Server)
+	// Under a Type=notify unit the agent counts as started only once
+	// its node is registered; elsewhere both calls are no-ops.
+	systemd.Ready()
+	go systemd.Watchdog(context.Background())
+	go a.runMesh()
+	if a.StaticPodsDir == "" {
+		go a.runPodSync()
+	}
+	go a.runResync()
+	go a.runImagePulls()
+	go a.runServiceProxy()
+	go a.runIngressProxy()
+	go a.runAnnouncer()
+	go a.runLogServer()
+	go a.runUpgrade()
+	go a.runStats()
+	go a.runPressure()
+
+	for range time.Tick(a.HeartbeatInterval) {
+		if err := a.renewLease(); err != nil {
+			a.log.Error("renewing lease failed", "err", err)
+		}
+	}
+	return nil
+}
+
+// systemReserved is held back from capacity when computing allocatable
+// unless the operator configures a reservation, leaving room for the
+// OS and the agent itself.
+var systemReserved = api.ResourceList{
+	api.ResourceCPU:    100,               // 100m
+	api.ResourceMemory: 128 * 1024 * 1024, // 128Mi
+}
+
+func (a *Agent) register() error {
+	capacity, err := hostCapacity()
+	if err != nil {
+		return fmt.Errorf("inspecting host: %w", err)
+	}
+	reserved := systemReserved
+	if a.Reserved != nil {
+		reserved = a.Reserved
+	}
+	node := &api.Node{
+		Meta: api.ObjectMeta{
+			Name:      a.NodeName,
+			CreatedAt: time.Now(),
+			Labels: map[string]string{
+				api.ArchLabel: goruntime.GOARCH,
+				api.OSLabel:   goruntime.GOOS,
+			},
+		},
+		Status: api.NodeStatus{
+			Address:      a.NodeIP,
+			Capacity:     capacity,
+			Allocatable:  capacity.Sub(reserved),
+			AgentVersion: version.Version,
+		},
+	}
+	if err := a.put("/api/v1/nodes/"+url.PathEscape(a.NodeName), node); err != nil {
+		return err
+	}
+	return a.renewLease()
+}
+
+func (a *Agent) renewLease() error {
+	lease := &api.Lease{
+		Meta: api.ObjectMeta{Name: a.NodeName},
+		Spec: api.LeaseSpec{
+			HolderIdentity:  a.NodeName,
+			DurationSeconds: int(3 * a.HeartbeatInterval / time.Second),
+		},
+	}
+	return a.put("/api/v1/leases/"+url.PathEscape(a.NodeName), lease)
+}
+
+func (a *Agent) get(path string, out any) error {
+	resp, err := http.Get(a.Server + path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("GET %s: server returned %s", path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (a *Agent) put(path string, body any) error {
+	return a.send(http.MethodPut, path, body)
+}
+
+func (a *Agent) post(path string, body any) error {
+	return a.send(http.MethodPost, path, body)
+}
+
+func (a *Agent) send(method, path string, body any) error {
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(method, a.Server+path, bytes.NewReader(buf))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s: server returned %s", method, path, resp.Status)
+	}
+	return nil
+}
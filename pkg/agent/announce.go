@@ -0,0 +1,140 @@
+package agent
+
+import (
+	"fmt"
+	"net"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// runAnnouncer keeps this node's share of the load balancer addresses
+// reachable, MetalLB layer-2 style: one ready node — the
+// lexicographically first, so every agent picks the same one without
+// extra coordination — adds each allocated address to its interface and
+// answers ARP for it. When that node goes unready the next one takes
+// over on its following pass.
+func (a *Agent) runAnnouncer() {
+	announced := make(map[string]bool)
+	ticker := time.NewTicker(a.SyncInterval)
+	defer ticker.Stop()
+	for ; ; <-ticker.C {
+		if err := a.syncAnnouncements(announced); err != nil {
+			a.log.Error("syncing load balancer announcements failed", "err", err)
+		}
+	}
+}
+
+func (a *Agent) syncAnnouncements(announced map[string]bool) error {
+	want := make(map[string]bool)
+	if a.isAnnouncer() {
+		var svcs []*api.Service
+		if err := a.get("/api/v1/services", &svcs); err != nil {
+			return err
+		}
+		for _, svc := range svcs {
+			for _, ing := range svc.Status.LoadBalancer.Ingress {
+				want[ing.IP] = true
+			}
+		}
+	}
+	iface := ""
+	for ip := range want {
+		if announced[ip] {
+			continue
+		}
+		if iface == "" {
+			var err error
+			if iface, err = ifaceForIP(a.NodeIP); err != nil {
+				return err
+			}
+		}
+		if err := a.announce(ip, iface); err != nil {
+			a.log.Error("announcing load balancer address failed", "ip", ip, "err", err)
+			continue
+		}
+		announced[ip] = true
+		a.log.Info("announcing load balancer address", "ip", ip, "iface", iface)
+	}
+	for ip := range announced {
+		if want[ip] {
+			continue
+		}
+		if iface == "" {
+			var err error
+			if iface, err = ifaceForIP(a.NodeIP); err != nil {
+				return err
+			}
+		}
+		if err := a.withdraw(ip, iface); err != nil {
+			a.log.Error("withdrawing load balancer address failed", "ip", ip, "err", err)
+			continue
+		}
+		delete(announced, ip)
+		a.log.Info("withdrew load balancer address", "ip", ip)
+	}
+	return nil
+}
+
+// isAnnouncer reports whether this node should announce the load
+// balancer addresses right now.
+func (a *Agent) isAnnouncer() bool {
+	var nodes []*api.Node
+	if err := a.get("/api/v1/nodes", &nodes); err != nil {
+		a.log.Error("listing nodes failed", "err", err)
+		return false
+	}
+	leader := ""
+	for _, node := range nodes {
+		if !node.Ready() {
+			continue
+		}
+		if leader == "" || node.Meta.Name < leader {
+			leader = node.Meta.Name
+		}
+	}
+	return leader != "" && leader == a.NodeName
+}
+
+func (a *Agent) announce(ip, iface string) error {
+	out, err := exec.Command("ip", "addr", "add", ip+"/32", "dev", iface).CombinedOutput()
+	if err != nil && !strings.Contains(string(out), "File exists") {
+		return fmt.Errorf("adding %s to %s: %v: %s", ip, iface, err, strings.TrimSpace(string(out)))
+	}
+	// A gratuitous ARP flushes stale neighbor caches after a failover;
+	// without arping installed, peers converge as their entries expire.
+	exec.Command("arping", "-c", "2", "-U", "-I", iface, ip).Run()
+	return nil
+}
+
+func (a *Agent) withdraw(ip, iface string) error {
+	out, err := exec.Command("ip", "addr", "del", ip+"/32", "dev", iface).CombinedOutput()
+	if err != nil && !strings.Contains(string(out), "Cannot assign") {
+		return fmt.Errorf("removing %s from %s: %v: %s", ip, iface, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// ifaceForIP finds the interface holding the node's advertised address,
+// which is where the load balancer addresses belong too.
+func ifaceForIP(nodeIP string) (string, error) {
+	want := net.ParseIP(nodeIP)
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return "", err
+	}
+	for _, iface := range ifaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if ipn, ok := addr.(*net.IPNet); ok && ipn.IP.Equal(want) {
+				return iface.Name, nil
+			}
+		}
+	}
+	return "", &net.AddrError{Err: "no interface holds the node address", Addr: nodeIP}
+}
@@ -0,0 +1,64 @@
+package agent
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// runImagePulls polls the server's pre-pull queue and warms this
+// node's image cache through the pull manager, so a later rollout
+// starts containers without waiting on the registry. Outcomes are
+// reported as node events. Requires a runtime that can pull.
+func (a *Agent) runImagePulls() {
+	if a.pulls == nil {
+		return
+	}
+	var after int64
+	inflight := make(map[int64]string)
+	ticker := time.NewTicker(a.SyncInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		var reqs []api.ImagePullRequest
+		path := fmt.Sprintf("/api/v1/images/pulls?node=%s&after=%d",
+			url.QueryEscape(a.NodeName), after)
+		if err := a.get(path, &reqs); err != nil {
+			a.log.Warn("listing image pulls failed", "err", err)
+			continue
+		}
+		for _, req := range reqs {
+			inflight[req.ID] = req.Image
+			if req.ID > after {
+				after = req.ID
+			}
+		}
+		for id, image := range inflight {
+			done, err := a.pulls.TryPull(image)
+			switch {
+			case err != nil:
+				a.log.Warn("pre-pull failed", "image", image, "err", err)
+				a.recordPull(api.EventWarning, "ImagePullFailed",
+					fmt.Sprintf("pre-pull of %s failed: %v", image, err))
+				delete(inflight, id)
+			case done:
+				a.log.Info("pre-pulled image", "image", image)
+				a.recordPull(api.EventNormal, "ImagePulled", "pre-pulled "+image)
+				delete(inflight, id)
+			}
+		}
+	}
+}
+
+func (a *Agent) recordPull(evType, reason, message string) {
+	ev := api.Event{
+		Type:      evType,
+		Reason:    reason,
+		Message:   message,
+		Regarding: api.ObjectRef{Kind: "Node", Name: a.NodeName},
+	}
+	if err := a.post("/api/v1/events", ev); err != nil {
+		a.log.Warn("recording pull event failed", "err", err)
+	}
+}
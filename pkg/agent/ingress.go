@@ -0,0 +1,717 @@
+package agent
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// acmeChallengePrefix is the well-known path HTTP-01 validations hit.
+const acmeChallengePrefix = "/.well-known/acme-challenge/"
+
+// probeTimeout bounds each backend health probe.
+const probeTimeout = 2 * time.Second
+
+// probeFailures is how many consecutive failed probes take a backend
+// out of rotation; one success puts it back.
+const probeFailures = 3
+
+// runIngressProxy serves the cluster's ingresses from this node: HTTP
+// on port 80 and, once any ingress carries TLS material, HTTPS on 443.
+// Routes and certificates are rebuilt from the API every sync
+// interval, so new ingresses, changed endpoints and renewed
+// certificates all take effect without restarting the agent.
+func (a *Agent) runIngressProxy() {
+	px := &ingressProxy{agent: a}
+	ticker := time.NewTicker(a.SyncInterval)
+	defer ticker.Stop()
+	for ; ; <-ticker.C {
+		if err := a.syncIngressProxy(px); err != nil {
+			a.log.Error("syncing ingress proxy failed", "err", err)
+		}
+	}
+}
+
+func (a *Agent) syncIngressProxy(px *ingressProxy) error {
+	var ings []*api.Ingress
+	if err := a.get("/api/v1/ingresses", &ings); err != nil {
+		return err
+	}
+	var gws []*api.Gateway
+	if err := a.get("/api/v1/gateways", &gws); err != nil {
+		return err
+	}
+	if len(ings) == 0 && len(gws) == 0 && !px.started {
+		return nil
+	}
+	routes := make(map[string][]pathRoute)
+	streams := make(map[string][]string)
+	certs := make(map[string]*tls.Certificate)
+	for _, ing := range ings {
+		namespace := ing.Meta.Namespace
+		if namespace == "" {
+			namespace = "default"
+		}
+		tuning := routeTuning(ing.Meta.Annotations)
+		for _, rule := range ing.Spec.Rules {
+			for _, p := range rule.Paths {
+				backends, err := a.pathBackends(namespace, p)
+				if err != nil {
+					a.log.Error("resolving ingress backends failed",
+						"ingress", ing.Meta.Name, "service", p.Service, "err", err)
+					continue
+				}
+				if tuning.stream {
+					// Stream relays route whole hosts; path routing
+					// needs the proxy to read the requests.
+					streams[rule.Host] = backends
+					continue
+				}
+				prefix := p.Path
+				if prefix == "" {
+					prefix = "/"
+				}
+				routes[rule.Host] = append(routes[rule.Host], pathRoute{
+					prefix:     prefix,
+					groups:     []backendGroup{{addrs: backends, weight: 1}},
+					timeout:    tuning.timeout,
+					retries:    tuning.retries,
+					healthPath: tuning.healthPath,
+				})
+			}
+		}
+		for _, t := range ing.Spec.TLS {
+			if cert := a.loadCertificate(namespace, t.SecretName, ing.Meta.Name); cert != nil {
+				for _, host := range t.Hosts {
+					certs[host] = cert
+				}
+			}
+		}
+	}
+	a.compileGateways(gws, routes, certs)
+	// The most specific route wins — exact over prefix, longer
+	// prefixes and more header conditions first — so each host's
+	// routes are ordered once here instead of scanning per request.
+	for host := range routes {
+		sort.SliceStable(routes[host], func(i, j int) bool {
+			a, b := routes[host][i], routes[host][j]
+			if a.exact != b.exact {
+				return a.exact
+			}
+			if len(a.prefix) != len(b.prefix) {
+				return len(a.prefix) > len(b.prefix)
+			}
+			return len(a.headers) > len(b.headers)
+		})
+	}
+	px.update(routes, streams, certs)
+	px.probeBackends()
+	return px.ensureStarted(len(certs) > 0)
+}
+
+// compileGateways folds every gateway's attached HTTPRoutes into the
+// shared route table, giving Gateway API users header matching and
+// weighted splits on the same data plane Ingress uses.
+func (a *Agent) compileGateways(gws []*api.Gateway, routes map[string][]pathRoute, certs map[string]*tls.Certificate) {
+	if len(gws) == 0 {
+		return
+	}
+	var hrs []*api.HTTPRoute
+	if err := a.get("/api/v1/httproutes", &hrs); err != nil {
+		a.log.Error("listing httproutes failed", "err", err)
+		return
+	}
+	type listenerSet struct {
+		gw    *api.Gateway
+		hosts []string
+	}
+	gateways := make(map[string]listenerSet)
+	for _, gw := range gws {
+		namespace := gw.Meta.Namespace
+		if namespace == "" {
+			namespace = "default"
+		}
+		var hosts []string
+		for _, l := range gw.Spec.Listeners {
+			if l.Protocol == "HTTPS" && l.TLS != nil {
+				if cert := a.loadCertificate(namespace, l.TLS.SecretName, gw.Meta.Name); cert != nil && l.Hostname != "" {
+					certs[l.Hostname] = cert
+				}
+			}
+			hosts = append(hosts, l.Hostname)
+		}
+		gateways[namespace+"/"+gw.Meta.Name] = listenerSet{gw: gw, hosts: hosts}
+	}
+	for _, hr := range hrs {
+		namespace := hr.Meta.Namespace
+		if namespace == "" {
+			namespace = "default"
+		}
+		var hosts []string
+		attached := false
+		for _, ref := range hr.Spec.ParentRefs {
+			set, ok := gateways[namespace+"/"+ref]
+			if !ok {
+				a.log.Warn("httproute references unknown gateway",
+					"httproute", hr.Meta.Name, "gateway", ref)
+				continue
+			}
+			attached = true
+			hosts = append(hosts, set.hosts...)
+		}
+		if !attached {
+			continue
+		}
+		if len(hr.Spec.Hostnames) > 0 {
+			hosts = hr.Spec.Hostnames
+		}
+		tuning := routeTuning(hr.Meta.Annotations)
+		for _, rule := range hr.Spec.Rules {
+			var groups []backendGroup
+			for _, ref := range rule.BackendRefs {
+				backends, err := a.pathBackends(namespace, api.IngressPath{
+					Service: ref.Service, Port: ref.Port,
+				})
+				if err != nil {
+					a.log.Error("resolving httproute backends failed",
+						"httproute", hr.Meta.Name, "service", ref.Service, "err", err)
+					continue
+				}
+				weight := ref.Weight
+				if weight == 0 {
+					weight = 1
+				}
+				groups = append(groups, backendGroup{addrs: backends, weight: weight})
+			}
+			if len(groups) == 0 {
+				continue
+			}
+			matches := rule.Matches
+			if len(matches) == 0 {
+				matches = []api.HTTPRouteMatch{{}}
+			}
+			for _, m := range matches {
+				route := pathRoute{
+					prefix:     "/",
+					headers:    m.Headers,
+					groups:     groups,
+					timeout:    tuning.timeout,
+					retries:    tuning.retries,
+					healthPath: tuning.healthPath,
+				}
+				if m.Path != nil && m.Path.Value != "" {
+					route.prefix = m.Path.Value
+					route.exact = m.Path.Type == api.PathMatchExact
+				}
+				for _, host := range hosts {
+					routes[host] = append(routes[host], route)
+				}
+			}
+		}
+	}
+}
+
+// loadCertificate fetches a TLS secret and parses its key pair,
+// returning nil when it is absent or not issued yet.
+func (a *Agent) loadCertificate(namespace, secretName, owner string) *tls.Certificate {
+	var sec api.Secret
+	path := "/api/v1/namespaces/" + url.PathEscape(namespace) +
+		"/secrets/" + url.PathEscape(secretName)
+	if err := a.get(path, &sec); err != nil {
+		return nil // not issued yet; serve plain HTTP meanwhile
+	}
+	cert, err := tls.X509KeyPair(sec.Data["tls.crt"], sec.Data["tls.key"])
+	if err != nil {
+		a.log.Error("loading ingress certificate failed",
+			"owner", owner, "secret", secretName, "err", err)
+		return nil
+	}
+	return &cert
+}
+
+// tuning carries annotation-derived proxy settings.
+type tuning struct {
+	timeout    time.Duration
+	retries    int
+	healthPath string
+	stream     bool
+}
+
+func routeTuning(ann map[string]string) tuning {
+	var t tuning
+	if d, err := time.ParseDuration(ann[api.ProxyTimeoutAnnotation]); err == nil && d > 0 {
+		t.timeout = d
+	}
+	if n, err := strconv.Atoi(ann[api.ProxyRetriesAnnotation]); err == nil && n > 0 {
+		t.retries = n
+	}
+	t.healthPath = ann[api.HealthCheckPathAnnotation]
+	switch ann[api.BackendProtocolAnnotation] {
+	case "h2c", "grpc":
+		t.stream = true
+	}
+	return t
+}
+
+// pathBackends resolves one ingress path to its backend addresses via
+// the named service's endpoints.
+func (a *Agent) pathBackends(namespace string, p api.IngressPath) ([]string, error) {
+	base := "/api/v1/namespaces/" + url.PathEscape(namespace) +
+		"/services/" + url.PathEscape(p.Service)
+	var svc api.Service
+	if err := a.get(base, &svc); err != nil {
+		return nil, err
+	}
+	var port *api.ServicePort
+	for i, sp := range svc.Spec.Ports {
+		if p.Port == "" && len(svc.Spec.Ports) == 1 ||
+			p.Port == sp.Name ||
+			p.Port == strconv.Itoa(sp.Port) {
+			port = &svc.Spec.Ports[i]
+			break
+		}
+	}
+	if port == nil {
+		return nil, fmt.Errorf("service %s has no port %q", p.Service, p.Port)
+	}
+	var endpoints []api.Endpoint
+	if err := a.get(base+"/endpoints", &endpoints); err != nil {
+		return nil, err
+	}
+	var backends []string
+	for _, ep := range endpoints {
+		if ep.PortName != port.Name {
+			continue
+		}
+		backends = append(backends, fmt.Sprintf("%s:%d", ep.IP, ep.Port))
+	}
+	return backends, nil
+}
+
+// backendGroup is one weighted share of a route's traffic.
+type backendGroup struct {
+	addrs  []string
+	weight int
+}
+
+// pathRoute is one compiled route: a path condition, optional header
+// conditions, and the weighted backend groups behind them.
+type pathRoute struct {
+	prefix     string
+	exact      bool
+	headers    []api.HTTPHeaderMatch
+	groups     []backendGroup
+	timeout    time.Duration
+	retries    int
+	healthPath string
+}
+
+// backendHealth tracks one backend's probe record.
+type backendHealth struct {
+	fails int
+	down  bool
+}
+
+// ingressProxy is the node's HTTP(S) ingress data plane.
+type ingressProxy struct {
+	agent   *Agent
+	started bool
+	tlsUp   bool
+
+	mu      sync.Mutex
+	routes  map[string][]pathRoute
+	streams map[string][]string
+	certs   map[string]*tls.Certificate
+	health  map[string]*backendHealth
+	next    int
+
+	// tlsConns feeds handshaken non-stream connections to the HTTPS
+	// server.
+	tlsConns chan net.Conn
+}
+
+func (px *ingressProxy) update(routes map[string][]pathRoute, streams map[string][]string, certs map[string]*tls.Certificate) {
+	px.mu.Lock()
+	defer px.mu.Unlock()
+	px.routes = routes
+	px.streams = streams
+	px.certs = certs
+}
+
+// probeBackends checks every route's backends once, in the background,
+// and updates their rotation status: an HTTP probe when the route
+// configures a path, a bare TCP dial otherwise.
+func (px *ingressProxy) probeBackends() {
+	paths := make(map[string]string)
+	px.mu.Lock()
+	for _, routes := range px.routes {
+		for _, route := range routes {
+			for _, group := range route.groups {
+				for _, b := range group.addrs {
+					if _, ok := paths[b]; !ok || route.healthPath != "" {
+						paths[b] = route.healthPath
+					}
+				}
+			}
+		}
+	}
+	if px.health == nil {
+		px.health = make(map[string]*backendHealth)
+	}
+	for addr := range px.health {
+		if _, ok := paths[addr]; !ok {
+			delete(px.health, addr)
+		}
+	}
+	px.mu.Unlock()
+	for addr, path := range paths {
+		go px.probe(addr, path)
+	}
+}
+
+func (px *ingressProxy) probe(addr, path string) {
+	var err error
+	if path == "" {
+		var conn net.Conn
+		if conn, err = net.DialTimeout("tcp", addr, probeTimeout); err == nil {
+			conn.Close()
+		}
+	} else {
+		client := &http.Client{Timeout: probeTimeout}
+		var resp *http.Response
+		if resp, err = client.Get("http://" + addr + path); err == nil {
+			resp.Body.Close()
+			if resp.StatusCode >= 500 {
+				err = fmt.Errorf("probe returned %s", resp.Status)
+			}
+		}
+	}
+	px.mu.Lock()
+	defer px.mu.Unlock()
+	h := px.health[addr]
+	if h == nil {
+		h = &backendHealth{}
+		px.health[addr] = h
+	}
+	if err == nil {
+		if h.down {
+			px.agent.log.Info("ingress backend back in rotation", "backend", addr)
+		}
+		h.fails, h.down = 0, false
+		return
+	}
+	h.fails++
+	if h.fails >= probeFailures && !h.down {
+		h.down = true
+		px.agent.log.Warn("ingress backend out of rotation", "backend", addr, "err", err)
+	}
+}
+
+// ensureStarted brings up the listeners the current state needs. The
+// HTTPS listener starts on the first pass that has a certificate and
+// then stays up; failures are retried next pass.
+func (px *ingressProxy) ensureStarted(wantTLS bool) error {
+	if !px.started {
+		srv := &http.Server{Addr: ":80", Handler: px}
+		go func() {
+			if err := srv.ListenAndServe(); err != nil {
+				px.agent.log.Error("ingress proxy failed", "err", err)
+			}
+		}()
+		px.started = true
+		px.agent.log.Info("ingress proxy listening", "port", 80)
+	}
+	if wantTLS && !px.tlsUp {
+		ln, err := tls.Listen("tcp", ":443", &tls.Config{
+			GetCertificate:     px.certificate,
+			GetConfigForClient: px.configForClient,
+		})
+		if err != nil {
+			return fmt.Errorf("listening on 443: %w", err)
+		}
+		px.tlsConns = make(chan net.Conn)
+		srv := &http.Server{Handler: px}
+		go srv.Serve(&chanListener{conns: px.tlsConns, addr: ln.Addr()})
+		go px.acceptTLS(ln)
+		px.tlsUp = true
+		px.agent.log.Info("ingress proxy listening", "port", 443)
+	}
+	return nil
+}
+
+// acceptTLS splits incoming TLS connections by their handshake: hosts
+// marked for stream relay are piped straight to a backend, everything
+// else goes to the HTTP server.
+func (px *ingressProxy) acceptTLS(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			tc := conn.(*tls.Conn)
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			err := tc.HandshakeContext(ctx)
+			cancel()
+			if err != nil {
+				conn.Close()
+				return
+			}
+			if backends := px.streamBackends(tc.ConnectionState().ServerName); backends != nil {
+				px.relay(tc, backends)
+				return
+			}
+			px.tlsConns <- conn
+		}()
+	}
+}
+
+// configForClient advertises HTTP/2 only on stream-relayed hosts, where
+// the client's frames pass through untouched; everything the HTTP
+// server handles itself stays on HTTP/1.1.
+func (px *ingressProxy) configForClient(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+	if px.streamBackends(hello.ServerName) != nil {
+		return &tls.Config{
+			GetCertificate: px.certificate,
+			NextProtos:     []string{"h2", "http/1.1"},
+		}, nil
+	}
+	return nil, nil
+}
+
+func (px *ingressProxy) streamBackends(host string) []string {
+	px.mu.Lock()
+	defer px.mu.Unlock()
+	return px.streams[host]
+}
+
+// relay pipes one decrypted connection to a backend, which carries
+// HTTP/2 and gRPC end to end without this proxy re-framing them.
+func (px *ingressProxy) relay(conn *tls.Conn, backends []string) {
+	defer conn.Close()
+	px.mu.Lock()
+	var candidates []string
+	for _, b := range backends {
+		if h := px.health[b]; h == nil || !h.down {
+			candidates = append(candidates, b)
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = backends
+	}
+	backend := candidates[px.next%len(candidates)]
+	px.next++
+	px.mu.Unlock()
+	upstream, err := net.DialTimeout("tcp", backend, 10*time.Second)
+	if err != nil {
+		px.agent.log.Warn("dialing ingress backend failed", "backend", backend, "err", err)
+		return
+	}
+	defer upstream.Close()
+	done := make(chan struct{})
+	go func() {
+		io.Copy(upstream, conn)
+		upstream.(*net.TCPConn).CloseWrite()
+		done <- struct{}{}
+	}()
+	io.Copy(conn, upstream)
+	<-done
+}
+
+// chanListener adapts the handshaken-connection channel to the
+// net.Listener the HTTP server consumes.
+type chanListener struct {
+	conns chan net.Conn
+	addr  net.Addr
+}
+
+func (l *chanListener) Accept() (net.Conn, error) {
+	conn, ok := <-l.conns
+	if !ok {
+		return nil, net.ErrClosed
+	}
+	return conn, nil
+}
+
+func (l *chanListener) Close() error   { return nil }
+func (l *chanListener) Addr() net.Addr { return l.addr }
+
+// certificate picks the certificate for the handshake's server name,
+// falling back to any certificate when SNI is absent.
+func (px *ingressProxy) certificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	px.mu.Lock()
+	defer px.mu.Unlock()
+	if cert, ok := px.certs[hello.ServerName]; ok {
+		return cert, nil
+	}
+	for _, cert := range px.certs {
+		return cert, nil
+	}
+	return nil, fmt.Errorf("no certificate for %q", hello.ServerName)
+}
+
+func (px *ingressProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, acmeChallengePrefix) {
+		px.serveChallenge(w, r)
+		return
+	}
+	route := px.route(r)
+	if route == nil {
+		http.Error(w, "no route for this host and path", http.StatusNotFound)
+		return
+	}
+	if route.timeout > 0 {
+		ctx, cancel := context.WithTimeout(r.Context(), route.timeout)
+		defer cancel()
+		r = r.WithContext(ctx)
+	}
+	// Retrying is safe only when there is no body to replay.
+	attempts := 1
+	if route.retries > 0 && (r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0) {
+		attempts = route.retries + 1
+	}
+	for attempt := 0; attempt < attempts; attempt++ {
+		backend := px.pick(route)
+		if backend == "" {
+			http.Error(w, "no backends available", http.StatusServiceUnavailable)
+			return
+		}
+		// Nothing has been written yet when the round trip itself
+		// fails, so the next attempt starts clean.
+		var failed error
+		proxy := httputil.NewSingleHostReverseProxy(&url.URL{Scheme: "http", Host: backend})
+		proxy.ErrorHandler = func(_ http.ResponseWriter, _ *http.Request, err error) { failed = err }
+		proxy.ServeHTTP(w, r)
+		if failed == nil {
+			return
+		}
+		px.agent.log.Warn("proxying ingress request failed",
+			"backend", backend, "attempt", attempt+1, "err", failed)
+		if r.Context().Err() != nil {
+			break // timed out; more attempts cannot help
+		}
+	}
+	http.Error(w, "backend unavailable", http.StatusBadGateway)
+}
+
+// serveChallenge relays an HTTP-01 validation to the server, which
+// holds the pending key authorizations for the whole cluster.
+func (px *ingressProxy) serveChallenge(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimPrefix(r.URL.Path, acmeChallengePrefix)
+	resp, err := http.Get(px.agent.Server + "/api/v1/acme-challenges/" + url.PathEscape(token))
+	if err != nil {
+		http.Error(w, "challenge lookup failed", http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		http.Error(w, "unknown challenge", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	io.Copy(w, resp.Body)
+}
+
+// route matches the request to the most specific route under its host:
+// path condition first, then any required headers.
+func (px *ingressProxy) route(r *http.Request) *pathRoute {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	px.mu.Lock()
+	defer px.mu.Unlock()
+	paths, ok := px.routes[host]
+	if !ok {
+		paths = px.routes[""]
+	}
+	for i := range paths {
+		route := &paths[i]
+		if route.exact {
+			if r.URL.Path != route.prefix {
+				continue
+			}
+		} else if !matchesPrefix(r.URL.Path, route.prefix) {
+			continue
+		}
+		if !headersMatch(r, route.headers) {
+			continue
+		}
+		return route
+	}
+	return nil
+}
+
+func headersMatch(r *http.Request, headers []api.HTTPHeaderMatch) bool {
+	for _, h := range headers {
+		if r.Header.Get(h.Name) != h.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// pick chooses a backend: a weighted draw across the route's groups,
+// then round-robin over the group's in-rotation addresses, falling
+// back to the full set when probing has every backend down — a wrong
+// probe config should degrade service, not end it.
+func (px *ingressProxy) pick(route *pathRoute) string {
+	px.mu.Lock()
+	defer px.mu.Unlock()
+	total := 0
+	for _, g := range route.groups {
+		if len(g.addrs) > 0 {
+			total += g.weight
+		}
+	}
+	if total == 0 {
+		return ""
+	}
+	n := rand.Intn(total)
+	var chosen backendGroup
+	for _, g := range route.groups {
+		if len(g.addrs) == 0 {
+			continue
+		}
+		if n < g.weight {
+			chosen = g
+			break
+		}
+		n -= g.weight
+	}
+	var candidates []string
+	for _, b := range chosen.addrs {
+		if h := px.health[b]; h == nil || !h.down {
+			candidates = append(candidates, b)
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = chosen.addrs
+	}
+	backend := candidates[px.next%len(candidates)]
+	px.next++
+	return backend
+}
+
+// matchesPrefix reports whether path falls under prefix on path
+// segment boundaries, so /app does not capture /apparel.
+func matchesPrefix(path, prefix string) bool {
+	if prefix == "/" {
+		return true
+	}
+	prefix = strings.TrimSuffix(prefix, "/")
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
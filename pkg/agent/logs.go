@@ -0,0 +1,83 @@
+package agent
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/runtime"
+)
+
+// runLogServer serves container logs on the node port the API server
+// proxies pod log requests to. Nothing listens when the runtime cannot
+// stream logs.
+func (a *Agent) runLogServer() {
+	if _, ok := a.Runtime.(runtime.LogStreamer); !ok {
+		return
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /logs/{namespace}/{pod}/{container}", a.handleLogs)
+	addr := fmt.Sprintf(":%d", api.AgentLogPort)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		a.log.Error("log server failed", "err", err)
+	}
+}
+
+func (a *Agent) handleLogs(w http.ResponseWriter, r *http.Request) {
+	streamer := a.Runtime.(runtime.LogStreamer)
+	namespace, pod, name := r.PathValue("namespace"), r.PathValue("pod"), r.PathValue("container")
+	var id string
+	for _, ctr := range a.cache.PodContainers(namespace, pod) {
+		if ctr.Name == name {
+			id = ctr.ID
+			break
+		}
+	}
+	if id == "" {
+		http.Error(w, fmt.Sprintf("no container %q in pod %s/%s on this node", name, namespace, pod),
+			http.StatusNotFound)
+		return
+	}
+	follow := r.URL.Query().Get("follow") == "true"
+	tail := -1
+	if v := r.URL.Query().Get("tail"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "tail must be an integer", http.StatusBadRequest)
+			return
+		}
+		tail = n
+	}
+	stream, err := streamer.Logs(r.Context(), id, follow, tail)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer stream.Close()
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	flushCopy(w, stream)
+}
+
+// flushCopy copies stream output to the response, flushing after every
+// chunk so followed logs arrive as they are written rather than when a
+// buffer fills.
+func flushCopy(w http.ResponseWriter, r io.Reader) {
+	flusher, _ := w.(http.Flusher)
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
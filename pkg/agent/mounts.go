@@ -0,0 +1,138 @@
+package agent
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/runtime"
+)
+
+// ensureNetworkMounts mounts the pod's NFS and CIFS volumes on the
+// host before its containers bind-mount them. Already-mounted volumes
+// are left alone, so a sync pass costs one /proc read per volume.
+func (a *Agent) ensureNetworkMounts(pod *api.Pod) error {
+	for _, vol := range pod.Spec.Volumes {
+		if vol.NFS == nil && vol.CIFS == nil {
+			continue
+		}
+		dir := runtime.NetworkMountDir(pod.Meta.Namespace, pod.Meta.Name, vol.Name)
+		mounted, err := isMountPoint(dir)
+		if err != nil {
+			return fmt.Errorf("volume %q: %w", vol.Name, err)
+		}
+		if mounted {
+			continue
+		}
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("volume %q: %w", vol.Name, err)
+		}
+		if err := a.mountVolume(pod, vol, dir); err != nil {
+			return fmt.Errorf("volume %q: %w", vol.Name, err)
+		}
+		a.log.Info("mounted network volume",
+			"namespace", pod.Meta.Namespace, "pod", pod.Meta.Name, "volume", vol.Name)
+	}
+	return nil
+}
+
+func (a *Agent) mountVolume(pod *api.Pod, vol api.Volume, dir string) error {
+	var fstype, source string
+	var opts []string
+	switch {
+	case vol.NFS != nil:
+		fstype = "nfs"
+		source = vol.NFS.Server + ":" + vol.NFS.Path
+		opts = vol.NFS.MountOptions
+	case vol.CIFS != nil:
+		fstype = "cifs"
+		source = vol.CIFS.Source
+		cred, err := a.cifsCredentials(pod, vol, dir)
+		if err != nil {
+			return err
+		}
+		opts = append([]string{cred}, vol.CIFS.MountOptions...)
+	}
+	args := []string{"-t", fstype}
+	if len(opts) > 0 {
+		args = append(args, "-o", strings.Join(opts, ","))
+	}
+	args = append(args, source, dir)
+	if out, err := exec.Command("mount", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("mounting %s: %v: %s", source, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// cifsCredentials resolves the share's credentials option. Named
+// secrets become a root-only credentials file next to the mount point,
+// keeping the password out of the mount command line.
+func (a *Agent) cifsCredentials(pod *api.Pod, vol api.Volume, dir string) (string, error) {
+	if vol.CIFS.CredentialsSecret == "" {
+		return "guest", nil
+	}
+	namespace := pod.Meta.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+	var sec api.Secret
+	path := "/api/v1/namespaces/" + url.PathEscape(namespace) +
+		"/secrets/" + url.PathEscape(vol.CIFS.CredentialsSecret)
+	if err := a.get(path, &sec); err != nil {
+		return "", err
+	}
+	file := dir + ".creds"
+	content := fmt.Sprintf("username=%s\npassword=%s\n", sec.Data["username"], sec.Data["password"])
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		return "", err
+	}
+	return "credentials=" + file, nil
+}
+
+// removeNetworkMounts unmounts and deletes a removed pod's network
+// volumes, and any leftover credentials files with them.
+func (a *Agent) removeNetworkMounts(namespace, name string) {
+	dirs, err := filepath.Glob(runtime.NetworkMountDir(namespace, name, "*"))
+	if err != nil {
+		return
+	}
+	for _, dir := range dirs {
+		if strings.HasSuffix(dir, ".creds") {
+			continue
+		}
+		if mounted, err := isMountPoint(dir); err == nil && mounted {
+			if out, err := exec.Command("umount", dir).CombinedOutput(); err != nil {
+				a.log.Warn("unmounting network volume failed",
+					"dir", dir, "err", err, "output", strings.TrimSpace(string(out)))
+				continue
+			}
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			a.log.Warn("removing network volume mount point failed", "dir", dir, "err", err)
+		}
+		os.Remove(dir + ".creds")
+	}
+}
+
+// isMountPoint reports whether dir currently has something mounted on
+// it, per /proc/self/mounts.
+func isMountPoint(dir string) (bool, error) {
+	f, err := os.Open("/proc/self/mounts")
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) >= 2 && fields[1] == dir {
+			return true, nil
+		}
+	}
+	return false, sc.Err()
+}
@@ -0,0 +1,501 @@
+package agent
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/runtime"
+	"github.com/ptfpinho23/synthesis/pkg/scheduler"
+)
+
+// runPodSync drives the node's containers toward the pods bound here,
+// on the interval ticker and immediately after a relevant runtime
+// event.
+func (a *Agent) runPodSync() {
+	ticker := time.NewTicker(a.SyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-a.syncKick:
+		}
+		if err := a.syncPods(); err != nil {
+			a.log.Error("syncing pods failed", "err", err)
+		}
+	}
+}
+
+// onContainerEvent reacts to the runtime's event stream: a container
+// starting or dying changes pod status now, not at the next tick.
+// Kicks coalesce, so an event burst costs one extra pass.
+func (a *Agent) onContainerEvent(ev runtime.ContainerEvent) {
+	switch ev.Action {
+	case "start", "die", "oom":
+		select {
+		case a.syncKick <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (a *Agent) syncPods() error {
+	static := a.loadStaticPods()
+	mine := make(map[string]*api.Pod, len(static))
+	for key, pod := range static {
+		mine[key] = pod
+	}
+	var pods []*api.Pod
+	serverOK := true
+	orphaned := false
+	if err := a.get("/api/v1/pods", &pods); err != nil {
+		serverOK = false
+		switch {
+		case !a.lastContact.IsZero() && a.OrphanAfter > 0 && time.Since(a.lastContact) > a.OrphanAfter:
+			// Past the orphan deadline the cached pods come down; only
+			// static pods outlive a control-plane outage this long.
+			if len(a.lastDesired) > 0 {
+				a.log.Warn("server unreachable past the orphan deadline, tearing down cached pods",
+					"offline", time.Since(a.lastContact), "orphanAfter", a.OrphanAfter)
+			}
+			a.lastDesired = nil
+			orphaned = true
+		case len(a.lastDesired) > 0:
+			// Offline: keep running the last state the server handed us
+			// rather than dumping workloads on a flaky uplink.
+			a.log.Warn("listing pods failed, running from cached state",
+				"err", err, "offline", time.Since(a.lastContact))
+			for key, pod := range a.lastDesired {
+				if _, ok := mine[key]; !ok {
+					mine[key] = pod
+				}
+			}
+		case len(static) == 0:
+			return err
+		default:
+			a.log.Warn("listing pods failed, syncing static pods only", "err", err)
+		}
+	} else {
+		a.lastContact = time.Now()
+		a.flushPendingStatus()
+	}
+	cached := make(map[string]*api.Pod)
+	for _, pod := range pods {
+		if nodeFor(pod) != a.NodeName {
+			continue
+		}
+		key := pod.Meta.Namespace + "/" + pod.Meta.Name
+		if local, ok := static[key]; ok {
+			// The file is authoritative over its API mirror; carry the
+			// mirror's status over so unchanged pods stay quiet.
+			local.Status = pod.Status
+			local.Status.NodeName = a.NodeName
+			a.ensureMirror(local, pod)
+			delete(static, key)
+			continue
+		}
+		mine[key] = pod
+		cached[key] = pod
+	}
+	if serverOK {
+		a.lastDesired = cached
+		// Static pods the list did not return have no mirror yet.
+		for _, pod := range static {
+			a.ensureMirror(pod, nil)
+		}
+	}
+
+	// Tear down containers whose pod is gone or moved elsewhere. With
+	// the server unreachable the desired set is unknown, so nothing is
+	// torn down until it answers again — unless the orphan deadline
+	// passed, at which point the cached pods come down too.
+	containers := a.cache.Containers()
+	changed := false
+	removed := make(map[string]bool)
+	for _, c := range containers {
+		key := c.PodNamespace + "/" + c.PodName
+		if _, ok := mine[key]; ok || removed[key] || (!serverOK && !orphaned) {
+			continue
+		}
+		removed[key] = true
+		changed = true
+		if err := a.Runtime.RemovePod(c.PodNamespace, c.PodName); err != nil {
+			a.log.Error("removing pod failed", "pod", key, "err", err)
+		}
+		a.removeProjectedVolumes(c.PodNamespace, c.PodName)
+		a.removeNetworkMounts(c.PodNamespace, c.PodName)
+		a.restarts.forget(c.PodNamespace, c.PodName)
+	}
+
+	a.assessRestore(mine)
+	started := 0
+	for _, key := range a.restoreOrder(mine) {
+		pod := mine[key]
+		// Pods stamped with an eviction grace period were handed to
+		// this node for graceful teardown; nothing below applies.
+		if pod.Meta.Annotations[api.EvictionGracePeriodAnnotation] != "" {
+			a.startEviction(pod)
+			continue
+		}
+		// Terminal pods keep their containers around for inspection but
+		// are never resynced or resurrected.
+		if pod.Status.Phase == api.PodSucceeded || pod.Status.Phase == api.PodFailed {
+			continue
+		}
+		existing := a.cache.PodContainers(pod.Meta.Namespace, pod.Meta.Name)
+		// While restoring after a restart, only StartupBurst cold pods
+		// may start per pass, spreading image mounts and IO out instead
+		// of thundering through them on a small device.
+		if a.restore != nil && len(existing) == 0 && a.StartupBurst > 0 && started >= a.StartupBurst {
+			continue
+		}
+		if a.restartDue(pod, existing) {
+			a.log.Info("restarting pod containers", "pod", key)
+			if err := a.Runtime.RemovePod(pod.Meta.Namespace, pod.Meta.Name); err != nil {
+				a.log.Error("removing pod for restart failed", "pod", key, "err", err)
+				continue
+			}
+			a.recordPodEvent(pod, api.EventNormal, "Restarted", "recreated containers on restart request")
+			existing = nil
+			changed = true
+		}
+		if err := a.ensureProjectedVolumes(pod); err != nil {
+			a.log.Error("projecting volumes failed", "pod", key, "err", err)
+			continue
+		}
+		if err := a.ensureNetworkMounts(pod); err != nil {
+			a.log.Error("mounting network volumes failed", "pod", key, "err", err)
+			continue
+		}
+		if ready, msg := a.ensureImages(pod, existing); !ready {
+			// Surface pull progress (or the final failure) to the user
+			// and come back next pass; failed pulls start over then.
+			if pod.Status.Message != msg {
+				pod.Status.Message = msg
+				pod.Status.NodeName = a.NodeName
+				if !serverOK {
+					a.pendingStatus[key] = pod
+					continue
+				}
+				if err := a.put(podPath(pod), pod); err != nil {
+					a.log.Error("updating pod status failed", "pod", key, "err", err)
+				}
+			}
+			continue
+		}
+		if len(existing) < len(pod.Spec.InitContainers)+len(pod.Spec.Containers)+len(pod.Spec.EphemeralContainers) {
+			changed = true
+		}
+		if err := a.Runtime.SyncPod(pod, existing); err != nil {
+			a.log.Error("syncing pod failed", "pod", key, "err", err)
+			continue
+		}
+		if len(existing) == 0 {
+			started++
+			if a.restore != nil {
+				a.restore.done++
+			}
+		}
+		// Report the phase the containers imply; sync failures above
+		// leave the previous phase in place for the next pass.
+		phase, qos := podPhase(pod, existing), api.QoSOf(pod)
+		statuses := a.restarts.observe(pod, existing)
+		if pod.Status.Phase != phase || pod.Status.QOSClass != qos ||
+			!reflect.DeepEqual(pod.Status.ContainerStatuses, statuses) {
+			pod.Status.Phase = phase
+			pod.Status.QOSClass = qos
+			pod.Status.NodeName = a.NodeName
+			pod.Status.Message = ""
+			pod.Status.ContainerStatuses = statuses
+			if !serverOK {
+				a.pendingStatus[key] = pod
+				continue
+			}
+			if err := a.put(podPath(pod), pod); err != nil {
+				a.log.Error("updating pod status failed", "pod", key, "err", err)
+			}
+		}
+	}
+	if a.restore != nil {
+		if a.restore.done >= a.restore.total {
+			a.recordNodeEvent(api.EventNormal, "NodeRestarted",
+				fmt.Sprintf("restored all %d pods after restart", a.restore.total))
+			a.restore = nil
+		} else {
+			a.log.Info("restoring pods after restart",
+				"done", a.restore.done, "total", a.restore.total)
+		}
+	}
+	if changed {
+		a.cache.MarkDirty()
+	}
+	return nil
+}
+
+// restoreState tracks a post-restart restoration: how many previously
+// running pods were found without containers and how many have been
+// started again.
+type restoreState struct {
+	total, done int
+}
+
+// assessRestore runs once, on the first pass with a desired set: pods
+// bound here that have no containers were running before the node (or
+// agent) restarted, and restoring more than one is staged in priority
+// order under the StartupBurst cap.
+func (a *Agent) assessRestore(mine map[string]*api.Pod) {
+	if a.restoreAssessed {
+		return
+	}
+	a.restoreAssessed = true
+	cold := 0
+	for _, pod := range mine {
+		if pod.Status.Phase == api.PodSucceeded || pod.Status.Phase == api.PodFailed {
+			continue
+		}
+		if len(a.cache.PodContainers(pod.Meta.Namespace, pod.Meta.Name)) == 0 {
+			cold++
+		}
+	}
+	if cold <= 1 {
+		return
+	}
+	a.restore = &restoreState{total: cold}
+	a.recordNodeEvent(api.EventNormal, "NodeRestarted",
+		fmt.Sprintf("restoring %d pods in priority order", cold))
+}
+
+// restoreOrder is the iteration order for the sync loop: arbitrary in
+// steady state, highest priority first while restoring so the critical
+// pods come back before the burst cap delays the rest.
+func (a *Agent) restoreOrder(mine map[string]*api.Pod) []string {
+	keys := make([]string, 0, len(mine))
+	for key := range mine {
+		keys = append(keys, key)
+	}
+	if a.restore == nil {
+		return keys
+	}
+	var classes []*api.PriorityClass
+	if err := a.get("/api/v1/priorityclasses", &classes); err != nil {
+		a.log.Warn("listing priority classes failed, restoring unordered", "err", err)
+		return keys
+	}
+	prios := scheduler.NewPriorities(classes)
+	sort.SliceStable(keys, func(i, j int) bool {
+		return prios.Of(mine[keys[i]]) > prios.Of(mine[keys[j]])
+	})
+	return keys
+}
+
+// flushPendingStatus delivers the status updates queued while the
+// server was unreachable. Pods deleted in the meantime fail their put
+// and are dropped; the pass that follows reconciles everything else.
+func (a *Agent) flushPendingStatus() {
+	for key, pod := range a.pendingStatus {
+		if err := a.put(podPath(pod), pod); err != nil {
+			a.log.Warn("delivering queued status update failed", "pod", key, "err", err)
+		}
+		delete(a.pendingStatus, key)
+	}
+}
+
+// startEviction kicks off the graceful teardown of an evicted pod in
+// the background, once; the stop may wait out the whole grace period
+// and must not stall the sync loop.
+func (a *Agent) startEviction(pod *api.Pod) {
+	key := pod.Meta.Namespace + "/" + pod.Meta.Name
+	a.evictMu.Lock()
+	if a.evicting == nil {
+		a.evicting = make(map[string]bool)
+	}
+	if a.evicting[key] {
+		a.evictMu.Unlock()
+		return
+	}
+	a.evicting[key] = true
+	a.evictMu.Unlock()
+	go func() {
+		defer func() {
+			a.evictMu.Lock()
+			delete(a.evicting, key)
+			a.evictMu.Unlock()
+		}()
+		a.finishEviction(pod)
+	}()
+}
+
+// finishEviction is the node's half of a drain: stop the containers
+// within the granted grace period, release the pod's volumes, then
+// delete the pod through the API so the eviction converges.
+func (a *Agent) finishEviction(pod *api.Pod) {
+	key := pod.Meta.Namespace + "/" + pod.Meta.Name
+	raw := pod.Meta.Annotations[api.EvictionGracePeriodAnnotation]
+	grace, err := time.ParseDuration(raw)
+	if err != nil {
+		a.log.Warn("malformed eviction grace period, stopping immediately", "pod", key, "value", raw)
+		grace = 0
+	}
+	if stopper, ok := a.Runtime.(runtime.PodStopper); ok && grace > 0 {
+		if err := stopper.StopPod(pod.Meta.Namespace, pod.Meta.Name, grace); err != nil {
+			a.log.Error("stopping evicted pod failed", "pod", key, "err", err)
+		}
+	}
+	if err := a.Runtime.RemovePod(pod.Meta.Namespace, pod.Meta.Name); err != nil {
+		a.log.Error("removing evicted pod failed", "pod", key, "err", err)
+		return
+	}
+	a.removeProjectedVolumes(pod.Meta.Namespace, pod.Meta.Name)
+	a.removeNetworkMounts(pod.Meta.Namespace, pod.Meta.Name)
+	a.restarts.forget(pod.Meta.Namespace, pod.Meta.Name)
+	a.cache.MarkDirty()
+	path := "/api/v1/namespaces/" + url.PathEscape(pod.Meta.Namespace) +
+		"/pods/" + url.PathEscape(pod.Meta.Name)
+	if err := a.send(http.MethodDelete, path, nil); err != nil {
+		a.log.Error("deleting evicted pod failed", "pod", key, "err", err)
+	}
+}
+
+// ensureImages kicks off pulls for the images of containers that do
+// not exist yet and reports whether they have all landed, along with a
+// progress message for the pod status while they have not.
+func (a *Agent) ensureImages(pod *api.Pod, existing []runtime.Container) (bool, string) {
+	if a.pulls == nil {
+		return true, ""
+	}
+	have := make(map[string]bool, len(existing))
+	for _, c := range existing {
+		have[c.Name] = true
+	}
+	specs := append(append(append([]api.Container(nil),
+		pod.Spec.InitContainers...), pod.Spec.Containers...), pod.Spec.EphemeralContainers...)
+	for _, spec := range specs {
+		if have[spec.Name] {
+			continue
+		}
+		if spec.ImagePullPolicy == api.PullNever {
+			continue
+		}
+		if spec.ImagePullPolicy == api.PullIfNotPresent && a.imagePresent(spec.Image) {
+			continue
+		}
+		done, err := a.pulls.TryPull(spec.Image)
+		if err != nil {
+			a.log.Error("pulling image failed", "image", spec.Image, "err", err)
+			return false, fmt.Sprintf("Failed to pull image %q: %v", spec.Image, err)
+		}
+		if !done {
+			return false, a.pulls.Status(spec.Image)
+		}
+	}
+	return true, ""
+}
+
+// imagePresent reports whether the node's engine already holds the
+// image, so IfNotPresent containers can start without registry access.
+func (a *Agent) imagePresent(ref string) bool {
+	mgr, ok := a.Runtime.(runtime.ImageManager)
+	if !ok {
+		return false
+	}
+	if !strings.Contains(ref[strings.LastIndex(ref, "/")+1:], ":") {
+		ref += ":latest"
+	}
+	images, err := mgr.ListImages()
+	if err != nil {
+		return false
+	}
+	for _, img := range images {
+		if img.Ref == ref || img.ID == ref {
+			return true
+		}
+	}
+	return false
+}
+
+// restartDue reports whether the pod carries a restart stamp newer
+// than one of its containers, meaning the restart verb ran after that
+// container started. Containers without a reported start time never
+// qualify, so a lying engine cannot cause a restart loop.
+func (a *Agent) restartDue(pod *api.Pod, existing []runtime.Container) bool {
+	stamp, err := time.Parse(time.RFC3339Nano, pod.Meta.Annotations[api.RestartedAtAnnotation])
+	if err != nil {
+		return false
+	}
+	for _, c := range existing {
+		if !c.StartedAt.IsZero() && c.StartedAt.Before(stamp) {
+			return true
+		}
+	}
+	return false
+}
+
+// podPhase derives the phase from the containers and restart policy:
+// Always pods stay Running, one-shot pods reach Succeeded once every
+// container has exited cleanly and Failed when a Never pod's container
+// exits non-zero. OnFailure pods stay Running while failed containers
+// are being restarted.
+func podPhase(pod *api.Pod, existing []runtime.Container) api.PodPhase {
+	policy := pod.Spec.RestartPolicy
+	if policy == "" {
+		policy = api.RestartAlways
+	}
+	byName := make(map[string]runtime.Container, len(existing))
+	for _, c := range existing {
+		byName[c.Name] = c
+	}
+	// A one-shot init container that failed for good fails the pod.
+	if policy == api.RestartNever {
+		for _, spec := range pod.Spec.InitContainers {
+			if spec.Sidecar() {
+				continue
+			}
+			if c, ok := byName[spec.Name]; ok && !c.Running && c.ExitCode != 0 {
+				return api.PodFailed
+			}
+		}
+	}
+	if policy == api.RestartAlways {
+		return api.PodRunning
+	}
+	failed := false
+	for _, spec := range pod.Spec.Containers {
+		c, ok := byName[spec.Name]
+		if !ok || c.Running {
+			return api.PodRunning
+		}
+		if c.ExitCode != 0 {
+			failed = true
+		}
+	}
+	switch {
+	case !failed:
+		return api.PodSucceeded
+	case policy == api.RestartOnFailure:
+		return api.PodRunning
+	default:
+		return api.PodFailed
+	}
+}
+
+// nodeFor is the node a pod is bound to: the scheduler's binding, or
+// the spec pin for pods that bypassed scheduling.
+func nodeFor(pod *api.Pod) string {
+	if pod.Status.NodeName != "" {
+		return pod.Status.NodeName
+	}
+	return pod.Spec.NodeName
+}
+
+func podPath(pod *api.Pod) string {
+	namespace := pod.Meta.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+	return "/api/v1/namespaces/" + url.PathEscape(namespace) + "/pods/" + url.PathEscape(pod.Meta.Name)
+}
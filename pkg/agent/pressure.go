@@ -0,0 +1,189 @@
+package agent
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/runtime"
+)
+
+// runPressure watches node memory and disk usage so pods are evicted
+// in QoS order before the kernel starts OOM-killing at random. The
+// same cadence enforces per-pod ephemeral-storage limits when the
+// runtime can report writable-layer usage.
+func (a *Agent) runPressure() {
+	reporter, _ := a.Runtime.(runtime.DiskUsageReporter)
+	for range time.Tick(a.PressureInterval) {
+		if err := a.checkPressure(); err != nil {
+			a.log.Error("pressure check failed", "err", err)
+		}
+		if reporter == nil {
+			continue
+		}
+		if err := a.checkEphemeralStorage(reporter); err != nil {
+			a.log.Error("ephemeral storage check failed", "err", err)
+		}
+	}
+}
+
+// checkEphemeralStorage evicts pods whose containers have written more
+// than their summed ephemeral-storage limits allow, so one runaway log
+// writer cannot fill the node before disk pressure kicks in.
+func (a *Agent) checkEphemeralStorage(reporter runtime.DiskUsageReporter) error {
+	var pods []*api.Pod
+	if err := a.get("/api/v1/pods", &pods); err != nil {
+		return err
+	}
+	for _, pod := range pods {
+		if nodeFor(pod) != a.NodeName ||
+			pod.Status.Phase == api.PodSucceeded || pod.Status.Phase == api.PodFailed {
+			continue
+		}
+		var limit int64
+		for _, c := range pod.Spec.Containers {
+			limit += c.Resources.Limits[api.ResourceEphemeralStorage]
+		}
+		if limit == 0 {
+			continue
+		}
+		containers := a.cache.PodContainers(pod.Meta.Namespace, pod.Meta.Name)
+		ids := make([]string, 0, len(containers))
+		for _, c := range containers {
+			ids = append(ids, c.ID)
+		}
+		usage, err := reporter.ContainerDiskUsage(ids)
+		if err != nil {
+			return err
+		}
+		var used int64
+		for _, n := range usage {
+			used += n
+		}
+		if used <= limit {
+			continue
+		}
+		a.log.Info("evicting pod over ephemeral-storage limit",
+			"namespace", pod.Meta.Namespace, "pod", pod.Meta.Name,
+			"used", used, "limit", limit)
+		pod.Status.Phase = api.PodFailed
+		pod.Status.Message = fmt.Sprintf(
+			"evicted: ephemeral-storage usage %d exceeds the %d limit", used, limit)
+		if err := a.put(podPath(pod), pod); err != nil {
+			return err
+		}
+		if err := a.Runtime.RemovePod(pod.Meta.Namespace, pod.Meta.Name); err != nil {
+			return err
+		}
+		a.cache.MarkDirty()
+	}
+	return nil
+}
+
+func (a *Agent) checkPressure() error {
+	memFrac, diskFrac, err := pressureUsage()
+	if err != nil {
+		return err
+	}
+	memPressure := memFrac >= a.MemoryPressureThreshold
+	diskPressure := diskFrac >= a.DiskPressureThreshold
+	if err := a.setPressureConditions(memPressure, diskPressure); err != nil {
+		return err
+	}
+	switch {
+	case memPressure:
+		return a.evictForPressure("memory", memFrac)
+	case diskPressure:
+		return a.evictForPressure("disk", diskFrac)
+	}
+	return nil
+}
+
+// setPressureConditions records the MemoryPressure and DiskPressure
+// conditions on the node, writing only on transitions.
+func (a *Agent) setPressureConditions(mem, disk bool) error {
+	var node api.Node
+	if err := a.get("/api/v1/nodes/"+url.PathEscape(a.NodeName), &node); err != nil {
+		return err
+	}
+	changed := false
+	now := time.Now()
+	for _, want := range []struct {
+		t api.NodeConditionType
+		v bool
+	}{
+		{api.NodeMemoryPressure, mem},
+		{api.NodeDiskPressure, disk},
+	} {
+		cond := node.Status.Condition(want.t)
+		created := cond == nil
+		if created {
+			node.Status.Conditions = append(node.Status.Conditions, api.NodeCondition{Type: want.t})
+			cond = &node.Status.Conditions[len(node.Status.Conditions)-1]
+		}
+		if !created && cond.Status == want.v {
+			continue
+		}
+		cond.Status = want.v
+		cond.LastTransitionTime = now
+		if want.v {
+			cond.Reason = "EvictionThresholdMet"
+		} else {
+			cond.Reason = "BelowThreshold"
+		}
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return a.put("/api/v1/nodes/"+url.PathEscape(a.NodeName), &node)
+}
+
+// evictForPressure removes one pod per pass, worst QoS class first:
+// BestEffort, then Burstable. Guaranteed pods are never
+// pressure-evicted.
+func (a *Agent) evictForPressure(resource string, frac float64) error {
+	var pods []*api.Pod
+	if err := a.get("/api/v1/pods", &pods); err != nil {
+		return err
+	}
+	var victim *api.Pod
+	victimRank := 2
+	for _, pod := range pods {
+		if nodeFor(pod) != a.NodeName ||
+			pod.Status.Phase == api.PodSucceeded || pod.Status.Phase == api.PodFailed {
+			continue
+		}
+		var rank int
+		switch api.QoSOf(pod) {
+		case api.QoSBestEffort:
+			rank = 0
+		case api.QoSBurstable:
+			rank = 1
+		default:
+			continue
+		}
+		if rank < victimRank {
+			victim, victimRank = pod, rank
+		}
+	}
+	if victim == nil {
+		a.log.Warn("node under pressure but no evictable pods",
+			"resource", resource, "used", fmt.Sprintf("%.0f%%", frac*100))
+		return nil
+	}
+	a.log.Info("evicting pod for node pressure",
+		"namespace", victim.Meta.Namespace, "pod", victim.Meta.Name,
+		"qos", api.QoSOf(victim), "resource", resource, "used", fmt.Sprintf("%.0f%%", frac*100))
+	victim.Status.Phase = api.PodFailed
+	victim.Status.Message = fmt.Sprintf("evicted: node under %s pressure", resource)
+	if err := a.put(podPath(victim), victim); err != nil {
+		return err
+	}
+	if err := a.Runtime.RemovePod(victim.Meta.Namespace, victim.Meta.Name); err != nil {
+		return err
+	}
+	a.cache.MarkDirty()
+	return nil
+}
@@ -0,0 +1,246 @@
+package agent
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// defaultAffinityTimeout pins a client to its backend for this long
+// after its last connection when the service does not say otherwise.
+const defaultAffinityTimeout = 3 * time.Hour
+
+// runServiceProxy keeps one TCP listener per service node port on this
+// node, forwarding connections to the service's current endpoints.
+// Every sync interval the desired listener set is rebuilt from the
+// API: new node ports get listeners, removed ones are closed, and
+// surviving proxies swap in fresh endpoints and tuning without
+// dropping established connections.
+func (a *Agent) runServiceProxy() {
+	proxies := make(map[string]*portProxy)
+	ticker := time.NewTicker(a.SyncInterval)
+	defer ticker.Stop()
+	for ; ; <-ticker.C {
+		if err := a.syncServiceProxies(proxies); err != nil {
+			a.log.Error("syncing service proxies failed", "err", err)
+		}
+	}
+}
+
+func (a *Agent) syncServiceProxies(proxies map[string]*portProxy) error {
+	var svcs []*api.Service
+	if err := a.get("/api/v1/services", &svcs); err != nil {
+		return err
+	}
+	want := make(map[string]bool)
+	for _, svc := range svcs {
+		var endpoints []api.Endpoint
+		fetched := false
+		for _, sp := range svc.Spec.Ports {
+			if sp.NodePort == 0 || sp.Protocol == "UDP" {
+				// The userspace proxy forwards TCP only.
+				continue
+			}
+			if !fetched {
+				namespace := svc.Meta.Namespace
+				if namespace == "" {
+					namespace = "default"
+				}
+				path := "/api/v1/namespaces/" + url.PathEscape(namespace) +
+					"/services/" + url.PathEscape(svc.Meta.Name) + "/endpoints"
+				if err := a.get(path, &endpoints); err != nil {
+					a.log.Error("fetching service endpoints failed",
+						"service", svc.Meta.Name, "err", err)
+					break
+				}
+				fetched = true
+			}
+			key := fmt.Sprintf("%d", sp.NodePort)
+			want[key] = true
+			backends := a.portBackends(svc, sp, endpoints)
+			p, ok := proxies[key]
+			if !ok {
+				ln, err := net.Listen("tcp", fmt.Sprintf(":%d", sp.NodePort))
+				if err != nil {
+					a.log.Error("listening on node port failed",
+						"service", svc.Meta.Name, "port", sp.NodePort, "err", err)
+					continue
+				}
+				p = &portProxy{ln: ln, agent: a, active: make(map[string]int),
+					clients: make(map[string]affinityEntry)}
+				proxies[key] = p
+				go p.serve()
+				a.log.Info("service proxy listening",
+					"service", svc.Meta.Name, "port", sp.NodePort)
+			}
+			p.update(svc, backends)
+		}
+	}
+	for key, p := range proxies {
+		if !want[key] {
+			p.ln.Close()
+			delete(proxies, key)
+		}
+	}
+	return nil
+}
+
+// portBackends selects the endpoints this node's proxy may use for one
+// service port, honoring the service's locality settings: Local keeps
+// only same-node backends (and fails closed without any), PreferLocal
+// keeps them only while at least one exists.
+func (a *Agent) portBackends(svc *api.Service, sp api.ServicePort, endpoints []api.Endpoint) []string {
+	var all, local []string
+	for _, ep := range endpoints {
+		if ep.PortName != sp.Name {
+			continue
+		}
+		addr := net.JoinHostPort(ep.IP, fmt.Sprint(ep.Port))
+		all = append(all, addr)
+		if ep.NodeName == a.NodeName {
+			local = append(local, addr)
+		}
+	}
+	switch {
+	case svc.Spec.InternalTrafficPolicy == "Local":
+		return local
+	case svc.Spec.TopologyMode == api.TopologyPreferLocal && len(local) > 0:
+		return local
+	}
+	return all
+}
+
+// affinityEntry pins one client address to a backend.
+type affinityEntry struct {
+	backend string
+	seen    time.Time
+}
+
+// portProxy forwards one node port to a service's backends.
+type portProxy struct {
+	ln    net.Listener
+	agent *Agent
+
+	mu       sync.Mutex
+	backends []string
+	policy   string
+	affinity time.Duration // zero means no session affinity
+	next     int
+	active   map[string]int
+	clients  map[string]affinityEntry
+}
+
+// update swaps in the latest backends and service tuning.
+func (p *portProxy) update(svc *api.Service, backends []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.backends = backends
+	p.policy = svc.Spec.LoadBalancing
+	p.affinity = 0
+	if svc.Spec.SessionAffinity == api.AffinityClientIP {
+		p.affinity = defaultAffinityTimeout
+		if svc.Spec.SessionAffinityTimeoutSeconds > 0 {
+			p.affinity = time.Duration(svc.Spec.SessionAffinityTimeoutSeconds) * time.Second
+		}
+	}
+}
+
+func (p *portProxy) serve() {
+	for {
+		conn, err := p.ln.Accept()
+		if err != nil {
+			return // listener closed by the sync pass
+		}
+		go p.handle(conn)
+	}
+}
+
+func (p *portProxy) handle(conn net.Conn) {
+	defer conn.Close()
+	client, _, _ := net.SplitHostPort(conn.RemoteAddr().String())
+	backend := p.pick(client)
+	if backend == "" {
+		return // no endpoints right now
+	}
+	upstream, err := net.DialTimeout("tcp", backend, 10*time.Second)
+	if err != nil {
+		p.agent.log.Warn("dialing service backend failed", "backend", backend, "err", err)
+		p.release(backend)
+		return
+	}
+	defer upstream.Close()
+	done := make(chan struct{})
+	go func() {
+		io.Copy(upstream, conn)
+		upstream.(*net.TCPConn).CloseWrite()
+		done <- struct{}{}
+	}()
+	io.Copy(conn, upstream)
+	<-done
+	p.release(backend)
+}
+
+// pick chooses a backend for one connection, honoring session
+// affinity first and the balancing policy otherwise, and counts the
+// connection against it.
+func (p *portProxy) pick(client string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.backends) == 0 {
+		return ""
+	}
+	if p.affinity > 0 && client != "" {
+		if e, ok := p.clients[client]; ok && time.Since(e.seen) < p.affinity && slices.Contains(p.backends, e.backend) {
+			p.clients[client] = affinityEntry{backend: e.backend, seen: time.Now()}
+			p.active[e.backend]++
+			return e.backend
+		}
+	}
+	var backend string
+	switch p.policy {
+	case api.BalanceLeastConnections:
+		for _, b := range p.backends {
+			if backend == "" || p.active[b] < p.active[backend] {
+				backend = b
+			}
+		}
+	case api.BalanceRandom:
+		backend = p.backends[rand.Intn(len(p.backends))]
+	default: // round-robin
+		backend = p.backends[p.next%len(p.backends)]
+		p.next++
+	}
+	if p.affinity > 0 && client != "" {
+		p.clients[client] = affinityEntry{backend: backend, seen: time.Now()}
+		p.pruneClients()
+	}
+	p.active[backend]++
+	return backend
+}
+
+func (p *portProxy) release(backend string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.active[backend] > 1 {
+		p.active[backend]--
+	} else {
+		delete(p.active, backend)
+	}
+}
+
+// pruneClients drops expired pins so long-lived proxies do not hoard
+// every client address ever seen. Called with the lock held.
+func (p *portProxy) pruneClients() {
+	for client, e := range p.clients {
+		if time.Since(e.seen) >= p.affinity {
+			delete(p.clients, client)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+//go:build linux
+
+package agent
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// maxPodsPerNode caps how many pods a single node will accept.
+const maxPodsPerNode = 110
+
+// hostCapacity inspects the machine and returns its total resources.
+func hostCapacity() (api.ResourceList, error) {
+	mem, err := memTotalBytes()
+	if err != nil {
+		return nil, fmt.Errorf("reading memory size: %w", err)
+	}
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs("/var/lib", &fs); err != nil {
+		return nil, fmt.Errorf("statfs /var/lib: %w", err)
+	}
+	return api.ResourceList{
+		api.ResourceCPU:              int64(runtime.NumCPU()) * 1000,
+		api.ResourceMemory:           mem,
+		api.ResourceEphemeralStorage: int64(fs.Blocks) * fs.Bsize,
+		api.ResourcePods:             maxPodsPerNode,
+	}, nil
+}
+
+// memTotalBytes parses MemTotal out of /proc/meminfo.
+func memTotalBytes() (int64, error) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) >= 2 && fields[0] == "MemTotal:" {
+			kb, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return 0, err
+			}
+			return kb * 1024, nil
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("MemTotal not found in /proc/meminfo")
+}
@@ -0,0 +1,22 @@
+//go:build !linux
+
+package agent
+
+import (
+	"runtime"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// maxPodsPerNode caps how many pods a single node will accept.
+const maxPodsPerNode = 110
+
+// hostCapacity on non-Linux hosts reports CPU count only; memory and
+// storage inspection is Linux-specific and those resources are simply
+// not enforced here. Useful for development on macOS.
+func hostCapacity() (api.ResourceList, error) {
+	return api.ResourceList{
+		api.ResourceCPU:  int64(runtime.NumCPU()) * 1000,
+		api.ResourcePods: maxPodsPerNode,
+	}, nil
+}
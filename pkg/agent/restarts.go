@@ -0,0 +1,91 @@
+package agent
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/runtime"
+)
+
+// restartTracker remembers what it last saw of each pod container so
+// restart counts and last terminations survive the container being
+// replaced. The runtime recreates containers instead of restarting them
+// in place, so the counts cannot come from the engine; they restart at
+// zero when the agent does.
+type restartTracker struct {
+	mu sync.Mutex
+
+	// records is keyed by "namespace/pod", then container name.
+	records map[string]map[string]*containerRecord
+}
+
+type containerRecord struct {
+	lastID          string
+	wasRunning      bool
+	restarts        int
+	lastTermination *api.ContainerTermination
+}
+
+func newRestartTracker() *restartTracker {
+	return &restartTracker{records: make(map[string]map[string]*containerRecord)}
+}
+
+// observe folds one sync pass's view of the pod into the tracker and
+// returns the container statuses the pod should report.
+func (t *restartTracker) observe(pod *api.Pod, existing []runtime.Container) []api.ContainerStatus {
+	byName := make(map[string]runtime.Container, len(existing))
+	for _, c := range existing {
+		byName[c.Name] = c
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := pod.Meta.Namespace + "/" + pod.Meta.Name
+	recs := t.records[key]
+	if recs == nil {
+		recs = make(map[string]*containerRecord)
+		t.records[key] = recs
+	}
+	statuses := make([]api.ContainerStatus, 0, len(pod.Spec.Containers))
+	for _, spec := range pod.Spec.Containers {
+		rec := recs[spec.Name]
+		if rec == nil {
+			rec = &containerRecord{}
+			recs[spec.Name] = rec
+		}
+		status := api.ContainerStatus{Name: spec.Name}
+		c, ok := byName[spec.Name]
+		if ok {
+			if rec.lastID != "" && rec.lastID != c.ID {
+				rec.restarts++
+			}
+			if !c.Running && (rec.wasRunning || rec.lastTermination == nil) {
+				reason := "Error"
+				if c.ExitCode == 0 {
+					reason = "Completed"
+				}
+				rec.lastTermination = &api.ContainerTermination{
+					ExitCode:   c.ExitCode,
+					Reason:     reason,
+					FinishedAt: time.Now().Truncate(time.Second),
+				}
+			}
+			rec.lastID, rec.wasRunning = c.ID, c.Running
+			status.Running = c.Running
+			if c.Running {
+				status.StartedAt = c.StartedAt
+			}
+		}
+		status.RestartCount = rec.restarts
+		status.LastTermination = rec.lastTermination
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// forget drops a pod's records once its containers are torn down.
+func (t *restartTracker) forget(namespace, name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.records, namespace+"/"+name)
+}
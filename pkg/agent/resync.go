@@ -0,0 +1,151 @@
+package agent
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/runtime"
+)
+
+// runResync is the slow anti-entropy loop. The regular sync trusts the
+// container cache and only checks that containers exist and run, so
+// out-of-band changes — a `docker rm`, a container swapped to another
+// image, a mutated env — can linger until replicas happen to change.
+// Each resync pass relists the engine from scratch, inspects every
+// running container against its spec, and removes drifted ones so the
+// next sync pass recreates them, recording an event for each repair.
+func (a *Agent) runResync() {
+	checker, ok := a.Runtime.(runtime.DriftChecker)
+	if !ok {
+		return
+	}
+	ticker := time.NewTicker(a.ResyncInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := a.resync(checker); err != nil {
+			a.log.Error("resync failed", "err", err)
+		}
+	}
+}
+
+func (a *Agent) resync(checker runtime.DriftChecker) error {
+	resizer, _ := a.Runtime.(runtime.InPlaceResizer)
+	// Relist the engine rather than trusting the cache, so containers
+	// removed behind our back are seen now instead of at the next
+	// lifecycle event.
+	a.cache.MarkDirty()
+
+	var pods []*api.Pod
+	if err := a.get("/api/v1/pods", &pods); err != nil {
+		return err
+	}
+	repaired := false
+	for _, pod := range pods {
+		if nodeFor(pod) != a.NodeName ||
+			pod.Status.Phase == api.PodSucceeded || pod.Status.Phase == api.PodFailed {
+			continue
+		}
+		if a.resyncPod(checker, resizer, pod) {
+			repaired = true
+		}
+	}
+	if repaired {
+		// Recreate replaced containers now, not at the next tick.
+		select {
+		case a.syncKick <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// resyncPod checks one pod's containers for drift, removing and
+// reporting any that diverge, and reports missing ones the next sync
+// will restore. Containers that match their spec get their cpu/memory
+// limits resized in place when the runtime can, so a resource-only
+// spec change lands without a restart. It returns whether anything
+// needed repair.
+func (a *Agent) resyncPod(checker runtime.DriftChecker, resizer runtime.InPlaceResizer, pod *api.Pod) bool {
+	existing := a.cache.PodContainers(pod.Meta.Namespace, pod.Meta.Name)
+	byName := make(map[string]runtime.Container, len(existing))
+	for _, c := range existing {
+		byName[c.Name] = c
+	}
+	repaired := false
+	specs := append(append([]api.Container(nil), pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for _, spec := range specs {
+		c, ok := byName[spec.Name]
+		if !ok {
+			a.log.Warn("container missing, restoring",
+				"namespace", pod.Meta.Namespace, "pod", pod.Meta.Name, "container", spec.Name)
+			a.recordDrift(pod, "container "+spec.Name+" was missing, recreating")
+			repaired = true
+			continue
+		}
+		if !c.Running {
+			// The regular sync owns exited containers; restart policy
+			// decides their fate, not drift repair.
+			continue
+		}
+		findings, err := checker.ContainerDrift(c.ID, spec)
+		if err != nil {
+			a.log.Error("checking container drift failed",
+				"namespace", pod.Meta.Namespace, "pod", pod.Meta.Name,
+				"container", spec.Name, "err", err)
+			continue
+		}
+		if len(findings) == 0 {
+			if resizer == nil {
+				continue
+			}
+			changed, err := resizer.ResizeContainer(c.ID, spec)
+			if err != nil {
+				a.log.Warn("resizing container failed",
+					"namespace", pod.Meta.Namespace, "pod", pod.Meta.Name,
+					"container", spec.Name, "err", err)
+			} else if changed {
+				a.log.Info("resized container in place",
+					"namespace", pod.Meta.Namespace, "pod", pod.Meta.Name, "container", spec.Name)
+				a.recordPodEvent(pod, api.EventNormal, "ResourcesUpdated",
+					"resized container "+spec.Name+" in place")
+			}
+			continue
+		}
+		a.log.Warn("container drifted from spec, replacing",
+			"namespace", pod.Meta.Namespace, "pod", pod.Meta.Name,
+			"container", spec.Name, "drift", strings.Join(findings, "; "))
+		if err := checker.RemoveContainer(c.ID); err != nil {
+			a.log.Error("removing drifted container failed",
+				"namespace", pod.Meta.Namespace, "pod", pod.Meta.Name,
+				"container", spec.Name, "err", err)
+			continue
+		}
+		a.recordDrift(pod, "replaced container "+spec.Name+": "+strings.Join(findings, "; "))
+		repaired = true
+	}
+	if repaired {
+		a.cache.MarkDirty()
+	}
+	return repaired
+}
+
+// recordDrift reports one repair as a pod event; the resync still
+// repairs when the API server will not take the event.
+func (a *Agent) recordDrift(pod *api.Pod, message string) {
+	a.recordPodEvent(pod, api.EventWarning, "DriftRepaired", message)
+}
+
+func (a *Agent) recordPodEvent(pod *api.Pod, evType, reason, message string) {
+	ev := api.Event{
+		Type:    evType,
+		Reason:  reason,
+		Message: message,
+		Regarding: api.ObjectRef{
+			Kind: "Pod", Namespace: pod.Meta.Namespace, Name: pod.Meta.Name,
+		},
+	}
+	if err := a.post("/api/v1/events", ev); err != nil {
+		a.log.Warn("recording pod event failed", "err", err)
+	}
+}
@@ -0,0 +1,115 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/yaml"
+)
+
+// loadStaticPods reads the pod manifests under StaticPodsDir. Static
+// pods run from local files so critical workloads survive control-plane
+// outages; each is pinned to this node, suffixed with the node name so
+// mirrors from different nodes never collide, and stamped with the
+// static-pod annotation. Broken files are logged and skipped rather
+// than failing the pass.
+func (a *Agent) loadStaticPods() map[string]*api.Pod {
+	if a.StaticPodsDir == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(a.StaticPodsDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			a.log.Warn("reading static pods directory failed", "dir", a.StaticPodsDir, "err", err)
+		}
+		return nil
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".yaml", ".yml", ".json":
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	static := make(map[string]*api.Pod)
+	for _, name := range names {
+		path := filepath.Join(a.StaticPodsDir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			a.log.Warn("reading static pod manifest failed", "file", path, "err", err)
+			continue
+		}
+		for _, doc := range yaml.SplitDocuments(data) {
+			pod, err := decodeStaticPod(doc)
+			if err != nil {
+				a.log.Warn("decoding static pod manifest failed", "file", path, "err", err)
+				continue
+			}
+			pod.Meta.Name = pod.Meta.Name + "-" + a.NodeName
+			if pod.Meta.Namespace == "" {
+				pod.Meta.Namespace = "default"
+			}
+			if pod.Meta.Annotations == nil {
+				pod.Meta.Annotations = map[string]string{}
+			}
+			pod.Meta.Annotations[api.StaticPodAnnotation] = a.NodeName
+			pod.Spec.NodeName = a.NodeName
+			static[pod.Meta.Namespace+"/"+pod.Meta.Name] = pod
+		}
+	}
+	return static
+}
+
+// decodeStaticPod parses one manifest document, JSON first and YAML as
+// the fallback, and checks it is a runnable pod.
+func decodeStaticPod(doc []byte) (*api.Pod, error) {
+	var pod api.Pod
+	if err := json.Unmarshal(doc, &pod); err != nil {
+		converted, yerr := yaml.ToJSON(doc)
+		if yerr != nil {
+			return nil, yerr
+		}
+		if err := json.Unmarshal(converted, &pod); err != nil {
+			return nil, err
+		}
+	}
+	if pod.Meta.Name == "" {
+		return nil, fmt.Errorf("manifest needs metadata.name")
+	}
+	if len(pod.Spec.Containers) == 0 {
+		return nil, fmt.Errorf("manifest needs at least one container")
+	}
+	return &pod, nil
+}
+
+// ensureMirror keeps the API's read-only view of a static pod in step
+// with the file: it creates the mirror when missing and reverts spec
+// edits, so the API always shows what the node actually runs.
+func (a *Agent) ensureMirror(local, mirror *api.Pod) {
+	key := local.Meta.Namespace + "/" + local.Meta.Name
+	if mirror == nil {
+		path := "/api/v1/namespaces/" + url.PathEscape(local.Meta.Namespace) + "/pods"
+		if err := a.post(path, local); err != nil {
+			a.log.Warn("creating static pod mirror failed", "pod", key, "err", err)
+		}
+		return
+	}
+	if reflect.DeepEqual(mirror.Spec, local.Spec) &&
+		mirror.Meta.Annotations[api.StaticPodAnnotation] == a.NodeName {
+		return
+	}
+	if err := a.put(podPath(local), local); err != nil {
+		a.log.Warn("reverting static pod mirror failed", "pod", key, "err", err)
+	}
+}
@@ -0,0 +1,86 @@
+package agent
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// cpuTracker keeps the previous /proc/stat reading so node CPU usage
+// can be computed as a delta between scrapes.
+type cpuTracker struct {
+	prevIdle  uint64
+	prevTotal uint64
+}
+
+// runStats samples node and container usage and pushes it to the API
+// server, where it backs the metrics endpoints.
+func (a *Agent) runStats() {
+	tracker := &cpuTracker{}
+	for range time.Tick(a.StatsInterval) {
+		if err := a.pushStats(tracker); err != nil {
+			a.log.Error("pushing stats failed", "err", err)
+		}
+	}
+}
+
+func (a *Agent) pushStats(tracker *cpuTracker) error {
+	now := time.Now()
+	containers := a.cache.Containers()
+	var running []string
+	for _, c := range containers {
+		if c.Running {
+			running = append(running, c.ID)
+		}
+	}
+	usage, err := a.Runtime.ContainerStats(running)
+	if err != nil {
+		return err
+	}
+
+	pods := make(map[string]*api.PodMetrics)
+	for _, c := range containers {
+		stats, ok := usage[c.ID]
+		if !ok {
+			continue
+		}
+		key := c.PodNamespace + "/" + c.PodName
+		pm := pods[key]
+		if pm == nil {
+			pm = &api.PodMetrics{
+				Namespace: c.PodNamespace,
+				Name:      c.PodName,
+				Timestamp: now,
+				Window:    a.StatsInterval,
+			}
+			pods[key] = pm
+		}
+		pm.Containers = append(pm.Containers, api.ContainerMetrics{
+			Name: c.Name,
+			Usage: api.ResourceList{
+				api.ResourceCPU:       stats.CPUMilli,
+				api.ResourceMemory:    stats.MemoryBytes,
+				api.ResourceNetworkRx: stats.NetRxBytes,
+				api.ResourceNetworkTx: stats.NetTxBytes,
+			},
+		})
+	}
+
+	nodeUse, err := nodeUsage(tracker)
+	if err != nil {
+		return err
+	}
+	push := api.NodeStats{
+		Node: api.NodeMetrics{
+			Name:      a.NodeName,
+			Timestamp: now,
+			Window:    a.StatsInterval,
+			Usage:     nodeUse,
+		},
+	}
+	for _, pm := range pods {
+		push.Pods = append(push.Pods, *pm)
+	}
+	return a.put("/api/v1/nodes/"+url.PathEscape(a.NodeName)+"/stats", &push)
+}
@@ -0,0 +1,105 @@
+package agent
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/version"
+)
+
+// upgradeCheckInterval is how often the agent looks at its node object
+// for upgrade annotations.
+const upgradeCheckInterval = 30 * time.Second
+
+// runUpgrade watches this node's object for the upgrade annotations.
+// When they name a version other than the running one, the agent
+// downloads the new binary, swaps it in place keeping the old one as a
+// .old backup, and exits for its supervisor to restart. Re-registration
+// then clears the annotations and reports the new version.
+func (a *Agent) runUpgrade() {
+	for range time.Tick(upgradeCheckInterval) {
+		var node api.Node
+		if err := a.get("/api/v1/nodes/"+url.PathEscape(a.NodeName), &node); err != nil {
+			continue
+		}
+		target := node.Meta.Annotations[api.UpgradeToAnnotation]
+		source := node.Meta.Annotations[api.UpgradeURLAnnotation]
+		if target == "" || target == version.Version || source == "" {
+			continue
+		}
+		a.log.Info("upgrading agent binary",
+			"from", version.Version, "to", target, "url", source)
+		if err := replaceExecutable(source); err != nil {
+			a.log.Error("upgrading agent binary failed", "err", err)
+			a.recordNodeEvent(api.EventWarning, "UpgradeFailed",
+				fmt.Sprintf("replacing agent binary with %s failed: %v", target, err))
+			continue
+		}
+		a.recordNodeEvent(api.EventNormal, "Upgraded",
+			fmt.Sprintf("agent binary replaced with %s, restarting", target))
+		a.log.Info("agent binary replaced, exiting for restart", "version", target)
+		os.Exit(0)
+	}
+}
+
+// replaceExecutable downloads a new agent binary and atomically swaps
+// it over the running one. The previous binary stays next to it as
+// .old, so a bad build can be put back by hand even with the control
+// plane down.
+func replaceExecutable(source string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	resp, err := http.Get(source)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("downloading %s: server returned %s", source, resp.Status)
+	}
+	tmp, err := os.OpenFile(exe+".new", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o755)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	os.Remove(exe + ".old")
+	if err := os.Rename(exe, exe+".old"); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), exe); err != nil {
+		// Put the old binary back so the supervisor restarts something.
+		os.Rename(exe+".old", exe)
+		return err
+	}
+	return nil
+}
+
+// recordNodeEvent posts an event about this node; failures only warn,
+// events being best-effort.
+func (a *Agent) recordNodeEvent(evType, reason, message string) {
+	ev := api.Event{
+		Type:      evType,
+		Reason:    reason,
+		Message:   message,
+		Regarding: api.ObjectRef{Kind: "Node", Name: a.NodeName},
+	}
+	if err := a.post("/api/v1/events", ev); err != nil {
+		a.log.Warn("recording node event failed", "err", err)
+	}
+}
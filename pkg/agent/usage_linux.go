@@ -0,0 +1,121 @@
+//go:build linux
+
+package agent
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// nodeUsage samples whole-node CPU and memory from /proc. CPU is the
+// busy share of all cores since the previous call, in millicores; the
+// first call has no baseline and reports zero.
+func nodeUsage(tracker *cpuTracker) (api.ResourceList, error) {
+	idle, total, err := readCPUStat()
+	if err != nil {
+		return nil, err
+	}
+	var cpuMilli int64
+	if tracker.prevTotal != 0 && total > tracker.prevTotal {
+		dTotal := float64(total - tracker.prevTotal)
+		dIdle := float64(idle - tracker.prevIdle)
+		cpuMilli = int64((dTotal - dIdle) / dTotal * float64(runtime.NumCPU()) * 1000)
+	}
+	tracker.prevIdle, tracker.prevTotal = idle, total
+
+	memTotal, memAvailable, err := readMemInfo()
+	if err != nil {
+		return nil, err
+	}
+	return api.ResourceList{
+		api.ResourceCPU:    cpuMilli,
+		api.ResourceMemory: memTotal - memAvailable,
+	}, nil
+}
+
+// pressureUsage reports the fractions of memory and root filesystem
+// capacity in use, for the node-pressure eviction loop.
+func pressureUsage() (memFrac, diskFrac float64, err error) {
+	memTotal, memAvailable, err := readMemInfo()
+	if err != nil {
+		return 0, 0, err
+	}
+	if memTotal > 0 {
+		memFrac = 1 - float64(memAvailable)/float64(memTotal)
+	}
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs("/", &fs); err != nil {
+		return 0, 0, fmt.Errorf("statfs /: %w", err)
+	}
+	if fs.Blocks > 0 {
+		diskFrac = 1 - float64(fs.Bavail)/float64(fs.Blocks)
+	}
+	return memFrac, diskFrac, nil
+}
+
+// readCPUStat returns the aggregate idle and total jiffy counters from
+// the first line of /proc/stat. Iowait counts as idle.
+func readCPUStat() (idle, total uint64, err error) {
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	if !sc.Scan() {
+		return 0, 0, fmt.Errorf("/proc/stat: empty")
+	}
+	fields := strings.Fields(sc.Text())
+	if len(fields) < 5 || fields[0] != "cpu" {
+		return 0, 0, fmt.Errorf("/proc/stat: unexpected first line %q", sc.Text())
+	}
+	for i, field := range fields[1:] {
+		v, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("/proc/stat: %w", err)
+		}
+		total += v
+		// Columns 4 and 5 (idle, iowait) both count as idle time.
+		if i == 3 || i == 4 {
+			idle += v
+		}
+	}
+	return idle, total, nil
+}
+
+// readMemInfo returns MemTotal and MemAvailable from /proc/meminfo.
+func readMemInfo() (total, available int64, err error) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		kb, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "MemTotal:":
+			total = kb * 1024
+		case "MemAvailable:":
+			available = kb * 1024
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return 0, 0, err
+	}
+	return total, available, nil
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+
+package agent
+
+import "github.com/ptfpinho23/synthesis/pkg/api"
+
+// nodeUsage has no /proc to sample on non-Linux hosts; the node still
+// reports so its pods' container stats reach the metrics API.
+func nodeUsage(tracker *cpuTracker) (api.ResourceList, error) {
+	return api.ResourceList{}, nil
+}
+
+// pressureUsage reports no pressure on non-Linux hosts.
+func pressureUsage() (memFrac, diskFrac float64, err error) {
+	return 0, 0, nil
+}
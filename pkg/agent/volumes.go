@@ -0,0 +1,209 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/runtime"
+)
+
+// tokenRefreshAge is how old a projected service account token file
+// may grow before it is re-minted, comfortably inside the server's
+// 24h token TTL.
+const tokenRefreshAge = time.Hour
+
+// ensureProjectedVolumes materializes the pod's projected volumes on
+// the host before its containers mount them. Contents are rebuilt
+// every sync pass, so rotated secrets reach running containers through
+// the live bind mount without a restart.
+func (a *Agent) ensureProjectedVolumes(pod *api.Pod) error {
+	for _, vol := range pod.Spec.Volumes {
+		if vol.Projected == nil {
+			continue
+		}
+		dir := runtime.ProjectedVolumeDir(pod.Meta.Namespace, pod.Meta.Name, vol.Name)
+		files, err := a.projectSources(pod, dir, vol.Projected)
+		if err != nil {
+			return fmt.Errorf("volume %q: %w", vol.Name, err)
+		}
+		if err := writeProjected(dir, files); err != nil {
+			return fmt.Errorf("volume %q: %w", vol.Name, err)
+		}
+	}
+	return nil
+}
+
+// projectSources flattens a projected volume's sources into relative
+// path → content. Later sources win on a path collision, matching
+// declaration order.
+func (a *Agent) projectSources(pod *api.Pod, dir string, proj *api.ProjectedVolume) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	for _, src := range proj.Sources {
+		switch {
+		case src.Secret != nil:
+			if err := a.projectSecret(pod, src.Secret, files); err != nil {
+				return nil, err
+			}
+		case src.DownwardAPI != nil:
+			for _, item := range src.DownwardAPI.Items {
+				val, err := downwardField(pod, item.FieldRef)
+				if err != nil {
+					return nil, err
+				}
+				files[item.Path] = []byte(val)
+			}
+		case src.ServiceAccountToken != nil:
+			path := src.ServiceAccountToken.Path
+			if path == "" {
+				path = "token"
+			}
+			tok, err := a.projectedToken(pod, filepath.Join(dir, path))
+			if err != nil {
+				return nil, err
+			}
+			files[path] = tok
+		}
+	}
+	return files, nil
+}
+
+func (a *Agent) projectSecret(pod *api.Pod, src *api.SecretProjection, files map[string][]byte) error {
+	namespace := pod.Meta.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+	var sec api.Secret
+	path := "/api/v1/namespaces/" + url.PathEscape(namespace) + "/secrets/" + url.PathEscape(src.Name)
+	if err := a.get(path, &sec); err != nil {
+		return err
+	}
+	if len(src.Items) == 0 {
+		for k, v := range sec.Data {
+			files[k] = v
+		}
+		return nil
+	}
+	for _, item := range src.Items {
+		v, ok := sec.Data[item.Key]
+		if !ok {
+			return fmt.Errorf("secret %q has no key %q", src.Name, item.Key)
+		}
+		files[item.Path] = v
+	}
+	return nil
+}
+
+// projectedToken returns the token to write at dst: the existing file
+// while it is fresh, a newly minted one otherwise. Minting on every
+// sync pass would hammer the signer for no gain.
+func (a *Agent) projectedToken(pod *api.Pod, dst string) ([]byte, error) {
+	if info, err := os.Stat(dst); err == nil && time.Since(info.ModTime()) < tokenRefreshAge {
+		if tok, err := os.ReadFile(dst); err == nil {
+			return tok, nil
+		}
+	}
+	namespace, sa := pod.Meta.Namespace, pod.Spec.ServiceAccountName
+	if namespace == "" {
+		namespace = "default"
+	}
+	if sa == "" {
+		sa = "default"
+	}
+	path := "/api/v1/namespaces/" + url.PathEscape(namespace) +
+		"/serviceaccounts/" + url.PathEscape(sa) + "/token"
+	resp, err := http.Post(a.Server+path, "application/json", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("POST %s: server returned %s", path, resp.Status)
+	}
+	var out struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	return []byte(out.Token), nil
+}
+
+// downwardField resolves one downward API field reference.
+func downwardField(pod *api.Pod, ref string) (string, error) {
+	switch ref {
+	case "metadata.name":
+		return pod.Meta.Name, nil
+	case "metadata.namespace":
+		if pod.Meta.Namespace == "" {
+			return "default", nil
+		}
+		return pod.Meta.Namespace, nil
+	case "spec.nodeName":
+		return nodeFor(pod), nil
+	case "spec.serviceAccountName":
+		if pod.Spec.ServiceAccountName == "" {
+			return "default", nil
+		}
+		return pod.Spec.ServiceAccountName, nil
+	default:
+		return "", fmt.Errorf("unsupported downward API field %q", ref)
+	}
+}
+
+// writeProjected syncs dir to exactly the given files. The directory
+// is world-traversable so non-root container processes can read
+// through the bind mount; its parents stay root-only on the host.
+func writeProjected(dir string, files map[string][]byte) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for path, content := range files {
+		full := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(full, content, 0o644); err != nil {
+			return err
+		}
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		keep := false
+		for path := range files {
+			if path == e.Name() || strings.HasPrefix(path, e.Name()+"/") {
+				keep = true
+				break
+			}
+		}
+		if !keep {
+			if err := os.RemoveAll(filepath.Join(dir, e.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// removeProjectedVolumes deletes the host directories backing a
+// removed pod's projected volumes.
+func (a *Agent) removeProjectedVolumes(namespace, name string) {
+	dirs, err := filepath.Glob(runtime.ProjectedVolumeDir(namespace, name, "*"))
+	if err != nil {
+		return
+	}
+	for _, dir := range dirs {
+		if err := os.RemoveAll(dir); err != nil {
+			a.log.Warn("removing projected volume failed", "dir", dir, "err", err)
+		}
+	}
+}
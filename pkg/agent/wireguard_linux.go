@@ -0,0 +1,155 @@
+//go:build linux
+
+package agent
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// wgInterface is the WireGuard device every agent maintains.
+const wgInterface = "synthesis0"
+
+// wgKeyDir is where the node's mesh private key is persisted.
+const wgKeyDir = "/var/lib/synthesis"
+
+// meshSyncer keeps the local WireGuard interface in step with the node
+// list: one peer per remote node, allowed IPs set to its pod CIDR.
+type meshSyncer struct {
+	agent      *Agent
+	privateKey string
+	publicKey  string
+}
+
+// runMesh sets up the WireGuard interface and then resyncs peers until
+// the process exits. Errors are logged and retried; a node without the
+// wireguard tools simply stays off the mesh.
+func (a *Agent) runMesh() {
+	syncer, err := newMeshSyncer(a)
+	if err != nil {
+		a.log.Warn("overlay mesh disabled", "err", err)
+		return
+	}
+	for range time.Tick(15 * time.Second) {
+		if err := syncer.sync(); err != nil {
+			a.log.Error("mesh sync failed", "err", err)
+		}
+	}
+}
+
+func newMeshSyncer(a *Agent) (*meshSyncer, error) {
+	if _, err := exec.LookPath("wg"); err != nil {
+		return nil, fmt.Errorf("wg not found in PATH")
+	}
+	priv, pub, err := loadOrCreateKeys()
+	if err != nil {
+		return nil, err
+	}
+	return &meshSyncer{agent: a, privateKey: priv, publicKey: pub}, nil
+}
+
+// loadOrCreateKeys reads the persisted private key or generates one with
+// `wg genkey`, deriving the public key either way.
+func loadOrCreateKeys() (priv, pub string, err error) {
+	keyPath := filepath.Join(wgKeyDir, "wireguard.key")
+	if buf, err := os.ReadFile(keyPath); err == nil {
+		priv = strings.TrimSpace(string(buf))
+	} else {
+		out, err := exec.Command("wg", "genkey").Output()
+		if err != nil {
+			return "", "", fmt.Errorf("wg genkey: %w", err)
+		}
+		priv = strings.TrimSpace(string(out))
+		if err := os.MkdirAll(wgKeyDir, 0o700); err != nil {
+			return "", "", err
+		}
+		if err := os.WriteFile(keyPath, []byte(priv+"\n"), 0o600); err != nil {
+			return "", "", err
+		}
+	}
+	cmd := exec.Command("wg", "pubkey")
+	cmd.Stdin = strings.NewReader(priv)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", "", fmt.Errorf("wg pubkey: %w", err)
+	}
+	return priv, strings.TrimSpace(string(out)), nil
+}
+
+// sync fetches the node list, ensures the local interface exists and is
+// addressed out of this node's pod CIDR, and reconciles one peer per
+// remote node.
+func (m *meshSyncer) sync() error {
+	var self api.Node
+	if err := m.agent.get("/api/v1/nodes/"+url.PathEscape(m.agent.NodeName), &self); err != nil {
+		return err
+	}
+	if self.Spec.PodCIDR == "" {
+		return nil // controller has not allocated us a CIDR yet
+	}
+	if self.Status.WireGuardPublicKey != m.publicKey {
+		self.Status.WireGuardPublicKey = m.publicKey
+		if err := m.agent.put("/api/v1/nodes/"+url.PathEscape(m.agent.NodeName), &self); err != nil {
+			return err
+		}
+	}
+	if err := m.ensureInterface(self.Spec.PodCIDR); err != nil {
+		return err
+	}
+
+	var nodes []api.Node
+	if err := m.agent.get("/api/v1/nodes", &nodes); err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		if node.Meta.Name == m.agent.NodeName {
+			continue
+		}
+		if node.Status.WireGuardPublicKey == "" || node.Spec.PodCIDR == "" || node.Status.Address == "" {
+			continue
+		}
+		args := []string{"set", wgInterface,
+			"peer", node.Status.WireGuardPublicKey,
+			"endpoint", node.Status.Address + ":51871",
+			"allowed-ips", node.Spec.PodCIDR,
+			"persistent-keepalive", "25"}
+		if out, err := exec.Command("wg", args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("wg set peer %s: %v: %s", node.Meta.Name, err, out)
+		}
+		if out, err := exec.Command("ip", "route", "replace", node.Spec.PodCIDR, "dev", wgInterface).CombinedOutput(); err != nil {
+			return fmt.Errorf("routing %s: %v: %s", node.Spec.PodCIDR, err, out)
+		}
+	}
+	return nil
+}
+
+// ensureInterface creates and configures the WireGuard device if it is
+// not already up. The node claims the .1 address of its pod CIDR.
+func (m *meshSyncer) ensureInterface(podCIDR string) error {
+	if err := exec.Command("ip", "link", "show", wgInterface).Run(); err == nil {
+		return nil
+	}
+	if out, err := exec.Command("ip", "link", "add", wgInterface, "type", "wireguard").CombinedOutput(); err != nil {
+		return fmt.Errorf("creating %s: %v: %s", wgInterface, err, out)
+	}
+	keyFile := filepath.Join(wgKeyDir, "wireguard.key")
+	if out, err := exec.Command("wg", "set", wgInterface,
+		"listen-port", "51871", "private-key", keyFile).CombinedOutput(); err != nil {
+		return fmt.Errorf("configuring %s: %v: %s", wgInterface, err, out)
+	}
+	addr := strings.Replace(podCIDR, ".0/24", ".1/24", 1)
+	if out, err := exec.Command("ip", "addr", "add", addr, "dev", wgInterface).CombinedOutput(); err != nil {
+		return fmt.Errorf("addressing %s: %v: %s", wgInterface, err, out)
+	}
+	if out, err := exec.Command("ip", "link", "set", wgInterface, "up").CombinedOutput(); err != nil {
+		return fmt.Errorf("bringing up %s: %v: %s", wgInterface, err, out)
+	}
+	return nil
+}
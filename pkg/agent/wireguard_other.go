@@ -0,0 +1,8 @@
+//go:build !linux
+
+package agent
+
+// runMesh is a no-op off Linux; the overlay needs kernel WireGuard.
+func (a *Agent) runMesh() {
+	a.log.Info("overlay mesh is only supported on Linux")
+}
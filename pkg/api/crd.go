@@ -0,0 +1,58 @@
+package api
+
+import "encoding/json"
+
+// CustomResourceDefinition registers a new resource type at runtime.
+// Its objects are served under /apis/<group>/<version>/<plural> with
+// the same CRUD verbs as built-in resources.
+type CustomResourceDefinition struct {
+	Meta ObjectMeta `json:"metadata"`
+	Spec CRDSpec    `json:"spec"`
+}
+
+// CRDSpec names the type and optionally constrains its objects.
+type CRDSpec struct {
+	// Group and Version form the API path the objects are served under.
+	Group   string `json:"group"`
+	Version string `json:"version"`
+
+	// Kind is the singular PascalCase type name.
+	Kind string `json:"kind"`
+
+	// Plural is the lowercase path segment objects live under.
+	Plural string `json:"plural"`
+
+	// Namespaced scopes objects to namespaces when true.
+	Namespaced bool `json:"namespaced,omitempty"`
+
+	// Schema, when set, validates every object's spec on write.
+	Schema *JSONSchema `json:"schema,omitempty"`
+}
+
+// Resource is the group/plural key the CRD's objects are stored under.
+func (c *CustomResourceDefinition) Resource() string {
+	return c.Spec.Group + "/" + c.Spec.Plural
+}
+
+// JSONSchema is the OpenAPI subset custom resource specs are checked
+// against: types, properties, required fields, items and enums.
+type JSONSchema struct {
+	Type       string                 `json:"type,omitempty"`
+	Properties map[string]*JSONSchema `json:"properties,omitempty"`
+	Required   []string               `json:"required,omitempty"`
+	Items      *JSONSchema            `json:"items,omitempty"`
+	Enum       []any                  `json:"enum,omitempty"`
+}
+
+// CustomObject is one instance of a registered custom resource. Spec
+// and Status stay raw JSON; the CRD's schema (when present) vets Spec
+// on every write.
+type CustomObject struct {
+	// Resource is the owning CRD's group/plural, filled in by the
+	// server from the request path.
+	Resource string `json:"resource,omitempty"`
+
+	Meta   ObjectMeta      `json:"metadata"`
+	Spec   json.RawMessage `json:"spec,omitempty"`
+	Status json.RawMessage `json:"status,omitempty"`
+}
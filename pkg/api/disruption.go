@@ -0,0 +1,53 @@
+package api
+
+import "fmt"
+
+// PodDisruptionBudget limits how many pods selected by its selector may
+// be voluntarily evicted (e.g. during a node drain) at the same time.
+type PodDisruptionBudget struct {
+	Meta ObjectMeta              `json:"metadata"`
+	Spec PodDisruptionBudgetSpec `json:"spec"`
+}
+
+// PodDisruptionBudgetSpec names the protected pods and the floor that
+// must stay running.
+type PodDisruptionBudgetSpec struct {
+	Selector *LabelSelector `json:"selector,omitempty"`
+
+	// MinAvailable is the number of selected pods that must remain
+	// running; an eviction that would drop below it is refused.
+	MinAvailable int `json:"minAvailable"`
+}
+
+// CheckDisruptionBudgets returns an error if evicting the pod would drop
+// any matching budget below its minAvailable floor. Every voluntary
+// eviction — drains, descheduling — consults it first.
+func CheckDisruptionBudgets(pod *Pod, pods []*Pod, pdbs []*PodDisruptionBudget) error {
+	for _, pdb := range pdbs {
+		if pdb.Meta.Namespace != pod.Meta.Namespace {
+			continue
+		}
+		if !pdb.Spec.Selector.Matches(pod.Meta.Labels) {
+			continue
+		}
+		running := 0
+		for _, other := range pods {
+			// Pods already handed over for graceful teardown are on
+			// their way down and must not count as available, or a
+			// drain could evict past the floor before they finish.
+			if other.Meta.Annotations[EvictionGracePeriodAnnotation] != "" {
+				continue
+			}
+			if other.Meta.Namespace == pdb.Meta.Namespace &&
+				other.Status.Phase == PodRunning &&
+				pdb.Spec.Selector.Matches(other.Meta.Labels) {
+				running++
+			}
+		}
+		if running-1 < pdb.Spec.MinAvailable {
+			return fmt.Errorf("eviction blocked by disruption budget %s (minAvailable %d, running %d)",
+				pdb.Meta.Name, pdb.Spec.MinAvailable, running)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package api
+
+import "time"
+
+// Event types, mirroring the Kubernetes convention.
+const (
+	EventNormal  = "Normal"
+	EventWarning = "Warning"
+)
+
+// ObjectRef names the object an event is about.
+type ObjectRef struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// Event records one notable cluster occurrence: a state transition, an
+// eviction, a failure. Events are a bounded log on the server, not a
+// stored resource; they age out instead of accumulating.
+type Event struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"` // Normal or Warning
+	Reason  string    `json:"reason"`
+	Message string    `json:"message,omitempty"`
+
+	// Regarding is the object the event describes.
+	Regarding ObjectRef `json:"regarding"`
+}
@@ -0,0 +1,118 @@
+package api
+
+// Gateway declares a set of listeners the ingress proxies serve, the
+// Gateway API counterpart to an Ingress. HTTPRoutes attach to it by
+// name and carry the actual routing rules.
+type Gateway struct {
+	Meta ObjectMeta  `json:"metadata"`
+	Spec GatewaySpec `json:"spec"`
+}
+
+// GatewaySpec lists the gateway's listeners.
+type GatewaySpec struct {
+	Listeners []GatewayListener `json:"listeners,omitempty"`
+}
+
+// GatewayListener is one entry point. The embedded proxies serve HTTP
+// listeners on port 80 and HTTPS listeners on port 443; other ports
+// are not bound.
+type GatewayListener struct {
+	// Name distinguishes listeners within the gateway.
+	Name string `json:"name"`
+
+	// Port is the port the listener expects traffic on.
+	Port int `json:"port"`
+
+	// Protocol is HTTP or HTTPS.
+	Protocol string `json:"protocol"`
+
+	// Hostname, when set, restricts the listener to one host and
+	// narrows the hostnames of attached routes.
+	Hostname string `json:"hostname,omitempty"`
+
+	// TLS carries the HTTPS listener's certificate reference.
+	TLS *GatewayTLS `json:"tls,omitempty"`
+}
+
+// GatewayTLS names the secret holding a listener's certificate, with
+// "tls.crt" and "tls.key" PEM entries like an ingress TLS secret.
+type GatewayTLS struct {
+	SecretName string `json:"secretName"`
+}
+
+// HTTPRoute routes requests arriving at its parent gateways, with
+// header matching and weighted traffic splitting beyond what Ingress
+// expresses.
+type HTTPRoute struct {
+	Meta ObjectMeta    `json:"metadata"`
+	Spec HTTPRouteSpec `json:"spec"`
+}
+
+// HTTPRouteSpec attaches rules to gateways.
+type HTTPRouteSpec struct {
+	// ParentRefs name the gateways in the route's namespace this route
+	// attaches to.
+	ParentRefs []string `json:"parentRefs,omitempty"`
+
+	// Hostnames are the hosts the route serves; empty inherits the
+	// listeners' hostnames.
+	Hostnames []string `json:"hostnames,omitempty"`
+
+	// Rules are tried in order; within a rule, any match admits the
+	// request.
+	Rules []HTTPRouteRule `json:"rules,omitempty"`
+}
+
+// HTTPRouteRule pairs matches with the backends splitting the traffic.
+type HTTPRouteRule struct {
+	// Matches admit a request when any of them matches; empty matches
+	// everything.
+	Matches []HTTPRouteMatch `json:"matches,omitempty"`
+
+	// BackendRefs share the rule's traffic in proportion to their
+	// weights.
+	BackendRefs []HTTPBackendRef `json:"backendRefs,omitempty"`
+}
+
+// HTTPRouteMatch is one condition set; all its parts must hold.
+type HTTPRouteMatch struct {
+	// Path matches the request path; nil matches any path.
+	Path *HTTPPathMatch `json:"path,omitempty"`
+
+	// Headers must all be present with the given values.
+	Headers []HTTPHeaderMatch `json:"headers,omitempty"`
+}
+
+// Supported HTTPPathMatch types.
+const (
+	PathMatchPrefix = "PathPrefix"
+	PathMatchExact  = "Exact"
+)
+
+// HTTPPathMatch matches the request path.
+type HTTPPathMatch struct {
+	// Type is PathMatchPrefix (the default) or PathMatchExact.
+	Type string `json:"type,omitempty"`
+
+	// Value is the path or prefix to match.
+	Value string `json:"value"`
+}
+
+// HTTPHeaderMatch requires one header to hold an exact value.
+type HTTPHeaderMatch struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HTTPBackendRef is one weighted share of a rule's traffic.
+type HTTPBackendRef struct {
+	// Service names the backing service in the route's namespace.
+	Service string `json:"service"`
+
+	// Port selects the service port like an ingress path's port.
+	Port string `json:"port,omitempty"`
+
+	// Weight is the backend's share relative to its siblings; zero
+	// counts as one, so unweighted refs split evenly.
+	Weight int `json:"weight,omitempty"`
+}
@@ -0,0 +1,16 @@
+package api
+
+// ImagePullRequest asks nodes to pull an image ahead of a rollout, so
+// the deploy window is not dominated by pull time. Requests live in a
+// bounded server-side queue that agents poll; progress comes back as
+// events on the pulling nodes.
+type ImagePullRequest struct {
+	// ID orders requests; agents poll with ?after=<last seen ID>.
+	ID int64 `json:"id,omitempty"`
+
+	// Image is the reference to pull.
+	Image string `json:"image"`
+
+	// Nodes selects which nodes pull; empty means every node.
+	Nodes []string `json:"nodes,omitempty"`
+}
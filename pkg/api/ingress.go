@@ -0,0 +1,81 @@
+package api
+
+// Ingress routes external HTTP traffic to services by host and path.
+// Every node's agent runs the ingress proxy, so any node address (or a
+// LoadBalancer address pointing at one) serves every ingress.
+type Ingress struct {
+	Meta ObjectMeta  `json:"metadata"`
+	Spec IngressSpec `json:"spec"`
+}
+
+// ACMEAnnotation set to "true" on an ingress asks the certificate
+// controller to obtain and renew its TLS certificates from the
+// configured ACME directory.
+const ACMEAnnotation = "synthesis.io/acme"
+
+// Annotations tuning how the proxies serve an ingress's routes.
+const (
+	// ProxyTimeoutAnnotation bounds each proxied request, as a Go
+	// duration ("30s"). Zero or absent means no limit.
+	ProxyTimeoutAnnotation = "synthesis.io/proxy-timeout"
+
+	// ProxyRetriesAnnotation is how many more backends to try when one
+	// cannot be reached. Only requests without a body are retried.
+	ProxyRetriesAnnotation = "synthesis.io/proxy-retries"
+
+	// HealthCheckPathAnnotation is an HTTP path the proxies probe on
+	// each backend; backends failing it are taken out of rotation.
+	// Absent, backends are probed with a plain TCP dial instead.
+	HealthCheckPathAnnotation = "synthesis.io/healthcheck-path"
+
+	// BackendProtocolAnnotation set to "h2c" or "grpc" makes the
+	// proxies relay this ingress's hosts as raw streams from the TLS
+	// listener to the backends, so HTTP/2 and gRPC survive end to end.
+	BackendProtocolAnnotation = "synthesis.io/backend-protocol"
+)
+
+// IngressSpec declares the routing rules and their TLS material.
+type IngressSpec struct {
+	// Rules map incoming requests to services. The first rule whose
+	// host matches is used, with the longest matching path prefix
+	// winning within it.
+	Rules []IngressRule `json:"rules,omitempty"`
+
+	// TLS lists the certificates the proxy serves for this ingress's
+	// hosts. Hosts without one are served over plain HTTP only.
+	TLS []IngressTLS `json:"tls,omitempty"`
+}
+
+// IngressRule routes one host's requests.
+type IngressRule struct {
+	// Host is the exact host to match; empty matches any host not
+	// claimed by another rule.
+	Host string `json:"host,omitempty"`
+
+	// Paths are the rule's path-prefix routes.
+	Paths []IngressPath `json:"paths,omitempty"`
+}
+
+// IngressPath routes one path prefix to a service port.
+type IngressPath struct {
+	// Path is the prefix to match; empty or "/" matches everything.
+	Path string `json:"path,omitempty"`
+
+	// Service names the backing service in the ingress's namespace.
+	Service string `json:"service"`
+
+	// Port selects the service port: a number, or the name of a port
+	// on the service. Empty picks the service's only port.
+	Port string `json:"port,omitempty"`
+}
+
+// IngressTLS pairs hosts with the secret holding their certificate.
+type IngressTLS struct {
+	// Hosts are the SNI names the certificate covers.
+	Hosts []string `json:"hosts,omitempty"`
+
+	// SecretName names a secret in the ingress's namespace with
+	// "tls.crt" and "tls.key" PEM entries. The certificate controller
+	// creates and renews it on ACME-managed ingresses.
+	SecretName string `json:"secretName"`
+}
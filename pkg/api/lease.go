@@ -0,0 +1,36 @@
+package api
+
+import "time"
+
+// Lease is a lightweight heartbeat and coordination record. Node
+// agents renew one cluster-scoped lease each; controllers and user
+// applications take namespaced leases for leader election, where the
+// server refuses renewals from anyone but the live holder.
+type Lease struct {
+	Meta ObjectMeta `json:"metadata"`
+	Spec LeaseSpec  `json:"spec"`
+}
+
+// LeaseSpec is the holder's identity and its renewal state.
+type LeaseSpec struct {
+	// HolderIdentity names the process renewing the lease.
+	HolderIdentity string `json:"holderIdentity"`
+
+	// DurationSeconds is how long the lease stays valid after a renewal.
+	DurationSeconds int `json:"durationSeconds"`
+
+	// RenewTime is the server-observed time of the last renewal.
+	RenewTime time.Time `json:"renewTime"`
+
+	// AcquireTime is when the current holder first took the lease.
+	AcquireTime time.Time `json:"acquireTime,omitempty"`
+
+	// Transitions counts holder changes over the lease's lifetime.
+	Transitions int `json:"transitions,omitempty"`
+}
+
+// Expired reports whether the lease has gone unrenewed past its duration
+// as of the given time.
+func (l *Lease) Expired(now time.Time) bool {
+	return now.Sub(l.Spec.RenewTime) > time.Duration(l.Spec.DurationSeconds)*time.Second
+}
@@ -0,0 +1,93 @@
+package api
+
+import "time"
+
+// ObjectMeta carries the identifying metadata shared by every Synthesis
+// object: pods, nodes, workloads and anything added later.
+type ObjectMeta struct {
+	Name string `json:"name"`
+
+	// GenerateName, on create requests that omit Name, is the prefix
+	// the server completes with a random suffix.
+	GenerateName string `json:"generateName,omitempty"`
+
+	Namespace   string            `json:"namespace,omitempty"`
+	UID         string            `json:"uid,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	CreatedAt   time.Time         `json:"createdAt,omitempty"`
+}
+
+// LabelSelector selects objects by exact label values and/or set-based
+// expressions. An empty selector matches everything.
+type LabelSelector struct {
+	MatchLabels      map[string]string          `json:"matchLabels,omitempty"`
+	MatchExpressions []LabelSelectorRequirement `json:"matchExpressions,omitempty"`
+}
+
+// LabelSelectorOperator is the comparator used by a selector requirement.
+type LabelSelectorOperator string
+
+const (
+	LabelSelectorOpIn           LabelSelectorOperator = "In"
+	LabelSelectorOpNotIn        LabelSelectorOperator = "NotIn"
+	LabelSelectorOpExists       LabelSelectorOperator = "Exists"
+	LabelSelectorOpDoesNotExist LabelSelectorOperator = "DoesNotExist"
+)
+
+// LabelSelectorRequirement is a single set-based selector term.
+type LabelSelectorRequirement struct {
+	Key      string                `json:"key"`
+	Operator LabelSelectorOperator `json:"operator"`
+	Values   []string              `json:"values,omitempty"`
+}
+
+// Matches reports whether the selector selects the given label set.
+func (s *LabelSelector) Matches(labels map[string]string) bool {
+	if s == nil {
+		return true
+	}
+	for k, v := range s.MatchLabels {
+		if labels[k] != v {
+			return false
+		}
+	}
+	for _, req := range s.MatchExpressions {
+		if !req.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// Matches reports whether a single requirement holds for the label set.
+func (r LabelSelectorRequirement) Matches(labels map[string]string) bool {
+	val, ok := labels[r.Key]
+	switch r.Operator {
+	case LabelSelectorOpIn:
+		if !ok {
+			return false
+		}
+		for _, v := range r.Values {
+			if v == val {
+				return true
+			}
+		}
+		return false
+	case LabelSelectorOpNotIn:
+		if !ok {
+			return true
+		}
+		for _, v := range r.Values {
+			if v == val {
+				return false
+			}
+		}
+		return true
+	case LabelSelectorOpExists:
+		return ok
+	case LabelSelectorOpDoesNotExist:
+		return !ok
+	}
+	return false
+}
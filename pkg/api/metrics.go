@@ -0,0 +1,43 @@
+package api
+
+import "time"
+
+// NodeMetrics is a point-in-time usage sample for one node, in the
+// canonical units (millicores, bytes).
+type NodeMetrics struct {
+	Name      string        `json:"name"`
+	Timestamp time.Time     `json:"timestamp"`
+	Window    time.Duration `json:"window"`
+	Usage     ResourceList  `json:"usage"`
+}
+
+// ContainerMetrics is one container's share of a pod sample.
+type ContainerMetrics struct {
+	Name  string       `json:"name"`
+	Usage ResourceList `json:"usage"`
+}
+
+// PodMetrics is a point-in-time usage sample for one pod.
+type PodMetrics struct {
+	Namespace  string             `json:"namespace"`
+	Name       string             `json:"name"`
+	Timestamp  time.Time          `json:"timestamp"`
+	Window     time.Duration      `json:"window"`
+	Containers []ContainerMetrics `json:"containers"`
+}
+
+// Usage sums the pod's per-container usage.
+func (m *PodMetrics) Usage() ResourceList {
+	total := ResourceList{}
+	for _, c := range m.Containers {
+		total = total.Add(c.Usage)
+	}
+	return total
+}
+
+// NodeStats is the sample batch an agent pushes on every scrape: the
+// node itself plus every pod running on it.
+type NodeStats struct {
+	Node NodeMetrics  `json:"node"`
+	Pods []PodMetrics `json:"pods"`
+}
@@ -0,0 +1,7 @@
+package api
+
+// Namespace scopes names and policy. Policy admission (pod security,
+// quotas) is configured through its labels.
+type Namespace struct {
+	Meta ObjectMeta `json:"metadata"`
+}
@@ -0,0 +1,103 @@
+package api
+
+import "time"
+
+// Node is a machine that can run pods.
+type Node struct {
+	Meta   ObjectMeta `json:"metadata"`
+	Spec   NodeSpec   `json:"spec,omitempty"`
+	Status NodeStatus `json:"status,omitempty"`
+}
+
+// NodeSpec is the desired configuration of a node.
+type NodeSpec struct {
+	// Unschedulable keeps the scheduler from placing new pods here.
+	Unschedulable bool `json:"unschedulable,omitempty"`
+
+	// PodCIDR is the address range this node hands out to its pods.
+	// Allocated by the network controller; agents must not set it.
+	PodCIDR string `json:"podCIDR,omitempty"`
+}
+
+// AgentLogPort is where every node's agent serves container logs; the
+// API server proxies pod log requests to it.
+const AgentLogPort = 8421
+
+// Upgrade annotations ask a node's agent to replace its own binary:
+// UpgradeToAnnotation names the version wanted and UpgradeURLAnnotation
+// where to download it. The agent swaps the binary and exits for its
+// supervisor to restart; re-registration clears the annotations and
+// reports the new version, which is how orchestration tells the node
+// is done.
+const (
+	UpgradeToAnnotation  = "synthesis.io/upgrade-to"
+	UpgradeURLAnnotation = "synthesis.io/upgrade-url"
+)
+
+// Well-known labels every agent stamps on its node at registration,
+// detected from the binary it runs as. Pods on mixed-architecture
+// fleets constrain placement with a nodeSelector on ArchLabel, which
+// the scheduler matches like any other label.
+const (
+	ArchLabel = "kubernetes.io/arch"
+	OSLabel   = "kubernetes.io/os"
+)
+
+// NodeConditionType identifies a class of node condition.
+type NodeConditionType string
+
+const (
+	// NodeReady means the node is healthy and accepting pods.
+	NodeReady NodeConditionType = "Ready"
+
+	// NodeMemoryPressure means available memory has crossed the agent's
+	// eviction threshold.
+	NodeMemoryPressure NodeConditionType = "MemoryPressure"
+
+	// NodeDiskPressure means root filesystem usage has crossed the
+	// agent's eviction threshold.
+	NodeDiskPressure NodeConditionType = "DiskPressure"
+)
+
+// NodeCondition is one observed aspect of node health.
+type NodeCondition struct {
+	Type               NodeConditionType `json:"type"`
+	Status             bool              `json:"status"`
+	Reason             string            `json:"reason,omitempty"`
+	Message            string            `json:"message,omitempty"`
+	LastTransitionTime time.Time         `json:"lastTransitionTime,omitempty"`
+}
+
+// NodeStatus is the observed state of a node.
+type NodeStatus struct {
+	Address     string          `json:"address,omitempty"`
+	Capacity    ResourceList    `json:"capacity,omitempty"`
+	Allocatable ResourceList    `json:"allocatable,omitempty"`
+	Conditions  []NodeCondition `json:"conditions,omitempty"`
+
+	// WireGuardPublicKey is the node's overlay mesh identity, reported
+	// by its agent so peers can add it to their WireGuard interface.
+	WireGuardPublicKey string `json:"wireguardPublicKey,omitempty"`
+
+	// AgentVersion is the build version of the agent running this node,
+	// reported at registration.
+	AgentVersion string `json:"agentVersion,omitempty"`
+}
+
+// Condition returns the condition of the given type, or nil.
+func (s *NodeStatus) Condition(t NodeConditionType) *NodeCondition {
+	for i := range s.Conditions {
+		if s.Conditions[i].Type == t {
+			return &s.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// Ready reports whether the node's Ready condition is true. A node with
+// no recorded conditions is treated as ready so a bare single-node setup
+// keeps working.
+func (n *Node) Ready() bool {
+	c := n.Status.Condition(NodeReady)
+	return c == nil || c.Status
+}
@@ -0,0 +1,460 @@
+package api
+
+import "time"
+
+// Pod is the smallest schedulable unit: one or more containers that share
+// a network namespace and are placed on a single node together.
+type Pod struct {
+	Meta   ObjectMeta `json:"metadata"`
+	Spec   PodSpec    `json:"spec"`
+	Status PodStatus  `json:"status,omitempty"`
+}
+
+// StaticPodAnnotation carries the node name on the API mirror of a
+// static pod — one defined in a file on the node's agent rather than
+// through the API. The agent owns the mirror: it recreates it when it
+// is deleted and reverts edits, so to the API the object is read-only.
+const StaticPodAnnotation = "synthesis.io/static-pod"
+
+// EvictionGracePeriodAnnotation carries the termination grace period a
+// voluntary eviction granted the pod, as a Go duration string. Its
+// presence marks the pod as terminating: the owning node's agent stops
+// the containers within the window and then deletes the pod, and
+// disruption budgets no longer count the pod as available.
+const EvictionGracePeriodAnnotation = "synthesis.io/eviction-grace-period"
+
+// PodSpec describes the desired containers and placement constraints.
+type PodSpec struct {
+	// InitContainers run in order before Containers start. An init
+	// container whose RestartPolicy is Always is a sidecar: it only has
+	// to be running, not finished, for startup to proceed, and it is
+	// kept running for the pod's whole life.
+	InitContainers []Container `json:"initContainers,omitempty"`
+
+	Containers []Container `json:"containers"`
+
+	// NodeName pins the pod to a node; the scheduler leaves it alone when set.
+	NodeName string `json:"nodeName,omitempty"`
+
+	// SchedulingGates hold the pod back from scheduling: while any gate
+	// is present the pod stays Pending and the scheduler skips it. CI
+	// pipelines use gates to create a pod ahead of an external
+	// precondition and release it once the precondition is met.
+	SchedulingGates []SchedulingGate `json:"schedulingGates,omitempty"`
+
+	// ServiceAccountName is the identity the pod runs as; empty means
+	// the namespace's "default" service account.
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// NodeSelector requires every listed label to be present on the node.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Affinity holds node and inter-pod (anti-)affinity terms.
+	Affinity *Affinity `json:"affinity,omitempty"`
+
+	// TopologySpreadConstraints spread replicas across node topology
+	// domains such as racks or zones.
+	TopologySpreadConstraints []TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// HostNetwork runs the pod in the node's network namespace.
+	HostNetwork bool `json:"hostNetwork,omitempty"`
+
+	// Hostname overrides the pod name as the containers' hostname.
+	Hostname string `json:"hostname,omitempty"`
+
+	// Subdomain extends the hostname to a fully qualified name of the
+	// form hostname.subdomain.namespace.svc.<cluster domain>.
+	Subdomain string `json:"subdomain,omitempty"`
+
+	// HostAliases are fixed /etc/hosts entries written into every
+	// container, for names that must resolve without DNS.
+	HostAliases []HostAlias `json:"hostAliases,omitempty"`
+
+	// DNSPolicy picks the container's resolv.conf source: ClusterFirst
+	// (the default) points at the cluster DNS with cluster search
+	// domains, Default inherits the node's resolver, None starts from
+	// scratch with only what DNSConfig provides.
+	DNSPolicy DNSPolicy `json:"dnsPolicy,omitempty"`
+
+	// DNSConfig adds nameservers, search domains and resolver options
+	// on top of whatever DNSPolicy chose.
+	DNSConfig *PodDNSConfig `json:"dnsConfig,omitempty"`
+
+	// EphemeralContainers are debug containers injected into a running
+	// pod. They share the pod's namespaces, are never restarted, and do
+	// not count toward the pod phase.
+	EphemeralContainers []Container `json:"ephemeralContainers,omitempty"`
+
+	// PriorityClassName names the PriorityClass this pod schedules
+	// under; empty falls back to the global default class, or zero.
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// RestartPolicy governs what happens to containers that exit:
+	// Always (the default) recreates them, OnFailure recreates only
+	// after a non-zero exit, Never leaves them down. One-shot pods with
+	// OnFailure or Never reach the Succeeded or Failed phase.
+	RestartPolicy RestartPolicy `json:"restartPolicy,omitempty"`
+
+	// Volumes are storage definitions containers may mount.
+	Volumes []Volume `json:"volumes,omitempty"`
+}
+
+// SchedulingGate names one precondition a pod is waiting on before it
+// may schedule.
+type SchedulingGate struct {
+	Name string `json:"name"`
+}
+
+// Gated reports whether any scheduling gate is still present.
+func (s *PodSpec) Gated() bool { return len(s.SchedulingGates) > 0 }
+
+// Volume is a named piece of storage available to a pod's containers.
+// Exactly one source should be set.
+type Volume struct {
+	Name      string           `json:"name"`
+	HostPath  *HostPathVolume  `json:"hostPath,omitempty"`
+	EmptyDir  *EmptyDirVolume  `json:"emptyDir,omitempty"`
+	Projected *ProjectedVolume `json:"projected,omitempty"`
+	Ephemeral *EphemeralVolume `json:"ephemeral,omitempty"`
+	NFS       *NFSVolume       `json:"nfs,omitempty"`
+	CIFS      *CIFSVolume      `json:"cifs,omitempty"`
+}
+
+// EphemeralVolume provisions a fresh engine-managed volume living and
+// dying with the pod — a disk emptyDir declared as a claim, so
+// manifests written for PVC-backed clusters carry over unchanged.
+type EphemeralVolume struct{}
+
+// NFSVolume mounts an NFS export. The node's agent performs the mount,
+// so the host needs NFS client support but containers need nothing.
+type NFSVolume struct {
+	Server string `json:"server"`
+	Path   string `json:"path"`
+
+	// MountOptions pass through to mount -o verbatim.
+	MountOptions []string `json:"mountOptions,omitempty"`
+}
+
+// CIFSVolume mounts an SMB/CIFS share, the usual shape of shared media
+// directories on a NAS.
+type CIFSVolume struct {
+	// Source is the UNC path, e.g. //nas/media.
+	Source string `json:"source"`
+
+	// CredentialsSecret names a secret in the pod's namespace holding
+	// username and password keys; empty mounts as guest.
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+
+	// MountOptions pass through to mount -o verbatim.
+	MountOptions []string `json:"mountOptions,omitempty"`
+}
+
+// HostPathVolume exposes a node directory to the pod.
+type HostPathVolume struct {
+	Path string `json:"path"`
+}
+
+// EmptyDirMemory backs an emptyDir with tmpfs, keeping its contents
+// off disk.
+const EmptyDirMemory = "Memory"
+
+// EmptyDirVolume is scratch space created with the pod and removed with
+// it.
+type EmptyDirVolume struct {
+	// Medium selects the backing: empty for an engine-managed disk
+	// volume, EmptyDirMemory for tmpfs.
+	Medium string `json:"medium,omitempty"`
+
+	// SizeLimit caps the volume in bytes. The engine enforces it only
+	// for the Memory medium; zero means unbounded.
+	SizeLimit int64 `json:"sizeLimit,omitempty"`
+}
+
+// ProjectedVolume combines several read-only sources into one volume,
+// so a container mounts a single directory of secret data, pod facts
+// and a service account token.
+type ProjectedVolume struct {
+	Sources []VolumeProjection `json:"sources,omitempty"`
+}
+
+// VolumeProjection is one source of files in a projected volume.
+// Exactly one field should be set.
+type VolumeProjection struct {
+	Secret              *SecretProjection              `json:"secret,omitempty"`
+	DownwardAPI         *DownwardAPIProjection         `json:"downwardAPI,omitempty"`
+	ServiceAccountToken *ServiceAccountTokenProjection `json:"serviceAccountToken,omitempty"`
+}
+
+// SecretProjection writes a secret's data as files. Without Items
+// every key becomes a file named after it.
+type SecretProjection struct {
+	Name  string      `json:"name"`
+	Items []KeyToPath `json:"items,omitempty"`
+}
+
+// KeyToPath maps one data key to a relative path within the volume.
+type KeyToPath struct {
+	Key  string `json:"key"`
+	Path string `json:"path"`
+}
+
+// DownwardAPIProjection exposes pod fields as files.
+type DownwardAPIProjection struct {
+	Items []DownwardAPIFile `json:"items,omitempty"`
+}
+
+// DownwardAPIFile writes one pod field to one file. Supported refs are
+// metadata.name, metadata.namespace, spec.nodeName and
+// spec.serviceAccountName.
+type DownwardAPIFile struct {
+	Path     string `json:"path"`
+	FieldRef string `json:"fieldRef"`
+}
+
+// ServiceAccountTokenProjection writes a bearer token for the pod's
+// service account, refreshed well before it expires.
+type ServiceAccountTokenProjection struct {
+	// Path is the file name within the volume; defaults to "token".
+	Path string `json:"path,omitempty"`
+}
+
+// VolumeMount attaches a pod volume inside a container.
+type VolumeMount struct {
+	Name      string `json:"name"`
+	MountPath string `json:"mountPath"`
+	ReadOnly  bool   `json:"readOnly,omitempty"`
+
+	// SubPath mounts only this path within the volume instead of its
+	// root, so a single file can land in an existing directory.
+	SubPath string `json:"subPath,omitempty"`
+}
+
+// SecurityContext holds a container's privilege settings.
+type SecurityContext struct {
+	Privileged               bool  `json:"privileged,omitempty"`
+	AllowPrivilegeEscalation *bool `json:"allowPrivilegeEscalation,omitempty"`
+	RunAsNonRoot             bool  `json:"runAsNonRoot,omitempty"`
+	RunAsUser                *int  `json:"runAsUser,omitempty"`
+}
+
+// Container is a single container within a pod.
+type Container struct {
+	Name            string               `json:"name"`
+	Image           string               `json:"image"`
+	ImagePullPolicy PullPolicy           `json:"imagePullPolicy,omitempty"`
+	Command         []string             `json:"command,omitempty"`
+	Args            []string             `json:"args,omitempty"`
+	Env             map[string]string    `json:"env,omitempty"`
+	Ports           []ContainerPort      `json:"ports,omitempty"`
+	Resources       ResourceRequirements `json:"resources,omitempty"`
+	VolumeMounts    []VolumeMount        `json:"volumeMounts,omitempty"`
+	SecurityContext *SecurityContext     `json:"securityContext,omitempty"`
+
+	// RestartPolicy is only meaningful on init containers: Always
+	// turns the init container into a sidecar.
+	RestartPolicy *RestartPolicy `json:"restartPolicy,omitempty"`
+}
+
+// Sidecar reports whether this container, when listed as an init
+// container, should keep running alongside the main containers.
+func (c *Container) Sidecar() bool {
+	return c.RestartPolicy != nil && *c.RestartPolicy == RestartAlways
+}
+
+// ContainerPort exposes a container port, optionally on the host.
+type ContainerPort struct {
+	Name          string `json:"name,omitempty"`
+	ContainerPort int    `json:"containerPort"`
+	HostPort      int    `json:"hostPort,omitempty"`
+	Protocol      string `json:"protocol,omitempty"`
+}
+
+// ResourceRequirements carries requests and limits in canonical units:
+// millicores for CPU, bytes for memory.
+type ResourceRequirements struct {
+	Requests ResourceList `json:"requests,omitempty"`
+	Limits   ResourceList `json:"limits,omitempty"`
+}
+
+// ResourceList maps a resource name ("cpu", "memory") to a quantity.
+type ResourceList map[string]int64
+
+// HostAlias maps one IP to the hostnames that should resolve to it
+// inside the pod.
+type HostAlias struct {
+	IP        string   `json:"ip"`
+	Hostnames []string `json:"hostnames"`
+}
+
+// DNSPolicy names the base resolver configuration for a pod.
+type DNSPolicy string
+
+const (
+	DNSClusterFirst DNSPolicy = "ClusterFirst"
+	DNSDefault      DNSPolicy = "Default"
+	DNSNone         DNSPolicy = "None"
+)
+
+// PodDNSConfig is extra resolver configuration merged into the pod's
+// resolv.conf. Options use resolv.conf syntax, e.g. "ndots:2".
+type PodDNSConfig struct {
+	Nameservers []string `json:"nameservers,omitempty"`
+	Searches    []string `json:"searches,omitempty"`
+	Options     []string `json:"options,omitempty"`
+}
+
+// PullPolicy says when a container's image is fetched from its
+// registry. Empty behaves like PullAlways, the historical default.
+type PullPolicy string
+
+const (
+	// PullAlways fetches the image before the container first starts.
+	PullAlways PullPolicy = "Always"
+
+	// PullIfNotPresent pulls only when the node's image store has no
+	// copy, so preloaded or cached images start without registry access.
+	PullIfNotPresent PullPolicy = "IfNotPresent"
+
+	// PullNever starts only from images already on the node.
+	PullNever PullPolicy = "Never"
+)
+
+// RestartPolicy says whether a pod's exited containers are recreated.
+type RestartPolicy string
+
+const (
+	RestartAlways    RestartPolicy = "Always"
+	RestartOnFailure RestartPolicy = "OnFailure"
+	RestartNever     RestartPolicy = "Never"
+)
+
+// PodPhase is the coarse lifecycle state of a pod.
+type PodPhase string
+
+const (
+	PodPending   PodPhase = "Pending"
+	PodRunning   PodPhase = "Running"
+	PodSucceeded PodPhase = "Succeeded"
+	PodFailed    PodPhase = "Failed"
+)
+
+// PodStatus is the observed state of a pod.
+type PodStatus struct {
+	Phase    PodPhase `json:"phase,omitempty"`
+	NodeName string   `json:"nodeName,omitempty"`
+	PodIP    string   `json:"podIP,omitempty"`
+	Message  string   `json:"message,omitempty"`
+
+	// QOSClass is derived from the containers' requests and limits;
+	// see QoSOf.
+	QOSClass QoSClass `json:"qosClass,omitempty"`
+
+	// ContainerStatuses track each main container across restarts.
+	ContainerStatuses []ContainerStatus `json:"containerStatuses,omitempty"`
+}
+
+// Restarts sums the restart counts across the pod's containers.
+func (s *PodStatus) Restarts() int {
+	total := 0
+	for _, cs := range s.ContainerStatuses {
+		total += cs.RestartCount
+	}
+	return total
+}
+
+// ContainerStatus is the observed state of one spec container. The
+// restart count and last termination survive container replacement, so
+// clients can tell a healthy pod from one restarting every 30 seconds.
+type ContainerStatus struct {
+	Name    string `json:"name"`
+	Running bool   `json:"running"`
+
+	// RestartCount is how many times the container has been replaced
+	// since the pod landed on its node.
+	RestartCount int `json:"restartCount"`
+
+	// StartedAt is when the current container was created; zero while
+	// the container is down.
+	StartedAt time.Time `json:"startedAt,omitempty"`
+
+	// LastTermination records how the previous run ended.
+	LastTermination *ContainerTermination `json:"lastTermination,omitempty"`
+}
+
+// ContainerTermination records one container exit.
+type ContainerTermination struct {
+	ExitCode int `json:"exitCode"`
+
+	// Reason is Completed for clean exits and Error otherwise.
+	Reason string `json:"reason,omitempty"`
+
+	FinishedAt time.Time `json:"finishedAt,omitempty"`
+}
+
+// Affinity groups the scheduling affinity terms of a pod.
+type Affinity struct {
+	NodeAffinity    *NodeAffinity `json:"nodeAffinity,omitempty"`
+	PodAffinity     *PodAffinity  `json:"podAffinity,omitempty"`
+	PodAntiAffinity *PodAffinity  `json:"podAntiAffinity,omitempty"`
+}
+
+// NodeAffinity constrains which nodes a pod may land on by node labels.
+// Required terms are hard filters; preferred terms only affect scoring.
+type NodeAffinity struct {
+	Required  []NodeSelectorTerm          `json:"required,omitempty"`
+	Preferred []PreferredNodeSelectorTerm `json:"preferred,omitempty"`
+}
+
+// NodeSelectorTerm is a conjunction of match expressions over node labels.
+type NodeSelectorTerm struct {
+	MatchExpressions []LabelSelectorRequirement `json:"matchExpressions,omitempty"`
+}
+
+// Matches reports whether every expression in the term matches the labels.
+func (t NodeSelectorTerm) Matches(labels map[string]string) bool {
+	for _, req := range t.MatchExpressions {
+		if !req.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// PreferredNodeSelectorTerm is a weighted soft node affinity term.
+type PreferredNodeSelectorTerm struct {
+	Weight int              `json:"weight"`
+	Term   NodeSelectorTerm `json:"term"`
+}
+
+// PodAffinity matches against pods already placed in a topology domain.
+// The same shape serves both affinity and anti-affinity.
+type PodAffinity struct {
+	Required []PodAffinityTerm `json:"required,omitempty"`
+}
+
+// PodAffinityTerm selects peer pods and names the node label whose value
+// defines the topology domain (e.g. "topology.synthesis.io/zone").
+type PodAffinityTerm struct {
+	Selector    *LabelSelector `json:"selector,omitempty"`
+	TopologyKey string         `json:"topologyKey"`
+}
+
+// UnsatisfiableConstraintAction says what to do when a spread constraint
+// cannot be met.
+type UnsatisfiableConstraintAction string
+
+const (
+	// DoNotSchedule treats the constraint as a hard filter.
+	DoNotSchedule UnsatisfiableConstraintAction = "DoNotSchedule"
+	// ScheduleAnyway treats the constraint as a scoring preference.
+	ScheduleAnyway UnsatisfiableConstraintAction = "ScheduleAnyway"
+)
+
+// TopologySpreadConstraint limits the skew of matching pods across the
+// topology domains named by TopologyKey.
+type TopologySpreadConstraint struct {
+	MaxSkew           int                           `json:"maxSkew"`
+	TopologyKey       string                        `json:"topologyKey"`
+	WhenUnsatisfiable UnsatisfiableConstraintAction `json:"whenUnsatisfiable"`
+	Selector          *LabelSelector                `json:"selector,omitempty"`
+}
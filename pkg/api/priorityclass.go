@@ -0,0 +1,17 @@
+package api
+
+// PriorityClass maps a name to an integer scheduling priority. Pods
+// reference a class by name; higher values schedule first and may
+// preempt lower ones when nodes are full.
+type PriorityClass struct {
+	Meta ObjectMeta `json:"metadata"`
+
+	// Value is the priority given to pods of this class.
+	Value int `json:"value"`
+
+	// GlobalDefault applies this class to pods that name none. At most
+	// one class should set it.
+	GlobalDefault bool `json:"globalDefault,omitempty"`
+
+	Description string `json:"description,omitempty"`
+}
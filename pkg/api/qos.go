@@ -0,0 +1,43 @@
+package api
+
+// QoSClass ranks how firmly a pod's resources are promised, which
+// decides who is evicted first under node pressure.
+type QoSClass string
+
+const (
+	// QoSGuaranteed pods set limits equal to requests on every
+	// container; they are evicted last.
+	QoSGuaranteed QoSClass = "Guaranteed"
+
+	// QoSBurstable pods request something but may use more.
+	QoSBurstable QoSClass = "Burstable"
+
+	// QoSBestEffort pods request nothing and are evicted first.
+	QoSBestEffort QoSClass = "BestEffort"
+)
+
+// QoSOf derives a pod's QoS class from its containers' requests and
+// limits, the way Kubernetes does.
+func QoSOf(pod *Pod) QoSClass {
+	guaranteed := true
+	requested := false
+	for _, c := range pod.Spec.Containers {
+		for _, resource := range []string{ResourceCPU, ResourceMemory} {
+			request, limit := c.Resources.Requests[resource], c.Resources.Limits[resource]
+			if request > 0 || limit > 0 {
+				requested = true
+			}
+			if request == 0 || request != limit {
+				guaranteed = false
+			}
+		}
+	}
+	switch {
+	case guaranteed && len(pod.Spec.Containers) > 0:
+		return QoSGuaranteed
+	case requested:
+		return QoSBurstable
+	default:
+		return QoSBestEffort
+	}
+}
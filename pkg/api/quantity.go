@@ -0,0 +1,100 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// quantitySuffixes maps Kubernetes quantity suffixes to multipliers:
+// binary (Ki through Ei), decimal (k through E) and milli.
+var quantitySuffixes = map[string]float64{
+	"":   1,
+	"m":  1e-3,
+	"k":  1e3,
+	"M":  1e6,
+	"G":  1e9,
+	"T":  1e12,
+	"P":  1e15,
+	"E":  1e18,
+	"Ki": 1 << 10,
+	"Mi": 1 << 20,
+	"Gi": 1 << 30,
+	"Ti": 1 << 40,
+	"Pi": 1 << 50,
+	"Ei": 1 << 60,
+}
+
+// ParseQuantity converts a Kubernetes-style quantity string into the
+// canonical unit for the named resource: millicores for cpu, plain
+// units (bytes, counts) for everything else. So for cpu, "1.5" and
+// "1500m" both come back as 1500; for memory, "1Gi" is 1073741824 and
+// "1G" is 1000000000. Fractions round up, matching how Kubernetes
+// never rounds a request below what was asked for.
+func ParseQuantity(resource, s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty quantity")
+	}
+	// The number is the longest prefix ending in a digit; what follows
+	// is the suffix. Exponent forms like "12e3" have no suffix and are
+	// left whole for ParseFloat.
+	end := strings.LastIndexFunc(s, func(r rune) bool { return r >= '0' && r <= '9' }) + 1
+	if end == 0 {
+		return 0, fmt.Errorf("malformed quantity %q", s)
+	}
+	number, suffix := s[:end], s[end:]
+	mult, ok := quantitySuffixes[suffix]
+	if !ok {
+		return 0, fmt.Errorf("unknown quantity suffix %q in %q", suffix, s)
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed quantity %q", s)
+	}
+	canonical := value * mult
+	if resource == ResourceCPU {
+		canonical *= 1000
+	}
+	if canonical < 0 {
+		return 0, fmt.Errorf("quantity %q must not be negative", s)
+	}
+	if canonical > math.MaxInt64 {
+		return 0, fmt.Errorf("quantity %q overflows", s)
+	}
+	return int64(math.Ceil(canonical)), nil
+}
+
+// UnmarshalJSON accepts both canonical integers and quantity strings,
+// so manifests can say cpu: "500m" or memory: 1Gi where the API's
+// native unit is an int64.
+func (r *ResourceList) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	out := make(ResourceList, len(raw))
+	for name, v := range raw {
+		if len(v) > 0 && v[0] == '"' {
+			var s string
+			if err := json.Unmarshal(v, &s); err != nil {
+				return err
+			}
+			qty, err := ParseQuantity(name, s)
+			if err != nil {
+				return fmt.Errorf("resource %s: %w", name, err)
+			}
+			out[name] = qty
+			continue
+		}
+		var qty int64
+		if err := json.Unmarshal(v, &qty); err != nil {
+			return fmt.Errorf("resource %s: %w", name, err)
+		}
+		out[name] = qty
+	}
+	*r = out
+	return nil
+}
@@ -0,0 +1,52 @@
+package api
+
+// Role grants verbs on resources. A Role with a namespace only covers
+// objects in that namespace; a Role without one is cluster-wide — the
+// ClusterRole of larger systems, without a second type to store.
+type Role struct {
+	Meta  ObjectMeta   `json:"metadata"`
+	Rules []PolicyRule `json:"rules"`
+}
+
+// PolicyRule is one grant inside a Role. "*" matches any verb or
+// resource.
+type PolicyRule struct {
+	// Verbs are API verbs: get, list, create, update, delete.
+	Verbs []string `json:"verbs"`
+
+	// Resources are plural resource names: pods, nodes, roles, ...
+	Resources []string `json:"resources"`
+}
+
+// Allows reports whether the rule covers the verb and resource.
+func (r PolicyRule) Allows(verb, resource string) bool {
+	return matchAny(r.Verbs, verb) && matchAny(r.Resources, resource)
+}
+
+func matchAny(patterns []string, value string) bool {
+	for _, p := range patterns {
+		if p == "*" || p == value {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleBinding grants a Role to a set of subjects. Binding and role
+// scope follow the binding's namespace: a namespaced binding may refer
+// to a role in its namespace or to a cluster-wide one.
+type RoleBinding struct {
+	Meta ObjectMeta `json:"metadata"`
+
+	// RoleName names the bound Role.
+	RoleName string `json:"roleName"`
+
+	// Subjects are usernames (or "system:serviceaccount:<ns>:<name>"
+	// identities); "*" grants to everyone authenticated.
+	Subjects []string `json:"subjects"`
+}
+
+// HasSubject reports whether the binding names the user.
+func (b *RoleBinding) HasSubject(user string) bool {
+	return matchAny(b.Subjects, user)
+}
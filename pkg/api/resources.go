@@ -0,0 +1,55 @@
+package api
+
+// Canonical resource names. CPU is measured in millicores, memory and
+// ephemeral storage in bytes, pods as a plain count.
+const (
+	ResourceCPU              = "cpu"
+	ResourceMemory           = "memory"
+	ResourceEphemeralStorage = "ephemeral-storage"
+	ResourcePods             = "pods"
+)
+
+// Usage-only resource names that appear in metrics samples but are
+// never requested or limited: cumulative network bytes received and
+// sent since the container started.
+const (
+	ResourceNetworkRx = "network-rx"
+	ResourceNetworkTx = "network-tx"
+)
+
+// Add returns a new list with the quantities of both lists summed.
+func (r ResourceList) Add(other ResourceList) ResourceList {
+	out := make(ResourceList, len(r))
+	for k, v := range r {
+		out[k] = v
+	}
+	for k, v := range other {
+		out[k] += v
+	}
+	return out
+}
+
+// Sub returns a new list with other's quantities subtracted from r.
+// Results may go negative; callers decide whether that matters.
+func (r ResourceList) Sub(other ResourceList) ResourceList {
+	out := make(ResourceList, len(r))
+	for k, v := range r {
+		out[k] = v
+	}
+	for k, v := range other {
+		out[k] -= v
+	}
+	return out
+}
+
+// PodRequests sums the resource requests of every container in the pod,
+// counting the pod itself against the "pods" resource.
+func PodRequests(pod *Pod) ResourceList {
+	total := ResourceList{ResourcePods: 1}
+	for _, c := range pod.Spec.Containers {
+		for name, qty := range c.Resources.Requests {
+			total[name] += qty
+		}
+	}
+	return total
+}
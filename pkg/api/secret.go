@@ -0,0 +1,34 @@
+package api
+
+// Secret carries confidential key/value data. In transit Data holds the
+// plaintext; at rest the server replaces it with an envelope sealed by
+// the configured KMS provider.
+type Secret struct {
+	Meta ObjectMeta `json:"metadata"`
+
+	// Data maps keys to values; base64 in JSON.
+	Data map[string][]byte `json:"data,omitempty"`
+
+	// Encrypted is the sealed form of Data. Set by the server on
+	// storage, never by clients.
+	Encrypted *SecretEnvelope `json:"encrypted,omitempty"`
+}
+
+// SecretEnvelope is an envelope-encrypted payload: a fresh data key
+// encrypts the payload, and the KMS provider wraps the data key. Key
+// compromise therefore exposes one secret version, not the data dir.
+type SecretEnvelope struct {
+	// Provider names the KMS provider that wrapped the data key.
+	Provider string `json:"provider"`
+
+	// KeyID identifies the provider key used, so old envelopes stay
+	// readable across rotations.
+	KeyID string `json:"keyID"`
+
+	// WrappedKey is the data key, encrypted by the provider.
+	WrappedKey []byte `json:"wrappedKey"`
+
+	// Ciphertext and Nonce are the AES-GCM sealed payload.
+	Ciphertext []byte `json:"ciphertext"`
+	Nonce      []byte `json:"nonce"`
+}
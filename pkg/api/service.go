@@ -0,0 +1,128 @@
+package api
+
+// Service exposes the pods matching its selector on stable ports.
+// Synthesis has no virtual cluster IPs: endpoints resolve to node
+// addresses, and each node's agent can proxy a service's node port to
+// wherever the backends run.
+type Service struct {
+	Meta   ObjectMeta    `json:"metadata"`
+	Spec   ServiceSpec   `json:"spec"`
+	Status ServiceStatus `json:"status,omitempty"`
+}
+
+// ServiceStatus is the observed state of a service.
+type ServiceStatus struct {
+	LoadBalancer LoadBalancerStatus `json:"loadBalancer,omitempty"`
+}
+
+// LoadBalancerStatus lists the external addresses handed to a
+// LoadBalancer service.
+type LoadBalancerStatus struct {
+	Ingress []LoadBalancerIngress `json:"ingress,omitempty"`
+}
+
+// LoadBalancerIngress is one allocated external address.
+type LoadBalancerIngress struct {
+	IP string `json:"ip"`
+}
+
+// ServiceLoadBalancer asks for an external address from the server's
+// configured pool, announced on the local network in layer-2 mode.
+const ServiceLoadBalancer = "LoadBalancer"
+
+// ServiceSpec declares which pods back the service and on which ports.
+type ServiceSpec struct {
+	// Type is empty for a plain node-port service or
+	// ServiceLoadBalancer for one with an allocated external address.
+	Type string `json:"type,omitempty"`
+
+	// Selector matches backing pods by their labels.
+	Selector map[string]string `json:"selector,omitempty"`
+
+	// Ports are the service's exposed ports. Multi-port services must
+	// name every port.
+	Ports []ServicePort `json:"ports,omitempty"`
+
+	// SessionAffinity set to AffinityClientIP pins each client address
+	// to one backend; empty or "None" spreads every connection.
+	SessionAffinity string `json:"sessionAffinity,omitempty"`
+
+	// SessionAffinityTimeoutSeconds is how long a client stays pinned
+	// after its last connection; zero defaults to three hours.
+	SessionAffinityTimeoutSeconds int `json:"sessionAffinityTimeoutSeconds,omitempty"`
+
+	// LoadBalancing selects how the proxy spreads connections:
+	// round-robin (the default when empty), least-connections or
+	// random.
+	LoadBalancing string `json:"loadBalancing,omitempty"`
+
+	// InternalTrafficPolicy set to "Local" restricts each node's proxy
+	// to backends on that same node, dropping traffic when there are
+	// none; empty or "Cluster" uses every backend.
+	InternalTrafficPolicy string `json:"internalTrafficPolicy,omitempty"`
+
+	// TopologyMode set to TopologyPreferLocal makes each node's proxy
+	// prefer same-node backends when any exist and fall back to the
+	// full set otherwise — locality without Local's hard failure mode.
+	TopologyMode string `json:"topologyMode,omitempty"`
+}
+
+// TopologyPreferLocal is the one supported topology mode.
+const TopologyPreferLocal = "PreferLocal"
+
+// AffinityClientIP is the one supported session affinity mode.
+const AffinityClientIP = "ClientIP"
+
+// Supported LoadBalancing values.
+const (
+	BalanceRoundRobin       = "round-robin"
+	BalanceLeastConnections = "least-connections"
+	BalanceRandom           = "random"
+)
+
+// ServicePort maps one exposed port onto a port of the backing pods.
+type ServicePort struct {
+	// Name distinguishes ports on multi-port services and is what
+	// named targetPorts resolve against.
+	Name string `json:"name,omitempty"`
+
+	// Port is the service's own port number.
+	Port int `json:"port"`
+
+	// TargetPort is where traffic lands on a backend: a number, or the
+	// name of a container port on any of the pod's containers. Empty
+	// defaults to Port.
+	TargetPort string `json:"targetPort,omitempty"`
+
+	// Protocol is TCP or UDP; empty means TCP.
+	Protocol string `json:"protocol,omitempty"`
+
+	// AppProtocol names the application protocol (http, grpc, ...) for
+	// tooling; the proxy does not interpret it.
+	AppProtocol string `json:"appProtocol,omitempty"`
+
+	// NodePort, when set, makes every node's agent listen on it and
+	// proxy to the service's endpoints.
+	NodePort int `json:"nodePort,omitempty"`
+}
+
+// Endpoint is one resolved backend of a service port: the address and
+// port traffic should be sent to for one backing pod.
+type Endpoint struct {
+	// IP is the address of the node running the pod.
+	IP string `json:"ip"`
+
+	NodeName string `json:"nodeName,omitempty"`
+
+	PodName string `json:"podName,omitempty"`
+
+	// Port is the resolved target port on that address.
+	Port int `json:"port"`
+
+	// PortName is the service port this endpoint backs; empty on
+	// single unnamed ports.
+	PortName string `json:"portName,omitempty"`
+
+	Protocol    string `json:"protocol,omitempty"`
+	AppProtocol string `json:"appProtocol,omitempty"`
+}
@@ -0,0 +1,17 @@
+package api
+
+// ServiceAccount is an in-cluster identity for pods. Tokens minted for
+// it authenticate as "system:serviceaccount:<namespace>:<name>", which
+// role bindings can grant against like any user.
+type ServiceAccount struct {
+	Meta ObjectMeta `json:"metadata"`
+}
+
+// ServiceAccountUsername returns the identity a service account token
+// authenticates as.
+func ServiceAccountUsername(namespace, name string) string {
+	if namespace == "" {
+		namespace = "default"
+	}
+	return "system:serviceaccount:" + namespace + ":" + name
+}
@@ -0,0 +1,31 @@
+package api
+
+import "time"
+
+// UsageRecord is one accounting period of a namespace's consumption,
+// written by the API server as agents push usage samples. Records are
+// the chargeback currency: integrated usage rather than point-in-time
+// samples, so platform teams can attribute shared hardware cost back
+// to tenants long after the metrics window has rolled over. The name
+// encodes the period start, one record per namespace per period.
+type UsageRecord struct {
+	Meta ObjectMeta `json:"metadata"`
+
+	// Start and End bound the period. End advances as samples arrive
+	// and stops when the next period opens.
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+
+	// CPUCoreSeconds is the CPU consumed over the period, in
+	// core-seconds: one core fully busy for one second is 1.0.
+	CPUCoreSeconds float64 `json:"cpuCoreSeconds"`
+
+	// MemoryByteHours is resident memory integrated over time: one byte
+	// held for one hour is 1.0.
+	MemoryByteHours float64 `json:"memoryByteHours"`
+
+	// NetworkRxBytes and NetworkTxBytes are the bytes the namespace's
+	// pods received and sent during the period.
+	NetworkRxBytes int64 `json:"networkRxBytes"`
+	NetworkTxBytes int64 `json:"networkTxBytes"`
+}
@@ -0,0 +1,97 @@
+package api
+
+import "time"
+
+// WorkloadKind distinguishes how a workload's replicas are identified.
+type WorkloadKind string
+
+const (
+	// WorkloadDeployment replicas are interchangeable and get
+	// random-suffixed names.
+	WorkloadDeployment WorkloadKind = "Deployment"
+
+	// WorkloadStatefulSet replicas keep stable ordinal identities
+	// (name-0 through name-N-1).
+	WorkloadStatefulSet WorkloadKind = "StatefulSet"
+)
+
+// WorkloadLabel is stamped onto every pod a workload creates, tying the
+// pod back to its owner.
+const WorkloadLabel = "synthesis.io/workload"
+
+// RestartedAtAnnotation carries the timestamp of the last restart verb.
+// On a workload's pod template it marks pods stamped before it for
+// rolling replacement; on a pod it tells the node's agent to recreate
+// containers started before it.
+const RestartedAtAnnotation = "synthesis.io/restartedAt"
+
+// Workload is a replicated set of pods stamped from a template. It is
+// the one controller-managed pod owner in Synthesis; Kind selects
+// Deployment or StatefulSet replica semantics.
+type Workload struct {
+	Meta   ObjectMeta     `json:"metadata"`
+	Spec   WorkloadSpec   `json:"spec"`
+	Status WorkloadStatus `json:"status,omitempty"`
+}
+
+// WorkloadSpec declares how many replicas of the template should run.
+type WorkloadSpec struct {
+	// Kind defaults to Deployment when empty.
+	Kind WorkloadKind `json:"kind,omitempty"`
+
+	Replicas int `json:"replicas"`
+
+	// Paused stops the controller from acting on spec changes: no pods
+	// are created, replaced or trimmed while set, so several edits
+	// (image, env, replicas) can be staged and rolled out together on
+	// resume.
+	Paused bool `json:"paused,omitempty"`
+
+	// Template is the pod stamped out per replica.
+	Template PodTemplate `json:"template"`
+}
+
+// PodTemplate carries the metadata and spec copied onto each replica.
+type PodTemplate struct {
+	Meta ObjectMeta `json:"metadata,omitempty"`
+	Spec PodSpec    `json:"spec"`
+}
+
+// WorkloadStatus is maintained by the workload controller.
+type WorkloadStatus struct {
+	// Replicas counts the non-terminal pods the workload owns.
+	Replicas int `json:"replicas"`
+
+	// ReadyReplicas counts the owned pods that are Running.
+	ReadyReplicas int `json:"readyReplicas"`
+}
+
+// EffectiveKind is the spec's kind with the Deployment default applied.
+func (w *Workload) EffectiveKind() WorkloadKind {
+	if w.Spec.Kind == "" {
+		return WorkloadDeployment
+	}
+	return w.Spec.Kind
+}
+
+// WorkloadSummary is the flattened row the workload list API serves:
+// one line per workload (or bare pod) with the fields an operator scans
+// for, instead of the raw typed object.
+type WorkloadSummary struct {
+	Kind      string   `json:"kind"`
+	Namespace string   `json:"namespace,omitempty"`
+	Name      string   `json:"name"`
+	Replicas  int      `json:"replicas"`
+	Ready     int      `json:"ready"`
+	Images    []string `json:"images,omitempty"`
+
+	// Status is the one-word rollup operators scan for: Ready,
+	// Progressing or Paused for workloads, the phase for bare pods.
+	Status string `json:"status,omitempty"`
+
+	// Node is where a bare pod runs; empty for workloads, whose pods
+	// may be spread across nodes.
+	Node string `json:"node,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+}
@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ptfpinho23/synthesis/pkg/manifest"
+	"github.com/ptfpinho23/synthesis/pkg/yaml"
+)
+
+// runApply submits manifests: -f takes a YAML/JSON file (or - for
+// stdin), -k runs kustomize build on a directory, --chart renders a
+// Helm chart first. Kinds Synthesis does not serve are skipped with a
+// warning so mixed manifests still apply.
+func (c *CLI) runApply(args []string) error {
+	fs := flag.NewFlagSet("apply", flag.ContinueOnError)
+	file := fs.String("f", "", "manifest file to apply, - reads stdin")
+	kustomizeDir := fs.String("k", "", "kustomization directory to build and apply")
+	fs.StringVar(kustomizeDir, "kustomize", "", "alias for -k")
+	chart := fs.String("chart", "", "Helm chart directory to render and apply")
+	valuesFile := fs.String("values", "", "values file layered over the chart's defaults")
+	release := fs.String("release-name", "", "release name for chart templating (default: the chart's name)")
+	fs.SetOutput(c.Err)
+	if err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	var docs [][]byte
+	var err error
+	switch {
+	case *chart != "":
+		docs, err = renderChart(*chart, *valuesFile, *release, c.Err)
+	case *kustomizeDir != "" || *file != "":
+		docs, err = c.gatherDocs(*file, *kustomizeDir)
+	default:
+		return usagef("usage: synthesis apply -f manifest.yaml | -k dir | --chart dir [--values v.yaml]")
+	}
+	if err != nil {
+		return err
+	}
+	for _, doc := range docs {
+		m, err := manifest.Decode(doc)
+		if err != nil {
+			return err
+		}
+		if !m.Supported {
+			fmt.Fprintf(c.Err, "warning: skipping unsupported kind %s (%s)\n", m.Kind, m.Name)
+			continue
+		}
+		if err := c.applyManifest(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runDiff compares manifests against the live objects. It reports
+// creates and changes; field-level hunks are out of scope.
+func (c *CLI) runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	file := fs.String("f", "", "manifest file to diff, - reads stdin")
+	kustomizeDir := fs.String("k", "", "kustomization directory to build and diff")
+	fs.StringVar(kustomizeDir, "kustomize", "", "alias for -k")
+	fs.SetOutput(c.Err)
+	if err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	docs, err := c.gatherDocs(*file, *kustomizeDir)
+	if err != nil {
+		return err
+	}
+	for _, doc := range docs {
+		m, err := manifest.Decode(doc)
+		if err != nil {
+			return err
+		}
+		if !m.Supported {
+			fmt.Fprintf(c.Err, "warning: skipping unsupported kind %s (%s)\n", m.Kind, m.Name)
+			continue
+		}
+		var live map[string]any
+		if err := c.request("GET", m.ObjectPath, nil, &live); err != nil {
+			fmt.Fprintf(c.Out, "%s: would create\n", m.ID())
+			continue
+		}
+		if manifestSubset(m.Object, live) {
+			fmt.Fprintf(c.Out, "%s: unchanged\n", m.ID())
+		} else {
+			fmt.Fprintf(c.Out, "%s: would change\n", m.ID())
+		}
+	}
+	return nil
+}
+
+// runDelete deletes objects, either named by manifests (-f/-k) or
+// directly on the command line (`delete pod/web-0`, `delete svc web`).
+func (c *CLI) runDelete(args []string) error {
+	fs := flag.NewFlagSet("delete", flag.ContinueOnError)
+	file := fs.String("f", "", "manifest file naming objects to delete, - reads stdin")
+	kustomizeDir := fs.String("k", "", "kustomization directory to build and delete")
+	fs.StringVar(kustomizeDir, "kustomize", "", "alias for -k")
+	namespace := fs.String("n", "default", "namespace of objects named on the command line")
+	fs.SetOutput(c.Err)
+	if err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if *file == "" && *kustomizeDir == "" && fs.NArg() > 0 {
+		return c.deleteTargets(fs.Args(), *namespace)
+	}
+	docs, err := c.gatherDocs(*file, *kustomizeDir)
+	if err != nil {
+		return err
+	}
+	for _, doc := range docs {
+		m, err := manifest.Decode(doc)
+		if err != nil {
+			return err
+		}
+		if !m.Supported {
+			fmt.Fprintf(c.Err, "warning: skipping unsupported kind %s (%s)\n", m.Kind, m.Name)
+			continue
+		}
+		if err := c.request("DELETE", m.ObjectPath, nil, nil); err != nil {
+			return fmt.Errorf("deleting %s: %w", m.ID(), err)
+		}
+		fmt.Fprintf(c.Out, "%s deleted\n", m.ID())
+	}
+	return nil
+}
+
+// gatherDocs reads manifests from a file or builds them with kustomize.
+func (c *CLI) gatherDocs(file, kustomizeDir string) ([][]byte, error) {
+	switch {
+	case kustomizeDir != "":
+		return kustomizeBuild(kustomizeDir)
+	case file == "-":
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		return yaml.SplitDocuments(data), nil
+	case file != "":
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		return yaml.SplitDocuments(data), nil
+	}
+	return nil, fmt.Errorf("one of -f or -k is required")
+}
+
+// applyManifest creates the object when it does not exist yet and
+// updates it otherwise, matching the POST/PUT split of the API.
+func (c *CLI) applyManifest(m *manifest.Manifest) error {
+	if m.CollectionPath == "" {
+		if err := c.request("PUT", m.ObjectPath, m.Object, nil); err != nil {
+			return fmt.Errorf("applying %s: %w", m.ID(), err)
+		}
+		fmt.Fprintf(c.Out, "%s applied\n", m.ID())
+		return nil
+	}
+	if err := c.request("GET", m.ObjectPath, nil, nil); err != nil {
+		if err := c.request("POST", m.CollectionPath, m.Object, nil); err != nil {
+			return fmt.Errorf("creating %s: %w", m.ID(), err)
+		}
+		fmt.Fprintf(c.Out, "%s created\n", m.ID())
+		return nil
+	}
+	if err := c.request("PUT", m.ObjectPath, m.Object, nil); err != nil {
+		return fmt.Errorf("updating %s: %w", m.ID(), err)
+	}
+	fmt.Fprintf(c.Out, "%s configured\n", m.ID())
+	return nil
+}
+
+// manifestSubset reports whether every field the manifest sets already
+// holds the same value in the live object. Server-populated fields
+// (status, timestamps) are ignored that way.
+func manifestSubset(want, live any) bool {
+	switch want := want.(type) {
+	case map[string]any:
+		liveMap, ok := live.(map[string]any)
+		if !ok {
+			return false
+		}
+		for k, v := range want {
+			if k == "apiVersion" || k == "kind" {
+				continue
+			}
+			if !manifestSubset(v, liveMap[k]) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		liveArr, ok := live.([]any)
+		if !ok || len(liveArr) != len(want) {
+			return false
+		}
+		for i := range want {
+			if !manifestSubset(want[i], liveArr[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return want == live
+	}
+}
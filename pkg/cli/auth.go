@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// runAuth dispatches the `synthesis auth ...` subcommands.
+func (c *CLI) runAuth(args []string) error {
+	if len(args) == 0 {
+		return usagef("usage: synthesis auth login|whoami|can-i ...")
+	}
+	switch args[0] {
+	case "login":
+		return c.authLogin(args[1:])
+	case "whoami":
+		return c.authWhoAmI(args[1:])
+	case "can-i":
+		return c.authCanI(args[1:])
+	default:
+		return usagef("unknown auth command %q", args[0])
+	}
+}
+
+// tokenPath is where `auth login` saves the bearer token and where Run
+// picks it up when neither --token nor SYNTHESIS_TOKEN is set.
+func tokenPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".synthesis", "token"), nil
+}
+
+// savedToken reads the token `auth login` stored, if any.
+func savedToken() string {
+	path, err := tokenPath()
+	if err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// authLogin verifies a bearer token against the server and saves it for
+// later invocations. The token comes from --token or stdin, so it need
+// not appear in shell history.
+func (c *CLI) authLogin(args []string) error {
+	fs := flag.NewFlagSet("auth login", flag.ContinueOnError)
+	token := fs.String("token", "", "bearer token to log in with (default: read from stdin)")
+	fs.SetOutput(c.Err)
+	if err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if *token == "" {
+		fmt.Fprint(c.Err, "token: ")
+		var line string
+		if _, err := fmt.Scanln(&line); err != nil {
+			return fmt.Errorf("reading token: %w", err)
+		}
+		*token = strings.TrimSpace(line)
+	}
+	if *token == "" {
+		return fmt.Errorf("no token given")
+	}
+
+	// Prove the token resolves to an identity before persisting it.
+	c.Token = *token
+	var id identity
+	if err := c.request("GET", "/api/v1/auth/whoami", nil, &id); err != nil {
+		return err
+	}
+	if id.User == "system:anonymous" {
+		return fmt.Errorf("server did not recognize the token")
+	}
+
+	path, err := tokenPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(*token+"\n"), 0o600); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "logged in as %s\n", id.User)
+	return nil
+}
+
+// identity is the server's answer to a whoami request.
+type identity struct {
+	User   string   `json:"user"`
+	Groups []string `json:"groups"`
+}
+
+// authWhoAmI prints the identity and groups the server resolves from
+// the current credentials.
+func (c *CLI) authWhoAmI(args []string) error {
+	if len(args) != 0 {
+		return usagef("usage: synthesis auth whoami")
+	}
+	var id identity
+	if err := c.request("GET", "/api/v1/auth/whoami", nil, &id); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "User:   %s\n", id.User)
+	fmt.Fprintf(c.Out, "Groups: %s\n", strings.Join(id.Groups, ", "))
+	return nil
+}
+
+// authCanI asks the server whether the current identity may perform a
+// verb on a resource. Exit status follows the answer: 0 yes, 1 no.
+func (c *CLI) authCanI(args []string) error {
+	fs := flag.NewFlagSet("auth can-i", flag.ContinueOnError)
+	namespace := fs.String("n", "", "namespace to check in")
+	fs.SetOutput(c.Err)
+	if err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return usagef("usage: synthesis auth can-i <verb> <resource> [-n namespace]")
+	}
+	verb, resource := fs.Arg(0), fs.Arg(1)
+
+	var result struct {
+		Allowed bool `json:"allowed"`
+	}
+	path := fmt.Sprintf("/api/v1/auth/can-i?verb=%s&resource=%s&namespace=%s",
+		url.QueryEscape(verb), url.QueryEscape(resource), url.QueryEscape(*namespace))
+	if err := c.request("GET", path, nil, &result); err != nil {
+		return err
+	}
+	if !result.Allowed {
+		fmt.Fprintln(c.Out, "no")
+		return fmt.Errorf("access denied")
+	}
+	fmt.Fprintln(c.Out, "yes")
+	return nil
+}
@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ptfpinho23/synthesis/pkg/yaml"
+)
+
+// renderChart templates a Helm-style chart directory locally: values.yaml
+// (overlaid by an optional --values file) feeds .Values, Chart.yaml feeds
+// .Chart, and every *.yaml under templates/ is rendered with text/template.
+// Templates that fail to parse or render are skipped with a warning so one
+// exotic template does not block the rest of the chart.
+func renderChart(dir, valuesFile, release string, warn io.Writer) ([][]byte, error) {
+	meta, err := chartValues(filepath.Join(dir, "Chart.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("reading Chart.yaml: %w", err)
+	}
+	chartName, _ := meta["name"].(string)
+	if chartName == "" {
+		chartName = filepath.Base(dir)
+	}
+	if release == "" {
+		release = chartName
+	}
+
+	values := map[string]any{}
+	if defaults, err := chartValues(filepath.Join(dir, "values.yaml")); err == nil {
+		values = defaults
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading values.yaml: %w", err)
+	}
+	if valuesFile != "" {
+		overrides, err := chartValues(valuesFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", valuesFile, err)
+		}
+		mergeValues(values, overrides)
+	}
+
+	ctx := map[string]any{
+		"Values": values,
+		"Chart":  meta,
+		"Release": map[string]any{
+			"Name":      release,
+			"Namespace": "default",
+			"Service":   "Synthesis",
+		},
+	}
+
+	templatesDir := filepath.Join(dir, "templates")
+	entries, err := os.ReadDir(templatesDir)
+	if err != nil {
+		return nil, fmt.Errorf("reading templates: %w", err)
+	}
+	var docs [][]byte
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || (!strings.HasSuffix(name, ".yaml") && !strings.HasSuffix(name, ".yml")) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(templatesDir, name))
+		if err != nil {
+			return nil, err
+		}
+		tmpl, err := template.New(name).Funcs(chartFuncs()).Parse(string(data))
+		if err != nil {
+			fmt.Fprintf(warn, "warning: skipping template %s: %v\n", name, err)
+			continue
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, ctx); err != nil {
+			fmt.Fprintf(warn, "warning: skipping template %s: %v\n", name, err)
+			continue
+		}
+		for _, doc := range yaml.SplitDocuments(buf.Bytes()) {
+			if len(bytes.TrimSpace(doc)) == 0 {
+				continue
+			}
+			docs = append(docs, doc)
+		}
+	}
+	return docs, nil
+}
+
+// chartValues reads a YAML file into a string-keyed map.
+func chartValues(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := yaml.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	if parsed == nil {
+		return map[string]any{}, nil
+	}
+	m, ok := parsed.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: expected a mapping at the top level", path)
+	}
+	return m, nil
+}
+
+// mergeValues overlays src onto dst, recursing into nested mappings the
+// way helm merges --values files over the chart defaults.
+func mergeValues(dst, src map[string]any) {
+	for k, v := range src {
+		if sub, ok := v.(map[string]any); ok {
+			if existing, ok := dst[k].(map[string]any); ok {
+				mergeValues(existing, sub)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+}
+
+// chartFuncs is the small slice of the helm/sprig function set our
+// charts actually use.
+func chartFuncs() template.FuncMap {
+	indent := func(n int, s string) string {
+		pad := strings.Repeat(" ", n)
+		return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+	}
+	return template.FuncMap{
+		"default": func(def, v any) any {
+			if v == nil || v == "" || v == false {
+				return def
+			}
+			return v
+		},
+		"required": func(msg string, v any) (any, error) {
+			if v == nil || v == "" {
+				return nil, fmt.Errorf("%s", msg)
+			}
+			return v, nil
+		},
+		"quote":   func(v any) string { return strconv.Quote(fmt.Sprint(v)) },
+		"upper":   strings.ToUpper,
+		"lower":   strings.ToLower,
+		"trim":    strings.TrimSpace,
+		"indent":  indent,
+		"nindent": func(n int, s string) string { return "\n" + indent(n, s) },
+		"toYaml": func(v any) string {
+			out, err := yaml.Marshal(v)
+			if err != nil {
+				return ""
+			}
+			return strings.TrimRight(string(out), "\n")
+		},
+		"keys": func(m map[string]any) []string {
+			out := make([]string, 0, len(m))
+			for k := range m {
+				out = append(out, k)
+			}
+			sort.Strings(out)
+			return out
+		},
+	}
+}
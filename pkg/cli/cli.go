@@ -0,0 +1,222 @@
+// Package cli implements the synthesis command-line client. Commands are
+// plain functions dispatched off the argument list; each owns a FlagSet
+// so `synthesis <cmd> -h` prints focused usage.
+package cli
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// defaultServer is used when neither --server nor SYNTHESIS_SERVER is set.
+const defaultServer = "http://127.0.0.1:8420"
+
+// CLI holds the flags and output streams shared by all subcommands.
+type CLI struct {
+	Server string
+	Out    io.Writer
+	Err    io.Writer
+
+	// CAFile is a PEM bundle to trust instead of the system roots,
+	// e.g. the self-signed CA a synthesis-server generates.
+	CAFile string
+
+	// InsecureSkipVerify disables server certificate checks entirely.
+	InsecureSkipVerify bool
+
+	// Token is the bearer token presented on every request.
+	Token string
+
+	httpc *http.Client
+
+	// resourceTable caches the alias table built from discovery.
+	resourceTable map[string]resource
+}
+
+// Run parses the argument list (without the program name) and executes
+// the matching subcommand, returning the process exit code.
+func Run(args []string) int {
+	c := &CLI{Server: os.Getenv("SYNTHESIS_SERVER"), Out: os.Stdout, Err: os.Stderr}
+	if c.Server == "" {
+		c.Server = defaultServer
+	}
+
+	fs := flag.NewFlagSet("synthesis", flag.ContinueOnError)
+	fs.StringVar(&c.Server, "server", c.Server, "API server address")
+	fs.StringVar(&c.CAFile, "certificate-authority", os.Getenv("SYNTHESIS_CA"),
+		"PEM CA bundle to verify the server against")
+	fs.BoolVar(&c.InsecureSkipVerify, "insecure-skip-tls-verify", false,
+		"skip server certificate verification (insecure)")
+	fs.StringVar(&c.Token, "token", os.Getenv("SYNTHESIS_TOKEN"), "bearer token for authentication")
+	fs.Usage = func() { c.usage(fs) }
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if c.Token == "" {
+		c.Token = savedToken()
+	}
+	rest := fs.Args()
+	if len(rest) == 0 {
+		c.usage(fs)
+		return exitUsage
+	}
+
+	var err error
+	switch rest[0] {
+	case "get":
+		err = c.runGet(rest[1:])
+	case "describe":
+		err = c.runDescribe(rest[1:])
+	case "logs":
+		err = c.runLogs(rest[1:])
+	case "cluster":
+		err = c.runCluster(rest[1:])
+	case "node":
+		err = c.runNode(rest[1:])
+	case "pod":
+		err = c.runPod(rest[1:])
+	case "top":
+		err = c.runTop(rest[1:])
+	case "usage":
+		err = c.runUsage(rest[1:])
+	case "auth":
+		err = c.runAuth(rest[1:])
+	case "debug":
+		err = c.runDebug(rest[1:])
+	case "workload":
+		err = c.runWorkload(rest[1:])
+	case "apply":
+		err = c.runApply(rest[1:])
+	case "diff":
+		err = c.runDiff(rest[1:])
+	case "delete":
+		err = c.runDelete(rest[1:])
+	case "image":
+		err = c.runImage(rest[1:])
+	case "proxy":
+		err = c.runProxy(rest[1:])
+	default:
+		fmt.Fprintf(c.Err, "unknown command %q\n", rest[0])
+		c.usage(fs)
+		return exitUsage
+	}
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintf(c.Err, "error: %v\n", err)
+	}
+	return exitCode(err)
+}
+
+func (c *CLI) usage(fs *flag.FlagSet) {
+	fmt.Fprintln(c.Err, "usage: synthesis [--server URL] <command> ...")
+	fmt.Fprintln(c.Err, "commands:")
+	fmt.Fprintln(c.Err, "  get <kinds> [name]      list resources: all, deploy,svc, pod/web-0")
+	fmt.Fprintln(c.Err, "  describe <kind>/<name>  dump one object and its events")
+	fmt.Fprintln(c.Err, "  logs <pod>|deploy/<name> tail container logs (-f, -l selector, --all-containers)")
+	fmt.Fprintln(c.Err, "  cluster upgrade         roll agents to a new version one node at a time")
+	fmt.Fprintln(c.Err, "  node cordon <name>      mark a node unschedulable")
+	fmt.Fprintln(c.Err, "  node uncordon <name>    mark a node schedulable")
+	fmt.Fprintln(c.Err, "  node drain <name>       cordon and evict the node's pods")
+	fmt.Fprintln(c.Err, "  pod hold <name>         gate a pod so it stays Pending")
+	fmt.Fprintln(c.Err, "  pod unhold <name>       lift a pod's scheduling gates")
+	fmt.Fprintln(c.Err, "  pod restart <name>      recreate a pod's containers in place")
+	fmt.Fprintln(c.Err, "  top nodes               show per-node resource usage")
+	fmt.Fprintln(c.Err, "  usage report            per-namespace usage totals for chargeback (--since 30d)")
+	fmt.Fprintln(c.Err, "  auth login [--token T]  verify a bearer token and save it for later runs")
+	fmt.Fprintln(c.Err, "  auth whoami             show the identity the server resolves for you")
+	fmt.Fprintln(c.Err, "  auth can-i <verb> <res> check whether you may perform an action")
+	fmt.Fprintln(c.Err, "  debug <pod> --image=IMG inject an ephemeral debug container")
+	fmt.Fprintln(c.Err, "  workload list [-o wide] list workloads and bare pods with readiness")
+	fmt.Fprintln(c.Err, "  workload rollout pause|resume|restart <name>  stage, roll out or restart")
+	fmt.Fprintln(c.Err, "  apply -f FILE           apply manifests (-k DIR kustomize, --chart DIR Helm)")
+	fmt.Fprintln(c.Err, "  diff -f FILE | -k DIR   compare manifests against the live objects")
+	fmt.Fprintln(c.Err, "  delete -f FILE | <kind>/<name>  delete objects by manifest or name")
+	fmt.Fprintln(c.Err, "  image pull <image>      pre-pull an image on cluster nodes")
+	fmt.Fprintln(c.Err, "  image load -f FILE      import an image tarball into this node's engine")
+	fmt.Fprintln(c.Err, "  image list|remove       manage this node's image store")
+	fmt.Fprintln(c.Err, "  proxy [--listen ADDR]   serve the API locally with auth injected")
+	fs.PrintDefaults()
+	fmt.Fprintln(c.Err, "exit codes: 0 success, 1 error, 2 usage, 3 not found, 4 conflict or timeout")
+}
+
+// client builds (once) the HTTP client honoring the TLS trust flags.
+func (c *CLI) client() (*http.Client, error) {
+	if c.httpc != nil {
+		return c.httpc, nil
+	}
+	tlsConf := &tls.Config{InsecureSkipVerify: c.InsecureSkipVerify}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", c.CAFile)
+		}
+		tlsConf.RootCAs = pool
+	}
+	c.httpc = &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConf}}
+	return c.httpc, nil
+}
+
+// request performs an API call and decodes the JSON response into out
+// when out is non-nil. Non-2xx responses become errors carrying the
+// server's message.
+func (c *CLI) request(method, path string, body, out any) error {
+	var rdr io.Reader
+	if body != nil {
+		buf, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		rdr = bytes.NewReader(buf)
+	}
+	req, err := http.NewRequest(method, c.Server+path, rdr)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		// The request ID ties this failure to its server log line.
+		suffix := ""
+		if id := resp.Header.Get("X-Request-Id"); id != "" {
+			suffix = fmt.Sprintf(" (request ID %s)", id)
+		}
+		var e struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&e) == nil && e.Error != "" {
+			return &apiError{status: resp.StatusCode, message: e.Error + suffix}
+		}
+		return &apiError{status: resp.StatusCode, message: "server returned " + resp.Status + suffix}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
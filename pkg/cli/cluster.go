@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// runCluster dispatches the `synthesis cluster ...` subcommands.
+func (c *CLI) runCluster(args []string) error {
+	if len(args) == 0 {
+		return usagef("usage: synthesis cluster upgrade --version vX.Y --binary-url URL")
+	}
+	switch args[0] {
+	case "upgrade":
+		return c.clusterUpgrade(args[1:])
+	default:
+		return usagef("unknown cluster command %q", args[0])
+	}
+}
+
+// clusterUpgrade rolls the agents to a new version one node at a time:
+// cordon, drain, annotate the node so its agent swaps binaries, wait
+// for it to come back Ready on the target version, uncordon, next. A
+// node that misses the deadline is rolled back to its previous version
+// and the upgrade stops there, leaving the remaining nodes untouched.
+func (c *CLI) clusterUpgrade(args []string) error {
+	fs := flag.NewFlagSet("cluster upgrade", flag.ContinueOnError)
+	target := fs.String("version", "", "version to upgrade the agents to, e.g. v0.9.0")
+	urlTemplate := fs.String("binary-url", "",
+		"agent binary URL with {version} replaced, e.g. https://get.example.com/synthesis-agent-{version}")
+	timeout := fs.Duration("timeout", 5*time.Minute,
+		"how long each node may take to come back healthy on the new version")
+	rollback := fs.Bool("rollback-on-failure", true,
+		"roll a node that misses the deadline back to its previous version")
+	fs.SetOutput(c.Err)
+	if err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if *target == "" || *urlTemplate == "" {
+		return usagef("usage: synthesis cluster upgrade --version vX.Y --binary-url URL")
+	}
+	if !strings.Contains(*urlTemplate, "{version}") {
+		return usagef("--binary-url must contain a {version} placeholder")
+	}
+
+	var nodes []api.Node
+	if err := c.request("GET", "/api/v1/nodes", nil, &nodes); err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		name := node.Meta.Name
+		if node.Status.AgentVersion == *target {
+			fmt.Fprintf(c.Out, "node/%s already at %s, skipping\n", name, *target)
+			continue
+		}
+		fmt.Fprintf(c.Out, "node/%s upgrading %s -> %s\n", name, orDash(node.Status.AgentVersion), *target)
+		if err := c.request("POST", "/api/v1/nodes/"+url.PathEscape(name)+"/cordon", nil, nil); err != nil {
+			return fmt.Errorf("cordoning node %s: %w", name, err)
+		}
+		if err := c.request("POST", "/api/v1/nodes/"+url.PathEscape(name)+"/drain", nil, nil); err != nil {
+			return fmt.Errorf("draining node %s: %w", name, err)
+		}
+		if err := c.annotateUpgrade(name, *target, *urlTemplate); err != nil {
+			return fmt.Errorf("requesting upgrade on node %s: %w", name, err)
+		}
+		if err := c.waitNodeVersion(name, *target, *timeout); err != nil {
+			previous := node.Status.AgentVersion
+			if *rollback && previous != "" {
+				fmt.Fprintf(c.Err, "node/%s unhealthy, rolling back to %s\n", name, previous)
+				if rerr := c.annotateUpgrade(name, previous, *urlTemplate); rerr != nil {
+					return fmt.Errorf("upgrading node %s: %w (rollback request also failed: %v)", name, err, rerr)
+				}
+				if rerr := c.waitNodeVersion(name, previous, *timeout); rerr != nil {
+					return fmt.Errorf("upgrading node %s: %w (rollback did not complete: %v)", name, err, rerr)
+				}
+				if rerr := c.request("POST", "/api/v1/nodes/"+url.PathEscape(name)+"/uncordon", nil, nil); rerr != nil {
+					fmt.Fprintf(c.Err, "warning: uncordoning node %s failed: %v\n", name, rerr)
+				}
+				return fmt.Errorf("upgrading node %s: %w (rolled back to %s, remaining nodes untouched)",
+					name, err, previous)
+			}
+			return fmt.Errorf("upgrading node %s: %w (node left cordoned)", name, err)
+		}
+		if err := c.request("POST", "/api/v1/nodes/"+url.PathEscape(name)+"/uncordon", nil, nil); err != nil {
+			return fmt.Errorf("uncordoning node %s: %w", name, err)
+		}
+		fmt.Fprintf(c.Out, "node/%s upgraded to %s\n", name, *target)
+	}
+	return nil
+}
+
+// annotateUpgrade stamps the upgrade annotations on a node so its agent
+// downloads and swaps to the given version.
+func (c *CLI) annotateUpgrade(name, version, urlTemplate string) error {
+	path := "/api/v1/nodes/" + url.PathEscape(name)
+	var node api.Node
+	if err := c.request("GET", path, nil, &node); err != nil {
+		return err
+	}
+	if node.Meta.Annotations == nil {
+		node.Meta.Annotations = map[string]string{}
+	}
+	node.Meta.Annotations[api.UpgradeToAnnotation] = version
+	node.Meta.Annotations[api.UpgradeURLAnnotation] = strings.ReplaceAll(urlTemplate, "{version}", version)
+	return c.request("PUT", path, node, nil)
+}
+
+// waitNodeVersion polls until the node reports Ready on the wanted
+// version, which means its agent restarted on the new binary and
+// re-registered.
+func (c *CLI) waitNodeVersion(name, version string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		var node api.Node
+		err := c.request("GET", "/api/v1/nodes/"+url.PathEscape(name), nil, &node)
+		if err == nil && node.Status.AgentVersion == version && node.Ready() {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			if err != nil {
+				return fmt.Errorf("node did not come back within %s: %w", timeout, err)
+			}
+			return fmt.Errorf("node did not reach %s healthy within %s (at %s)",
+				version, timeout, orDash(node.Status.AgentVersion))
+		}
+		time.Sleep(5 * time.Second)
+	}
+}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// runDebug injects an ephemeral debug container into a running pod, so
+// distroless images can be inspected without restarting anything.
+// Arguments after "--" become the container command.
+func (c *CLI) runDebug(args []string) error {
+	fs := flag.NewFlagSet("debug", flag.ContinueOnError)
+	namespace := fs.String("n", "default", "namespace of the pod")
+	image := fs.String("image", "busybox", "image the debug container runs")
+	name := fs.String("container", "", "debug container name (default debugger-<pod>)")
+	fs.SetOutput(c.Err)
+	if err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return usagef("usage: synthesis debug <pod> [--image IMG] [-n namespace] [-- command ...]")
+	}
+	pod := fs.Arg(0)
+	ec := api.Container{Name: *name, Image: *image, Command: fs.Args()[1:]}
+	if ec.Name == "" {
+		ec.Name = "debugger-" + pod
+	}
+
+	path := fmt.Sprintf("/api/v1/namespaces/%s/pods/%s/ephemeralcontainers",
+		url.PathEscape(*namespace), url.PathEscape(pod))
+	if err := c.request("POST", path, &ec, nil); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "ephemeral container %s created in pod %s\n", ec.Name, pod)
+	return nil
+}
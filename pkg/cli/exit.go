@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// Exit codes are a contract for scripts wrapping the CLI:
+//
+//	0  success
+//	1  generic failure
+//	2  usage error (bad flags or arguments)
+//	3  object not found
+//	4  conflict or timeout
+//
+// Commands stop at the first error, so the code always reflects the
+// failure that halted them.
+const (
+	exitOK       = 0
+	exitError    = 1
+	exitUsage    = 2
+	exitNotFound = 3
+	exitConflict = 4
+)
+
+// usageError marks an error caused by how the command was invoked
+// rather than by anything the cluster did.
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string { return e.msg }
+
+// usagef builds a usageError the way fmt.Errorf builds a plain one.
+func usagef(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// parseArgs wraps FlagSet.Parse so bad flags carry the usage exit code.
+// An explicit -h passes through as flag.ErrHelp and exits 0.
+func parseArgs(fs *flag.FlagSet, args []string) error {
+	err := fs.Parse(args)
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		return &usageError{msg: err.Error()}
+	}
+	return err
+}
+
+// apiError is a non-2xx server response: the message for the user and
+// the status code for exit-code mapping.
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string { return e.message }
+
+// exitCode maps a command's error to the documented contract.
+func exitCode(err error) int {
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	var usage *usageError
+	if errors.As(err, &usage) {
+		return exitUsage
+	}
+	var api *apiError
+	if errors.As(err, &api) {
+		switch api.status {
+		case http.StatusNotFound:
+			return exitNotFound
+		case http.StatusConflict:
+			return exitConflict
+		}
+		return exitError
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return exitConflict
+	}
+	return exitError
+}
@@ -0,0 +1,589 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/url"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// resource describes one kind addressable from the command line.
+type resource struct {
+	kind       string // canonical kind, e.g. "Pod"
+	plural     string // API path segment, e.g. "pods"
+	namespaced bool
+
+	// filter narrows the Workload resource to one kind when the user
+	// asked for deployments or statefulsets specifically.
+	filter string
+}
+
+func (r resource) collectionPath() string {
+	return "/api/v1/" + r.plural
+}
+
+func (r resource) objectPath(namespace, name string) string {
+	if r.namespaced {
+		return fmt.Sprintf("/api/v1/namespaces/%s/%s/%s",
+			url.PathEscape(namespace), r.plural, url.PathEscape(name))
+	}
+	return "/api/v1/" + r.plural + "/" + url.PathEscape(name)
+}
+
+// resources returns the alias table mapping every accepted spelling —
+// singular, plural and short names — onto its resource. It is seeded
+// from the built-in table and overlaid with the server's discovery
+// document, so resource types added server-side become addressable
+// without a CLI release. Discovery failing (old server, offline) leaves
+// the built-ins.
+func (c *CLI) resources() map[string]resource {
+	if c.resourceTable != nil {
+		return c.resourceTable
+	}
+	table := builtinAliases()
+	var doc struct {
+		Resources []struct {
+			Name       string   `json:"name"`
+			Kind       string   `json:"kind"`
+			Namespaced bool     `json:"namespaced"`
+			ShortNames []string `json:"shortNames"`
+		} `json:"resources"`
+	}
+	if err := c.request("GET", "/api/v1", nil, &doc); err == nil {
+		for _, r := range doc.Resources {
+			res := resource{kind: r.Kind, plural: r.Name, namespaced: r.Namespaced}
+			table[r.Name] = res
+			table[strings.ToLower(r.Kind)] = res
+			for _, short := range r.ShortNames {
+				table[short] = res
+			}
+		}
+	}
+	c.resourceTable = table
+	return table
+}
+
+// builtinAliases is the offline fallback, plus the spellings discovery
+// cannot express: deployment and statefulset resolve to the Workload
+// resource that serves them, narrowed to their kind.
+func builtinAliases() map[string]resource {
+	m := map[string]resource{}
+	add := func(res resource, names ...string) {
+		for _, name := range names {
+			m[name] = res
+		}
+	}
+	add(resource{kind: "Pod", plural: "pods", namespaced: true}, "pod", "pods", "po")
+	add(resource{kind: "Node", plural: "nodes"}, "node", "nodes", "no")
+	add(resource{kind: "Workload", plural: "workloads", namespaced: true},
+		"workload", "workloads", "wl")
+	add(resource{kind: "Workload", plural: "workloads", namespaced: true, filter: "Deployment"},
+		"deployment", "deployments", "deploy")
+	add(resource{kind: "Workload", plural: "workloads", namespaced: true, filter: "StatefulSet"},
+		"statefulset", "statefulsets", "sts")
+	add(resource{kind: "Service", plural: "services", namespaced: true}, "service", "services", "svc")
+	add(resource{kind: "Ingress", plural: "ingresses", namespaced: true}, "ingress", "ingresses", "ing")
+	add(resource{kind: "Gateway", plural: "gateways", namespaced: true}, "gateway", "gateways", "gw")
+	add(resource{kind: "HTTPRoute", plural: "httproutes", namespaced: true}, "httproute", "httproutes")
+	add(resource{kind: "Secret", plural: "secrets", namespaced: true}, "secret", "secrets")
+	add(resource{kind: "Namespace", plural: "namespaces"}, "namespace", "namespaces", "ns")
+	add(resource{kind: "ServiceAccount", plural: "serviceaccounts", namespaced: true},
+		"serviceaccount", "serviceaccounts", "sa")
+	add(resource{kind: "Role", plural: "roles", namespaced: true}, "role", "roles")
+	add(resource{kind: "RoleBinding", plural: "rolebindings", namespaced: true},
+		"rolebinding", "rolebindings")
+	add(resource{kind: "PriorityClass", plural: "priorityclasses"}, "priorityclass", "priorityclasses", "pc")
+	add(resource{kind: "Lease", plural: "leases", namespaced: true}, "lease", "leases")
+	add(resource{kind: "CustomResourceDefinition", plural: "customresourcedefinitions"},
+		"customresourcedefinition", "customresourcedefinitions", "crd", "crds")
+	return m
+}
+
+// allKinds is what `get all` expands to: the resources operators scan
+// day to day.
+var allKinds = []string{"pods", "workloads", "services", "ingresses"}
+
+// target is one resolved argument: a resource, optionally narrowed to a
+// single name.
+type target struct {
+	res  resource
+	name string
+}
+
+// parseTargets resolves the positional arguments shared by get,
+// describe and delete: `all`, comma-separated kinds (`deploy,svc`),
+// `kind/name` forms, and bare names trailing a single kind.
+func (c *CLI) parseTargets(args []string) ([]target, error) {
+	aliases := c.resources()
+	var targets []target
+	var kinds []resource
+	named := false
+	for i, arg := range args {
+		if strings.Contains(arg, "/") {
+			kind, name, _ := strings.Cut(arg, "/")
+			res, ok := aliases[strings.ToLower(kind)]
+			if !ok {
+				return nil, fmt.Errorf("unknown resource kind %q", kind)
+			}
+			if name == "" {
+				return nil, fmt.Errorf("%q names no object", arg)
+			}
+			targets = append(targets, target{res: res, name: name})
+			continue
+		}
+		if i == 0 {
+			for _, kind := range strings.Split(arg, ",") {
+				if strings.ToLower(kind) == "all" {
+					for _, plural := range allKinds {
+						kinds = append(kinds, aliases[plural])
+					}
+					continue
+				}
+				res, ok := aliases[strings.ToLower(kind)]
+				if !ok {
+					return nil, fmt.Errorf("unknown resource kind %q", kind)
+				}
+				kinds = append(kinds, res)
+			}
+			continue
+		}
+		// A bare argument after the kinds is an object name.
+		if len(kinds) != 1 {
+			return nil, fmt.Errorf("names require exactly one kind, got %q", arg)
+		}
+		targets = append(targets, target{res: kinds[0], name: arg})
+		named = true
+	}
+	if !named {
+		for _, res := range kinds {
+			targets = append(targets, target{res: res})
+		}
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no resources named")
+	}
+	return targets, nil
+}
+
+// runGet lists or fetches resources: `get all`, `get deploy,svc`,
+// `get pod/web-0`, `get pods web-0`.
+func (c *CLI) runGet(args []string) error {
+	fs := flag.NewFlagSet("get", flag.ContinueOnError)
+	namespace := fs.String("n", "default", "namespace to list")
+	allNamespaces := fs.Bool("A", false, "list across all namespaces")
+	output := fs.String("o", "", "output format: wide adds detail columns")
+	fs.SetOutput(c.Err)
+	if err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return usagef("usage: synthesis get <kind>[,<kind>...] [name] | <kind>/<name> ...")
+	}
+	targets, err := c.parseTargets(fs.Args())
+	if err != nil {
+		return err
+	}
+	wide := *output == "wide"
+	for i, t := range targets {
+		if i > 0 {
+			fmt.Fprintln(c.Out)
+		}
+		if err := c.getTarget(t, *namespace, *allNamespaces, wide); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *CLI) getTarget(t target, namespace string, allNamespaces, wide bool) error {
+	switch t.res.kind {
+	case "Pod":
+		return c.getPods(t, namespace, allNamespaces, wide)
+	case "Node":
+		return c.getNodes(t)
+	case "Workload":
+		return c.getWorkloads(t, namespace, allNamespaces, wide)
+	case "Service":
+		return c.getServices(t, namespace, allNamespaces, wide)
+	default:
+		return c.getGeneric(t, namespace, allNamespaces)
+	}
+}
+
+// matchesNamespace applies the -n / -A narrowing with the API's
+// empty-means-default convention.
+func matchesNamespace(objNamespace, namespace string, allNamespaces bool) bool {
+	if allNamespaces {
+		return true
+	}
+	if objNamespace == "" {
+		objNamespace = "default"
+	}
+	return objNamespace == namespace
+}
+
+func (c *CLI) getPods(t target, namespace string, allNamespaces, wide bool) error {
+	var pods []api.Pod
+	switch {
+	case t.name != "":
+		var pod api.Pod
+		if err := c.request("GET", t.res.objectPath(namespace, t.name), nil, &pod); err != nil {
+			return err
+		}
+		pods = []api.Pod{pod}
+	case allNamespaces:
+		if err := c.request("GET", "/api/v1/pods", nil, &pods); err != nil {
+			return err
+		}
+	default:
+		path := "/api/v1/namespaces/" + url.PathEscape(namespace) + "/pods"
+		if err := c.request("GET", path, nil, &pods); err != nil {
+			return err
+		}
+	}
+	tw := tabwriter.NewWriter(c.Out, 0, 4, 2, ' ', 0)
+	header := "NAMESPACE\tNAME\tSTATUS\tRESTARTS\tAGE"
+	if wide {
+		header += "\tNODE\tIMAGES"
+	}
+	fmt.Fprintln(tw, header)
+	for _, pod := range pods {
+		phase := string(pod.Status.Phase)
+		if phase == "" {
+			phase = string(api.PodPending)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s",
+			defaultNamespace(pod.Meta.Namespace), pod.Meta.Name, phase,
+			pod.Status.Restarts(), formatAge(pod.Meta.CreatedAt))
+		if wide {
+			images := make([]string, 0, len(pod.Spec.Containers))
+			for _, ctr := range pod.Spec.Containers {
+				images = append(images, ctr.Image)
+			}
+			fmt.Fprintf(tw, "\t%s\t%s", orDash(pod.Status.NodeName), orDash(strings.Join(images, ",")))
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+func (c *CLI) getNodes(t target) error {
+	var nodes []api.Node
+	if t.name != "" {
+		var node api.Node
+		if err := c.request("GET", t.res.objectPath("", t.name), nil, &node); err != nil {
+			return err
+		}
+		nodes = []api.Node{node}
+	} else if err := c.request("GET", "/api/v1/nodes", nil, &nodes); err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(c.Out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tSTATUS\tADDRESS\tAGE")
+	for _, node := range nodes {
+		status := "NotReady"
+		if cond := node.Status.Condition(api.NodeReady); cond != nil && cond.Status {
+			status = "Ready"
+		}
+		if node.Spec.Unschedulable {
+			status += ",SchedulingDisabled"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n",
+			node.Meta.Name, status, orDash(node.Status.Address), formatAge(node.Meta.CreatedAt))
+	}
+	return tw.Flush()
+}
+
+func (c *CLI) getWorkloads(t target, namespace string, allNamespaces, wide bool) error {
+	var summaries []api.WorkloadSummary
+	if t.name != "" {
+		var wl api.Workload
+		if err := c.request("GET", t.res.objectPath(namespace, t.name), nil, &wl); err != nil {
+			return err
+		}
+		status := "Progressing"
+		switch {
+		case wl.Spec.Paused:
+			status = "Paused"
+		case wl.Status.ReadyReplicas == wl.Spec.Replicas:
+			status = "Ready"
+		}
+		summaries = []api.WorkloadSummary{{
+			Kind:      string(wl.EffectiveKind()),
+			Namespace: wl.Meta.Namespace,
+			Name:      wl.Meta.Name,
+			Replicas:  wl.Spec.Replicas,
+			Ready:     wl.Status.ReadyReplicas,
+			Status:    status,
+			CreatedAt: wl.Meta.CreatedAt,
+		}}
+	} else if err := c.request("GET", "/api/v1/workloads", nil, &summaries); err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(c.Out, 0, 4, 2, ' ', 0)
+	header := "NAMESPACE\tNAME\tKIND\tREADY\tSTATUS\tAGE"
+	if wide {
+		header += "\tNODE\tIMAGES"
+	}
+	fmt.Fprintln(tw, header)
+	for _, s := range summaries {
+		if t.filterOut(s.Kind) {
+			continue
+		}
+		if t.name == "" && !matchesNamespace(s.Namespace, namespace, allNamespaces) {
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d/%d\t%s\t%s",
+			defaultNamespace(s.Namespace), s.Name, s.Kind, s.Ready, s.Replicas,
+			orDash(s.Status), formatAge(s.CreatedAt))
+		if wide {
+			fmt.Fprintf(tw, "\t%s\t%s", orDash(s.Node), orDash(strings.Join(s.Images, ",")))
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+// filterOut reports whether the row's kind falls outside what the user
+// asked for: bare pods hide under `get workloads`, and the deploy/sts
+// aliases narrow to their kind.
+func (t target) filterOut(kind string) bool {
+	if t.res.filter != "" {
+		return kind != t.res.filter
+	}
+	return t.res.kind == "Workload" && kind == "Pod"
+}
+
+func (c *CLI) getServices(t target, namespace string, allNamespaces, wide bool) error {
+	var svcs []api.Service
+	if t.name != "" {
+		var svc api.Service
+		if err := c.request("GET", t.res.objectPath(namespace, t.name), nil, &svc); err != nil {
+			return err
+		}
+		svcs = []api.Service{svc}
+	} else if err := c.request("GET", "/api/v1/services", nil, &svcs); err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(c.Out, 0, 4, 2, ' ', 0)
+	header := "NAMESPACE\tNAME\tTYPE\tPORTS\tAGE"
+	if wide {
+		header += "\tEXTERNAL-IP"
+	}
+	fmt.Fprintln(tw, header)
+	for _, svc := range svcs {
+		if t.name == "" && !matchesNamespace(svc.Meta.Namespace, namespace, allNamespaces) {
+			continue
+		}
+		svcType := svc.Spec.Type
+		if svcType == "" {
+			svcType = "NodePort"
+		}
+		ports := make([]string, 0, len(svc.Spec.Ports))
+		for _, p := range svc.Spec.Ports {
+			proto := p.Protocol
+			if proto == "" {
+				proto = "TCP"
+			}
+			if p.NodePort != 0 {
+				ports = append(ports, fmt.Sprintf("%d:%d/%s", p.Port, p.NodePort, proto))
+			} else {
+				ports = append(ports, fmt.Sprintf("%d/%s", p.Port, proto))
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s",
+			defaultNamespace(svc.Meta.Namespace), svc.Meta.Name, svcType,
+			orDash(strings.Join(ports, ",")), formatAge(svc.Meta.CreatedAt))
+		if wide {
+			ips := make([]string, 0, len(svc.Status.LoadBalancer.Ingress))
+			for _, ing := range svc.Status.LoadBalancer.Ingress {
+				ips = append(ips, ing.IP)
+			}
+			fmt.Fprintf(tw, "\t%s", orDash(strings.Join(ips, ",")))
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+// getGeneric covers the kinds without a dedicated table: metadata-only
+// columns decoded from whatever the list endpoint serves.
+func (c *CLI) getGeneric(t target, namespace string, allNamespaces bool) error {
+	type row struct {
+		Meta api.ObjectMeta `json:"metadata"`
+	}
+	var rows []row
+	if t.name != "" {
+		ns := namespace
+		if !t.res.namespaced {
+			ns = ""
+		}
+		var one row
+		if err := c.request("GET", t.res.objectPath(ns, t.name), nil, &one); err != nil {
+			return err
+		}
+		rows = []row{one}
+	} else if err := c.request("GET", t.res.collectionPath(), nil, &rows); err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(c.Out, 0, 4, 2, ' ', 0)
+	if t.res.namespaced {
+		fmt.Fprintln(tw, "NAMESPACE\tNAME\tAGE")
+	} else {
+		fmt.Fprintln(tw, "NAME\tAGE")
+	}
+	for _, r := range rows {
+		if t.res.namespaced {
+			if t.name == "" && !matchesNamespace(r.Meta.Namespace, namespace, allNamespaces) {
+				continue
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\n",
+				defaultNamespace(r.Meta.Namespace), r.Meta.Name, formatAge(r.Meta.CreatedAt))
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", r.Meta.Name, formatAge(r.Meta.CreatedAt))
+	}
+	return tw.Flush()
+}
+
+func defaultNamespace(namespace string) string {
+	if namespace == "" {
+		return "default"
+	}
+	return namespace
+}
+
+// runDescribe dumps one object and the events regarding it:
+// `describe pod/web-0` or `describe pod web-0`.
+func (c *CLI) runDescribe(args []string) error {
+	fs := flag.NewFlagSet("describe", flag.ContinueOnError)
+	namespace := fs.String("n", "default", "namespace of the object")
+	fs.SetOutput(c.Err)
+	if err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return usagef("usage: synthesis describe <kind>/<name> | <kind> <name>")
+	}
+	targets, err := c.parseTargets(fs.Args())
+	if err != nil {
+		return err
+	}
+	for i, t := range targets {
+		if t.name == "" {
+			return fmt.Errorf("describe needs an object name for %s", strings.ToLower(t.res.kind))
+		}
+		if i > 0 {
+			fmt.Fprintln(c.Out)
+		}
+		if err := c.describeTarget(t, *namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *CLI) describeTarget(t target, namespace string) error {
+	ns := namespace
+	if !t.res.namespaced {
+		ns = ""
+	}
+	var obj map[string]any
+	if err := c.request("GET", t.res.objectPath(ns, t.name), nil, &obj); err != nil {
+		return err
+	}
+	buf, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "%s\n", buf)
+
+	if t.res.kind == "Pod" {
+		c.describePodUsage(namespace, t.name)
+	}
+
+	var events []api.Event
+	if err := c.request("GET", "/api/v1/events", nil, &events); err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(c.Out, 0, 4, 2, ' ', 0)
+	printed := false
+	for _, ev := range events {
+		if ev.Regarding.Kind != t.res.kind || ev.Regarding.Name != t.name {
+			continue
+		}
+		if t.res.namespaced && !matchesNamespace(ev.Regarding.Namespace, namespace, false) {
+			continue
+		}
+		if !printed {
+			fmt.Fprintln(c.Out, "Events:")
+			fmt.Fprintln(tw, "AGE\tTYPE\tREASON\tMESSAGE")
+			printed = true
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n",
+			formatAge(ev.Time), ev.Type, ev.Reason, ev.Message)
+	}
+	if printed {
+		return tw.Flush()
+	}
+	return nil
+}
+
+// describePodUsage prints current and windowed-max usage from the
+// server's metrics history, so capacity questions don't need a separate
+// monitoring stack. A pod with no samples prints nothing.
+func (c *CLI) describePodUsage(namespace, name string) {
+	var history []api.PodMetrics
+	path := fmt.Sprintf("/apis/metrics.k8s.io/v1beta1/namespaces/%s/pods/%s/history",
+		url.PathEscape(namespace), url.PathEscape(name))
+	if err := c.request("GET", path, nil, &history); err != nil || len(history) == 0 {
+		return
+	}
+	var maxCPU, maxMem int64
+	for _, m := range history {
+		u := m.Usage()
+		if cpu := u[api.ResourceCPU]; cpu > maxCPU {
+			maxCPU = cpu
+		}
+		if mem := u[api.ResourceMemory]; mem > maxMem {
+			maxMem = mem
+		}
+	}
+	current := history[len(history)-1].Usage()
+	window := time.Since(history[0].Timestamp).Round(time.Minute)
+	fmt.Fprintln(c.Out, "Usage:")
+	tw := tabwriter.NewWriter(c.Out, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "  CPU:\t%s current\t%s max over %s\n",
+		formatCPU(current[api.ResourceCPU]), formatCPU(maxCPU), window)
+	fmt.Fprintf(tw, "  Memory:\t%s current\t%s max over %s\n",
+		formatBytes(current[api.ResourceMemory]), formatBytes(maxMem), window)
+	tw.Flush()
+}
+
+// deleteTargets deletes objects named on the command line; the -f/-k
+// manifest forms stay in runDelete.
+func (c *CLI) deleteTargets(args []string, namespace string) error {
+	targets, err := c.parseTargets(args)
+	if err != nil {
+		return err
+	}
+	for _, t := range targets {
+		if t.name == "" {
+			return fmt.Errorf("delete needs an object name for %s", strings.ToLower(t.res.kind))
+		}
+		ns := namespace
+		if !t.res.namespaced {
+			ns = ""
+		}
+		if err := c.request("DELETE", t.res.objectPath(ns, t.name), nil, nil); err != nil {
+			return fmt.Errorf("deleting %s/%s: %w", strings.ToLower(t.res.kind), t.name, err)
+		}
+		fmt.Fprintf(c.Out, "%s/%s deleted\n", strings.ToLower(t.res.kind), t.name)
+	}
+	return nil
+}
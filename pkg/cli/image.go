@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/runtime"
+)
+
+// runImage dispatches the `synthesis image ...` subcommands. `pull`
+// goes through the API to warm selected nodes; the rest drive this
+// node's engine directly, so air-gapped installs can preload tarballs
+// with no registry (or even control plane) reachable — run those on
+// each node the images belong on.
+func (c *CLI) runImage(args []string) error {
+	if len(args) == 0 {
+		return usagef("usage: synthesis image <pull|load|list|remove> ...")
+	}
+	var mgr runtime.ImageManager = runtime.NewDocker()
+	switch args[0] {
+	case "pull":
+		return c.imagePull(args[1:])
+	case "load":
+		return c.imageLoad(mgr, args[1:])
+	case "list", "ls":
+		return c.imageList(mgr)
+	case "remove", "rm":
+		return c.imageRemove(mgr, args[1:])
+	default:
+		return usagef("unknown image command %q", args[0])
+	}
+}
+
+// imagePull queues an asynchronous pre-pull on the cluster; watch the
+// events API for ImagePulled/ImagePullFailed from each node.
+func (c *CLI) imagePull(args []string) error {
+	fs := flag.NewFlagSet("image pull", flag.ContinueOnError)
+	nodes := fs.String("nodes", "", "comma-separated nodes to pull on (default all)")
+	fs.SetOutput(c.Err)
+	if err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usagef("usage: synthesis image pull [--nodes a,b] <image>")
+	}
+	req := api.ImagePullRequest{Image: fs.Arg(0)}
+	if *nodes != "" {
+		req.Nodes = strings.Split(*nodes, ",")
+	}
+	if err := c.request("POST", "/api/v1/images/pull", &req, &req); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "pre-pull of %s queued\n", req.Image)
+	return nil
+}
+
+func (c *CLI) imageLoad(mgr runtime.ImageManager, args []string) error {
+	fs := flag.NewFlagSet("image load", flag.ContinueOnError)
+	file := fs.String("f", "", "image tarball to import (docker save / OCI layout)")
+	fs.SetOutput(c.Err)
+	if err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return usagef("usage: synthesis image load -f images.tar")
+	}
+	refs, err := mgr.LoadImages(*file)
+	if err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		fmt.Fprintf(c.Out, "loaded %s\n", ref)
+	}
+	if len(refs) == 0 {
+		fmt.Fprintln(c.Out, "no images found in tarball")
+	}
+	return nil
+}
+
+func (c *CLI) imageList(mgr runtime.ImageManager) error {
+	images, err := mgr.ListImages()
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(c.Out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "IMAGE\tID\tSIZE")
+	for _, img := range images {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", img.Ref, img.ID, img.Size)
+	}
+	return tw.Flush()
+}
+
+func (c *CLI) imageRemove(mgr runtime.ImageManager, args []string) error {
+	if len(args) == 0 {
+		return usagef("usage: synthesis image remove <ref> ...")
+	}
+	for _, ref := range args {
+		if err := mgr.RemoveImage(ref); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.Out, "removed %s\n", ref)
+	}
+	return nil
+}
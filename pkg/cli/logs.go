@@ -0,0 +1,239 @@
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// logColors is the ANSI palette cycled across sources so interleaved
+// streams can be told apart at a glance.
+var logColors = []string{
+	"\x1b[32m", // green
+	"\x1b[33m", // yellow
+	"\x1b[34m", // blue
+	"\x1b[35m", // magenta
+	"\x1b[36m", // cyan
+	"\x1b[31m", // red
+}
+
+// logSource is one stream to tail: a container of a pod.
+type logSource struct {
+	namespace, pod, container string
+}
+
+// runLogs tails container logs, fanning in every matching stream:
+// `logs web-0`, `logs deployment/web --all-containers -f`,
+// `logs -l app=web`.
+func (c *CLI) runLogs(args []string) error {
+	fs := flag.NewFlagSet("logs", flag.ContinueOnError)
+	namespace := fs.String("n", "default", "namespace of the pods")
+	follow := fs.Bool("f", false, "keep the streams open for new output")
+	tail := fs.Int("tail", -1, "lines of recent output per container, -1 for all")
+	selector := fs.String("l", "", "label selector matching pods to tail, e.g. app=web")
+	container := fs.String("c", "", "container to tail (default: each pod's first)")
+	allContainers := fs.Bool("all-containers", false, "tail every container of each pod")
+	noColor := fs.Bool("no-color", false, "plain prefixes without ANSI colors")
+	fs.SetOutput(c.Err)
+	if err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if *selector == "" && fs.NArg() != 1 {
+		return usagef("usage: synthesis logs <pod>|<kind>/<name> | -l selector")
+	}
+	pods, err := c.logPods(fs.Args(), *namespace, *selector)
+	if err != nil {
+		return err
+	}
+	if len(pods) == 0 {
+		return fmt.Errorf("no pods matched")
+	}
+	var sources []logSource
+	for _, pod := range pods {
+		ns := pod.Meta.Namespace
+		if ns == "" {
+			ns = "default"
+		}
+		switch {
+		case *container != "":
+			sources = append(sources, logSource{ns, pod.Meta.Name, *container})
+		case *allContainers:
+			for _, ctr := range pod.Spec.Containers {
+				sources = append(sources, logSource{ns, pod.Meta.Name, ctr.Name})
+			}
+		default:
+			if len(pod.Spec.Containers) == 0 {
+				continue
+			}
+			sources = append(sources, logSource{ns, pod.Meta.Name, pod.Spec.Containers[0].Name})
+		}
+	}
+
+	// A single stream passes through unprefixed, like tailing the pod
+	// directly; fan-in gets per-source prefixes.
+	prefixed := len(sources) > 1
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for i, src := range sources {
+		prefix := ""
+		if prefixed {
+			label := src.pod
+			if *allContainers {
+				label += "/" + src.container
+			}
+			if *noColor {
+				prefix = "[" + label + "] "
+			} else {
+				prefix = logColors[i%len(logColors)] + "[" + label + "]\x1b[0m "
+			}
+		}
+		wg.Add(1)
+		go func(src logSource, prefix string) {
+			defer wg.Done()
+			c.tailSource(src, prefix, *follow, *tail, &mu)
+		}(src, prefix)
+	}
+	wg.Wait()
+	return nil
+}
+
+// tailSource streams one container, writing whole lines under the lock
+// so interleaved sources never split mid-line.
+func (c *CLI) tailSource(src logSource, prefix string, follow bool, tail int, mu *sync.Mutex) {
+	query := url.Values{}
+	if follow {
+		query.Set("follow", "true")
+	}
+	if tail >= 0 {
+		query.Set("tail", strconv.Itoa(tail))
+	}
+	query.Set("container", src.container)
+	path := fmt.Sprintf("/api/v1/namespaces/%s/pods/%s/log?%s",
+		url.PathEscape(src.namespace), url.PathEscape(src.pod), query.Encode())
+	body, err := c.stream(path)
+	if err != nil {
+		mu.Lock()
+		fmt.Fprintf(c.Err, "%s%v\n", prefix, err)
+		mu.Unlock()
+		return
+	}
+	defer body.Close()
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		mu.Lock()
+		fmt.Fprintf(c.Out, "%s%s\n", prefix, scanner.Text())
+		mu.Unlock()
+	}
+}
+
+// logPods resolves the pods to tail: a bare pod name, pod/name, a
+// workload reference whose owned pods are matched by the workload
+// label, or a label selector.
+func (c *CLI) logPods(args []string, namespace, selector string) ([]api.Pod, error) {
+	if selector != "" {
+		want, err := parseSelector(selector)
+		if err != nil {
+			return nil, err
+		}
+		return c.listPodsMatching(namespace, func(pod api.Pod) bool {
+			for k, v := range want {
+				if pod.Meta.Labels[k] != v {
+					return false
+				}
+			}
+			return true
+		})
+	}
+	arg := args[0]
+	kind, name := "pod", arg
+	if k, n, ok := strings.Cut(arg, "/"); ok {
+		kind, name = k, n
+	}
+	res, ok := c.resources()[strings.ToLower(kind)]
+	if !ok {
+		return nil, fmt.Errorf("unknown resource kind %q", kind)
+	}
+	switch res.kind {
+	case "Pod":
+		var pod api.Pod
+		path := fmt.Sprintf("/api/v1/namespaces/%s/pods/%s",
+			url.PathEscape(namespace), url.PathEscape(name))
+		if err := c.request("GET", path, nil, &pod); err != nil {
+			return nil, err
+		}
+		return []api.Pod{pod}, nil
+	case "Workload":
+		return c.listPodsMatching(namespace, func(pod api.Pod) bool {
+			return pod.Meta.Labels[api.WorkloadLabel] == name
+		})
+	default:
+		return nil, fmt.Errorf("%s has no logs", strings.ToLower(res.kind))
+	}
+}
+
+func (c *CLI) listPodsMatching(namespace string, match func(api.Pod) bool) ([]api.Pod, error) {
+	var pods []api.Pod
+	path := "/api/v1/namespaces/" + url.PathEscape(namespace) + "/pods"
+	if err := c.request("GET", path, nil, &pods); err != nil {
+		return nil, err
+	}
+	out := pods[:0]
+	for _, pod := range pods {
+		if match(pod) {
+			out = append(out, pod)
+		}
+	}
+	return out, nil
+}
+
+// parseSelector reads the k=v[,k=v...] label selector grammar.
+func parseSelector(s string) (map[string]string, error) {
+	want := map[string]string{}
+	for _, part := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("selector %q is not k=v[,k=v...]", s)
+		}
+		want[k] = v
+	}
+	return want, nil
+}
+
+// stream performs a GET and hands back the raw body for streaming
+// responses like pod logs.
+func (c *CLI) stream(path string) (io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, c.Server+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	client, err := c.client()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		msg := strings.TrimSpace(string(body))
+		if msg == "" {
+			msg = "server returned " + resp.Status
+		}
+		return nil, &apiError{status: resp.StatusCode, message: msg}
+	}
+	return resp.Body, nil
+}
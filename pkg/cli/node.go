@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+)
+
+// runNode dispatches the `synthesis node ...` subcommands.
+func (c *CLI) runNode(args []string) error {
+	if len(args) == 0 {
+		return usagef("usage: synthesis node <cordon|uncordon|drain> <name>")
+	}
+	switch args[0] {
+	case "cordon":
+		return c.nodeVerb(args[1:], "cordon")
+	case "uncordon":
+		return c.nodeVerb(args[1:], "uncordon")
+	case "drain":
+		return c.nodeDrain(args[1:])
+	default:
+		return usagef("unknown node command %q", args[0])
+	}
+}
+
+func (c *CLI) nodeVerb(args []string, verb string) error {
+	if len(args) != 1 {
+		return usagef("usage: synthesis node %s <name>", verb)
+	}
+	name := args[0]
+	if err := c.request("POST", "/api/v1/nodes/"+url.PathEscape(name)+"/"+verb, nil, nil); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "node/%s %sed\n", name, verb)
+	return nil
+}
+
+func (c *CLI) nodeDrain(args []string) error {
+	fs := flag.NewFlagSet("node drain", flag.ContinueOnError)
+	grace := fs.Int("grace-period", 30, "seconds each pod gets to terminate")
+	fs.SetOutput(c.Err)
+	if err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usagef("usage: synthesis node drain [--grace-period N] <name>")
+	}
+	name := fs.Arg(0)
+
+	var result struct {
+		Evicted []string `json:"evicted"`
+		Refused []string `json:"refused"`
+	}
+	path := fmt.Sprintf("/api/v1/nodes/%s/drain?gracePeriodSeconds=%d", url.PathEscape(name), *grace)
+	if err := c.request("POST", path, nil, &result); err != nil {
+		return err
+	}
+	for _, p := range result.Evicted {
+		fmt.Fprintf(c.Out, "evicted pod/%s\n", p)
+	}
+	for _, p := range result.Refused {
+		fmt.Fprintf(c.Out, "refused: %s\n", p)
+	}
+	if len(result.Refused) > 0 {
+		return fmt.Errorf("node %s drained partially: %d pod(s) blocked by disruption budgets", name, len(result.Refused))
+	}
+	fmt.Fprintf(c.Out, "node/%s drained\n", name)
+	return nil
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+)
+
+// runPod dispatches the `synthesis pod ...` subcommands.
+func (c *CLI) runPod(args []string) error {
+	if len(args) == 0 {
+		return usagef("usage: synthesis pod <hold|unhold|restart> <name>")
+	}
+	switch args[0] {
+	case "hold":
+		return c.podGateVerb(args[1:], "hold")
+	case "unhold":
+		return c.podGateVerb(args[1:], "unhold")
+	case "restart":
+		return c.podRestart(args[1:])
+	default:
+		return usagef("unknown pod command %q", args[0])
+	}
+}
+
+// podRestart asks the pod's node to recreate its containers, replacing
+// the old habit of removing them through the engine behind the
+// agent's back.
+func (c *CLI) podRestart(args []string) error {
+	fs := flag.NewFlagSet("pod restart", flag.ContinueOnError)
+	namespace := fs.String("n", "default", "namespace of the pod")
+	fs.SetOutput(c.Err)
+	if err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usagef("usage: synthesis pod restart [-n namespace] <name>")
+	}
+	name := fs.Arg(0)
+	path := fmt.Sprintf("/api/v1/namespaces/%s/pods/%s/restart",
+		url.PathEscape(*namespace), url.PathEscape(name))
+	if err := c.request("POST", path, nil, nil); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "pod/%s restarted\n", name)
+	return nil
+}
+
+// podGateVerb adds or removes a scheduling gate on a pod. Hold without
+// --gate applies the default gate; unhold without --gate lifts every
+// gate.
+func (c *CLI) podGateVerb(args []string, verb string) error {
+	fs := flag.NewFlagSet("pod "+verb, flag.ContinueOnError)
+	namespace := fs.String("n", "default", "namespace of the pod")
+	gate := fs.String("gate", "", "scheduling gate name")
+	fs.SetOutput(c.Err)
+	if err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usagef("usage: synthesis pod %s [--gate NAME] [-n namespace] <name>", verb)
+	}
+	name := fs.Arg(0)
+
+	var body any
+	if *gate != "" {
+		body = map[string]string{"gate": *gate}
+	}
+	path := fmt.Sprintf("/api/v1/namespaces/%s/pods/%s/%s",
+		url.PathEscape(*namespace), url.PathEscape(name), verb)
+	if err := c.request("POST", path, body, nil); err != nil {
+		return err
+	}
+	if verb == "hold" {
+		fmt.Fprintf(c.Out, "pod/%s held\n", name)
+	} else {
+		fmt.Fprintf(c.Out, "pod/%s released\n", name)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+)
+
+// runProxy serves the API on a local listener with this CLI's
+// credentials injected into every forwarded request, so browser
+// dashboards can talk to Synthesis without embedding a token.
+func (c *CLI) runProxy(args []string) error {
+	fs := flag.NewFlagSet("proxy", flag.ContinueOnError)
+	addr := fs.String("listen", "127.0.0.1:8001", "local address the proxy serves on")
+	fs.SetOutput(c.Err)
+	if err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	target, err := url.Parse(c.Server)
+	if err != nil {
+		return fmt.Errorf("parsing server URL: %w", err)
+	}
+	httpc, err := c.client()
+	if err != nil {
+		return err
+	}
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(target)
+			if c.Token != "" {
+				pr.Out.Header.Set("Authorization", "Bearer "+c.Token)
+			}
+		},
+		Transport: httpc.Transport,
+	}
+	fmt.Fprintf(c.Out, "proxying %s on http://%s\n", c.Server, *addr)
+	return http.ListenAndServe(*addr, proxy)
+}
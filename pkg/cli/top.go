@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"fmt"
+	"net/url"
+	"text/tabwriter"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// runTop dispatches the `synthesis top ...` subcommands.
+func (c *CLI) runTop(args []string) error {
+	if len(args) == 0 {
+		return usagef("usage: synthesis top nodes|pods")
+	}
+	switch args[0] {
+	case "nodes", "node":
+		return c.topNodes()
+	case "pods", "pod":
+		return c.topPods()
+	default:
+		return usagef("unknown top command %q", args[0])
+	}
+}
+
+func (c *CLI) topNodes() error {
+	var nodes []api.Node
+	if err := c.request("GET", "/api/v1/nodes", nil, &nodes); err != nil {
+		return err
+	}
+	// Live usage comes from the metrics API; a node with no fresh
+	// sample shows dashes rather than misleading zeros.
+	var metrics []api.NodeMetrics
+	if err := c.request("GET", "/apis/metrics.k8s.io/v1beta1/nodes", nil, &metrics); err != nil {
+		return err
+	}
+	usage := make(map[string]api.ResourceList, len(metrics))
+	for _, m := range metrics {
+		usage[m.Name] = m.Usage
+	}
+	tw := tabwriter.NewWriter(c.Out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tCPU(use)\tCPU(req/alloc)\tMEMORY(use)\tMEMORY(req/alloc)\tPODS")
+	for _, node := range nodes {
+		var res struct {
+			Allocatable api.ResourceList `json:"allocatable"`
+			Requested   api.ResourceList `json:"requested"`
+		}
+		if err := c.request("GET", "/api/v1/nodes/"+url.PathEscape(node.Meta.Name)+"/resources", nil, &res); err != nil {
+			return err
+		}
+		cpuUse, memUse := "-", "-"
+		if u, ok := usage[node.Meta.Name]; ok {
+			cpuUse = formatCPU(u[api.ResourceCPU])
+			memUse = formatBytes(u[api.ResourceMemory])
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s/%s\t%s\t%s/%s\t%d/%d\n",
+			node.Meta.Name,
+			cpuUse,
+			formatCPU(res.Requested[api.ResourceCPU]), formatCPU(res.Allocatable[api.ResourceCPU]),
+			memUse,
+			formatBytes(res.Requested[api.ResourceMemory]), formatBytes(res.Allocatable[api.ResourceMemory]),
+			res.Requested[api.ResourcePods], res.Allocatable[api.ResourcePods])
+	}
+	return tw.Flush()
+}
+
+func (c *CLI) topPods() error {
+	var metrics []api.PodMetrics
+	if err := c.request("GET", "/apis/metrics.k8s.io/v1beta1/pods", nil, &metrics); err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(c.Out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAMESPACE\tNAME\tCPU\tMEMORY")
+	for _, m := range metrics {
+		u := m.Usage()
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n",
+			m.Namespace, m.Name,
+			formatCPU(u[api.ResourceCPU]), formatBytes(u[api.ResourceMemory]))
+	}
+	return tw.Flush()
+}
+
+// formatCPU renders millicores: whole cores without a suffix, fractions
+// with the m suffix.
+func formatCPU(millis int64) string {
+	if millis%1000 == 0 {
+		return fmt.Sprintf("%d", millis/1000)
+	}
+	return fmt.Sprintf("%dm", millis)
+}
+
+// formatBytes renders a byte quantity with a binary suffix.
+func formatBytes(b int64) string {
+	switch {
+	case b >= 1<<30:
+		return fmt.Sprintf("%.1fGi", float64(b)/(1<<30))
+	case b >= 1<<20:
+		return fmt.Sprintf("%.0fMi", float64(b)/(1<<20))
+	case b >= 1<<10:
+		return fmt.Sprintf("%.0fKi", float64(b)/(1<<10))
+	default:
+		return fmt.Sprintf("%d", b)
+	}
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// runUsage dispatches the `synthesis usage ...` subcommands.
+func (c *CLI) runUsage(args []string) error {
+	if len(args) == 0 {
+		return usagef("usage: synthesis usage report [--since 30d] [-n namespace]")
+	}
+	switch args[0] {
+	case "report":
+		return c.usageReport(args[1:])
+	default:
+		return usagef("unknown usage command %q", args[0])
+	}
+}
+
+// usageReport sums the server's accounting records per namespace over
+// the lookback window. Aggregation happens client-side from the raw
+// records, like the other list-and-join commands.
+func (c *CLI) usageReport(args []string) error {
+	fs := flag.NewFlagSet("usage report", flag.ContinueOnError)
+	since := fs.String("since", "30d", "how far back to aggregate, e.g. 12h, 7d, 30d")
+	namespace := fs.String("n", "", "restrict the report to one namespace")
+	fs.SetOutput(c.Err)
+	if err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	window, err := parseSince(*since)
+	if err != nil {
+		return usagef("--since: %v", err)
+	}
+
+	var records []api.UsageRecord
+	if err := c.request("GET", "/api/v1/usagerecords", nil, &records); err != nil {
+		return err
+	}
+	cutoff := time.Now().Add(-window)
+	totals := make(map[string]*api.UsageRecord)
+	for i := range records {
+		rec := &records[i]
+		if rec.End.Before(cutoff) {
+			continue
+		}
+		ns := rec.Meta.Namespace
+		if ns == "" {
+			ns = "default"
+		}
+		if *namespace != "" && ns != *namespace {
+			continue
+		}
+		t := totals[ns]
+		if t == nil {
+			t = &api.UsageRecord{}
+			totals[ns] = t
+		}
+		t.CPUCoreSeconds += rec.CPUCoreSeconds
+		t.MemoryByteHours += rec.MemoryByteHours
+		t.NetworkRxBytes += rec.NetworkRxBytes
+		t.NetworkTxBytes += rec.NetworkTxBytes
+	}
+
+	names := make([]string, 0, len(totals))
+	for ns := range totals {
+		names = append(names, ns)
+	}
+	sort.Strings(names)
+	tw := tabwriter.NewWriter(c.Out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAMESPACE\tCPU(core-hours)\tMEMORY(GiB-hours)\tNET(rx)\tNET(tx)")
+	for _, ns := range names {
+		t := totals[ns]
+		fmt.Fprintf(tw, "%s\t%.2f\t%.2f\t%s\t%s\n",
+			ns,
+			t.CPUCoreSeconds/3600,
+			t.MemoryByteHours/(1<<30),
+			formatBytes(t.NetworkRxBytes), formatBytes(t.NetworkTxBytes))
+	}
+	return tw.Flush()
+}
+
+// parseSince reads a lookback window: any Go duration, plus a "d"
+// suffix for the days chargeback windows are naturally quoted in.
+func parseSince(s string) (time.Duration, error) {
+	if n, ok := strings.CutSuffix(s, "d"); ok {
+		days, err := strconv.ParseFloat(n, 64)
+		if err != nil || days < 0 {
+			return 0, fmt.Errorf("malformed duration %q", s)
+		}
+		return time.Duration(days * 24 * float64(time.Hour)), nil
+	}
+	return time.ParseDuration(s)
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// runWorkload dispatches the `synthesis workload ...` subcommands.
+func (c *CLI) runWorkload(args []string) error {
+	if len(args) == 0 {
+		return usagef("usage: synthesis workload <list|rollout> ...")
+	}
+	switch args[0] {
+	case "list", "ls":
+		return c.workloadList(args[1:])
+	case "rollout":
+		return c.workloadRollout(args[1:])
+	default:
+		return usagef("unknown workload command %q", args[0])
+	}
+}
+
+// workloadRollout pauses or resumes a workload's reconciliation, so
+// several spec edits can be staged and rolled out as one, or triggers
+// a rolling restart of its pods.
+func (c *CLI) workloadRollout(args []string) error {
+	if len(args) == 0 || (args[0] != "pause" && args[0] != "resume" && args[0] != "restart") {
+		return usagef("usage: synthesis workload rollout <pause|resume|restart> [-n namespace] <name>")
+	}
+	verb := args[0]
+	fs := flag.NewFlagSet("workload rollout "+verb, flag.ContinueOnError)
+	namespace := fs.String("n", "default", "namespace of the workload")
+	fs.SetOutput(c.Err)
+	if err := parseArgs(fs, args[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usagef("usage: synthesis workload rollout %s [-n namespace] <name>", verb)
+	}
+	name := fs.Arg(0)
+	path := fmt.Sprintf("/api/v1/namespaces/%s/workloads/%s/%s",
+		url.PathEscape(*namespace), url.PathEscape(name), verb)
+	if err := c.request("POST", path, nil, nil); err != nil {
+		return err
+	}
+	past := verb + "d"
+	if verb == "restart" {
+		past = "restarted"
+	}
+	fmt.Fprintf(c.Out, "workload/%s %s\n", name, past)
+	return nil
+}
+
+func (c *CLI) workloadList(args []string) error {
+	fs := flag.NewFlagSet("workload list", flag.ContinueOnError)
+	output := fs.String("o", "", "output format: wide adds NODE and IMAGES columns")
+	fs.SetOutput(c.Err)
+	if err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	wide := *output == "wide"
+	var summaries []api.WorkloadSummary
+	if err := c.request("GET", "/api/v1/workloads", nil, &summaries); err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(c.Out, 0, 4, 2, ' ', 0)
+	header := "NAMESPACE\tNAME\tKIND\tREADY\tSTATUS\tAGE"
+	if wide {
+		header += "\tNODE\tIMAGES"
+	}
+	fmt.Fprintln(tw, header)
+	for _, s := range summaries {
+		namespace := s.Namespace
+		if namespace == "" {
+			namespace = "default"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d/%d\t%s\t%s",
+			namespace, s.Name, s.Kind, s.Ready, s.Replicas,
+			orDash(s.Status), formatAge(s.CreatedAt))
+		if wide {
+			fmt.Fprintf(tw, "\t%s\t%s", orDash(s.Node), orDash(strings.Join(s.Images, ",")))
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+// orDash substitutes a dash for an empty cell so columns stay readable.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// formatAge renders how long ago t was in its two largest units,
+// kubectl-style: 45s, 12m, 3h5m, 5d3h.
+func formatAge(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	d := time.Since(t)
+	if d < 0 {
+		d = 0
+	}
+	days, hours := int(d.Hours())/24, int(d.Hours())%24
+	minutes, seconds := int(d.Minutes())%60, int(d.Seconds())%60
+	switch {
+	case days > 0 && hours > 0:
+		return fmt.Sprintf("%dd%dh", days, hours)
+	case days > 0:
+		return fmt.Sprintf("%dd", days)
+	case hours > 0 && minutes > 0:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh", hours)
+	case minutes > 0:
+		return fmt.Sprintf("%dm", minutes)
+	default:
+		return fmt.Sprintf("%ds", seconds)
+	}
+}
@@ -0,0 +1,218 @@
+// Package client is the typed Go client external controllers build
+// against: resource accessors over the REST API, informer-style caches
+// and a small work queue, so third parties do not hand-roll HTTP the
+// way the CLI does.
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// Client talks to one Synthesis API server.
+type Client struct {
+	// Server is the base URL of the API server.
+	Server string
+
+	// Token is the bearer token presented on every request; empty
+	// sends none.
+	Token string
+
+	// HTTPClient, when set, replaces http.DefaultClient, e.g. to pin
+	// a CA or set timeouts.
+	HTTPClient *http.Client
+}
+
+// New returns a client for the given server URL.
+func New(server string) *Client {
+	return &Client{Server: server}
+}
+
+// do performs one API call, decoding the JSON response into out when
+// out is non-nil. Non-2xx responses become errors carrying the
+// server's message.
+func (c *Client) do(method, path string, body, out any) error {
+	var rdr io.Reader
+	if body != nil {
+		buf, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		rdr = bytes.NewReader(buf)
+	}
+	req, err := http.NewRequest(method, c.Server+path, rdr)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	httpc := c.HTTPClient
+	if httpc == nil {
+		httpc = http.DefaultClient
+	}
+	resp, err := httpc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		var e struct {
+			Error  string `json:"error"`
+			Reason string `json:"reason"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&e) == nil && e.Error != "" {
+			return &APIError{Status: resp.StatusCode, Message: e.Error, Reason: e.Reason}
+		}
+		return &APIError{Status: resp.StatusCode, Message: resp.Status}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// APIError is a non-2xx response: the status code plus the server's
+// message and machine-readable reason when it sent one.
+type APIError struct {
+	Status  int
+	Message string
+	Reason  string
+}
+
+func (e *APIError) Error() string { return e.Message }
+
+// IsNotFound reports whether the error is a 404 from the server.
+func IsNotFound(err error) bool {
+	ae, ok := err.(*APIError)
+	return ok && ae.Status == http.StatusNotFound
+}
+
+// IsAlreadyExists reports whether the error is a create conflict.
+func IsAlreadyExists(err error) bool {
+	ae, ok := err.(*APIError)
+	return ok && ae.Status == http.StatusConflict
+}
+
+func namespaceOr(namespace, fallback string) string {
+	if namespace == "" {
+		return fallback
+	}
+	return namespace
+}
+
+func podPath(namespace, name string) string {
+	return fmt.Sprintf("/api/v1/namespaces/%s/pods/%s",
+		url.PathEscape(namespaceOr(namespace, "default")), url.PathEscape(name))
+}
+
+// ListNodes returns all nodes.
+func (c *Client) ListNodes() ([]*api.Node, error) {
+	var nodes []*api.Node
+	err := c.do("GET", "/api/v1/nodes", nil, &nodes)
+	return nodes, err
+}
+
+// GetNode returns one node by name.
+func (c *Client) GetNode(name string) (*api.Node, error) {
+	var node api.Node
+	if err := c.do("GET", "/api/v1/nodes/"+url.PathEscape(name), nil, &node); err != nil {
+		return nil, err
+	}
+	return &node, nil
+}
+
+// UpdateNode replaces a node.
+func (c *Client) UpdateNode(node *api.Node) error {
+	return c.do("PUT", "/api/v1/nodes/"+url.PathEscape(node.Meta.Name), node, nil)
+}
+
+// ListPods returns all pods across namespaces.
+func (c *Client) ListPods() ([]*api.Pod, error) {
+	var pods []*api.Pod
+	err := c.do("GET", "/api/v1/pods", nil, &pods)
+	return pods, err
+}
+
+// GetPod returns one pod; an empty namespace means "default".
+func (c *Client) GetPod(namespace, name string) (*api.Pod, error) {
+	var pod api.Pod
+	if err := c.do("GET", podPath(namespace, name), nil, &pod); err != nil {
+		return nil, err
+	}
+	return &pod, nil
+}
+
+// CreatePod creates a pod, honoring metadata.generateName, and returns
+// the stored object with its final name.
+func (c *Client) CreatePod(pod *api.Pod) (*api.Pod, error) {
+	var created api.Pod
+	path := fmt.Sprintf("/api/v1/namespaces/%s/pods",
+		url.PathEscape(namespaceOr(pod.Meta.Namespace, "default")))
+	if err := c.do("POST", path, pod, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdatePod replaces an existing pod; missing pods are an error.
+func (c *Client) UpdatePod(pod *api.Pod) error {
+	return c.do("PUT", podPath(pod.Meta.Namespace, pod.Meta.Name), pod, nil)
+}
+
+// DeletePod removes a pod.
+func (c *Client) DeletePod(namespace, name string) error {
+	return c.do("DELETE", podPath(namespace, name), nil, nil)
+}
+
+func workloadPath(namespace, name string) string {
+	return fmt.Sprintf("/api/v1/namespaces/%s/workloads/%s",
+		url.PathEscape(namespaceOr(namespace, "default")), url.PathEscape(name))
+}
+
+// ListWorkloadSummaries returns the populated rows the workload list
+// API serves, covering workloads and bare pods.
+func (c *Client) ListWorkloadSummaries() ([]api.WorkloadSummary, error) {
+	var summaries []api.WorkloadSummary
+	err := c.do("GET", "/api/v1/workloads", nil, &summaries)
+	return summaries, err
+}
+
+// GetWorkload returns one workload; an empty namespace means "default".
+func (c *Client) GetWorkload(namespace, name string) (*api.Workload, error) {
+	var wl api.Workload
+	if err := c.do("GET", workloadPath(namespace, name), nil, &wl); err != nil {
+		return nil, err
+	}
+	return &wl, nil
+}
+
+// CreateWorkload creates a workload, honoring metadata.generateName.
+func (c *Client) CreateWorkload(wl *api.Workload) (*api.Workload, error) {
+	var created api.Workload
+	path := fmt.Sprintf("/api/v1/namespaces/%s/workloads",
+		url.PathEscape(namespaceOr(wl.Meta.Namespace, "default")))
+	if err := c.do("POST", path, wl, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdateWorkload replaces an existing workload.
+func (c *Client) UpdateWorkload(wl *api.Workload) error {
+	return c.do("PUT", workloadPath(wl.Meta.Namespace, wl.Meta.Name), wl, nil)
+}
+
+// DeleteWorkload removes a workload.
+func (c *Client) DeleteWorkload(namespace, name string) error {
+	return c.do("DELETE", workloadPath(namespace, name), nil, nil)
+}
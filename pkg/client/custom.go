@@ -0,0 +1,120 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// CustomResource addresses one registered custom type for CRUD and
+// watch calls.
+type CustomResource struct {
+	Group   string
+	Version string
+	Plural  string
+}
+
+func (cr CustomResource) basePath(namespace string) string {
+	base := fmt.Sprintf("/apis/%s/%s", url.PathEscape(cr.Group), url.PathEscape(cr.Version))
+	if namespace != "" {
+		base += "/namespaces/" + url.PathEscape(namespace)
+	}
+	return base + "/" + url.PathEscape(cr.Plural)
+}
+
+// ListCustom returns the resource's objects, optionally narrowed to a
+// namespace.
+func (c *Client) ListCustom(cr CustomResource, namespace string) ([]*api.CustomObject, error) {
+	var objs []*api.CustomObject
+	err := c.do("GET", cr.basePath(namespace), nil, &objs)
+	return objs, err
+}
+
+// GetCustom returns one object.
+func (c *Client) GetCustom(cr CustomResource, namespace, name string) (*api.CustomObject, error) {
+	var obj api.CustomObject
+	if err := c.do("GET", cr.basePath(namespace)+"/"+url.PathEscape(name), nil, &obj); err != nil {
+		return nil, err
+	}
+	return &obj, nil
+}
+
+// PutCustom creates or replaces an object under the given name.
+func (c *Client) PutCustom(cr CustomResource, obj *api.CustomObject) error {
+	return c.do("PUT", cr.basePath(obj.Meta.Namespace)+"/"+url.PathEscape(obj.Meta.Name), obj, nil)
+}
+
+// DeleteCustom removes an object.
+func (c *Client) DeleteCustom(cr CustomResource, namespace, name string) error {
+	return c.do("DELETE", cr.basePath(namespace)+"/"+url.PathEscape(name), nil, nil)
+}
+
+// WatchEvent is one change delivered to a watch handler.
+type WatchEvent struct {
+	Type   string            `json:"type"` // ADDED, MODIFIED or DELETED
+	Object *api.CustomObject `json:"object"`
+}
+
+// WatchCustom streams the resource's objects to the handler — the
+// current set as ADDED events, then every change — reconnecting with
+// backoff until ctx is canceled. The handler must not block for long;
+// slow handlers cost events server-side.
+func (c *Client) WatchCustom(ctx context.Context, cr CustomResource, namespace string, handler func(WatchEvent)) error {
+	backoff := time.Second
+	for {
+		err := c.watchOnce(ctx, cr, namespace, handler)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			if backoff < 30*time.Second {
+				backoff *= 2
+			}
+			continue
+		}
+		backoff = time.Second
+	}
+}
+
+func (c *Client) watchOnce(ctx context.Context, cr CustomResource, namespace string, handler func(WatchEvent)) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.Server+cr.basePath(namespace)+"?watch=true", nil)
+	if err != nil {
+		return err
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	httpc := c.HTTPClient
+	if httpc == nil {
+		httpc = http.DefaultClient
+	}
+	resp, err := httpc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("watch %s: server returned %s", cr.Plural, resp.Status)
+	}
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4<<20)
+	for scanner.Scan() {
+		var ev WatchEvent
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			return fmt.Errorf("decoding watch event: %w", err)
+		}
+		handler(ev)
+	}
+	return scanner.Err()
+}
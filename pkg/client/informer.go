@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Informer keeps a local cache of one listed resource and calls the
+// handlers as objects appear, change and vanish between polls.
+// Controllers read the cache instead of hammering the API server, the
+// same shape client-go informers take.
+type Informer[T any] struct {
+	// List fetches the current objects, typically a Client method.
+	List func() ([]T, error)
+
+	// KeyOf names an object, usually namespace/name.
+	KeyOf func(T) string
+
+	// Resync is the poll interval; zero means 30 seconds.
+	Resync time.Duration
+
+	// OnAdd, OnUpdate and OnDelete are called with the new (or, for
+	// deletes, last known) object. Any may be nil.
+	OnAdd    func(T)
+	OnUpdate func(T)
+	OnDelete func(T)
+
+	mu    sync.RWMutex
+	cache map[string]T
+}
+
+// Run polls until ctx is canceled. The first pass fills the cache and
+// reports every object through OnAdd.
+func (i *Informer[T]) Run(ctx context.Context) {
+	resync := i.Resync
+	if resync == 0 {
+		resync = 30 * time.Second
+	}
+	i.sync()
+	ticker := time.NewTicker(resync)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			i.sync()
+		}
+	}
+}
+
+func (i *Informer[T]) sync() {
+	objs, err := i.List()
+	if err != nil {
+		return // keep serving the last good cache; next poll retries
+	}
+	next := make(map[string]T, len(objs))
+	for _, obj := range objs {
+		next[i.KeyOf(obj)] = obj
+	}
+	i.mu.Lock()
+	prev := i.cache
+	i.cache = next
+	i.mu.Unlock()
+
+	for key, obj := range next {
+		old, existed := prev[key]
+		switch {
+		case !existed:
+			if i.OnAdd != nil {
+				i.OnAdd(obj)
+			}
+		case !reflect.DeepEqual(old, obj):
+			if i.OnUpdate != nil {
+				i.OnUpdate(obj)
+			}
+		}
+	}
+	for key, obj := range prev {
+		if _, still := next[key]; !still {
+			if i.OnDelete != nil {
+				i.OnDelete(obj)
+			}
+		}
+	}
+}
+
+// Get returns the cached object for the key, if present.
+func (i *Informer[T]) Get(key string) (T, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	obj, ok := i.cache[key]
+	return obj, ok
+}
+
+// Cached returns a snapshot of every cached object.
+func (i *Informer[T]) Cached() []T {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	out := make([]T, 0, len(i.cache))
+	for _, obj := range i.cache {
+		out = append(out, obj)
+	}
+	return out
+}
@@ -0,0 +1,81 @@
+package client
+
+import "sync"
+
+// Queue is a deduplicating work queue: keys added while already queued
+// or in flight collapse into one pending entry, so a burst of events
+// for an object costs one reconcile. Get blocks until a key is ready;
+// callers must pair it with Done.
+type Queue struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	order    []string
+	queued   map[string]bool
+	inflight map[string]bool
+	closed   bool
+}
+
+// NewQueue returns an empty queue.
+func NewQueue() *Queue {
+	q := &Queue{
+		queued:   make(map[string]bool),
+		inflight: make(map[string]bool),
+	}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// Add enqueues a key unless it is already pending. Keys being worked
+// on are re-queued when Done is called.
+func (q *Queue) Add(key string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed || q.queued[key] {
+		return
+	}
+	q.queued[key] = true
+	if !q.inflight[key] {
+		q.order = append(q.order, key)
+		q.cond.Signal()
+	}
+}
+
+// Get blocks until a key is available and marks it in flight. ok is
+// false once the queue is shut down and drained.
+func (q *Queue) Get() (key string, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.order) == 0 && !q.closed {
+		q.cond.Wait()
+	}
+	if len(q.order) == 0 {
+		return "", false
+	}
+	key = q.order[0]
+	q.order = q.order[1:]
+	delete(q.queued, key)
+	q.inflight[key] = true
+	return key, true
+}
+
+// Done marks a key's processing finished. If the key was re-added
+// while in flight it goes back on the queue.
+func (q *Queue) Done(key string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.inflight, key)
+	if q.queued[key] {
+		q.order = append(q.order, key)
+		q.cond.Signal()
+	}
+}
+
+// ShutDown wakes every blocked Get; pending keys are still delivered
+// before Get starts returning ok=false.
+func (q *Queue) ShutDown() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.cond.Broadcast()
+}
@@ -0,0 +1,302 @@
+package controller
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/acme"
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/kms"
+	"github.com/ptfpinho23/synthesis/pkg/logging"
+	"github.com/ptfpinho23/synthesis/pkg/store"
+)
+
+// renewBefore is how far ahead of expiry certificates are reissued,
+// matching the recommended third of a 90-day ACME lifetime.
+const renewBefore = 30 * 24 * time.Hour
+
+// accountKeySecret is where the ACME account key persists, so restarts
+// and leader changes reuse one account instead of minting new ones
+// against the CA's rate limits.
+const accountKeySecret = "acme-account-key"
+
+// CertificateController obtains and renews TLS certificates for
+// ingresses carrying the ACME annotation, validating over HTTP-01: the
+// key authorization is published through the server and every node's
+// ingress proxy serves it, so the CA may probe any ingress address.
+// Issued certificates land in the secret each TLS entry names, where
+// the proxies pick them up on their next pass.
+type CertificateController struct {
+	store  store.Store
+	log    *slog.Logger
+	health healthTracker
+	email  string
+	client *acme.Client
+
+	// SyncInterval is how often certificates are checked.
+	SyncInterval time.Duration
+
+	// Gate, when set, must return true for a pass to run.
+	Gate func() bool
+
+	// Record, when set, receives an event per issuance and failure.
+	Record func(api.Event)
+
+	// Publish and Unpublish hand HTTP-01 key authorizations to the
+	// server for the ingress proxies to serve.
+	Publish   func(token, keyAuth string)
+	Unpublish func(token string)
+
+	// KMS, when set, seals the secrets this controller writes the same
+	// way the server's secret endpoint does, so TLS and account keys
+	// never land in the store as plaintext.
+	KMS kms.Provider
+}
+
+// NewCertificateController returns a controller speaking to the given
+// ACME directory, registering with the contact email when set.
+func NewCertificateController(s store.Store, directory, email string) *CertificateController {
+	return &CertificateController{
+		store:        s,
+		log:          logging.Component("certificate"),
+		email:        email,
+		client:       &acme.Client{Directory: directory},
+		SyncInterval: time.Minute,
+	}
+}
+
+func (c *CertificateController) event(ev api.Event) {
+	if c.Record != nil {
+		c.Record(ev)
+	}
+}
+
+// Run reconciles until ctx is canceled, finishing any in-flight pass
+// first.
+func (c *CertificateController) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.SyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if c.Gate != nil && !c.Gate() {
+			continue
+		}
+		if err := c.reconcile(); err != nil {
+			c.log.Error("reconcile failed", "err", err)
+			continue
+		}
+		c.health.mark()
+	}
+}
+
+func (c *CertificateController) reconcile() error {
+	ings, err := c.store.ListIngresses()
+	if err != nil {
+		return fmt.Errorf("listing ingresses: %w", err)
+	}
+	for _, ing := range ings {
+		if ing.Meta.Annotations[api.ACMEAnnotation] != "true" {
+			continue
+		}
+		for _, t := range ing.Spec.TLS {
+			if !c.needsIssuance(namespaceOf(ing.Meta), t) {
+				continue
+			}
+			if err := c.issue(ing, t); err != nil {
+				c.log.Error("issuing certificate failed",
+					"namespace", namespaceOf(ing.Meta), "ingress", ing.Meta.Name,
+					"secret", t.SecretName, "err", err)
+				c.event(api.Event{
+					Type:    api.EventWarning,
+					Reason:  "IssuanceFailed",
+					Message: fmt.Sprintf("obtaining certificate for %v: %v", t.Hosts, err),
+					Regarding: api.ObjectRef{
+						Kind: "Ingress", Namespace: namespaceOf(ing.Meta), Name: ing.Meta.Name,
+					},
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// needsIssuance reports whether the secret is missing a certificate
+// covering every host with comfortable time left on the clock.
+func (c *CertificateController) needsIssuance(namespace string, t api.IngressTLS) bool {
+	sec, err := c.store.GetSecret(namespace, t.SecretName)
+	if err != nil {
+		return true
+	}
+	data, err := c.secretData(sec)
+	if err != nil {
+		return true
+	}
+	block, _ := pem.Decode(data["tls.crt"])
+	if block == nil {
+		return true
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return true
+	}
+	for _, host := range t.Hosts {
+		if !slices.Contains(cert.DNSNames, host) {
+			return true
+		}
+	}
+	return time.Until(cert.NotAfter) < renewBefore
+}
+
+func (c *CertificateController) issue(ing *api.Ingress, t api.IngressTLS) error {
+	if err := c.ensureAccount(); err != nil {
+		return err
+	}
+	solve := func(token, keyAuth string) error {
+		if c.Publish == nil {
+			return fmt.Errorf("no challenge publisher configured")
+		}
+		c.Publish(token, keyAuth)
+		return nil
+	}
+	cleanup := func(token string) {
+		if c.Unpublish != nil {
+			c.Unpublish(token)
+		}
+	}
+	certPEM, keyPEM, err := c.client.Obtain(t.Hosts, solve, cleanup)
+	if err != nil {
+		return err
+	}
+	namespace := namespaceOf(ing.Meta)
+	sec := &api.Secret{
+		Meta: api.ObjectMeta{
+			Namespace: namespace,
+			Name:      t.SecretName,
+			CreatedAt: time.Now(),
+		},
+		Data: map[string][]byte{"tls.crt": certPEM, "tls.key": keyPEM},
+	}
+	if err := c.putSecret(sec); err != nil {
+		return err
+	}
+	c.log.Info("issued certificate",
+		"namespace", namespace, "ingress", ing.Meta.Name,
+		"secret", t.SecretName, "hosts", t.Hosts)
+	c.event(api.Event{
+		Reason:  "CertificateIssued",
+		Message: fmt.Sprintf("obtained certificate for %v into secret %s", t.Hosts, t.SecretName),
+		Regarding: api.ObjectRef{
+			Kind: "Ingress", Namespace: namespace, Name: ing.Meta.Name,
+		},
+	})
+	return nil
+}
+
+// putSecret stores the secret, sealing its data first when a KMS
+// provider is configured — the same envelope the server's secret
+// endpoint writes, so the GET path opens it transparently.
+func (c *CertificateController) putSecret(sec *api.Secret) error {
+	if c.KMS != nil && sec.Data != nil {
+		payload, err := json.Marshal(sec.Data)
+		if err != nil {
+			return err
+		}
+		env, err := kms.Seal(c.KMS, payload)
+		if err != nil {
+			return err
+		}
+		sec.Encrypted = env
+		sec.Data = nil
+	}
+	return c.store.PutSecret(sec)
+}
+
+// secretData returns the secret's plaintext data, opening the KMS
+// envelope when it was stored sealed.
+func (c *CertificateController) secretData(sec *api.Secret) (map[string][]byte, error) {
+	if sec.Encrypted == nil {
+		return sec.Data, nil
+	}
+	if c.KMS == nil {
+		return nil, fmt.Errorf("secret %s/%s is encrypted but no KMS provider is configured",
+			sec.Meta.Namespace, sec.Meta.Name)
+	}
+	payload, err := kms.Open(c.KMS, sec.Encrypted)
+	if err != nil {
+		return nil, err
+	}
+	var data map[string][]byte
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// ensureAccount loads or creates the persistent ACME account key and
+// registers it with the directory.
+func (c *CertificateController) ensureAccount() error {
+	if c.client.Key != nil {
+		return nil
+	}
+	if sec, err := c.store.GetSecret("default", accountKeySecret); err == nil {
+		if data, err := c.secretData(sec); err == nil {
+			block, _ := pem.Decode(data["key"])
+			if block != nil {
+				if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+					c.client.Key = key
+				}
+			}
+		}
+	}
+	if c.client.Key == nil {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return err
+		}
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return err
+		}
+		sec := &api.Secret{
+			Meta: api.ObjectMeta{
+				Namespace: "default",
+				Name:      accountKeySecret,
+				CreatedAt: time.Now(),
+			},
+			Data: map[string][]byte{
+				"key": pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}),
+			},
+		}
+		if err := c.putSecret(sec); err != nil {
+			return err
+		}
+		c.client.Key = key
+	}
+	if err := c.client.Register(c.email); err != nil {
+		c.client.Key = nil // retry from the stored key next pass
+		return err
+	}
+	return nil
+}
+
+// Healthy reports whether the controller reconciled recently. Gated
+// followers idle on purpose and count as healthy.
+func (c *CertificateController) Healthy() error {
+	if c.Gate != nil && !c.Gate() {
+		return nil
+	}
+	return c.health.check(3 * c.SyncInterval)
+}
@@ -0,0 +1,248 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/logging"
+	"github.com/ptfpinho23/synthesis/pkg/scheduler"
+	"github.com/ptfpinho23/synthesis/pkg/store"
+)
+
+// DeschedulerController corrects the imbalance that accumulates as nodes
+// join and leave: pods placed while the cluster was smaller stay packed
+// on the old nodes while newcomers sit idle, because the scheduler only
+// decides at admission time. Each pass releases pods from nodes whose
+// requested resources exceed HighThreshold back to Pending so the
+// scheduler — which favors emptier nodes — can spread them out.
+// Evictions honor disruption budgets and never touch static, pinned or
+// finished pods. The loop is opt-in, wired up only when the operator
+// sets a threshold.
+type DeschedulerController struct {
+	store  store.Store
+	log    *slog.Logger
+	health healthTracker
+
+	// SyncInterval is how often balance is checked. Every eviction
+	// restarts containers somewhere, so passes are deliberately far
+	// apart.
+	SyncInterval time.Duration
+
+	// HighThreshold is the requested fraction of allocatable — cpu or
+	// memory, whichever is higher — above which a node counts as
+	// overloaded.
+	HighThreshold float64
+
+	// LowThreshold is the fraction below which a node counts as having
+	// room. With no node under it, evictions would only hand the
+	// scheduler the same crowded choices back, so the pass does nothing.
+	LowThreshold float64
+
+	// MaxEvictionsPerPass bounds churn: at most this many pods are
+	// released per pass across the whole cluster. Zero lifts the cap.
+	MaxEvictionsPerPass int
+
+	// Gate, when set, must return true for a pass to run. Used to hold
+	// controllers on non-leader replicas.
+	Gate func() bool
+
+	// Record, when set, receives an event for every eviction.
+	Record func(api.Event)
+}
+
+func (c *DeschedulerController) event(ev api.Event) {
+	if c.Record != nil {
+		c.Record(ev)
+	}
+}
+
+// NewDeschedulerController returns a descheduler with default tuning.
+func NewDeschedulerController(s store.Store) *DeschedulerController {
+	return &DeschedulerController{
+		store:               s,
+		log:                 logging.Component("descheduler"),
+		SyncInterval:        5 * time.Minute,
+		HighThreshold:       0.8,
+		LowThreshold:        0.5,
+		MaxEvictionsPerPass: 3,
+	}
+}
+
+// Run rebalances until ctx is canceled. An in-flight pass always
+// finishes before Run returns, so callers can drain cleanly on shutdown.
+func (c *DeschedulerController) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.SyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if c.Gate != nil && !c.Gate() {
+			continue
+		}
+		if err := c.reconcile(); err != nil {
+			c.log.Error("reconcile failed", "err", err)
+			continue
+		}
+		c.health.mark()
+	}
+}
+
+func (c *DeschedulerController) reconcile() error {
+	nodes, err := c.store.ListNodes()
+	if err != nil {
+		return fmt.Errorf("listing nodes: %w", err)
+	}
+	pods, err := c.store.ListPods()
+	if err != nil {
+		return fmt.Errorf("listing pods: %w", err)
+	}
+	pdbs, err := c.store.ListPodDisruptionBudgets()
+	if err != nil {
+		return fmt.Errorf("listing disruption budgets: %w", err)
+	}
+	classes, err := c.store.ListPriorityClasses()
+	if err != nil {
+		return fmt.Errorf("listing priority classes: %w", err)
+	}
+	prios := scheduler.NewPriorities(classes)
+
+	usage := make(map[string]float64, len(nodes))
+	for _, node := range nodes {
+		usage[node.Meta.Name] = requestedFraction(node, pods)
+	}
+	if !c.hasRoom(nodes, usage) {
+		return nil
+	}
+
+	// Busiest nodes first, so a tight eviction budget goes where it
+	// helps most.
+	sort.Slice(nodes, func(i, j int) bool { return usage[nodes[i].Meta.Name] > usage[nodes[j].Meta.Name] })
+	evictions := 0
+	for _, node := range nodes {
+		if usage[node.Meta.Name] <= c.HighThreshold {
+			break
+		}
+		for _, pod := range c.victims(node, pods, prios) {
+			if c.MaxEvictionsPerPass > 0 && evictions >= c.MaxEvictionsPerPass {
+				return nil
+			}
+			if err := api.CheckDisruptionBudgets(pod, pods, pdbs); err != nil {
+				c.log.Debug("keeping pod",
+					"namespace", pod.Meta.Namespace, "pod", pod.Meta.Name, "err", err)
+				continue
+			}
+			if err := c.evict(pod, node); err != nil {
+				return err
+			}
+			evictions++
+			// The evicted pod dropped out of the node's bound set, so
+			// recomputing sees the relieved load.
+			usage[node.Meta.Name] = requestedFraction(node, pods)
+			if usage[node.Meta.Name] <= c.HighThreshold {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// hasRoom reports whether any schedulable node has headroom below the
+// low threshold for released pods to land on.
+func (c *DeschedulerController) hasRoom(nodes []*api.Node, usage map[string]float64) bool {
+	for _, node := range nodes {
+		if node.Ready() && !node.Spec.Unschedulable && usage[node.Meta.Name] < c.LowThreshold {
+			return true
+		}
+	}
+	return false
+}
+
+// victims lists the node's evictable pods, lowest priority first so the
+// cheapest moves are tried before anything important. Static pods,
+// node-pinned pods and finished pods are never moved.
+func (c *DeschedulerController) victims(node *api.Node, pods []*api.Pod, prios *scheduler.Priorities) []*api.Pod {
+	var out []*api.Pod
+	for _, pod := range pods {
+		if pod.Status.NodeName != node.Meta.Name {
+			continue
+		}
+		if pod.Status.Phase == api.PodSucceeded || pod.Status.Phase == api.PodFailed {
+			continue
+		}
+		if pod.Spec.NodeName != "" || pod.Meta.Annotations[api.StaticPodAnnotation] != "" {
+			continue
+		}
+		out = append(out, pod)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		pi, pj := prios.Of(out[i]), prios.Of(out[j])
+		if pi != pj {
+			return pi < pj
+		}
+		return out[i].Meta.Name < out[j].Meta.Name
+	})
+	return out
+}
+
+// evict releases the pod back to Pending for the scheduler to replace.
+func (c *DeschedulerController) evict(pod *api.Pod, node *api.Node) error {
+	c.log.Info("descheduling pod from overloaded node",
+		"namespace", pod.Meta.Namespace, "pod", pod.Meta.Name, "node", node.Meta.Name)
+	pod.Status.NodeName = ""
+	pod.Status.Phase = api.PodPending
+	pod.Status.PodIP = ""
+	pod.Status.Message = fmt.Sprintf("rebalanced: node %s over requested threshold", node.Meta.Name)
+	if err := c.store.PutPod(pod); err != nil {
+		return err
+	}
+	c.event(api.Event{
+		Type:    api.EventNormal,
+		Reason:  "Descheduled",
+		Message: pod.Status.Message,
+		Regarding: api.ObjectRef{
+			Kind: "Pod", Namespace: pod.Meta.Namespace, Name: pod.Meta.Name,
+		},
+	})
+	return nil
+}
+
+// requestedFraction is the node's dominant requested share: the requests
+// of its bound pods over allocatable, taking the higher of cpu and
+// memory. Nodes that report no allocatable resources are never
+// overloaded.
+func requestedFraction(node *api.Node, pods []*api.Pod) float64 {
+	if len(node.Status.Allocatable) == 0 {
+		return 0
+	}
+	used := api.ResourceList{}
+	for _, pod := range pods {
+		if pod.Status.NodeName == node.Meta.Name {
+			used = used.Add(api.PodRequests(pod))
+		}
+	}
+	fraction := 0.0
+	for _, name := range []string{api.ResourceCPU, api.ResourceMemory} {
+		if alloc := node.Status.Allocatable[name]; alloc > 0 {
+			if f := float64(used[name]) / float64(alloc); f > fraction {
+				fraction = f
+			}
+		}
+	}
+	return fraction
+}
+
+// Healthy reports whether the controller reconciled recently. Gated
+// followers idle on purpose and count as healthy.
+func (c *DeschedulerController) Healthy() error {
+	if c.Gate != nil && !c.Gate() {
+		return nil
+	}
+	return c.health.check(3 * c.SyncInterval)
+}
@@ -0,0 +1,35 @@
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// healthTracker records when a loop last reconciled successfully, so
+// the server's health endpoint can tell a live controller from a
+// wedged one.
+type healthTracker struct {
+	mu          sync.Mutex
+	lastSuccess time.Time
+}
+
+// mark records a successful pass.
+func (h *healthTracker) mark() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastSuccess = time.Now()
+}
+
+// check fails when no pass has succeeded within staleAfter.
+func (h *healthTracker) check(staleAfter time.Duration) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.lastSuccess.IsZero() {
+		return fmt.Errorf("no successful reconcile yet")
+	}
+	if age := time.Since(h.lastSuccess); age > staleAfter {
+		return fmt.Errorf("last successful reconcile %s ago", age.Round(time.Second))
+	}
+	return nil
+}
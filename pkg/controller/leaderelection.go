@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/logging"
+	"github.com/ptfpinho23/synthesis/pkg/store"
+)
+
+// leaderLease is the well-known lease the controller leader holds.
+const leaderLease = "synthesis-controller-leader"
+
+// LeaderElector lets several synthesis-server replicas share one store:
+// every replica serves reads, but only the replica holding the leader
+// lease runs the reconcile loops. Followers take over once the leader
+// stops renewing.
+type LeaderElector struct {
+	store    store.Store
+	identity string
+	log      *slog.Logger
+
+	// RenewInterval is how often the leader refreshes its claim; the
+	// lease lasts three intervals.
+	RenewInterval time.Duration
+
+	leading atomic.Bool
+}
+
+// NewLeaderElector returns an elector campaigning under the given
+// identity, typically hostname plus pid.
+func NewLeaderElector(s store.Store, identity string) *LeaderElector {
+	return &LeaderElector{
+		store:         s,
+		identity:      identity,
+		log:           logging.Component("leaderelection"),
+		RenewInterval: 5 * time.Second,
+	}
+}
+
+// IsLeader reports whether this replica currently holds the lease. Pass
+// it as the Gate of every controller that must be singleton.
+func (e *LeaderElector) IsLeader() bool {
+	return e.leading.Load()
+}
+
+// Run campaigns until ctx is canceled: acquire the lease when free or
+// expired, renew it while held, and step down if another holder shows up.
+func (e *LeaderElector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.RenewInterval)
+	defer ticker.Stop()
+	for {
+		e.tick()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *LeaderElector) tick() {
+	now := time.Now()
+	lease, err := e.store.GetLease("", leaderLease)
+	if err == nil && lease.Spec.HolderIdentity != e.identity && !lease.Expired(now) {
+		if e.leading.Swap(false) {
+			e.log.Info("lost leadership", "holder", lease.Spec.HolderIdentity)
+		}
+		return
+	}
+	claim := &api.Lease{
+		Meta: api.ObjectMeta{Name: leaderLease},
+		Spec: api.LeaseSpec{
+			HolderIdentity:  e.identity,
+			DurationSeconds: int(3 * e.RenewInterval / time.Second),
+			RenewTime:       now,
+		},
+	}
+	if err := e.store.PutLease(claim); err != nil {
+		e.log.Error("renewing lease failed", "err", err)
+		e.leading.Store(false)
+		return
+	}
+	if !e.leading.Swap(true) {
+		e.log.Info("acquired leadership", "identity", e.identity)
+	}
+}
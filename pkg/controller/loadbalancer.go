@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/logging"
+	"github.com/ptfpinho23/synthesis/pkg/store"
+)
+
+// maxPoolSize caps how many addresses a pool may expand to, so a typo
+// like a /8 CIDR does not allocate a giant slice.
+const maxPoolSize = 1024
+
+// LoadBalancerController hands out addresses from a static pool to
+// LoadBalancer services, MetalLB-style: the allocation lands in the
+// service's status and the agents announce it on the local network in
+// layer-2 mode. Without this, bare-metal LoadBalancer services would
+// sit pending forever.
+type LoadBalancerController struct {
+	store  store.Store
+	log    *slog.Logger
+	health healthTracker
+	pool   []string
+
+	// SyncInterval is how often services are reconciled.
+	SyncInterval time.Duration
+
+	// Gate, when set, must return true for a pass to run.
+	Gate func() bool
+
+	// Record, when set, receives an event per allocation and failure.
+	Record func(api.Event)
+}
+
+// NewLoadBalancerController returns a controller allocating from the
+// given pool, written as a CIDR ("192.168.1.240/28") or a dash range
+// ("192.168.1.240-192.168.1.250").
+func NewLoadBalancerController(s store.Store, pool string) (*LoadBalancerController, error) {
+	ips, err := parseAddressPool(pool)
+	if err != nil {
+		return nil, err
+	}
+	return &LoadBalancerController{
+		store:        s,
+		log:          logging.Component("loadbalancer"),
+		pool:         ips,
+		SyncInterval: 10 * time.Second,
+	}, nil
+}
+
+func parseAddressPool(pool string) ([]string, error) {
+	var ips []string
+	if from, to, ok := strings.Cut(pool, "-"); ok {
+		start, err := netip.ParseAddr(strings.TrimSpace(from))
+		if err != nil {
+			return nil, fmt.Errorf("address pool: %w", err)
+		}
+		end, err := netip.ParseAddr(strings.TrimSpace(to))
+		if err != nil {
+			return nil, fmt.Errorf("address pool: %w", err)
+		}
+		for a := start; a.Compare(end) <= 0; a = a.Next() {
+			if len(ips) >= maxPoolSize {
+				return nil, fmt.Errorf("address pool holds more than %d addresses", maxPoolSize)
+			}
+			ips = append(ips, a.String())
+		}
+		return ips, nil
+	}
+	prefix, err := netip.ParsePrefix(pool)
+	if err != nil {
+		return nil, fmt.Errorf("address pool: %w", err)
+	}
+	// Skip the network and broadcast addresses of IPv4 prefixes.
+	for a := prefix.Addr().Next(); prefix.Contains(a.Next()); a = a.Next() {
+		if len(ips) >= maxPoolSize {
+			return nil, fmt.Errorf("address pool holds more than %d addresses", maxPoolSize)
+		}
+		ips = append(ips, a.String())
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("address pool %q holds no usable addresses", pool)
+	}
+	return ips, nil
+}
+
+func (c *LoadBalancerController) event(ev api.Event) {
+	if c.Record != nil {
+		c.Record(ev)
+	}
+}
+
+// Run reconciles until ctx is canceled, finishing any in-flight pass
+// first.
+func (c *LoadBalancerController) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.SyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if c.Gate != nil && !c.Gate() {
+			continue
+		}
+		if err := c.reconcile(); err != nil {
+			c.log.Error("reconcile failed", "err", err)
+			continue
+		}
+		c.health.mark()
+	}
+}
+
+func (c *LoadBalancerController) reconcile() error {
+	svcs, err := c.store.ListServices()
+	if err != nil {
+		return fmt.Errorf("listing services: %w", err)
+	}
+	// Existing in-pool allocations stay put across restarts; only
+	// addresses nothing holds are handed out.
+	used := make(map[string]bool)
+	for _, svc := range svcs {
+		for _, ing := range svc.Status.LoadBalancer.Ingress {
+			used[ing.IP] = true
+		}
+	}
+	for _, svc := range svcs {
+		switch {
+		case svc.Spec.Type == api.ServiceLoadBalancer && len(svc.Status.LoadBalancer.Ingress) == 0:
+			if err := c.allocate(svc, used); err != nil {
+				return err
+			}
+		case svc.Spec.Type != api.ServiceLoadBalancer && len(svc.Status.LoadBalancer.Ingress) > 0:
+			// The service stopped asking for an address; free it.
+			svc.Status.LoadBalancer.Ingress = nil
+			if err := c.store.PutService(svc); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *LoadBalancerController) allocate(svc *api.Service, used map[string]bool) error {
+	for _, ip := range c.pool {
+		if used[ip] {
+			continue
+		}
+		used[ip] = true
+		svc.Status.LoadBalancer.Ingress = []api.LoadBalancerIngress{{IP: ip}}
+		if err := c.store.PutService(svc); err != nil {
+			return err
+		}
+		c.log.Info("allocated load balancer address",
+			"namespace", namespaceOf(svc.Meta), "service", svc.Meta.Name, "ip", ip)
+		c.event(api.Event{
+			Reason:  "IPAllocated",
+			Message: fmt.Sprintf("assigned %s from the address pool", ip),
+			Regarding: api.ObjectRef{
+				Kind: "Service", Namespace: namespaceOf(svc.Meta), Name: svc.Meta.Name,
+			},
+		})
+		return nil
+	}
+	c.event(api.Event{
+		Type:    api.EventWarning,
+		Reason:  "AllocationFailed",
+		Message: "address pool is exhausted",
+		Regarding: api.ObjectRef{
+			Kind: "Service", Namespace: namespaceOf(svc.Meta), Name: svc.Meta.Name,
+		},
+	})
+	return nil
+}
+
+// Healthy reports whether the controller reconciled recently. Gated
+// followers idle on purpose and count as healthy.
+func (c *LoadBalancerController) Healthy() error {
+	if c.Gate != nil && !c.Gate() {
+		return nil
+	}
+	return c.health.check(3 * c.SyncInterval)
+}
@@ -0,0 +1,193 @@
+// Package controller holds the reconciliation loops that turn desired
+// state in the store into observed state: node health, workload scaling
+// and whatever else needs a periodic pass.
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/logging"
+	"github.com/ptfpinho23/synthesis/pkg/store"
+)
+
+// NodeLifecycleController watches node leases, flips the Ready condition
+// when heartbeats stop, and reschedules pods off nodes that stay dead
+// past the eviction timeout.
+type NodeLifecycleController struct {
+	store  store.Store
+	log    *slog.Logger
+	health healthTracker
+
+	// MonitorInterval is how often the controller scans leases.
+	MonitorInterval time.Duration
+
+	// EvictionTimeout is how long a node may stay NotReady before its
+	// pods are released for rescheduling.
+	EvictionTimeout time.Duration
+
+	// Gate, when set, must return true for a pass to run. Used to hold
+	// controllers on non-leader replicas.
+	Gate func() bool
+
+	// Record, when set, receives an event for every readiness
+	// transition and eviction.
+	Record func(api.Event)
+}
+
+func (c *NodeLifecycleController) event(ev api.Event) {
+	if c.Record != nil {
+		c.Record(ev)
+	}
+}
+
+// NewNodeLifecycleController returns a controller with default timings.
+func NewNodeLifecycleController(s store.Store) *NodeLifecycleController {
+	return &NodeLifecycleController{
+		store:           s,
+		log:             logging.Component("nodelifecycle"),
+		MonitorInterval: 5 * time.Second,
+		EvictionTimeout: 60 * time.Second,
+	}
+}
+
+// Run scans until ctx is canceled. An in-flight pass always finishes
+// before Run returns, so callers can drain cleanly on shutdown.
+func (c *NodeLifecycleController) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.MonitorInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if c.Gate != nil && !c.Gate() {
+			continue
+		}
+		if err := c.reconcile(); err != nil {
+			c.log.Error("reconcile failed", "err", err)
+			continue
+		}
+		c.health.mark()
+	}
+}
+
+func (c *NodeLifecycleController) reconcile() error {
+	nodes, err := c.store.ListNodes()
+	if err != nil {
+		return fmt.Errorf("listing nodes: %w", err)
+	}
+	now := time.Now()
+	for _, node := range nodes {
+		alive := c.leaseAlive(node.Meta.Name, now)
+		if err := c.setReady(node, alive, now); err != nil {
+			return err
+		}
+		if !alive {
+			if err := c.maybeEvict(node, now); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// leaseAlive reports whether the node's lease exists and is unexpired.
+// A node that never wrote a lease is treated as alive so clusters whose
+// agents predate leases keep working.
+func (c *NodeLifecycleController) leaseAlive(nodeName string, now time.Time) bool {
+	lease, err := c.store.GetLease("", nodeName)
+	if err != nil {
+		return true
+	}
+	return !lease.Expired(now)
+}
+
+// setReady records the Ready condition, only writing on transitions.
+func (c *NodeLifecycleController) setReady(node *api.Node, ready bool, now time.Time) error {
+	cond := node.Status.Condition(api.NodeReady)
+	if cond != nil && cond.Status == ready {
+		return nil
+	}
+	reason, message := "HeartbeatTimeout", "node lease expired"
+	if ready {
+		reason, message = "HeartbeatRenewed", "node lease is current"
+	}
+	if cond == nil {
+		node.Status.Conditions = append(node.Status.Conditions, api.NodeCondition{Type: api.NodeReady})
+		cond = &node.Status.Conditions[len(node.Status.Conditions)-1]
+	}
+	cond.Status = ready
+	cond.Reason = reason
+	cond.Message = message
+	cond.LastTransitionTime = now
+	c.log.Info("node readiness changed", "node", node.Meta.Name, "ready", ready, "reason", reason)
+	eventType, eventReason := api.EventWarning, "NodeNotReady"
+	if ready {
+		eventType, eventReason = api.EventNormal, "NodeReady"
+	}
+	c.event(api.Event{
+		Type:      eventType,
+		Reason:    eventReason,
+		Message:   message,
+		Regarding: api.ObjectRef{Kind: "Node", Name: node.Meta.Name},
+	})
+	return c.store.PutNode(node)
+}
+
+// maybeEvict releases the node's pods back to Pending once the node has
+// been NotReady longer than the eviction timeout, so the scheduler can
+// place them elsewhere.
+func (c *NodeLifecycleController) maybeEvict(node *api.Node, now time.Time) error {
+	cond := node.Status.Condition(api.NodeReady)
+	if cond == nil || cond.Status || now.Sub(cond.LastTransitionTime) < c.EvictionTimeout {
+		return nil
+	}
+	pods, err := c.store.ListPods()
+	if err != nil {
+		return fmt.Errorf("listing pods: %w", err)
+	}
+	for _, pod := range pods {
+		if pod.Status.NodeName != node.Meta.Name {
+			continue
+		}
+		// Finished pods have nothing left to reschedule.
+		if pod.Status.Phase == api.PodSucceeded || pod.Status.Phase == api.PodFailed {
+			continue
+		}
+		c.log.Info("evicting pod from dead node",
+			"namespace", pod.Meta.Namespace, "pod", pod.Meta.Name, "node", node.Meta.Name)
+		pod.Status.NodeName = ""
+		pod.Status.Phase = api.PodPending
+		pod.Status.PodIP = ""
+		pod.Status.Message = fmt.Sprintf("rescheduled: node %s not ready", node.Meta.Name)
+		// A graceful eviction the dead node never finished must not
+		// follow the pod to its next placement.
+		delete(pod.Meta.Annotations, api.EvictionGracePeriodAnnotation)
+		if err := c.store.PutPod(pod); err != nil {
+			return err
+		}
+		c.event(api.Event{
+			Type:    api.EventWarning,
+			Reason:  "Evicted",
+			Message: pod.Status.Message,
+			Regarding: api.ObjectRef{
+				Kind: "Pod", Namespace: pod.Meta.Namespace, Name: pod.Meta.Name,
+			},
+		})
+	}
+	return nil
+}
+
+// Healthy reports whether the controller reconciled recently. Gated
+// followers idle on purpose and count as healthy.
+func (c *NodeLifecycleController) Healthy() error {
+	if c.Gate != nil && !c.Gate() {
+		return nil
+	}
+	return c.health.check(3 * c.MonitorInterval)
+}
@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/logging"
+	"github.com/ptfpinho23/synthesis/pkg/store"
+)
+
+// PodCIDRController carves the cluster pod CIDR into one /24 per node,
+// writing the allocation into node.Spec.PodCIDR. Agents route the
+// overlay mesh from these allocations.
+type PodCIDRController struct {
+	store  store.Store
+	log    *slog.Logger
+	health healthTracker
+
+	// ClusterCIDR is the range sliced up across nodes.
+	ClusterCIDR *net.IPNet
+
+	// SyncInterval is how often unallocated nodes are scanned for.
+	SyncInterval time.Duration
+
+	// Gate, when set, must return true for a pass to run. Used to hold
+	// controllers on non-leader replicas.
+	Gate func() bool
+}
+
+// NewPodCIDRController returns a controller allocating out of the given
+// cluster CIDR, which must be at least a /16 wide for /24 slices.
+func NewPodCIDRController(s store.Store, clusterCIDR string) (*PodCIDRController, error) {
+	_, ipnet, err := net.ParseCIDR(clusterCIDR)
+	if err != nil {
+		return nil, fmt.Errorf("parsing cluster CIDR %q: %w", clusterCIDR, err)
+	}
+	if ones, _ := ipnet.Mask.Size(); ones > 24 {
+		return nil, fmt.Errorf("cluster CIDR %q is narrower than /24", clusterCIDR)
+	}
+	return &PodCIDRController{
+		store:        s,
+		log:          logging.Component("podcidr"),
+		ClusterCIDR:  ipnet,
+		SyncInterval: 10 * time.Second,
+	}, nil
+}
+
+// Run allocates until ctx is canceled, finishing any in-flight pass
+// first.
+func (c *PodCIDRController) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.SyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if c.Gate != nil && !c.Gate() {
+			continue
+		}
+		if err := c.reconcile(); err != nil {
+			c.log.Error("reconcile failed", "err", err)
+			continue
+		}
+		c.health.mark()
+	}
+}
+
+func (c *PodCIDRController) reconcile() error {
+	nodes, err := c.store.ListNodes()
+	if err != nil {
+		return fmt.Errorf("listing nodes: %w", err)
+	}
+	taken := make(map[string]bool)
+	for _, node := range nodes {
+		if node.Spec.PodCIDR != "" {
+			taken[node.Spec.PodCIDR] = true
+		}
+	}
+	for _, node := range nodes {
+		if node.Spec.PodCIDR != "" {
+			continue
+		}
+		cidr, err := c.nextFree(taken)
+		if err != nil {
+			return err
+		}
+		node.Spec.PodCIDR = cidr
+		taken[cidr] = true
+		if err := c.store.PutNode(node); err != nil {
+			return err
+		}
+		c.log.Info("allocated pod CIDR", "cidr", cidr, "node", node.Meta.Name)
+	}
+	return nil
+}
+
+// nextFree returns the first /24 inside the cluster CIDR not yet handed
+// to a node.
+func (c *PodCIDRController) nextFree(taken map[string]bool) (string, error) {
+	base := c.ClusterCIDR.IP.To4()
+	if base == nil {
+		return "", fmt.Errorf("cluster CIDR must be IPv4")
+	}
+	ones, _ := c.ClusterCIDR.Mask.Size()
+	slices := 1 << (24 - ones)
+	for i := 0; i < slices; i++ {
+		ip := make(net.IP, 4)
+		copy(ip, base)
+		ip[2] = base[2] + byte(i&0xff)
+		ip[1] = base[1] + byte(i>>8)
+		cidr := fmt.Sprintf("%s/24", ip)
+		if !taken[cidr] {
+			return cidr, nil
+		}
+	}
+	return "", fmt.Errorf("cluster CIDR %s exhausted", c.ClusterCIDR)
+}
+
+// Healthy reports whether the controller reconciled recently. Gated
+// followers idle on purpose and count as healthy.
+func (c *PodCIDRController) Healthy() error {
+	if c.Gate != nil && !c.Gate() {
+		return nil
+	}
+	return c.health.check(3 * c.SyncInterval)
+}
@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/logging"
+	"github.com/ptfpinho23/synthesis/pkg/store"
+)
+
+// recommendAnnotationPrefix heads the per-container annotations the
+// recommender writes, e.g. recommend.synthesis.io/web.cpu.
+const recommendAnnotationPrefix = "recommend.synthesis.io/"
+
+// RecommendationController is VPA-lite: it watches each container's
+// retained usage history and annotates the pod with right-sized
+// requests (peak usage plus headroom), so workloads on memory-tight
+// nodes can be tuned without external tooling. Recommendations are
+// advisory; nothing is resized in place.
+type RecommendationController struct {
+	store store.Store
+
+	// history returns a pod's usage samples, typically the API server's
+	// in-memory metrics window.
+	history func(namespace, name string) []api.PodMetrics
+
+	log    *slog.Logger
+	health healthTracker
+
+	// SyncInterval is how often recommendations are recomputed.
+	SyncInterval time.Duration
+
+	// MinSamples is how many usage samples a container needs before a
+	// recommendation is written.
+	MinSamples int
+
+	// Headroom scales the observed peak into the recommendation.
+	Headroom float64
+
+	// Gate, when set, must return true for a pass to run.
+	Gate func() bool
+}
+
+// NewRecommendationController returns a recommender with default
+// tuning.
+func NewRecommendationController(s store.Store, history func(namespace, name string) []api.PodMetrics) *RecommendationController {
+	return &RecommendationController{
+		store:        s,
+		history:      history,
+		log:          logging.Component("recommender"),
+		SyncInterval: time.Minute,
+		MinSamples:   6,
+		Headroom:     1.2,
+	}
+}
+
+// Run recomputes until ctx is canceled, finishing any in-flight pass
+// first.
+func (c *RecommendationController) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.SyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if c.Gate != nil && !c.Gate() {
+			continue
+		}
+		if err := c.reconcile(); err != nil {
+			c.log.Error("reconcile failed", "err", err)
+			continue
+		}
+		c.health.mark()
+	}
+}
+
+func (c *RecommendationController) reconcile() error {
+	pods, err := c.store.ListPods()
+	if err != nil {
+		return fmt.Errorf("listing pods: %w", err)
+	}
+	for _, pod := range pods {
+		if pod.Status.Phase == api.PodSucceeded || pod.Status.Phase == api.PodFailed {
+			continue
+		}
+		samples := c.history(pod.Meta.Namespace, pod.Meta.Name)
+		if len(samples) < c.MinSamples {
+			continue
+		}
+		if !c.annotate(pod, samples) {
+			continue
+		}
+		c.log.Debug("updated recommendations",
+			"namespace", pod.Meta.Namespace, "pod", pod.Meta.Name)
+		if err := c.store.PutPod(pod); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// annotate writes the per-container recommendations onto the pod and
+// reports whether anything changed.
+func (c *RecommendationController) annotate(pod *api.Pod, samples []api.PodMetrics) bool {
+	peaks := make(map[string]api.ResourceList)
+	for _, sample := range samples {
+		for _, cm := range sample.Containers {
+			peak := peaks[cm.Name]
+			if peak == nil {
+				peak = api.ResourceList{}
+				peaks[cm.Name] = peak
+			}
+			for resource, value := range cm.Usage {
+				if value > peak[resource] {
+					peak[resource] = value
+				}
+			}
+		}
+	}
+	changed := false
+	for name, peak := range peaks {
+		for _, resource := range []string{api.ResourceCPU, api.ResourceMemory} {
+			recommended := int64(float64(peak[resource]) * c.Headroom)
+			if recommended == 0 {
+				continue
+			}
+			key := recommendAnnotationPrefix + name + "." + resource
+			value := strconv.FormatInt(recommended, 10)
+			if pod.Meta.Annotations[key] == value {
+				continue
+			}
+			if pod.Meta.Annotations == nil {
+				pod.Meta.Annotations = map[string]string{}
+			}
+			pod.Meta.Annotations[key] = value
+			changed = true
+		}
+	}
+	return changed
+}
+
+// Healthy reports whether the controller reconciled recently. Gated
+// followers idle on purpose and count as healthy.
+func (c *RecommendationController) Healthy() error {
+	if c.Gate != nil && !c.Gate() {
+		return nil
+	}
+	return c.health.check(3 * c.SyncInterval)
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/logging"
+	"github.com/ptfpinho23/synthesis/pkg/store"
+)
+
+// ServiceAccountController ensures every namespace that holds pods has
+// a "default" service account for them to run as.
+type ServiceAccountController struct {
+	store  store.Store
+	log    *slog.Logger
+	health healthTracker
+
+	// SyncInterval is how often namespaces are scanned.
+	SyncInterval time.Duration
+
+	// Gate, when set, must return true for a pass to run. Used to hold
+	// controllers on non-leader replicas.
+	Gate func() bool
+}
+
+// NewServiceAccountController returns a controller with default timings.
+func NewServiceAccountController(s store.Store) *ServiceAccountController {
+	return &ServiceAccountController{
+		store:        s,
+		log:          logging.Component("serviceaccount"),
+		SyncInterval: 10 * time.Second,
+	}
+}
+
+// Run reconciles until ctx is canceled, finishing any in-flight pass
+// first.
+func (c *ServiceAccountController) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.SyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if c.Gate != nil && !c.Gate() {
+			continue
+		}
+		if err := c.reconcile(); err != nil {
+			c.log.Error("reconcile failed", "err", err)
+			continue
+		}
+		c.health.mark()
+	}
+}
+
+func (c *ServiceAccountController) reconcile() error {
+	pods, err := c.store.ListPods()
+	if err != nil {
+		return fmt.Errorf("listing pods: %w", err)
+	}
+	namespaces := map[string]bool{"default": true}
+	for _, pod := range pods {
+		ns := pod.Meta.Namespace
+		if ns == "" {
+			ns = "default"
+		}
+		namespaces[ns] = true
+	}
+	for ns := range namespaces {
+		if _, err := c.store.GetServiceAccount(ns, "default"); err == nil {
+			continue
+		}
+		sa := &api.ServiceAccount{Meta: api.ObjectMeta{
+			Namespace: ns,
+			Name:      "default",
+			CreatedAt: time.Now(),
+		}}
+		if err := c.store.PutServiceAccount(sa); err != nil {
+			return err
+		}
+		c.log.Info("created default service account", "namespace", ns)
+	}
+	return nil
+}
+
+// Healthy reports whether the controller reconciled recently. Gated
+// followers idle on purpose and count as healthy.
+func (c *ServiceAccountController) Healthy() error {
+	if c.Gate != nil && !c.Gate() {
+		return nil
+	}
+	return c.health.check(3 * c.SyncInterval)
+}
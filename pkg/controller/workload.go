@@ -0,0 +1,341 @@
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/logging"
+	"github.com/ptfpinho23/synthesis/pkg/store"
+)
+
+// WorkloadController keeps each workload's pod count at its declared
+// replicas: it stamps missing pods from the template, replaces pods
+// that exited, trims excess ones, and maintains the workload's status
+// counts. Pods are tied to their owner by the synthesis.io/workload
+// label, so pods created by hand are never touched.
+type WorkloadController struct {
+	store  store.Store
+	log    *slog.Logger
+	health healthTracker
+
+	// SyncInterval is how often workloads are reconciled.
+	SyncInterval time.Duration
+
+	// Gate, when set, must return true for a pass to run.
+	Gate func() bool
+
+	// Record, when set, receives an event for every replica created or
+	// deleted.
+	Record func(api.Event)
+}
+
+func (c *WorkloadController) event(ev api.Event) {
+	if c.Record != nil {
+		c.Record(ev)
+	}
+}
+
+// NewWorkloadController returns a controller with default tuning.
+func NewWorkloadController(s store.Store) *WorkloadController {
+	return &WorkloadController{
+		store:        s,
+		log:          logging.Component("workload"),
+		SyncInterval: 5 * time.Second,
+	}
+}
+
+// Run reconciles until ctx is canceled, finishing any in-flight pass
+// first.
+func (c *WorkloadController) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.SyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if c.Gate != nil && !c.Gate() {
+			continue
+		}
+		if err := c.reconcile(); err != nil {
+			c.log.Error("reconcile failed", "err", err)
+			continue
+		}
+		c.health.mark()
+	}
+}
+
+func (c *WorkloadController) reconcile() error {
+	workloads, err := c.store.ListWorkloads()
+	if err != nil {
+		return fmt.Errorf("listing workloads: %w", err)
+	}
+	pods, err := c.store.ListPods()
+	if err != nil {
+		return fmt.Errorf("listing pods: %w", err)
+	}
+	for _, wl := range workloads {
+		if err := c.reconcileWorkload(wl, ownedPods(wl, pods)); err != nil {
+			return fmt.Errorf("workload %s/%s: %w", namespaceOf(wl.Meta), wl.Meta.Name, err)
+		}
+	}
+	return nil
+}
+
+// ownedPods filters the pods carrying this workload's label in its
+// namespace.
+func ownedPods(wl *api.Workload, pods []*api.Pod) []*api.Pod {
+	var owned []*api.Pod
+	for _, pod := range pods {
+		if pod.Meta.Labels[api.WorkloadLabel] == wl.Meta.Name &&
+			namespaceOf(pod.Meta) == namespaceOf(wl.Meta) {
+			owned = append(owned, pod)
+		}
+	}
+	return owned
+}
+
+func namespaceOf(meta api.ObjectMeta) string {
+	if meta.Namespace == "" {
+		return "default"
+	}
+	return meta.Namespace
+}
+
+func (c *WorkloadController) reconcileWorkload(wl *api.Workload, owned []*api.Pod) error {
+	if wl.Spec.Paused {
+		// Staged spec changes wait for resume: nothing is created,
+		// replaced or trimmed, but the observed counts keep flowing.
+		alive := owned[:0]
+		for _, pod := range owned {
+			if pod.Status.Phase != api.PodSucceeded && pod.Status.Phase != api.PodFailed {
+				alive = append(alive, pod)
+			}
+		}
+		return c.updateStatus(wl, alive)
+	}
+
+	// Exited pods are deleted rather than restarted in place, so the
+	// replacement goes back through scheduling and can land elsewhere.
+	alive := owned[:0]
+	for _, pod := range owned {
+		if pod.Status.Phase == api.PodSucceeded || pod.Status.Phase == api.PodFailed {
+			if err := c.deletePod(wl, pod, "replacing exited pod"); err != nil {
+				return err
+			}
+			continue
+		}
+		alive = append(alive, pod)
+	}
+
+	// A restart stamp on the template marks every pod created before it
+	// for replacement. One victim per pass, and only while every other
+	// replica runs, keeps the restart rolling: each replacement must
+	// come up before the next pod goes.
+	if want := wl.Spec.Template.Meta.Annotations[api.RestartedAtAnnotation]; want != "" {
+		ready := true
+		for _, pod := range alive {
+			if pod.Status.Phase != api.PodRunning {
+				ready = false
+				break
+			}
+		}
+		for i, pod := range alive {
+			if !ready || pod.Meta.Annotations[api.RestartedAtAnnotation] == want {
+				continue
+			}
+			if err := c.deletePod(wl, pod, "restarting"); err != nil {
+				return err
+			}
+			alive = append(alive[:i], alive[i+1:]...)
+			break
+		}
+	}
+
+	switch wl.EffectiveKind() {
+	case api.WorkloadStatefulSet:
+		if err := c.reconcileStateful(wl, alive); err != nil {
+			return err
+		}
+	default:
+		if err := c.reconcileStateless(wl, alive); err != nil {
+			return err
+		}
+	}
+	return c.updateStatus(wl, alive)
+}
+
+// reconcileStateless keeps len(alive) at the replica count, with
+// random-suffixed names. Excess pods are trimmed in scale-down order,
+// so the replicas that survive are the warmed-up ones.
+func (c *WorkloadController) reconcileStateless(wl *api.Workload, alive []*api.Pod) error {
+	for i := len(alive); i < wl.Spec.Replicas; i++ {
+		if err := c.createPod(wl, wl.Meta.Name+"-"+nameSuffix()); err != nil {
+			return err
+		}
+	}
+	if len(alive) > wl.Spec.Replicas {
+		sort.Slice(alive, func(i, j int) bool { return scaleDownBefore(alive[i], alive[j]) })
+		for _, pod := range alive[:len(alive)-wl.Spec.Replicas] {
+			if err := c.deletePod(wl, pod, "scaling down"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// DeletionCostAnnotation biases scale-down victim selection: pods with
+// lower cost go first. The Kubernetes annotation name is honored so
+// tooling that already sets it carries over.
+const DeletionCostAnnotation = "controller.kubernetes.io/pod-deletion-cost"
+
+// scaleDownBefore ranks which of two pods to delete first on scale
+// down: lowest deletion cost, then not-running before running, then
+// newest first, with the name as a final tiebreak so every pass picks
+// the same victims.
+func scaleDownBefore(a, b *api.Pod) bool {
+	if ca, cb := deletionCost(a), deletionCost(b); ca != cb {
+		return ca < cb
+	}
+	if ra, rb := a.Status.Phase == api.PodRunning, b.Status.Phase == api.PodRunning; ra != rb {
+		return !ra
+	}
+	if !a.Meta.CreatedAt.Equal(b.Meta.CreatedAt) {
+		return a.Meta.CreatedAt.After(b.Meta.CreatedAt)
+	}
+	return a.Meta.Name > b.Meta.Name
+}
+
+// deletionCost reads the pod's deletion-cost annotation; pods without
+// one (or with garbage in it) cost zero.
+func deletionCost(pod *api.Pod) int {
+	n, err := strconv.Atoi(pod.Meta.Annotations[DeletionCostAnnotation])
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// reconcileStateful keeps exactly the ordinal names name-0 through
+// name-N-1, creating the missing ones and removing anything outside
+// that range.
+func (c *WorkloadController) reconcileStateful(wl *api.Workload, alive []*api.Pod) error {
+	byName := make(map[string]*api.Pod, len(alive))
+	for _, pod := range alive {
+		byName[pod.Meta.Name] = pod
+	}
+	want := make(map[string]bool, wl.Spec.Replicas)
+	for i := 0; i < wl.Spec.Replicas; i++ {
+		name := wl.Meta.Name + "-" + strconv.Itoa(i)
+		want[name] = true
+		if byName[name] == nil {
+			if err := c.createPod(wl, name); err != nil {
+				return err
+			}
+		}
+	}
+	for _, pod := range alive {
+		if !want[pod.Meta.Name] {
+			if err := c.deletePod(wl, pod, "scaling down"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// createPod stamps one replica from the template.
+func (c *WorkloadController) createPod(wl *api.Workload, name string) error {
+	labels := make(map[string]string, len(wl.Spec.Template.Meta.Labels)+1)
+	for k, v := range wl.Spec.Template.Meta.Labels {
+		labels[k] = v
+	}
+	labels[api.WorkloadLabel] = wl.Meta.Name
+	pod := &api.Pod{
+		Meta: api.ObjectMeta{
+			Name:        name,
+			Namespace:   wl.Meta.Namespace,
+			Labels:      labels,
+			Annotations: wl.Spec.Template.Meta.Annotations,
+			CreatedAt:   time.Now(),
+		},
+		Spec:   wl.Spec.Template.Spec,
+		Status: api.PodStatus{Phase: api.PodPending},
+	}
+	c.log.Info("creating pod",
+		"namespace", namespaceOf(wl.Meta), "workload", wl.Meta.Name, "pod", name)
+	if err := c.store.PutPod(pod); err != nil {
+		return err
+	}
+	c.event(api.Event{
+		Reason:  "SuccessfulCreate",
+		Message: fmt.Sprintf("created pod %s", name),
+		Regarding: api.ObjectRef{
+			Kind: "Workload", Namespace: namespaceOf(wl.Meta), Name: wl.Meta.Name,
+		},
+	})
+	return nil
+}
+
+func (c *WorkloadController) deletePod(wl *api.Workload, pod *api.Pod, why string) error {
+	c.log.Info("deleting pod",
+		"namespace", namespaceOf(wl.Meta), "workload", wl.Meta.Name,
+		"pod", pod.Meta.Name, "reason", why)
+	if err := c.store.DeletePod(pod.Meta.Namespace, pod.Meta.Name); err != nil {
+		return err
+	}
+	c.event(api.Event{
+		Reason:  "SuccessfulDelete",
+		Message: fmt.Sprintf("deleted pod %s: %s", pod.Meta.Name, why),
+		Regarding: api.ObjectRef{
+			Kind: "Workload", Namespace: namespaceOf(wl.Meta), Name: wl.Meta.Name,
+		},
+	})
+	return nil
+}
+
+// updateStatus records the counts observed this pass, writing only on
+// change. Pods created above count from the next pass.
+func (c *WorkloadController) updateStatus(wl *api.Workload, alive []*api.Pod) error {
+	ready := 0
+	for _, pod := range alive {
+		if pod.Status.Phase == api.PodRunning {
+			ready++
+		}
+	}
+	status := api.WorkloadStatus{Replicas: len(alive), ReadyReplicas: ready}
+	if wl.Status == status {
+		return nil
+	}
+	wl.Status = status
+	return c.store.PutWorkload(wl)
+}
+
+// nameSuffix is the random tail on stateless replica names, mirroring
+// the hashes Kubernetes appends.
+func nameSuffix() string {
+	var b [3]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return strings.ToLower(strconv.FormatInt(time.Now().UnixNano()%1e6, 36))
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// Healthy reports whether the controller reconciled recently. Gated
+// followers idle on purpose and count as healthy.
+func (c *WorkloadController) Healthy() error {
+	if c.Gate != nil && !c.Gate() {
+		return nil
+	}
+	return c.health.check(3 * c.SyncInterval)
+}
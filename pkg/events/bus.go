@@ -0,0 +1,120 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/ptfpinho23/synthesis/pkg/logging"
+)
+
+// bus publishes a raw payload for one resource; each implementation
+// maps the resource name onto its own subject or topic scheme.
+type bus interface {
+	name() string
+	publishResource(resource string, payload []byte) error
+}
+
+// ChangeEvent is the payload published for every resource mutation:
+// the same shape the watch streams emit, plus the resource name so
+// subscribers on a wildcard subject can demultiplex.
+type ChangeEvent struct {
+	Resource string `json:"resource"`
+	Type     string `json:"type"` // ADDED, MODIFIED or DELETED
+	Object   any    `json:"object"`
+}
+
+// BusPublisher mirrors resource change events onto a message bus so
+// edge systems react to deployments without polling the REST API.
+// Publishing is asynchronous and best-effort, like the Notifier.
+type BusPublisher struct {
+	log *slog.Logger
+	bus bus
+	ch  chan ChangeEvent
+}
+
+// NewBusPublisher parses a bus URL of the form nats://host:4222[/prefix]
+// or mqtt://host:1883[/prefix]. NATS subjects become prefix.resource
+// (default prefix synthesis.events); MQTT topics become prefix/resource
+// (default synthesis/events).
+func NewBusPublisher(rawURL string) (*BusPublisher, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing bus URL: %w", err)
+	}
+	prefix := strings.Trim(u.Path, "/")
+	var b bus
+	switch u.Scheme {
+	case "nats":
+		if prefix == "" {
+			prefix = "synthesis.events"
+		}
+		b = &natsBus{prefix: prefix, conn: &natsConn{addr: u.Host}}
+	case "mqtt":
+		if prefix == "" {
+			prefix = "synthesis/events"
+		}
+		b = &mqttBus{prefix: prefix, conn: &mqttConn{addr: u.Host, clientID: "synthesis-server"}}
+	default:
+		return nil, fmt.Errorf("unknown bus scheme %q, want nats or mqtt", u.Scheme)
+	}
+	return &BusPublisher{
+		log: logging.Component("eventbus"),
+		bus: b,
+		ch:  make(chan ChangeEvent, 256),
+	}, nil
+}
+
+// Publish queues a change event without blocking.
+func (p *BusPublisher) Publish(resource, eventType string, obj any) {
+	select {
+	case p.ch <- ChangeEvent{Resource: resource, Type: eventType, Object: obj}:
+	default:
+		p.log.Warn("bus queue full, dropping event", "resource", resource)
+	}
+}
+
+// Run publishes queued events until ctx is canceled.
+func (p *BusPublisher) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-p.ch:
+			payload, err := json.Marshal(ev)
+			if err != nil {
+				p.log.Warn("encoding change event failed", "resource", ev.Resource, "err", err)
+				continue
+			}
+			if err := p.bus.publishResource(ev.Resource, payload); err != nil {
+				p.log.Warn("bus publish failed",
+					"bus", p.bus.name(), "resource", ev.Resource, "err", err)
+			}
+		}
+	}
+}
+
+type natsBus struct {
+	prefix string
+	conn   *natsConn
+}
+
+func (b *natsBus) name() string { return "nats " + b.conn.addr }
+
+func (b *natsBus) publishResource(resource string, payload []byte) error {
+	return b.conn.publish(b.prefix+"."+resource, payload)
+}
+
+type mqttBus struct {
+	prefix string
+	conn   *mqttConn
+}
+
+func (b *mqttBus) name() string { return "mqtt " + b.conn.addr }
+
+func (b *mqttBus) publishResource(resource string, payload []byte) error {
+	return b.conn.publish(b.prefix+"/"+resource, payload)
+}
@@ -0,0 +1,72 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ptfpinho23/synthesis/pkg/yaml"
+)
+
+// SinkConfig is one entry of the --event-sinks file.
+type SinkConfig struct {
+	// Type is webhook, slack or nats.
+	Type string `json:"type"`
+
+	// URL is the delivery endpoint for webhook and slack sinks.
+	URL string `json:"url,omitempty"`
+
+	// Headers are added to webhook requests.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Addr and Subject address nats sinks.
+	Addr    string `json:"addr,omitempty"`
+	Subject string `json:"subject,omitempty"`
+
+	// Filter selects which events this sink receives.
+	Filter Filter `json:"filter,omitempty"`
+}
+
+// LoadConfig reads a YAML or JSON list of sink configurations and
+// returns a notifier with every sink registered.
+func LoadConfig(path string) (*Notifier, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(data)) > 0 && bytes.TrimSpace(data)[0] != '[' {
+		if data, err = yaml.ToJSON(data); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+	}
+	var configs []SinkConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	n := NewNotifier()
+	for i, cfg := range configs {
+		switch cfg.Type {
+		case "webhook":
+			if cfg.URL == "" {
+				return nil, fmt.Errorf("%s: sink %d: webhook needs a url", path, i)
+			}
+			sink := NewWebhookSink(cfg.URL)
+			sink.Headers = cfg.Headers
+			n.AddSink(sink, cfg.Filter)
+		case "slack":
+			if cfg.URL == "" {
+				return nil, fmt.Errorf("%s: sink %d: slack needs a url", path, i)
+			}
+			n.AddSink(NewSlackSink(cfg.URL), cfg.Filter)
+		case "nats":
+			if cfg.Addr == "" || cfg.Subject == "" {
+				return nil, fmt.Errorf("%s: sink %d: nats needs addr and subject", path, i)
+			}
+			n.AddSink(NewNATSSink(cfg.Addr, cfg.Subject), cfg.Filter)
+		default:
+			return nil, fmt.Errorf("%s: sink %d: unknown type %q", path, i, cfg.Type)
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,109 @@
+// Package events fans cluster events out to operator-configured sinks:
+// plain HTTP webhooks, Slack, or a NATS subject. Each sink carries a
+// filter; delivery is asynchronous and best-effort, so a slow or dead
+// sink never blocks the loop that raised the event.
+package events
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/logging"
+)
+
+// Sink delivers one event to an external system.
+type Sink interface {
+	Name() string
+	Send(ev api.Event) error
+}
+
+// Filter selects which events a sink receives. Empty lists match
+// everything, so the zero Filter passes all events through.
+type Filter struct {
+	// Types limits delivery to these event types (Normal, Warning).
+	Types []string `json:"types,omitempty"`
+
+	// Reasons limits delivery to these event reasons.
+	Reasons []string `json:"reasons,omitempty"`
+
+	// Kinds limits delivery to events about these object kinds.
+	Kinds []string `json:"kinds,omitempty"`
+}
+
+// Matches reports whether the filter lets the event through.
+func (f Filter) Matches(ev api.Event) bool {
+	return matchesAny(f.Types, ev.Type) &&
+		matchesAny(f.Reasons, ev.Reason) &&
+		matchesAny(f.Kinds, ev.Regarding.Kind)
+}
+
+func matchesAny(allowed []string, value string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, a := range allowed {
+		if a == value {
+			return true
+		}
+	}
+	return false
+}
+
+type sinkEntry struct {
+	sink   Sink
+	filter Filter
+}
+
+// Notifier queues events and delivers them to every matching sink from
+// its own goroutine. Publishers never block: when the queue is full the
+// event is dropped with a warning, the same trade watch streams make.
+type Notifier struct {
+	log   *slog.Logger
+	ch    chan api.Event
+	sinks []sinkEntry
+}
+
+// NewNotifier returns a notifier with no sinks yet.
+func NewNotifier() *Notifier {
+	return &Notifier{
+		log: logging.Component("events"),
+		ch:  make(chan api.Event, 256),
+	}
+}
+
+// AddSink registers a sink. Not safe to call once Run has started.
+func (n *Notifier) AddSink(s Sink, f Filter) {
+	n.sinks = append(n.sinks, sinkEntry{sink: s, filter: f})
+}
+
+// Publish queues an event for delivery without blocking.
+func (n *Notifier) Publish(ev api.Event) {
+	select {
+	case n.ch <- ev:
+	default:
+		n.log.Warn("event queue full, dropping event", "reason", ev.Reason)
+	}
+}
+
+// Run delivers queued events until ctx is canceled. Sink failures are
+// logged and the event is not retried; sinks that need durability
+// should accept fast and queue internally.
+func (n *Notifier) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-n.ch:
+			for _, entry := range n.sinks {
+				if !entry.filter.Matches(ev) {
+					continue
+				}
+				if err := entry.sink.Send(ev); err != nil {
+					n.log.Warn("event delivery failed",
+						"sink", entry.sink.Name(), "reason", ev.Reason, "err", err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package events
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// mqttConn is a publish-only MQTT 3.1.1 connection: CONNECT, then QoS 0
+// PUBLISH packets. Like natsConn it is hand-rolled to keep the tree
+// dependency-free, and fire-and-forget by design — edge brokers that
+// need delivery guarantees should bridge locally.
+type mqttConn struct {
+	addr     string
+	clientID string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// publish sends one message, dialing lazily and retrying once.
+func (c *mqttConn) publish(topic string, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if c.conn == nil {
+			if err = c.connect(); err != nil {
+				return err
+			}
+		}
+		if err = c.pub(topic, payload); err != nil {
+			c.close()
+			continue
+		}
+		return nil
+	}
+	return fmt.Errorf("publishing to %s: %w", c.addr, err)
+}
+
+func (c *mqttConn) connect() error {
+	conn, err := net.DialTimeout("tcp", c.addr, 5*time.Second)
+	if err != nil {
+		return err
+	}
+	// CONNECT: protocol "MQTT" level 4, clean session, no keepalive.
+	var body []byte
+	body = append(body, 0, 4, 'M', 'Q', 'T', 'T', 4, 0x02, 0, 0)
+	body = append(body, mqttString(c.clientID)...)
+	packet := append([]byte{0x10}, mqttLength(len(body))...)
+	packet = append(packet, body...)
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := conn.Write(packet); err != nil {
+		conn.Close()
+		return err
+	}
+	ack := make([]byte, 4)
+	if _, err := io.ReadFull(conn, ack); err != nil {
+		conn.Close()
+		return fmt.Errorf("reading CONNACK: %w", err)
+	}
+	if ack[0] != 0x20 || ack[3] != 0 {
+		conn.Close()
+		return fmt.Errorf("broker refused connection (code %d)", ack[3])
+	}
+	conn.SetDeadline(time.Time{})
+	c.conn = conn
+	return nil
+}
+
+func (c *mqttConn) pub(topic string, payload []byte) error {
+	body := append(mqttString(topic), payload...)
+	packet := append([]byte{0x30}, mqttLength(len(body))...)
+	packet = append(packet, body...)
+	c.conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	_, err := c.conn.Write(packet)
+	return err
+}
+
+func (c *mqttConn) close() {
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+}
+
+// mqttString is the length-prefixed string encoding.
+func mqttString(s string) []byte {
+	out := make([]byte, 0, 2+len(s))
+	out = append(out, byte(len(s)>>8), byte(len(s)))
+	return append(out, s...)
+}
+
+// mqttLength is the variable-length remaining-length encoding.
+func mqttLength(n int) []byte {
+	var out []byte
+	for {
+		b := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			b |= 0x80
+		}
+		out = append(out, b)
+		if n == 0 {
+			return out
+		}
+	}
+}
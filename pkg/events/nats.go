@@ -0,0 +1,125 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// natsConn is a publish-only NATS connection. It speaks just that side
+// of the text protocol (INFO/CONNECT/PING/PUB) over plain TCP, which
+// keeps the tree dependency-free; auth and TLS belong on a local leaf
+// node or sidecar.
+type natsConn struct {
+	addr string
+
+	mu   sync.Mutex
+	conn net.Conn
+	rd   *bufio.Reader
+}
+
+// publish sends one message, dialing lazily and retrying once so a
+// broken connection surfaces on the first write after the server went
+// away.
+func (c *natsConn) publish(subject string, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if c.conn == nil {
+			if err = c.connect(); err != nil {
+				return err
+			}
+		}
+		if err = c.pub(subject, payload); err != nil {
+			c.close()
+			continue
+		}
+		return nil
+	}
+	return fmt.Errorf("publishing to %s: %w", c.addr, err)
+}
+
+func (c *natsConn) connect() error {
+	conn, err := net.DialTimeout("tcp", c.addr, 5*time.Second)
+	if err != nil {
+		return err
+	}
+	rd := bufio.NewReader(conn)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := rd.ReadString('\n'); err != nil { // INFO banner
+		conn.Close()
+		return fmt.Errorf("reading INFO: %w", err)
+	}
+	if _, err := fmt.Fprintf(conn, "CONNECT {\"verbose\":false,\"name\":\"synthesis\"}\r\n"); err != nil {
+		conn.Close()
+		return err
+	}
+	c.conn, c.rd = conn, rd
+	return nil
+}
+
+// pub answers any queued PINGs first, so an idle connection is not
+// dropped for staleness, then writes the PUB frame.
+func (c *natsConn) pub(subject string, payload []byte) error {
+	c.conn.SetReadDeadline(time.Now().Add(time.Millisecond))
+	for {
+		line, err := c.rd.ReadString('\n')
+		if strings.HasPrefix(line, "PING") {
+			if _, err := c.conn.Write([]byte("PONG\r\n")); err != nil {
+				return err
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	c.conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	if _, err := fmt.Fprintf(c.conn, "PUB %s %d\r\n", subject, len(payload)); err != nil {
+		return err
+	}
+	if _, err := c.conn.Write(payload); err != nil {
+		return err
+	}
+	_, err := c.conn.Write([]byte("\r\n"))
+	return err
+}
+
+func (c *natsConn) close() {
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn, c.rd = nil, nil
+	}
+}
+
+// NATSSink publishes events to a NATS subject.
+type NATSSink struct {
+	// Addr is the server's host:port.
+	Addr string
+
+	// Subject is the subject events are published to.
+	Subject string
+
+	conn *natsConn
+}
+
+// NewNATSSink returns a sink that connects lazily on first send.
+func NewNATSSink(addr, subject string) *NATSSink {
+	return &NATSSink{Addr: addr, Subject: subject, conn: &natsConn{addr: addr}}
+}
+
+func (s *NATSSink) Name() string { return "nats " + s.Addr }
+
+func (s *NATSSink) Send(ev api.Event) error {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	return s.conn.publish(s.Subject, payload)
+}
@@ -0,0 +1,88 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// WebhookSink POSTs each event as JSON to a URL.
+type WebhookSink struct {
+	URL string
+
+	// Headers are added to every request, e.g. an Authorization token.
+	Headers map[string]string
+
+	client *http.Client
+}
+
+// NewWebhookSink returns a sink with a 10s delivery timeout.
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{URL: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (s *WebhookSink) Name() string { return "webhook " + s.URL }
+
+func (s *WebhookSink) Send(ev api.Event) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// SlackSink posts events to a Slack incoming-webhook URL as a one-line
+// text message.
+type SlackSink struct {
+	WebhookURL string
+
+	client *http.Client
+}
+
+// NewSlackSink returns a sink with a 10s delivery timeout.
+func NewSlackSink(url string) *SlackSink {
+	return &SlackSink{WebhookURL: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (s *SlackSink) Name() string { return "slack" }
+
+func (s *SlackSink) Send(ev api.Event) error {
+	object := ev.Regarding.Kind + "/" + ev.Regarding.Name
+	if ev.Regarding.Namespace != "" {
+		object = ev.Regarding.Kind + "/" + ev.Regarding.Namespace + "/" + ev.Regarding.Name
+	}
+	text := fmt.Sprintf("[%s] %s %s: %s", ev.Type, object, ev.Reason, ev.Message)
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Post(s.WebhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("slack returned %s", resp.Status)
+	}
+	return nil
+}
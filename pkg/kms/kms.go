@@ -0,0 +1,84 @@
+// Package kms seals secrets with envelope encryption: every payload is
+// encrypted under a fresh AES-256-GCM data key, and a Provider wraps
+// the data key. Swapping the Provider (local keyring, Vault, cloud KMS)
+// changes where trust is rooted without touching stored envelopes.
+package kms
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// Provider wraps and unwraps data keys.
+type Provider interface {
+	// Name identifies the provider in stored envelopes.
+	Name() string
+
+	// CurrentKeyID names the key new envelopes are wrapped with.
+	CurrentKeyID() string
+
+	// Wrap encrypts a data key under the current provider key.
+	Wrap(dataKey []byte) (wrapped []byte, keyID string, err error)
+
+	// Unwrap decrypts a data key wrapped under the named key.
+	Unwrap(wrapped []byte, keyID string) ([]byte, error)
+}
+
+// Seal envelope-encrypts the payload with a fresh data key.
+func Seal(p Provider, payload []byte) (*api.SecretEnvelope, error) {
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	gcm, err := gcmFor(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	wrapped, keyID, err := p.Wrap(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("wrapping data key: %w", err)
+	}
+	return &api.SecretEnvelope{
+		Provider:   p.Name(),
+		KeyID:      keyID,
+		WrappedKey: wrapped,
+		Ciphertext: gcm.Seal(nil, nonce, payload, nil),
+		Nonce:      nonce,
+	}, nil
+}
+
+// Open decrypts an envelope produced by Seal.
+func Open(p Provider, env *api.SecretEnvelope) ([]byte, error) {
+	if env.Provider != p.Name() {
+		return nil, fmt.Errorf("envelope sealed by provider %q, have %q", env.Provider, p.Name())
+	}
+	dataKey, err := p.Unwrap(env.WrappedKey, env.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapping data key: %w", err)
+	}
+	gcm, err := gcmFor(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := gcm.Open(nil, env.Nonce, env.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting payload: %w", err)
+	}
+	return payload, nil
+}
+
+func gcmFor(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,95 @@
+// Package logging provides the structured loggers used across
+// Synthesis. Every component logs through a named slog.Logger whose
+// level can be raised or lowered at runtime, together or individually,
+// so one noisy controller can be put on debug without drowning the rest.
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+)
+
+var (
+	mu           sync.RWMutex
+	defaultLevel = slog.LevelInfo
+	overrides    = map[string]slog.Level{}
+	components   = map[string]bool{}
+)
+
+// componentLevel resolves a component's effective level lazily, so
+// loggers handed out before a SetLevel call still honour it.
+type componentLevel struct{ name string }
+
+func (c componentLevel) Level() slog.Level {
+	mu.RLock()
+	defer mu.RUnlock()
+	if lvl, ok := overrides[c.name]; ok {
+		return lvl
+	}
+	return defaultLevel
+}
+
+// Component returns the logger for the named component. Records carry
+// a "component" attribute so lines can be filtered mechanically.
+func Component(name string) *slog.Logger {
+	mu.Lock()
+	components[name] = true
+	mu.Unlock()
+	h := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: componentLevel{name}})
+	return slog.New(h).With("component", name)
+}
+
+// SetDefaultLevel sets the level for components without an override.
+func SetDefaultLevel(level slog.Level) {
+	mu.Lock()
+	defer mu.Unlock()
+	defaultLevel = level
+}
+
+// SetLevel overrides one component's level; ClearLevel undoes it.
+func SetLevel(component string, level slog.Level) {
+	mu.Lock()
+	defer mu.Unlock()
+	overrides[component] = level
+}
+
+// ClearLevel removes a component's override.
+func ClearLevel(component string) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(overrides, component)
+}
+
+// Levels reports the effective level of every known component, plus
+// the default under the empty key.
+func Levels() map[string]string {
+	mu.RLock()
+	defer mu.RUnlock()
+	out := map[string]string{"": defaultLevel.String()}
+	for name := range components {
+		lvl, ok := overrides[name]
+		if !ok {
+			lvl = defaultLevel
+		}
+		out[name] = lvl.String()
+	}
+	return out
+}
+
+// ParseLevel reads a level name ("debug", "info", "warn", "error").
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q", s)
+}
@@ -0,0 +1,111 @@
+// Package manifest decodes YAML or JSON manifests and resolves each
+// kind to its API paths. The CLI's apply family and the server's
+// auto-deploying manifests directory share it, so both address objects
+// the same way.
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/ptfpinho23/synthesis/pkg/yaml"
+)
+
+// Manifest is one decoded document resolved to its API paths.
+// Deployment and StatefulSet manifests are translated onto the Workload
+// resource during decoding, so apply, diff and delete all address the
+// same object.
+type Manifest struct {
+	Kind      string
+	Name      string
+	Namespace string
+	Object    map[string]any
+
+	ObjectPath     string
+	CollectionPath string // empty for kinds whose PUT is an upsert
+	Supported      bool
+}
+
+// ID is the kind/name handle shown to users, e.g. "workload/web".
+func (m *Manifest) ID() string {
+	return strings.ToLower(m.Kind) + "/" + m.Name
+}
+
+// Decode parses one document, JSON first and YAML as the fallback, and
+// resolves its kind to the paths it is served under.
+func Decode(doc []byte) (*Manifest, error) {
+	var obj map[string]any
+	if err := json.Unmarshal(doc, &obj); err != nil {
+		parsed, err := yaml.Parse(doc)
+		if err != nil {
+			return nil, fmt.Errorf("parsing manifest: %w", err)
+		}
+		var ok bool
+		if obj, ok = parsed.(map[string]any); !ok {
+			return nil, fmt.Errorf("manifest is not an object")
+		}
+	}
+	kind, _ := obj["kind"].(string)
+	meta, _ := obj["metadata"].(map[string]any)
+	name, _ := meta["name"].(string)
+	if kind == "" || name == "" {
+		return nil, fmt.Errorf("manifest needs kind and metadata.name")
+	}
+	namespace, _ := meta["namespace"].(string)
+	if namespace == "" {
+		namespace = "default"
+	}
+	m := &Manifest{Kind: kind, Name: name, Namespace: namespace, Object: obj, Supported: true}
+	ns, n := url.PathEscape(namespace), url.PathEscape(name)
+
+	switch kind {
+	case "Pod":
+		m.ObjectPath = fmt.Sprintf("/api/v1/namespaces/%s/pods/%s", ns, n)
+		m.CollectionPath = fmt.Sprintf("/api/v1/namespaces/%s/pods", ns)
+	case "Deployment", "StatefulSet":
+		spec, _ := obj["spec"].(map[string]any)
+		replicas, ok := spec["replicas"]
+		if !ok {
+			replicas = 1
+		}
+		m.Object = map[string]any{
+			"metadata": meta,
+			"spec": map[string]any{
+				"kind":     kind,
+				"replicas": replicas,
+				"template": spec["template"],
+			},
+		}
+		fallthrough
+	case "Workload":
+		m.ObjectPath = fmt.Sprintf("/api/v1/namespaces/%s/workloads/%s", ns, n)
+		m.CollectionPath = fmt.Sprintf("/api/v1/namespaces/%s/workloads", ns)
+	case "Namespace":
+		m.ObjectPath = "/api/v1/namespaces/" + n
+	case "Secret":
+		m.ObjectPath = fmt.Sprintf("/api/v1/namespaces/%s/secrets/%s", ns, n)
+	case "Service":
+		m.ObjectPath = fmt.Sprintf("/api/v1/namespaces/%s/services/%s", ns, n)
+	case "Ingress":
+		m.ObjectPath = fmt.Sprintf("/api/v1/namespaces/%s/ingresses/%s", ns, n)
+	case "Gateway":
+		m.ObjectPath = fmt.Sprintf("/api/v1/namespaces/%s/gateways/%s", ns, n)
+	case "HTTPRoute":
+		m.ObjectPath = fmt.Sprintf("/api/v1/namespaces/%s/httproutes/%s", ns, n)
+	case "ServiceAccount":
+		m.ObjectPath = fmt.Sprintf("/api/v1/namespaces/%s/serviceaccounts/%s", ns, n)
+	case "PriorityClass":
+		m.ObjectPath = "/api/v1/priorityclasses/" + n
+	case "Role":
+		m.ObjectPath = fmt.Sprintf("/api/v1/namespaces/%s/roles/%s", ns, n)
+	case "RoleBinding":
+		m.ObjectPath = fmt.Sprintf("/api/v1/namespaces/%s/rolebindings/%s", ns, n)
+	case "CustomResourceDefinition":
+		m.ObjectPath = "/api/v1/customresourcedefinitions/" + n
+	default:
+		m.Supported = false
+	}
+	return m, nil
+}
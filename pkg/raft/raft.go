@@ -0,0 +1,305 @@
+// Package raft is a small, dependency-free implementation of the raft
+// consensus protocol: leader election, log replication, and commitment.
+// It covers what Synthesis needs to replicate its store across three
+// control-plane nodes — no log compaction, snapshots or membership
+// changes. Peers talk JSON over HTTP.
+package raft
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/logging"
+)
+
+// State is a raft node's current role.
+type State string
+
+const (
+	Follower  State = "follower"
+	Candidate State = "candidate"
+	Leader    State = "leader"
+)
+
+// ErrNotLeader is returned by Propose on followers. It wraps the leader
+// address when known so callers can redirect.
+var ErrNotLeader = errors.New("not the raft leader")
+
+// logger is shared by the package; raft has no central struct to
+// hang it off.
+var logger = logging.Component("raft")
+
+// Entry is one replicated log record.
+type Entry struct {
+	Term    uint64 `json:"term"`
+	Command []byte `json:"command"`
+}
+
+// Node is one member of a raft cluster.
+type Node struct {
+	mu sync.Mutex
+
+	id    string
+	peers map[string]string // peer id -> base URL
+
+	state       State
+	currentTerm uint64
+	votedFor    string
+	leaderID    string
+
+	logEntries  []Entry
+	commitIndex int
+	lastApplied int
+
+	// nextIndex tracks, per peer, the next log index to send.
+	nextIndex  map[string]int
+	matchIndex map[string]int
+
+	// apply is invoked, in log order, for every committed command.
+	apply func(cmd []byte)
+
+	heartbeatInterval time.Duration
+	electionResetAt   time.Time
+
+	transport *transport
+}
+
+// NewNode creates a raft node. peers maps every other member's id to
+// its raft base URL; apply receives committed commands in order.
+func NewNode(id string, peers map[string]string, apply func(cmd []byte)) *Node {
+	n := &Node{
+		id:    id,
+		peers: peers,
+		state: Follower,
+		// The log is 0-based, so "nothing committed, nothing applied"
+		// is -1 — the same sentinel becomeLeader uses for matchIndex.
+		commitIndex:       -1,
+		lastApplied:       -1,
+		nextIndex:         make(map[string]int),
+		matchIndex:        make(map[string]int),
+		apply:             apply,
+		heartbeatInterval: 100 * time.Millisecond,
+		electionResetAt:   time.Now(),
+	}
+	n.transport = newTransport(n)
+	return n
+}
+
+// Run drives election timeouts and leader heartbeats until the process
+// exits.
+func (n *Node) Run() {
+	go n.electionLoop()
+	go n.heartbeatLoop()
+}
+
+// IsLeader reports whether this node currently leads the cluster.
+func (n *Node) IsLeader() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.state == Leader
+}
+
+// LeaderAddr returns the base URL of the last known leader, or "".
+func (n *Node) LeaderAddr() string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.peers[n.leaderID]
+}
+
+// Propose appends a command to the replicated log and returns once it
+// is committed on a majority, or fails with ErrNotLeader elsewhere.
+func (n *Node) Propose(cmd []byte) error {
+	n.mu.Lock()
+	if n.state != Leader {
+		n.mu.Unlock()
+		return fmt.Errorf("%w (leader: %s)", ErrNotLeader, n.peers[n.leaderID])
+	}
+	n.logEntries = append(n.logEntries, Entry{Term: n.currentTerm, Command: cmd})
+	index := len(n.logEntries) - 1
+	term := n.currentTerm
+	n.mu.Unlock()
+
+	n.replicate()
+
+	// Wait for the entry to commit; time out if the majority is gone.
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		n.mu.Lock()
+		committed := n.commitIndex >= index && n.logEntries[index].Term == term
+		lost := n.state != Leader
+		n.mu.Unlock()
+		if committed {
+			return nil
+		}
+		if lost {
+			return ErrNotLeader
+		}
+		time.Sleep(5 * time.Millisecond)
+		n.replicate()
+	}
+	return fmt.Errorf("raft: proposal not committed before deadline")
+}
+
+func (n *Node) electionLoop() {
+	for {
+		timeout := 300*time.Millisecond + time.Duration(rand.Intn(300))*time.Millisecond
+		time.Sleep(50 * time.Millisecond)
+		n.mu.Lock()
+		overdue := n.state != Leader && time.Since(n.electionResetAt) >= timeout
+		n.mu.Unlock()
+		if overdue {
+			n.startElection()
+		}
+	}
+}
+
+func (n *Node) heartbeatLoop() {
+	for range time.Tick(n.heartbeatInterval) {
+		if n.IsLeader() {
+			n.replicate()
+		}
+	}
+}
+
+func (n *Node) startElection() {
+	n.mu.Lock()
+	n.state = Candidate
+	n.currentTerm++
+	n.votedFor = n.id
+	n.electionResetAt = time.Now()
+	term := n.currentTerm
+	lastIndex := len(n.logEntries) - 1
+	lastTerm := uint64(0)
+	if lastIndex >= 0 {
+		lastTerm = n.logEntries[lastIndex].Term
+	}
+	n.mu.Unlock()
+
+	votes := 1
+	var voteMu sync.Mutex
+	for peerID, addr := range n.peers {
+		go func(peerID, addr string) {
+			granted, peerTerm := n.transport.requestVote(addr, voteRequest{
+				Term: term, CandidateID: n.id, LastLogIndex: lastIndex, LastLogTerm: lastTerm,
+			})
+			n.mu.Lock()
+			defer n.mu.Unlock()
+			if peerTerm > n.currentTerm {
+				n.stepDown(peerTerm)
+				return
+			}
+			if !granted || n.state != Candidate || n.currentTerm != term {
+				return
+			}
+			voteMu.Lock()
+			votes++
+			won := votes > (len(n.peers)+1)/2
+			voteMu.Unlock()
+			if won && n.state == Candidate {
+				n.becomeLeader()
+			}
+		}(peerID, addr)
+	}
+}
+
+// becomeLeader transitions to leader; callers hold n.mu.
+func (n *Node) becomeLeader() {
+	n.state = Leader
+	n.leaderID = n.id
+	for peerID := range n.peers {
+		n.nextIndex[peerID] = len(n.logEntries)
+		n.matchIndex[peerID] = -1
+	}
+	logger.Info("became leader", "id", n.id, "term", n.currentTerm)
+}
+
+// stepDown reverts to follower on seeing a higher term; callers hold n.mu.
+func (n *Node) stepDown(term uint64) {
+	n.currentTerm = term
+	n.state = Follower
+	n.votedFor = ""
+	n.electionResetAt = time.Now()
+}
+
+// replicate sends AppendEntries to every peer and advances commitIndex
+// as majorities confirm.
+func (n *Node) replicate() {
+	n.mu.Lock()
+	if n.state != Leader {
+		n.mu.Unlock()
+		return
+	}
+	term := n.currentTerm
+	n.mu.Unlock()
+
+	for peerID, addr := range n.peers {
+		go func(peerID, addr string) {
+			n.mu.Lock()
+			next := n.nextIndex[peerID]
+			prevIndex := next - 1
+			prevTerm := uint64(0)
+			if prevIndex >= 0 && prevIndex < len(n.logEntries) {
+				prevTerm = n.logEntries[prevIndex].Term
+			}
+			entries := append([]Entry(nil), n.logEntries[next:]...)
+			commit := n.commitIndex
+			n.mu.Unlock()
+
+			ok, peerTerm := n.transport.appendEntries(addr, appendRequest{
+				Term: term, LeaderID: n.id,
+				PrevLogIndex: prevIndex, PrevLogTerm: prevTerm,
+				Entries: entries, LeaderCommit: commit,
+			})
+
+			n.mu.Lock()
+			defer n.mu.Unlock()
+			if peerTerm > n.currentTerm {
+				n.stepDown(peerTerm)
+				return
+			}
+			if n.state != Leader || n.currentTerm != term {
+				return
+			}
+			if ok {
+				n.nextIndex[peerID] = next + len(entries)
+				n.matchIndex[peerID] = n.nextIndex[peerID] - 1
+				n.advanceCommit()
+			} else if next > 0 {
+				n.nextIndex[peerID] = next - 1
+			}
+		}(peerID, addr)
+	}
+}
+
+// advanceCommit moves commitIndex to the highest index replicated on a
+// majority within the current term; callers hold n.mu.
+func (n *Node) advanceCommit() {
+	for idx := len(n.logEntries) - 1; idx > n.commitIndex; idx-- {
+		if n.logEntries[idx].Term != n.currentTerm {
+			continue
+		}
+		count := 1
+		for peerID := range n.peers {
+			if n.matchIndex[peerID] >= idx {
+				count++
+			}
+		}
+		if count > (len(n.peers)+1)/2 {
+			n.commitIndex = idx
+			n.applyCommitted()
+			return
+		}
+	}
+}
+
+// applyCommitted feeds newly committed entries to the FSM; callers hold
+// n.mu.
+func (n *Node) applyCommitted() {
+	for n.lastApplied < n.commitIndex {
+		n.lastApplied++
+		n.apply(n.logEntries[n.lastApplied].Command)
+	}
+}
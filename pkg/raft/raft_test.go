@@ -0,0 +1,186 @@
+package raft
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// appliedLog records the commands a node's FSM has applied, in order.
+type appliedLog struct {
+	mu   sync.Mutex
+	cmds []string
+}
+
+func (l *appliedLog) apply(cmd []byte) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cmds = append(l.cmds, string(cmd))
+}
+
+func (l *appliedLog) snapshot() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.cmds...)
+}
+
+// newTestCluster starts n nodes talking over loopback HTTP.
+func newTestCluster(t *testing.T, n int) ([]*Node, []*appliedLog) {
+	t.Helper()
+	listeners := make([]net.Listener, n)
+	addrs := make([]string, n)
+	for i := range listeners {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("listen: %v", err)
+		}
+		listeners[i] = l
+		addrs[i] = "http://" + l.Addr().String()
+	}
+	nodes := make([]*Node, n)
+	logs := make([]*appliedLog, n)
+	for i := range nodes {
+		peers := make(map[string]string)
+		for j, addr := range addrs {
+			if j != i {
+				peers[fmt.Sprintf("node-%d", j)] = addr
+			}
+		}
+		logs[i] = &appliedLog{}
+		nodes[i] = NewNode(fmt.Sprintf("node-%d", i), peers, logs[i].apply)
+		srv := &http.Server{Handler: nodes[i].Handler()}
+		go srv.Serve(listeners[i])
+		t.Cleanup(func() { srv.Close() })
+		nodes[i].Run()
+	}
+	return nodes, logs
+}
+
+// waitLeader polls until one node leads.
+func waitLeader(t *testing.T, nodes []*Node) *Node {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, n := range nodes {
+			if n.IsLeader() {
+				return n
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("no leader elected")
+	return nil
+}
+
+// TestProposeCommitsAndApplies drives commands through a three-node
+// cluster and checks every node applies them, in order, starting with
+// the very first entry — the 0-based log's index 0 must not be skipped.
+func TestProposeCommitsAndApplies(t *testing.T) {
+	nodes, logs := newTestCluster(t, 3)
+	leader := waitLeader(t, nodes)
+
+	want := []string{"first", "second", "third"}
+	for _, cmd := range want {
+		if err := leader.Propose([]byte(cmd)); err != nil {
+			t.Fatalf("Propose(%q): %v", cmd, err)
+		}
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for _, log := range logs {
+		for {
+			got := log.snapshot()
+			if len(got) == len(want) {
+				for i := range want {
+					if got[i] != want[i] {
+						t.Fatalf("applied %v, want %v", got, want)
+					}
+				}
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("applied %v, want %v", got, want)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
+
+// TestProposeOnFollowerFails checks followers refuse proposals.
+func TestProposeOnFollowerFails(t *testing.T) {
+	nodes, _ := newTestCluster(t, 3)
+	leader := waitLeader(t, nodes)
+	for _, n := range nodes {
+		if n == leader {
+			continue
+		}
+		if err := n.Propose([]byte("nope")); !errors.Is(err, ErrNotLeader) {
+			t.Fatalf("follower Propose: got %v, want ErrNotLeader", err)
+		}
+	}
+}
+
+// sendAppend delivers one AppendEntries RPC straight to the node's
+// handler.
+func sendAppend(t *testing.T, n *Node, req appendRequest) appendResponse {
+	t.Helper()
+	body, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	rec := httptest.NewRecorder()
+	n.transport.handleAppend(rec, httptest.NewRequest("POST", "/raft/append", bytes.NewReader(body)))
+	var resp appendResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return resp
+}
+
+// TestStaleAppendKeepsNewerEntries replays an AppendEntries covering an
+// already-delivered prefix and checks the follower keeps the suffix a
+// newer request appended: truncation is only allowed on term conflict.
+func TestStaleAppendKeepsNewerEntries(t *testing.T) {
+	n := NewNode("follower", map[string]string{}, func([]byte) {})
+
+	full := appendRequest{
+		Term: 1, LeaderID: "leader", PrevLogIndex: -1, LeaderCommit: -1,
+		Entries: []Entry{
+			{Term: 1, Command: []byte("0")},
+			{Term: 1, Command: []byte("1")},
+			{Term: 1, Command: []byte("2")},
+		},
+	}
+	if resp := sendAppend(t, n, full); !resp.Success {
+		t.Fatal("initial append refused")
+	}
+
+	stale := appendRequest{
+		Term: 1, LeaderID: "leader", PrevLogIndex: -1, LeaderCommit: -1,
+		Entries: []Entry{{Term: 1, Command: []byte("0")}},
+	}
+	if resp := sendAppend(t, n, stale); !resp.Success {
+		t.Fatal("stale append refused")
+	}
+	if len(n.logEntries) != 3 {
+		t.Fatalf("stale append truncated the log to %d entries, want 3", len(n.logEntries))
+	}
+
+	conflict := appendRequest{
+		Term: 2, LeaderID: "leader2", PrevLogIndex: 0, PrevLogTerm: 1, LeaderCommit: -1,
+		Entries: []Entry{{Term: 2, Command: []byte("1'")}},
+	}
+	if resp := sendAppend(t, n, conflict); !resp.Success {
+		t.Fatal("conflicting append refused")
+	}
+	if len(n.logEntries) != 2 || n.logEntries[1].Term != 2 {
+		t.Fatalf("conflict not resolved: %+v", n.logEntries)
+	}
+}
@@ -0,0 +1,173 @@
+package raft
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+type voteRequest struct {
+	Term         uint64 `json:"term"`
+	CandidateID  string `json:"candidateId"`
+	LastLogIndex int    `json:"lastLogIndex"`
+	LastLogTerm  uint64 `json:"lastLogTerm"`
+}
+
+type voteResponse struct {
+	Term    uint64 `json:"term"`
+	Granted bool   `json:"granted"`
+}
+
+type appendRequest struct {
+	Term         uint64  `json:"term"`
+	LeaderID     string  `json:"leaderId"`
+	PrevLogIndex int     `json:"prevLogIndex"`
+	PrevLogTerm  uint64  `json:"prevLogTerm"`
+	Entries      []Entry `json:"entries,omitempty"`
+	LeaderCommit int     `json:"leaderCommit"`
+}
+
+type appendResponse struct {
+	Term    uint64 `json:"term"`
+	Success bool   `json:"success"`
+}
+
+// transport carries raft RPCs between peers as JSON over HTTP.
+type transport struct {
+	node   *Node
+	client *http.Client
+}
+
+func newTransport(n *Node) *transport {
+	return &transport{node: n, client: &http.Client{Timeout: 2 * time.Second}}
+}
+
+// Handler returns the HTTP handler peers call; mount it on the raft
+// listener.
+func (n *Node) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /raft/vote", n.transport.handleVote)
+	mux.HandleFunc("POST /raft/append", n.transport.handleAppend)
+	return mux
+}
+
+func (t *transport) requestVote(addr string, req voteRequest) (granted bool, term uint64) {
+	var resp voteResponse
+	if err := t.post(addr+"/raft/vote", req, &resp); err != nil {
+		return false, 0
+	}
+	return resp.Granted, resp.Term
+}
+
+func (t *transport) appendEntries(addr string, req appendRequest) (ok bool, term uint64) {
+	var resp appendResponse
+	if err := t.post(addr+"/raft/append", req, &resp); err != nil {
+		return false, 0
+	}
+	return resp.Success, resp.Term
+}
+
+func (t *transport) post(url string, body, out any) error {
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	resp, err := t.client.Post(url, "application/json", bytes.NewReader(buf))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (t *transport) handleVote(w http.ResponseWriter, r *http.Request) {
+	var req voteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	n := t.node
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if req.Term > n.currentTerm {
+		n.stepDown(req.Term)
+	}
+	resp := voteResponse{Term: n.currentTerm}
+	lastIndex := len(n.logEntries) - 1
+	lastTerm := uint64(0)
+	if lastIndex >= 0 {
+		lastTerm = n.logEntries[lastIndex].Term
+	}
+	upToDate := req.LastLogTerm > lastTerm ||
+		(req.LastLogTerm == lastTerm && req.LastLogIndex >= lastIndex)
+	if req.Term == n.currentTerm && upToDate &&
+		(n.votedFor == "" || n.votedFor == req.CandidateID) {
+		n.votedFor = req.CandidateID
+		n.electionResetAt = time.Now()
+		resp.Granted = true
+	}
+	writeJSON(w, resp)
+}
+
+func (t *transport) handleAppend(w http.ResponseWriter, r *http.Request) {
+	var req appendRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	n := t.node
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	resp := appendResponse{Term: n.currentTerm}
+	if req.Term < n.currentTerm {
+		writeJSON(w, resp)
+		return
+	}
+	if req.Term > n.currentTerm {
+		n.stepDown(req.Term)
+	}
+	n.state = Follower
+	n.leaderID = req.LeaderID
+	n.electionResetAt = time.Now()
+	resp.Term = n.currentTerm
+
+	// Log consistency check on the entry preceding the new batch.
+	if req.PrevLogIndex >= 0 {
+		if req.PrevLogIndex >= len(n.logEntries) ||
+			n.logEntries[req.PrevLogIndex].Term != req.PrevLogTerm {
+			writeJSON(w, resp)
+			return
+		}
+	}
+	// Append the leader's entries, truncating only where an existing
+	// entry's term actually conflicts. A delayed or reordered request
+	// covering an older prefix must not discard entries a newer request
+	// already delivered — they may be committed.
+	for i, entry := range req.Entries {
+		idx := req.PrevLogIndex + 1 + i
+		if idx < len(n.logEntries) {
+			if n.logEntries[idx].Term == entry.Term {
+				continue
+			}
+			n.logEntries = n.logEntries[:idx]
+		}
+		n.logEntries = append(n.logEntries, entry)
+	}
+	if req.LeaderCommit > n.commitIndex {
+		n.commitIndex = req.LeaderCommit
+		if n.commitIndex > len(n.logEntries)-1 {
+			n.commitIndex = len(n.logEntries) - 1
+		}
+		n.applyCommitted()
+	}
+	resp.Success = true
+	writeJSON(w, resp)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,136 @@
+package runtime
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/logging"
+)
+
+var logger = logging.Component("runtime")
+
+// ContainerCache is an informer-style view of the runtime's
+// containers: one background loop keeps an indexed snapshot that every
+// caller shares, so the sync and stats loops stop shelling out to the
+// engine once per pass.
+type ContainerCache struct {
+	runtime Runtime
+
+	// ResyncInterval is the fallback full relist, catching anything
+	// the event stream misses.
+	ResyncInterval time.Duration
+
+	// OnEvent, when set, sees every runtime event after the cache has
+	// been marked stale, so consumers like the agent's pod sync loop
+	// can react without their own stream. Must not block.
+	OnEvent func(ContainerEvent)
+
+	mu    sync.RWMutex
+	byID  map[string]Container
+	byPod map[string][]Container
+
+	// dirty coalesces refresh requests: many events between two
+	// refreshes cost one relist.
+	dirty chan struct{}
+}
+
+// NewContainerCache returns an empty cache over the runtime; call Run
+// to start keeping it fresh.
+func NewContainerCache(rt Runtime) *ContainerCache {
+	return &ContainerCache{
+		runtime:        rt,
+		ResyncInterval: 30 * time.Second,
+		byID:           make(map[string]Container),
+		byPod:          make(map[string][]Container),
+		dirty:          make(chan struct{}, 1),
+	}
+}
+
+// Run keeps the cache fresh until the process exits: refresh on
+// runtime events when the engine can stream them, and on the resync
+// ticker regardless.
+func (c *ContainerCache) Run() {
+	if src, ok := c.runtime.(EventSource); ok {
+		go func() {
+			for {
+				ch, err := src.Events(context.Background())
+				if err != nil {
+					logger.Warn("starting event stream failed, polling instead", "err", err)
+					time.Sleep(5 * time.Second)
+					continue
+				}
+				for ev := range ch {
+					c.markDirty()
+					if c.OnEvent != nil {
+						c.OnEvent(ev)
+					}
+				}
+				logger.Warn("event stream ended, reconnecting")
+				time.Sleep(5 * time.Second)
+			}
+		}()
+	}
+	if err := c.refresh(); err != nil {
+		logger.Error("refreshing container cache failed", "err", err)
+	}
+	ticker := time.NewTicker(c.ResyncInterval)
+	for {
+		select {
+		case <-ticker.C:
+		case <-c.dirty:
+		}
+		if err := c.refresh(); err != nil {
+			logger.Error("refreshing container cache failed", "err", err)
+		}
+	}
+}
+
+// MarkDirty schedules a refresh, for callers that just mutated the
+// runtime and cannot rely on the event stream.
+func (c *ContainerCache) MarkDirty() { c.markDirty() }
+
+func (c *ContainerCache) markDirty() {
+	select {
+	case c.dirty <- struct{}{}:
+	default:
+	}
+}
+
+func (c *ContainerCache) refresh() error {
+	containers, err := c.runtime.ListContainers()
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]Container, len(containers))
+	byPod := make(map[string][]Container)
+	for _, container := range containers {
+		byID[container.ID] = container
+		key := container.PodNamespace + "/" + container.PodName
+		byPod[key] = append(byPod[key], container)
+	}
+	c.mu.Lock()
+	c.byID = byID
+	c.byPod = byPod
+	c.mu.Unlock()
+	return nil
+}
+
+// Containers returns the cached snapshot.
+func (c *ContainerCache) Containers() []Container {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make([]Container, 0, len(c.byID))
+	for _, container := range c.byID {
+		out = append(out, container)
+	}
+	return out
+}
+
+// PodContainers returns the cached containers of one pod via the
+// label index.
+func (c *ContainerCache) PodContainers(namespace, name string) []Container {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.byPod[namespace+"/"+name]
+}
@@ -0,0 +1,800 @@
+package runtime
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os/exec"
+	goruntime "runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// Labels stamped onto every container so the ones Synthesis manages can
+// be told apart from anything else running on the host.
+const (
+	labelNamespace = "io.synthesis.pod-namespace"
+	labelPod       = "io.synthesis.pod-name"
+	labelContainer = "io.synthesis.container-name"
+)
+
+// RuntimeConfig is the containerd-level tuning for engines that front
+// containerd (nerdctl). Plain docker has no equivalent flags and rejects
+// them, so everything defaults to empty and is only emitted when set.
+type RuntimeConfig struct {
+	// Namespace is the containerd namespace the engine works in, e.g.
+	// "synthesis" to keep these containers apart from other containerd
+	// users on the host. Empty uses the engine default.
+	Namespace string
+
+	// Snapshotter backs container filesystems: overlayfs (the usual
+	// default), stargz for lazy pulls — cold starts stream only the
+	// image bytes they touch, which is what slow edge uplinks want — or
+	// zfs. Empty uses the engine default.
+	Snapshotter string
+
+	// RuntimeHandler is the OCI runtime containers are launched under
+	// (e.g. io.containerd.runc.v2 or a kata/gVisor handler). Empty uses
+	// the engine default.
+	RuntimeHandler string
+}
+
+// Docker runs pod containers through the docker CLI.
+type Docker struct {
+	// Binary is the engine CLI to invoke, "docker" by default. Any
+	// CLI-compatible engine (podman, nerdctl) works too.
+	Binary string
+
+	// RuntimeConfig tunes the containerd side of a nerdctl engine; the
+	// zero value changes nothing and keeps plain docker working.
+	RuntimeConfig
+
+	// ClusterDNS is the resolver ClusterFirst pods point at; empty
+	// leaves them on the engine's default resolv.conf.
+	ClusterDNS string
+
+	// ClusterDomain is the DNS suffix behind ClusterDNS search domains.
+	ClusterDomain string
+
+	// Platform pins image pulls to an explicit os/arch ("linux/arm64")
+	// so multi-arch manifests resolve to this node's real architecture
+	// even when the engine's default platform is configured differently.
+	// NewDocker detects it from the running binary; empty lets the
+	// engine decide.
+	Platform string
+
+	// Mirrors maps registry hosts to the mirror pulls for that registry
+	// are routed through ("docker.io" -> "mirror.internal:5000"), so a
+	// fleet of edge nodes hits a nearby pull-through cache instead of
+	// the upstream registry and its rate limits. Mirror pulls are
+	// re-tagged under the original reference, so pod specs never
+	// change; a mirror miss falls back to the source registry.
+	Mirrors map[string]string
+
+	// OpTimeout bounds each engine CLI invocation (create, start,
+	// remove, list, inspect). A wedged engine call is killed when it
+	// expires and surfaces as an error for that one operation, instead
+	// of hanging the sync loop indefinitely. Zero means no bound. Image
+	// pulls carry their own, longer timeout via PullImage.
+	OpTimeout time.Duration
+
+	// EngineRestarts delegates container restarts to the engine: pods
+	// run under the docker restart policy translated from their spec,
+	// and SyncPod leaves exited containers alone. Off by default —
+	// containers run with --restart=no and the agent replaces exited
+	// ones itself, so spec changes always take effect and the engine
+	// never fights the orchestrator's reconciliation.
+	EngineRestarts bool
+}
+
+// NewDocker returns a runtime backed by the docker binary on PATH.
+func NewDocker() *Docker {
+	return &Docker{
+		Binary:        "docker",
+		ClusterDomain: "cluster.local",
+		Platform:      goruntime.GOOS + "/" + goruntime.GOARCH,
+		OpTimeout:     time.Minute,
+	}
+}
+
+// globalArgs translates the containerd config into the engine's global
+// flags, emitted before the subcommand on every invocation.
+func (d *Docker) globalArgs() []string {
+	var args []string
+	if d.Namespace != "" {
+		args = append(args, "--namespace", d.Namespace)
+	}
+	if d.Snapshotter != "" {
+		args = append(args, "--snapshotter", d.Snapshotter)
+	}
+	return args
+}
+
+func (d *Docker) exec(args ...string) (string, error) {
+	ctx := context.Background()
+	if d.OpTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.OpTimeout)
+		defer cancel()
+	}
+	out, err := exec.CommandContext(ctx, d.Binary, append(d.globalArgs(), args...)...).CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("%s %s: timed out after %s", d.Binary, args[0], d.OpTimeout)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s %s: %v: %s", d.Binary, args[0], err, strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// containerName is the host-visible name, unique per spec container.
+func containerName(namespace, pod, container string) string {
+	return fmt.Sprintf("synthesis_%s_%s_%s", namespace, pod, container)
+}
+
+// labelArgs stamps the management labels plus the pod's own labels onto
+// a container, so engine-level filters (docker ps --filter
+// label=app=web) line up with the API objects — including the workload
+// owner label the controller puts on its pods. Keys in the io.synthesis
+// namespace are reserved and never copied from the pod.
+func labelArgs(pod *api.Pod, container string) []string {
+	args := []string{
+		"--label", labelNamespace + "=" + pod.Meta.Namespace,
+		"--label", labelPod + "=" + pod.Meta.Name,
+		"--label", labelContainer + "=" + container,
+	}
+	keys := make([]string, 0, len(pod.Meta.Labels))
+	for k := range pod.Meta.Labels {
+		if strings.HasPrefix(k, "io.synthesis.") {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, "--label", k+"="+pod.Meta.Labels[k])
+	}
+	return args
+}
+
+// SyncPod implements Runtime. Init containers gate the main ones: each
+// must have exited cleanly — or, for sidecars, just be running — before
+// anything later starts. Stopped containers the restart policy allows
+// back up are replaced rather than restarted so spec changes always
+// take effect.
+func (d *Docker) SyncPod(pod *api.Pod, existing []Container) error {
+	byName := make(map[string]Container, len(existing))
+	for _, c := range existing {
+		byName[c.Name] = c
+	}
+	for _, spec := range pod.Spec.InitContainers {
+		done, err := d.syncInitContainer(pod, spec, byName)
+		if err != nil {
+			return err
+		}
+		if !done {
+			// Still initializing; later containers wait for the next
+			// sync pass.
+			return nil
+		}
+	}
+	for _, spec := range pod.Spec.Containers {
+		if c, ok := byName[spec.Name]; ok {
+			if c.Running || d.EngineRestarts || !shouldRestart(pod.Spec.RestartPolicy, c.ExitCode) {
+				continue
+			}
+			if _, err := d.exec("rm", "-f", c.ID); err != nil {
+				return err
+			}
+		}
+		if err := d.startContainer(pod, spec); err != nil {
+			return fmt.Errorf("starting container %s: %w", spec.Name, err)
+		}
+	}
+	// Ephemeral debug containers join the namespaces of a running main
+	// container; one that exits stays down.
+	for _, spec := range pod.Spec.EphemeralContainers {
+		if _, ok := byName[spec.Name]; ok {
+			continue
+		}
+		if err := d.startEphemeralContainer(pod, spec, existing); err != nil {
+			return fmt.Errorf("starting ephemeral container %s: %w", spec.Name, err)
+		}
+	}
+	return nil
+}
+
+// startEphemeralContainer runs a debug container sharing the network
+// and PID namespaces of the pod's first running container. With
+// nothing running yet it waits for a later pass rather than failing.
+func (d *Docker) startEphemeralContainer(pod *api.Pod, spec api.Container, existing []Container) error {
+	var target string
+	for _, c := range existing {
+		if c.Running {
+			target = c.ID
+			break
+		}
+	}
+	if target == "" {
+		return nil
+	}
+	args := []string{"run", "-d",
+		"--name", containerName(pod.Meta.Namespace, pod.Meta.Name, spec.Name)}
+	args = append(args, labelArgs(pod, spec.Name)...)
+	args = append(args,
+		"--network", "container:"+target,
+		"--pid", "container:"+target,
+	)
+	for k, v := range spec.Env {
+		args = append(args, "-e", k+"="+v)
+	}
+	if d.RuntimeHandler != "" {
+		args = append(args, "--runtime", d.RuntimeHandler)
+	}
+	args = append(args, spec.Image)
+	args = append(args, spec.Command...)
+	args = append(args, spec.Args...)
+	_, err := d.exec(args...)
+	return err
+}
+
+// oomScoreAdj ranks containers for the kernel OOM killer by the pod's
+// QoS class, mirroring the kubelet's policy: best-effort work dies
+// first, guaranteed work is all but untouchable.
+func oomScoreAdj(pod *api.Pod) int {
+	switch api.QoSOf(pod) {
+	case api.QoSGuaranteed:
+		return -997
+	case api.QoSBestEffort:
+		return 1000
+	default:
+		return 500
+	}
+}
+
+// hostsArgs sets the container hostname and the managed /etc/hosts
+// entries from the pod's hostAliases. The engine forbids a hostname on
+// host-network containers, which keep the node's.
+func (d *Docker) hostsArgs(pod *api.Pod) []string {
+	var args []string
+	hostname := pod.Spec.Hostname
+	if hostname == "" {
+		hostname = pod.Meta.Name
+	}
+	if !pod.Spec.HostNetwork {
+		args = append(args, "--hostname", hostname)
+		if pod.Spec.Subdomain != "" {
+			namespace := pod.Meta.Namespace
+			if namespace == "" {
+				namespace = "default"
+			}
+			fqdn := fmt.Sprintf("%s.%s.%s.svc.%s", hostname, pod.Spec.Subdomain, namespace, d.ClusterDomain)
+			args = append(args, "--add-host", fqdn+":127.0.0.1")
+		}
+	}
+	for _, alias := range pod.Spec.HostAliases {
+		for _, name := range alias.Hostnames {
+			args = append(args, "--add-host", name+":"+alias.IP)
+		}
+	}
+	return args
+}
+
+// dnsArgs turns the pod's DNS policy and config into docker run
+// flags; the engine generates the container's resolv.conf from them.
+func (d *Docker) dnsArgs(pod *api.Pod) []string {
+	var args []string
+	policy := pod.Spec.DNSPolicy
+	if policy == "" {
+		policy = api.DNSClusterFirst
+	}
+	switch policy {
+	case api.DNSClusterFirst:
+		if d.ClusterDNS != "" {
+			namespace := pod.Meta.Namespace
+			if namespace == "" {
+				namespace = "default"
+			}
+			args = append(args, "--dns", d.ClusterDNS,
+				"--dns-search", namespace+".svc."+d.ClusterDomain,
+				"--dns-search", "svc."+d.ClusterDomain,
+				"--dns-search", d.ClusterDomain,
+				"--dns-option", "ndots:5")
+		}
+	case api.DNSDefault:
+		// Inherit the node's resolv.conf, the engine's default.
+	case api.DNSNone:
+		// Only what DNSConfig provides below.
+	}
+	if cfg := pod.Spec.DNSConfig; cfg != nil {
+		for _, ns := range cfg.Nameservers {
+			args = append(args, "--dns", ns)
+		}
+		for _, search := range cfg.Searches {
+			args = append(args, "--dns-search", search)
+		}
+		for _, opt := range cfg.Options {
+			args = append(args, "--dns-option", opt)
+		}
+	}
+	return args
+}
+
+// syncInitContainer advances one init container and reports whether
+// startup may proceed past it. A sidecar is done once running; a
+// one-shot init is done once it has exited zero, and is restarted
+// after a failure only if the pod restart policy allows it.
+func (d *Docker) syncInitContainer(pod *api.Pod, spec api.Container, byName map[string]Container) (bool, error) {
+	c, ok := byName[spec.Name]
+	if !ok {
+		if err := d.startContainer(pod, spec); err != nil {
+			return false, fmt.Errorf("starting init container %s: %w", spec.Name, err)
+		}
+		// Freshly started: a sidecar is now running, a one-shot init
+		// still has to finish.
+		return spec.Sidecar(), nil
+	}
+	if c.Running {
+		return spec.Sidecar(), nil
+	}
+	if spec.Sidecar() || (c.ExitCode != 0 && shouldRestart(pod.Spec.RestartPolicy, c.ExitCode)) {
+		if _, err := d.exec("rm", "-f", c.ID); err != nil {
+			return false, err
+		}
+		if err := d.startContainer(pod, spec); err != nil {
+			return false, fmt.Errorf("restarting init container %s: %w", spec.Name, err)
+		}
+		return spec.Sidecar(), nil
+	}
+	return c.ExitCode == 0, nil
+}
+
+// restartArg translates the pod restart policy into the engine's
+// flag. With the agent managing restarts the policy is always "no" —
+// an explicit "no" rather than the engine default, since CLI-compatible
+// engines do not all agree on what the default is.
+func (d *Docker) restartArg(policy api.RestartPolicy) string {
+	if !d.EngineRestarts {
+		return "no"
+	}
+	switch policy {
+	case api.RestartNever:
+		return "no"
+	case api.RestartOnFailure:
+		return "on-failure"
+	}
+	return "always"
+}
+
+// shouldRestart applies the pod restart policy to one exited container.
+func shouldRestart(policy api.RestartPolicy, exitCode int) bool {
+	switch policy {
+	case api.RestartNever:
+		return false
+	case api.RestartOnFailure:
+		return exitCode != 0
+	}
+	return true
+}
+
+func (d *Docker) startContainer(pod *api.Pod, spec api.Container) error {
+	args := []string{"run", "-d",
+		"--name", containerName(pod.Meta.Namespace, pod.Meta.Name, spec.Name)}
+	args = append(args, labelArgs(pod, spec.Name)...)
+	if pod.Spec.HostNetwork {
+		args = append(args, "--network", "host")
+	}
+	for k, v := range spec.Env {
+		args = append(args, "-e", k+"="+v)
+	}
+	for _, port := range spec.Ports {
+		if port.HostPort != 0 {
+			args = append(args, "-p", fmt.Sprintf("%d:%d", port.HostPort, port.ContainerPort))
+		}
+	}
+	args = append(args, volumeArgs(pod, spec)...)
+	if cpu := spec.Resources.Limits[api.ResourceCPU]; cpu > 0 {
+		args = append(args, "--cpus", strconv.FormatFloat(float64(cpu)/1000, 'f', -1, 64))
+	}
+	if mem := spec.Resources.Limits[api.ResourceMemory]; mem > 0 {
+		args = append(args, "--memory", strconv.FormatInt(mem, 10))
+	}
+	args = append(args, "--restart", d.restartArg(pod.Spec.RestartPolicy))
+	args = append(args, d.dnsArgs(pod)...)
+	args = append(args, d.hostsArgs(pod)...)
+	args = append(args, "--oom-score-adj", strconv.Itoa(oomScoreAdj(pod)))
+	if d.RuntimeHandler != "" {
+		args = append(args, "--runtime", d.RuntimeHandler)
+	}
+	if sc := spec.SecurityContext; sc != nil {
+		if sc.Privileged {
+			args = append(args, "--privileged")
+		}
+		if sc.RunAsUser != nil {
+			args = append(args, "--user", strconv.Itoa(*sc.RunAsUser))
+		}
+	}
+	args = append(args, spec.Image)
+	args = append(args, spec.Command...)
+	args = append(args, spec.Args...)
+	_, err := d.exec(args...)
+	if err != nil && strings.Contains(err.Error(), "already in use") {
+		adopted, resolveErr := d.resolveNameConflict(pod, spec)
+		if resolveErr != nil || adopted {
+			return resolveErr
+		}
+		_, err = d.exec(args...)
+	}
+	return err
+}
+
+// resolveNameConflict frees (or adopts) a managed container name that
+// another container holds, which happens when a previous reconcile
+// crashed between create and relist, or when something else squatted on
+// the synthesis_ prefix. Ownership is verified by labels, not name: a
+// running holder carrying this pod's labels is ours and is adopted;
+// everything else is removed so the retry can create a container from
+// the current spec.
+func (d *Docker) resolveNameConflict(pod *api.Pod, spec api.Container) (adopted bool, err error) {
+	name := containerName(pod.Meta.Namespace, pod.Meta.Name, spec.Name)
+	out, err := d.exec("inspect", "--format",
+		`{{index .Config.Labels "`+labelNamespace+`"}}/`+
+			`{{index .Config.Labels "`+labelPod+`"}}/`+
+			`{{index .Config.Labels "`+labelContainer+`"}}`+
+			"\t{{.State.Running}}", name)
+	if err != nil {
+		// The holder disappeared on its own; nothing left to resolve.
+		return false, nil
+	}
+	owner, state, _ := strings.Cut(out, "\t")
+	want := pod.Meta.Namespace + "/" + pod.Meta.Name + "/" + spec.Name
+	if owner == want && state == "true" {
+		// A live leftover of ours: adopt it rather than restart the
+		// workload; the cache picks it up on the next relist.
+		return true, nil
+	}
+	if owner != want {
+		logger.Warn("removing stray container holding a managed name",
+			"container", name, "labels", owner)
+	}
+	_, err = d.exec("rm", "-f", name)
+	return false, err
+}
+
+// RemoveStrayContainers implements StrayCleaner: containers using the
+// synthesis_ name prefix without the management labels can only be
+// leftovers from a crashed create or an impostor, and either way they
+// block the names they hold.
+func (d *Docker) RemoveStrayContainers() error {
+	out, err := d.exec("ps", "-a",
+		"--filter", "name=synthesis_",
+		"--format", "{{.ID}}\t"+`{{.Label "`+labelPod+`"}}`)
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(out, "\n") {
+		id, owner, ok := strings.Cut(line, "\t")
+		if !ok || id == "" || owner != "" {
+			continue
+		}
+		logger.Warn("removing unlabeled stray container", "container", id)
+		if _, err := d.exec("rm", "-f", id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StopPod implements PodStopper. The engine delivers SIGTERM and kills
+// whatever is still running after the grace period; a grace longer
+// than OpTimeout is effectively capped by it.
+func (d *Docker) StopPod(namespace, name string, grace time.Duration) error {
+	containers, err := d.podContainers(namespace, name)
+	if err != nil {
+		return err
+	}
+	for _, c := range containers {
+		if _, err := d.exec("stop", "-t", strconv.Itoa(int(grace.Seconds())), c.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemovePod implements Runtime.
+func (d *Docker) RemovePod(namespace, name string) error {
+	containers, err := d.podContainers(namespace, name)
+	if err != nil {
+		return err
+	}
+	for _, c := range containers {
+		if _, err := d.exec("rm", "-f", c.ID); err != nil {
+			return err
+		}
+	}
+	return d.removePodVolumes(namespace, name)
+}
+
+func (d *Docker) podContainers(namespace, name string) ([]Container, error) {
+	all, err := d.ListContainers()
+	if err != nil {
+		return nil, err
+	}
+	var out []Container
+	for _, c := range all {
+		if c.PodNamespace == namespace && c.PodName == name {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+// ListContainers implements Runtime by listing containers carrying the
+// pod label, running or not.
+func (d *Docker) ListContainers() ([]Container, error) {
+	out, err := d.exec("ps", "-a",
+		"--filter", "label="+labelPod,
+		"--format", "{{.ID}}\t"+
+			`{{.Label "`+labelNamespace+`"}}`+"\t"+
+			`{{.Label "`+labelPod+`"}}`+"\t"+
+			`{{.Label "`+labelContainer+`"}}`+"\t{{.State}}\t{{.Status}}\t{{.CreatedAt}}")
+	if err != nil {
+		return nil, err
+	}
+	var containers []Container
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			continue
+		}
+		containers = append(containers, Container{
+			ID:           fields[0],
+			PodNamespace: fields[1],
+			PodName:      fields[2],
+			Name:         fields[3],
+			Running:      fields[4] == "running",
+			ExitCode:     parseExitCode(fields[5]),
+			StartedAt:    parseCreatedAt(fields[6]),
+		})
+	}
+	return containers, nil
+}
+
+// parseCreatedAt reads docker's CreatedAt timestamps, e.g.
+// "2024-01-27 14:19:03 +0100 CET"; unparseable input yields zero.
+func parseCreatedAt(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04:05 -0700 MST", strings.TrimSpace(s))
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// parseExitCode pulls the code out of a status like "Exited (1) 2
+// minutes ago"; anything else reads as zero.
+func parseExitCode(status string) int {
+	_, rest, ok := strings.Cut(status, "(")
+	if !ok {
+		return 0
+	}
+	code, _, _ := strings.Cut(rest, ")")
+	n, err := strconv.Atoi(code)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// ContainerStats implements Runtime with one `docker stats` sample.
+func (d *Docker) ContainerStats(ids []string) (map[string]Stats, error) {
+	stats := make(map[string]Stats, len(ids))
+	if len(ids) == 0 {
+		return stats, nil
+	}
+	args := append([]string{"stats", "--no-stream", "--format", "{{.ID}}\t{{.CPUPerc}}\t{{.MemUsage}}\t{{.NetIO}}"}, ids...)
+	out, err := d.exec(args...)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 4 {
+			continue
+		}
+		// CPUPerc is a percentage of one core, e.g. "12.07%".
+		perc, err := strconv.ParseFloat(strings.TrimSuffix(fields[1], "%"), 64)
+		if err != nil {
+			continue
+		}
+		// MemUsage looks like "21.5MiB / 7.6GiB"; only the usage matters.
+		used, _, _ := strings.Cut(fields[2], " / ")
+		// NetIO is "received / sent" since container start.
+		rx, tx, _ := strings.Cut(fields[3], " / ")
+		stats[fields[0]] = Stats{
+			CPUMilli:    int64(perc * 10),
+			MemoryBytes: parseQuantity(used),
+			NetRxBytes:  parseQuantity(rx),
+			NetTxBytes:  parseQuantity(tx),
+		}
+	}
+	return stats, nil
+}
+
+// ContainerDiskUsage implements DiskUsageReporter. `inspect --size`
+// reports SizeRw: the bytes a container has written over its image.
+// Results line up with the requested IDs by position.
+func (d *Docker) ContainerDiskUsage(ids []string) (map[string]int64, error) {
+	usage := make(map[string]int64, len(ids))
+	if len(ids) == 0 {
+		return usage, nil
+	}
+	args := append([]string{"inspect", "--size", "--format", "{{.SizeRw}}"}, ids...)
+	out, err := d.exec(args...)
+	if err != nil {
+		return nil, err
+	}
+	for i, line := range strings.Split(out, "\n") {
+		if i >= len(ids) {
+			break
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(line), 10, 64)
+		if err != nil {
+			continue
+		}
+		usage[ids[i]] = n
+	}
+	return usage, nil
+}
+
+// PullImage implements ImagePuller. With a mirror configured for the
+// image's registry the pull goes through the mirror first and the
+// result is re-tagged under the original reference; when the mirror
+// cannot serve it the source registry is tried directly.
+func (d *Docker) PullImage(image string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if mirrored := d.mirrorRef(image); mirrored != image {
+		err := d.pull(ctx, mirrored, timeout)
+		if err == nil {
+			if _, err := d.exec("tag", mirrored, image); err != nil {
+				return err
+			}
+			// The retag keeps the layers; dropping the mirror's name
+			// keeps the image store readable. Best effort.
+			d.exec("rmi", mirrored)
+			return nil
+		}
+		logger.Warn("mirror pull failed, falling back to source registry",
+			"image", image, "mirror", mirrored, "err", err)
+	}
+	return d.pull(ctx, image, timeout)
+}
+
+func (d *Docker) pull(ctx context.Context, image string, timeout time.Duration) error {
+	args := append(d.globalArgs(), "pull")
+	if d.Platform != "" {
+		args = append(args, "--platform", d.Platform)
+	}
+	args = append(args, image)
+	out, err := exec.CommandContext(ctx, d.Binary, args...).CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("%s pull: %v: %s", d.Binary, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// mirrorRef rewrites an image reference through the mirror configured
+// for its registry, returning it unchanged when no mirror matches.
+func (d *Docker) mirrorRef(image string) string {
+	host, repository := splitRegistry(image)
+	mirror := d.Mirrors[host]
+	if mirror == "" {
+		return image
+	}
+	return strings.TrimSuffix(mirror, "/") + "/" + repository
+}
+
+// splitRegistry splits a reference into registry host and repository
+// path, by Docker's rule: the first path segment is a host only when
+// it contains a dot or colon or is "localhost". Bare docker.io names
+// gain their implicit library/ prefix so mirrors see the canonical
+// path.
+func splitRegistry(image string) (host, repository string) {
+	first, rest, found := strings.Cut(image, "/")
+	if found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		return first, rest
+	}
+	if !found {
+		image = "library/" + image
+	}
+	return "docker.io", image
+}
+
+// Events implements EventSource by tailing `docker events` for managed
+// containers, mapping each line back to its pod via the labels.
+func (d *Docker) Events(ctx context.Context) (<-chan ContainerEvent, error) {
+	args := append(d.globalArgs(), "events",
+		"--filter", "type=container",
+		"--filter", "label="+labelPod,
+		"--format", "{{.Status}}\t{{.ID}}\t"+
+			`{{index .Actor.Attributes "`+labelNamespace+`"}}`+"\t"+
+			`{{index .Actor.Attributes "`+labelPod+`"}}`+"\t"+
+			`{{index .Actor.Attributes "`+labelContainer+`"}}`)
+	cmd := exec.CommandContext(ctx, d.Binary, args...)
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	ch := make(chan ContainerEvent, 64)
+	go func() {
+		defer close(ch)
+		defer cmd.Wait()
+		sc := bufio.NewScanner(out)
+		for sc.Scan() {
+			fields := strings.Split(sc.Text(), "\t")
+			if len(fields) != 5 {
+				continue
+			}
+			ev := ContainerEvent{
+				Action:       fields[0],
+				ID:           fields[1],
+				PodNamespace: fields[2],
+				PodName:      fields[3],
+				Name:         fields[4],
+			}
+			select {
+			case ch <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// parseQuantity reads docker's humanized byte quantities ("21.5MiB",
+// "1.2GB"); unparseable input yields zero.
+func parseQuantity(s string) int64 {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i <= 0 {
+		return 0
+	}
+	value, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return 0
+	}
+	switch strings.TrimSpace(s[i:]) {
+	case "B":
+		return int64(value)
+	case "KiB":
+		return int64(value * (1 << 10))
+	case "MiB":
+		return int64(value * (1 << 20))
+	case "GiB":
+		return int64(value * (1 << 30))
+	case "TiB":
+		return int64(value * (1 << 40))
+	case "kB":
+		return int64(value * 1e3)
+	case "MB":
+		return int64(value * 1e6)
+	case "GB":
+		return int64(value * 1e9)
+	case "TB":
+		return int64(value * 1e12)
+	}
+	return 0
+}
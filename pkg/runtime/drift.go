@@ -0,0 +1,91 @@
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// ContainerDrift implements DriftChecker by inspecting the container's
+// recorded config. The image must match the spec exactly, and every
+// spec env var must be present with its spec value; extra engine-set
+// vars (PATH, HOSTNAME, ...) are ignored.
+func (d *Docker) ContainerDrift(containerID string, spec api.Container) ([]string, error) {
+	out, err := d.exec("inspect", "--format",
+		"{{.Config.Image}}\t{{json .Config.Env}}", containerID)
+	if err != nil {
+		return nil, err
+	}
+	image, envJSON, _ := strings.Cut(out, "\t")
+
+	var findings []string
+	if image != spec.Image {
+		findings = append(findings, fmt.Sprintf("image %q (want %q)", image, spec.Image))
+	}
+
+	var envList []string
+	if err := json.Unmarshal([]byte(envJSON), &envList); err != nil {
+		return nil, fmt.Errorf("parsing container env: %w", err)
+	}
+	env := make(map[string]string, len(envList))
+	for _, kv := range envList {
+		k, v, _ := strings.Cut(kv, "=")
+		env[k] = v
+	}
+	keys := make([]string, 0, len(spec.Env))
+	for k := range spec.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		want := spec.Env[k]
+		got, ok := env[k]
+		switch {
+		case !ok:
+			findings = append(findings, fmt.Sprintf("env %s missing (want %q)", k, want))
+		case got != want:
+			findings = append(findings, fmt.Sprintf("env %s=%q (want %q)", k, got, want))
+		}
+	}
+	return findings, nil
+}
+
+// RemoveContainer implements DriftChecker.
+func (d *Docker) RemoveContainer(containerID string) error {
+	_, err := d.exec("rm", "-f", containerID)
+	return err
+}
+
+// ResizeContainer implements InPlaceResizer through `docker update`.
+// Zero limits read as unlimited on both sides, matching what
+// startContainer passes at create time.
+func (d *Docker) ResizeContainer(containerID string, spec api.Container) (bool, error) {
+	out, err := d.exec("inspect", "--format",
+		"{{.HostConfig.NanoCpus}}\t{{.HostConfig.Memory}}", containerID)
+	if err != nil {
+		return false, err
+	}
+	cpuStr, memStr, _ := strings.Cut(out, "\t")
+	curNano, _ := strconv.ParseInt(cpuStr, 10, 64)
+	curMem, _ := strconv.ParseInt(memStr, 10, 64)
+
+	// Millicores to docker's nano-CPUs; the flag itself takes cores.
+	wantCPU := spec.Resources.Limits[api.ResourceCPU]
+	wantMem := spec.Resources.Limits[api.ResourceMemory]
+	args := []string{"update"}
+	if wantCPU*1e6 != curNano {
+		args = append(args, "--cpus", strconv.FormatFloat(float64(wantCPU)/1000, 'f', -1, 64))
+	}
+	if wantMem != curMem {
+		args = append(args, "--memory", strconv.FormatInt(wantMem, 10))
+	}
+	if len(args) == 1 {
+		return false, nil
+	}
+	_, err = d.exec(append(args, containerID)...)
+	return err == nil, err
+}
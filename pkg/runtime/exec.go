@@ -0,0 +1,76 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// execCaptureLimit bounds each captured exec stream. 1MiB holds any
+// sane diagnostic output; past that the result is flagged truncated
+// instead of growing without bound.
+const execCaptureLimit = 1 << 20
+
+// Exec implements Execer through `docker exec`. Driving the CLI with
+// separate stdout and stderr pipes leaves the engine's attach-stream
+// demultiplexing to the CLI itself, so the streams arrive already
+// separated regardless of how much the command writes.
+func (d *Docker) Exec(ctx context.Context, containerID string, cmd []string) (*ExecResult, error) {
+	if len(cmd) == 0 {
+		return nil, fmt.Errorf("exec needs a command")
+	}
+	args := append(append(d.globalArgs(), "exec", containerID), cmd...)
+	c := exec.CommandContext(ctx, d.Binary, args...)
+	stdout := &cappedBuffer{limit: execCaptureLimit}
+	stderr := &cappedBuffer{limit: execCaptureLimit}
+	c.Stdout, c.Stderr = stdout, stderr
+	err := c.Run()
+	result := &ExecResult{
+		Stdout:          stdout.buf.Bytes(),
+		Stderr:          stderr.buf.Bytes(),
+		StdoutTruncated: stdout.truncated,
+		StderrTruncated: stderr.truncated,
+	}
+	if err != nil {
+		var exit *exec.ExitError
+		if !errors.As(err, &exit) {
+			return nil, fmt.Errorf("%s exec: %v", d.Binary, err)
+		}
+		result.ExitCode = exit.ExitCode()
+		// The engine reports a missing container through the CLI's own
+		// exit code and a stderr message; surface that as an error
+		// rather than a command failure.
+		if result.ExitCode == 125 || result.ExitCode == 126 ||
+			strings.Contains(string(result.Stderr), "No such container") {
+			return nil, fmt.Errorf("%s exec: %s", d.Binary, strings.TrimSpace(string(result.Stderr)))
+		}
+	}
+	return result, nil
+}
+
+// cappedBuffer collects writes up to limit bytes and flags overflow.
+type cappedBuffer struct {
+	limit     int
+	buf       bytes.Buffer
+	truncated bool
+}
+
+// Write implements io.Writer. It never errors: overflow is recorded,
+// not propagated, so the command runs to completion either way.
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	room := b.limit - b.buf.Len()
+	if room <= 0 {
+		b.truncated = true
+		return len(p), nil
+	}
+	if len(p) > room {
+		b.truncated = true
+		b.buf.Write(p[:room])
+		return len(p), nil
+	}
+	b.buf.Write(p)
+	return len(p), nil
+}
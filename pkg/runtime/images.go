@@ -0,0 +1,77 @@
+package runtime
+
+import (
+	"strings"
+)
+
+// Image is one entry in the node's image store.
+type Image struct {
+	// Ref is the repository:tag reference, or "<none>" for danglers.
+	Ref string
+
+	// ID is the engine's image identifier.
+	ID string
+
+	// Size is the engine's humanized size ("21.5MB").
+	Size string
+}
+
+// ImageManager is implemented by runtimes that can manage the node's
+// image store directly. Air-gapped installs use it to preload images
+// from tarballs instead of a registry.
+type ImageManager interface {
+	// ListImages returns the images present on the node.
+	ListImages() ([]Image, error)
+
+	// RemoveImage deletes one image by reference or ID. Images still
+	// used by containers are the engine's call to refuse.
+	RemoveImage(ref string) error
+
+	// LoadImages imports the OCI/docker tarball at path and returns
+	// the references it carried.
+	LoadImages(path string) ([]string, error)
+}
+
+// ListImages implements ImageManager.
+func (d *Docker) ListImages() ([]Image, error) {
+	out, err := d.exec("image", "ls", "--format", "{{.Repository}}:{{.Tag}}\t{{.ID}}\t{{.Size}}")
+	if err != nil {
+		return nil, err
+	}
+	var images []Image
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			continue
+		}
+		images = append(images, Image{Ref: fields[0], ID: fields[1], Size: fields[2]})
+	}
+	return images, nil
+}
+
+// RemoveImage implements ImageManager.
+func (d *Docker) RemoveImage(ref string) error {
+	_, err := d.exec("rmi", ref)
+	return err
+}
+
+// LoadImages implements ImageManager. `docker load` prints one
+// "Loaded image: ref" (or "Loaded image ID: sha") line per image in
+// the tarball.
+func (d *Docker) LoadImages(path string) ([]string, error) {
+	out, err := d.exec("load", "-i", path)
+	if err != nil {
+		return nil, err
+	}
+	var refs []string
+	for _, line := range strings.Split(out, "\n") {
+		if ref, ok := strings.CutPrefix(strings.TrimSpace(line), "Loaded image: "); ok {
+			refs = append(refs, ref)
+			continue
+		}
+		if ref, ok := strings.CutPrefix(strings.TrimSpace(line), "Loaded image ID: "); ok {
+			refs = append(refs, ref)
+		}
+	}
+	return refs, nil
+}
@@ -0,0 +1,49 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+)
+
+// Logs implements LogStreamer through `docker logs`. Stdout and stderr
+// share one pipe, so lines arrive in the order the CLI emits them.
+// Closing the returned reader kills the CLI process, so a dropped
+// follow never leaks one.
+func (d *Docker) Logs(ctx context.Context, containerID string, follow bool, tail int) (io.ReadCloser, error) {
+	args := append(d.globalArgs(), "logs")
+	if follow {
+		args = append(args, "--follow")
+	}
+	if tail >= 0 {
+		args = append(args, "--tail", strconv.Itoa(tail))
+	}
+	args = append(args, containerID)
+	ctx, cancel := context.WithCancel(ctx)
+	cmd := exec.CommandContext(ctx, d.Binary, args...)
+	pr, pw := io.Pipe()
+	cmd.Stdout, cmd.Stderr = pw, pw
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return nil, fmt.Errorf("%s logs: %v", d.Binary, err)
+	}
+	go func() {
+		pw.CloseWithError(cmd.Wait())
+	}()
+	return &logStream{pr: pr, cancel: cancel}, nil
+}
+
+// logStream couples the pipe with the cancel that stops the CLI.
+type logStream struct {
+	pr     *io.PipeReader
+	cancel context.CancelFunc
+}
+
+func (s *logStream) Read(p []byte) (int, error) { return s.pr.Read(p) }
+
+func (s *logStream) Close() error {
+	s.cancel()
+	return s.pr.Close()
+}
@@ -0,0 +1,131 @@
+package runtime
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ImagePuller is the slice of a runtime the pull manager needs.
+type ImagePuller interface {
+	// PullImage fetches the image, giving up after timeout.
+	PullImage(image string, timeout time.Duration) error
+}
+
+// pull is one in-flight fetch that any number of callers may join.
+type pull struct {
+	done chan struct{}
+	err  error
+}
+
+// PullManager deduplicates simultaneous pulls of the same image,
+// bounds how many run at once, retries failures with backoff, and
+// enforces a per-attempt timeout. Scaling a workload to ten replicas
+// costs one pull, not ten.
+type PullManager struct {
+	puller ImagePuller
+
+	// MaxAttempts is how often a failing pull is retried before the
+	// error is surfaced.
+	MaxAttempts int
+
+	// Timeout bounds a single pull attempt.
+	Timeout time.Duration
+
+	// Backoff is the delay after the first failure; it doubles per
+	// attempt.
+	Backoff time.Duration
+
+	// sem bounds concurrent pulls.
+	sem chan struct{}
+
+	mu       sync.Mutex
+	inflight map[string]*pull
+	pulled   map[string]bool
+	status   map[string]string
+}
+
+// NewPullManager returns a manager with default limits over the given
+// puller.
+func NewPullManager(p ImagePuller) *PullManager {
+	return &PullManager{
+		puller:      p,
+		MaxAttempts: 3,
+		Timeout:     10 * time.Minute,
+		Backoff:     5 * time.Second,
+		sem:         make(chan struct{}, 2),
+		inflight:    make(map[string]*pull),
+		pulled:      make(map[string]bool),
+		status:      make(map[string]string),
+	}
+}
+
+// TryPull starts or joins a pull without blocking and reports whether
+// the image is available. A finished failed pull surfaces its error
+// once; the next call starts over.
+func (m *PullManager) TryPull(image string) (bool, error) {
+	m.mu.Lock()
+	if m.pulled[image] {
+		m.mu.Unlock()
+		return true, nil
+	}
+	p, ok := m.inflight[image]
+	if !ok {
+		p = &pull{done: make(chan struct{})}
+		m.inflight[image] = p
+		go m.run(image, p)
+	}
+	m.mu.Unlock()
+	select {
+	case <-p.done:
+		return p.err == nil, p.err
+	default:
+		return false, nil
+	}
+}
+
+// Status is the human-readable progress of an in-flight pull, suitable
+// for a pod status message. Empty when nothing is happening.
+func (m *PullManager) Status(image string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status[image]
+}
+
+func (m *PullManager) run(image string, p *pull) {
+	p.err = m.pullWithRetry(image)
+	m.mu.Lock()
+	if p.err == nil {
+		m.pulled[image] = true
+	}
+	delete(m.inflight, image)
+	delete(m.status, image)
+	m.mu.Unlock()
+	close(p.done)
+}
+
+func (m *PullManager) pullWithRetry(image string) error {
+	backoff := m.Backoff
+	var err error
+	for attempt := 1; attempt <= m.MaxAttempts; attempt++ {
+		m.setStatus(image, fmt.Sprintf("Pulling image %q (attempt %d/%d)", image, attempt, m.MaxAttempts))
+		m.sem <- struct{}{}
+		err = m.puller.PullImage(image, m.Timeout)
+		<-m.sem
+		if err == nil {
+			return nil
+		}
+		if attempt < m.MaxAttempts {
+			m.setStatus(image, fmt.Sprintf("Pull of %q failed, retrying in %s: %v", image, backoff, err))
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+	return fmt.Errorf("pulling %q: %w", image, err)
+}
+
+func (m *PullManager) setStatus(image, s string) {
+	m.mu.Lock()
+	m.status[image] = s
+	m.mu.Unlock()
+}
@@ -0,0 +1,174 @@
+// Package runtime starts and stops pod containers on a node. The only
+// implementation drives the Docker CLI through os/exec, so the agent
+// carries no client library and works against whatever Docker (or a
+// CLI-compatible engine) is installed on the host.
+package runtime
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// Container is one runtime container managed by Synthesis, mapped back
+// to the pod and spec container it implements.
+type Container struct {
+	// ID is the runtime's identifier for the container.
+	ID string
+
+	// Name is the container's name within the pod spec.
+	Name string
+
+	PodNamespace string
+	PodName      string
+
+	Running bool
+
+	// ExitCode is meaningful only when Running is false.
+	ExitCode int
+
+	// StartedAt is when the runtime created the container; zero when
+	// the engine did not report it.
+	StartedAt time.Time
+}
+
+// Stats is a point-in-time usage sample for one container.
+type Stats struct {
+	// CPUMilli is the CPU currently in use, in millicores.
+	CPUMilli int64
+
+	// MemoryBytes is the resident memory in use.
+	MemoryBytes int64
+
+	// NetRxBytes and NetTxBytes are the bytes the container has
+	// received and sent since it started. Cumulative, not a rate:
+	// consumers diff successive samples.
+	NetRxBytes int64
+	NetTxBytes int64
+}
+
+// ContainerEvent is one lifecycle event reported by the engine for a
+// managed container.
+type ContainerEvent struct {
+	// Action is the engine's verb: start, die, oom, destroy, ...
+	Action string
+
+	// ID is the affected container.
+	ID string
+
+	PodNamespace string
+	PodName      string
+
+	// Name is the container's name within the pod spec.
+	Name string
+}
+
+// EventSource is implemented by runtimes that can stream container
+// lifecycle events. Consumers use it to react immediately instead of
+// polling the engine once per container per tick, which is both slow
+// and lossy.
+type EventSource interface {
+	// Events streams lifecycle events for managed containers until ctx
+	// is canceled or the engine drops the stream, which closes the
+	// channel; callers reconnect.
+	Events(ctx context.Context) (<-chan ContainerEvent, error)
+}
+
+// PodStopper is implemented by runtimes that can stop a pod's
+// containers gracefully — SIGTERM, then a kill after the grace period —
+// before they are removed. Without it, teardown is immediate.
+type PodStopper interface {
+	// StopPod stops all of the pod's containers, giving each up to
+	// grace to exit on its own.
+	StopPod(namespace, name string, grace time.Duration) error
+}
+
+// ExecResult is a finished in-container command. Stdout and stderr are
+// captured separately — never interleaved — and each stream is bounded;
+// a stream that hit the cap is flagged truncated rather than silently
+// cut.
+type ExecResult struct {
+	ExitCode int
+
+	Stdout []byte
+	Stderr []byte
+
+	StdoutTruncated bool
+	StderrTruncated bool
+}
+
+// Execer is implemented by runtimes that can run a command inside an
+// existing container.
+type Execer interface {
+	// Exec runs cmd in the container and returns its captured output.
+	// A non-zero command exit is reported in the result, not as an
+	// error; errors mean the exec itself could not run.
+	Exec(ctx context.Context, containerID string, cmd []string) (*ExecResult, error)
+}
+
+// LogStreamer is implemented by runtimes that can stream a container's
+// log output.
+type LogStreamer interface {
+	// Logs streams the container's combined stdout and stderr. follow
+	// keeps the stream open for new output; a non-negative tail limits
+	// the backlog to the last n lines. Closing the reader stops the
+	// stream.
+	Logs(ctx context.Context, containerID string, follow bool, tail int) (io.ReadCloser, error)
+}
+
+// DriftChecker is implemented by runtimes that can compare a container
+// against its spec and remove one that has diverged, so a slow resync
+// can repair out-of-band changes the regular sync never looks for.
+type DriftChecker interface {
+	// ContainerDrift reports the ways the container diverges from the
+	// spec, one human-readable finding per entry; empty means in sync.
+	ContainerDrift(containerID string, spec api.Container) ([]string, error)
+
+	// RemoveContainer force-removes one container so the next sync can
+	// recreate it from the spec.
+	RemoveContainer(containerID string) error
+}
+
+// InPlaceResizer is implemented by runtimes that can change a running
+// container's CPU and memory limits without recreating it, so a
+// resource-only spec change never bounces the workload.
+type InPlaceResizer interface {
+	// ResizeContainer applies the spec's cpu/memory limits to the
+	// container and reports whether anything actually changed.
+	ResizeContainer(containerID string, spec api.Container) (bool, error)
+}
+
+// DiskUsageReporter is implemented by runtimes that can report how
+// much each container has written to its writable layer, feeding the
+// agent's ephemeral-storage limit enforcement.
+type DiskUsageReporter interface {
+	// ContainerDiskUsage returns writable-layer bytes keyed by
+	// container ID.
+	ContainerDiskUsage(ids []string) (map[string]int64, error)
+}
+
+// StrayCleaner is implemented by runtimes that can find and remove
+// containers squatting on managed names without ownership labels.
+type StrayCleaner interface {
+	RemoveStrayContainers() error
+}
+
+// Runtime is what the agent needs from a container engine.
+type Runtime interface {
+	// SyncPod makes the node's containers match the pod spec, starting
+	// any that are missing or have stopped. existing is the pod's
+	// current containers, typically served from a ContainerCache.
+	SyncPod(pod *api.Pod, existing []Container) error
+
+	// RemovePod force-removes all of the pod's containers.
+	RemovePod(namespace, name string) error
+
+	// ListContainers returns every container this runtime manages.
+	ListContainers() ([]Container, error)
+
+	// ContainerStats samples current usage for the given containers,
+	// keyed by container ID.
+	ContainerStats(ids []string) (map[string]Stats, error)
+}
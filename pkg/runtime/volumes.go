@@ -0,0 +1,137 @@
+package runtime
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// volumeName is the engine volume backing one disk emptyDir, mirroring
+// the containerName scheme so a pod's volumes are found by prefix.
+func volumeName(namespace, pod, volume string) string {
+	return fmt.Sprintf("synthesis_%s_%s_%s", namespace, pod, volume)
+}
+
+// ProjectedVolumeRoot is where the agent materializes projected volume
+// contents; containers bind-mount per-volume directories beneath it.
+const ProjectedVolumeRoot = "/var/lib/synthesis/volumes"
+
+// ProjectedVolumeDir is the host directory backing one projected
+// volume.
+func ProjectedVolumeDir(namespace, pod, volume string) string {
+	return filepath.Join(ProjectedVolumeRoot, fmt.Sprintf("%s_%s_%s", namespace, pod, volume))
+}
+
+// NetworkMountRoot is where the agent mounts NFS and CIFS volumes;
+// containers bind-mount per-volume directories beneath it.
+const NetworkMountRoot = "/var/lib/synthesis/mounts"
+
+// NetworkMountDir is the host mount point backing one network volume.
+func NetworkMountDir(namespace, pod, volume string) string {
+	return filepath.Join(NetworkMountRoot, fmt.Sprintf("%s_%s_%s", namespace, pod, volume))
+}
+
+// volumeArgs renders the mount flags for one container. HostPath, disk
+// emptyDir and ephemeral volumes become bind/volume mounts; a
+// Memory-medium emptyDir becomes a tmpfs mount so its contents never
+// touch disk. Mounts
+// naming an undeclared volume are skipped rather than failing the
+// whole container.
+func volumeArgs(pod *api.Pod, spec api.Container) []string {
+	byName := make(map[string]api.Volume, len(pod.Spec.Volumes))
+	for _, vol := range pod.Spec.Volumes {
+		byName[vol.Name] = vol
+	}
+	var args []string
+	for _, m := range spec.VolumeMounts {
+		vol, ok := byName[m.Name]
+		if !ok {
+			logger.Warn("volume mount names no pod volume, skipping",
+				"pod", pod.Meta.Name, "container", spec.Name, "volume", m.Name)
+			continue
+		}
+		switch {
+		case vol.HostPath != nil:
+			args = append(args, "-v", bindSpec(hostSource(vol.HostPath.Path, m), m.MountPath, m.ReadOnly))
+		case vol.NFS != nil || vol.CIFS != nil:
+			// The agent has already mounted the share on the host.
+			src := hostSource(NetworkMountDir(pod.Meta.Namespace, pod.Meta.Name, vol.Name), m)
+			args = append(args, "-v", bindSpec(src, m.MountPath, m.ReadOnly))
+		case vol.Projected != nil:
+			// Projected content is materialized by the agent and is
+			// read-only by nature.
+			src := hostSource(ProjectedVolumeDir(pod.Meta.Namespace, pod.Meta.Name, vol.Name), m)
+			args = append(args, "-v", bindSpec(src, m.MountPath, true))
+		case vol.EmptyDir != nil && vol.EmptyDir.Medium == api.EmptyDirMemory:
+			// SubPath is meaningless here: a tmpfs starts empty, so
+			// there is nothing inside it to select.
+			opts := []string{}
+			if m.ReadOnly {
+				opts = append(opts, "ro")
+			}
+			if vol.EmptyDir.SizeLimit > 0 {
+				opts = append(opts, "size="+strconv.FormatInt(vol.EmptyDir.SizeLimit, 10))
+			}
+			tmpfs := m.MountPath
+			if len(opts) > 0 {
+				tmpfs += ":" + strings.Join(opts, ",")
+			}
+			args = append(args, "--tmpfs", tmpfs)
+		case vol.EmptyDir != nil || vol.Ephemeral != nil:
+			name := volumeName(pod.Meta.Namespace, pod.Meta.Name, vol.Name)
+			if m.SubPath != "" {
+				// -v has no subpath syntax; the long mount form does.
+				mount := fmt.Sprintf("type=volume,src=%s,dst=%s,volume-subpath=%s",
+					name, m.MountPath, m.SubPath)
+				if m.ReadOnly {
+					mount += ",readonly"
+				}
+				args = append(args, "--mount", mount)
+				continue
+			}
+			args = append(args, "-v", bindSpec(name, m.MountPath, m.ReadOnly))
+		}
+	}
+	return args
+}
+
+// hostSource resolves a host-backed mount source, honoring subPath by
+// pointing the bind at the path inside the volume.
+func hostSource(root string, m api.VolumeMount) string {
+	if m.SubPath != "" {
+		return filepath.Join(root, m.SubPath)
+	}
+	return root
+}
+
+func bindSpec(source, dest string, readOnly bool) string {
+	spec := source + ":" + dest
+	if readOnly {
+		spec += ":ro"
+	}
+	return spec
+}
+
+// removePodVolumes deletes the engine volumes backing the pod's disk
+// emptyDirs, so scratch space goes away with the pod. tmpfs and bind
+// mounts leave nothing behind.
+func (d *Docker) removePodVolumes(namespace, name string) error {
+	out, err := d.exec("volume", "ls", "-q",
+		"--filter", "name="+volumeName(namespace, name, ""))
+	if err != nil {
+		return err
+	}
+	for _, v := range strings.Split(out, "\n") {
+		// The name filter matches substrings; keep only this pod's.
+		if !strings.HasPrefix(v, volumeName(namespace, name, "")) {
+			continue
+		}
+		if _, err := d.exec("volume", "rm", "-f", v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// PredicateSchedulable rejects nodes that are cordoned or not ready.
+func PredicateSchedulable(pod *api.Pod, node *api.Node, snap *Snapshot) error {
+	if node.Spec.Unschedulable {
+		return fmt.Errorf("node is unschedulable")
+	}
+	if !node.Ready() {
+		return fmt.Errorf("node is not ready")
+	}
+	return nil
+}
+
+// PredicateNodeSelector enforces spec.nodeSelector: every listed label
+// must be present on the node with the same value.
+func PredicateNodeSelector(pod *api.Pod, node *api.Node, snap *Snapshot) error {
+	for k, v := range pod.Spec.NodeSelector {
+		if node.Meta.Labels[k] != v {
+			return fmt.Errorf("node label %s=%q does not match selector value %q", k, node.Meta.Labels[k], v)
+		}
+	}
+	return nil
+}
+
+// PredicateNodeAffinity enforces required node affinity terms. Terms are
+// ORed: the node passes if any one term matches its labels.
+func PredicateNodeAffinity(pod *api.Pod, node *api.Node, snap *Snapshot) error {
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil {
+		return nil
+	}
+	required := pod.Spec.Affinity.NodeAffinity.Required
+	if len(required) == 0 {
+		return nil
+	}
+	for _, term := range required {
+		if term.Matches(node.Meta.Labels) {
+			return nil
+		}
+	}
+	return fmt.Errorf("node does not match any required node affinity term")
+}
+
+// PredicatePodAffinity requires that for each affinity term a matching
+// pod already runs in the node's topology domain.
+func PredicatePodAffinity(pod *api.Pod, node *api.Node, snap *Snapshot) error {
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.PodAffinity == nil {
+		return nil
+	}
+	for _, term := range pod.Spec.Affinity.PodAffinity.Required {
+		if !termSatisfied(term, node, snap) {
+			return fmt.Errorf("no pod matching affinity term (topologyKey %s) in this domain", term.TopologyKey)
+		}
+	}
+	return nil
+}
+
+// PredicatePodAntiAffinity rejects the node if any anti-affinity term
+// matches a pod already running in the node's topology domain.
+func PredicatePodAntiAffinity(pod *api.Pod, node *api.Node, snap *Snapshot) error {
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.PodAntiAffinity == nil {
+		return nil
+	}
+	for _, term := range pod.Spec.Affinity.PodAntiAffinity.Required {
+		if termSatisfied(term, node, snap) {
+			return fmt.Errorf("pod matching anti-affinity term (topologyKey %s) already in this domain", term.TopologyKey)
+		}
+	}
+	return nil
+}
+
+// PriorityNodeAffinity adds the weights of preferred node affinity terms
+// that match the node.
+func PriorityNodeAffinity(pod *api.Pod, node *api.Node, snap *Snapshot) int {
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil {
+		return 0
+	}
+	score := 0
+	for _, pref := range pod.Spec.Affinity.NodeAffinity.Preferred {
+		if pref.Term.Matches(node.Meta.Labels) {
+			score += pref.Weight
+		}
+	}
+	return score
+}
+
+// termSatisfied reports whether some scheduled pod matching the term's
+// selector runs inside the topology domain the candidate node belongs
+// to. A node missing the topology label is never part of any domain.
+func termSatisfied(term api.PodAffinityTerm, node *api.Node, snap *Snapshot) bool {
+	domain, ok := node.Meta.Labels[term.TopologyKey]
+	if !ok {
+		return false
+	}
+	for _, other := range snap.Pods {
+		if other.Status.NodeName == "" {
+			continue
+		}
+		if !term.Selector.Matches(other.Meta.Labels) {
+			continue
+		}
+		if d, ok := snap.Domain(other.Status.NodeName, term.TopologyKey); ok && d == domain {
+			return true
+		}
+	}
+	return false
+}
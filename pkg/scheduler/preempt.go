@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// Priorities resolves pod priorities against the stored classes. Build
+// one per scheduling pass so every decision in it is consistent.
+type Priorities struct {
+	classes      map[string]int
+	defaultValue int
+}
+
+// NewPriorities indexes the given classes.
+func NewPriorities(classes []*api.PriorityClass) *Priorities {
+	p := &Priorities{classes: make(map[string]int, len(classes))}
+	for _, pc := range classes {
+		p.classes[pc.Meta.Name] = pc.Value
+		if pc.GlobalDefault {
+			p.defaultValue = pc.Value
+		}
+	}
+	return p
+}
+
+// Of is the pod's effective priority: its class's value, the global
+// default for pods naming no class, and zero for unknown classes.
+func (p *Priorities) Of(pod *api.Pod) int {
+	if pod.Spec.PriorityClassName == "" {
+		return p.defaultValue
+	}
+	return p.classes[pod.Spec.PriorityClassName]
+}
+
+// without is the snapshot minus the given pods, for simulating their
+// eviction.
+func (s *Snapshot) without(victims []*api.Pod) *Snapshot {
+	gone := make(map[*api.Pod]bool, len(victims))
+	for _, v := range victims {
+		gone[v] = true
+	}
+	out := &Snapshot{Nodes: s.Nodes, Pods: make([]*api.Pod, 0, len(s.Pods))}
+	for _, pod := range s.Pods {
+		if !gone[pod] {
+			out.Pods = append(out.Pods, pod)
+		}
+	}
+	return out
+}
+
+// Preempt finds a node where evicting lower-priority pods lets the pod
+// fit, returning the node and the minimal victim set tried in
+// lowest-priority-first order. It mutates nothing; the caller evicts
+// the victims and binds the pod.
+func (sc *Scheduler) Preempt(pod *api.Pod, prios *Priorities) (string, []*api.Pod, error) {
+	snap, err := sc.snapshot()
+	if err != nil {
+		return "", nil, err
+	}
+	priority := prios.Of(pod)
+
+	nodes := make([]*api.Node, 0, len(snap.Nodes))
+	for _, node := range snap.Nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Meta.Name < nodes[j].Meta.Name })
+
+	for _, node := range nodes {
+		var lower []*api.Pod
+		for _, p := range snap.PodsOnNode(node.Meta.Name) {
+			if prios.Of(p) < priority {
+				lower = append(lower, p)
+			}
+		}
+		if len(lower) == 0 {
+			continue
+		}
+		// Cheapest victims go first so high-priority pods displace as
+		// little as possible.
+		sort.Slice(lower, func(i, j int) bool { return prios.Of(lower[i]) < prios.Of(lower[j]) })
+		var victims []*api.Pod
+		for _, victim := range lower {
+			victims = append(victims, victim)
+			if sc.filter(pod, node, snap.without(victims)) == nil {
+				return node.Meta.Name, victims, nil
+			}
+		}
+	}
+	return "", nil, fmt.Errorf("preemption cannot make room for pod %s", podName(pod))
+}
@@ -0,0 +1,33 @@
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// PredicateResourcesFit rejects nodes whose allocatable resources cannot
+// cover the pod's requests on top of what is already bound there. Nodes
+// that report no allocatable resources (agents predating resource
+// reporting) are not filtered.
+func PredicateResourcesFit(pod *api.Pod, node *api.Node, snap *Snapshot) error {
+	if len(node.Status.Allocatable) == 0 {
+		return nil
+	}
+	used := api.ResourceList{}
+	for _, other := range snap.PodsOnNode(node.Meta.Name) {
+		used = used.Add(api.PodRequests(other))
+	}
+	want := api.PodRequests(pod)
+	for name, qty := range want {
+		alloc, tracked := node.Status.Allocatable[name]
+		if !tracked {
+			continue
+		}
+		if used[name]+qty > alloc {
+			return fmt.Errorf("insufficient %s: requested %d, used %d of %d allocatable",
+				name, qty, used[name], alloc)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+// Package scheduler assigns pending pods to nodes. Placement runs in two
+// stages: hard predicates filter out infeasible nodes, then priorities
+// score the survivors and the highest score wins.
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/store"
+)
+
+// Snapshot is a point-in-time view of the cluster taken at the start of
+// a scheduling cycle, so every predicate sees consistent state.
+type Snapshot struct {
+	Nodes map[string]*api.Node
+	Pods  []*api.Pod
+}
+
+// PodsOnNode returns the pods bound to the named node.
+func (s *Snapshot) PodsOnNode(name string) []*api.Pod {
+	var out []*api.Pod
+	for _, p := range s.Pods {
+		if p.Status.NodeName == name {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Domain returns the value of the topology label on the node a pod is
+// bound to, and whether the node carries that label at all.
+func (s *Snapshot) Domain(nodeName, topologyKey string) (string, bool) {
+	node, ok := s.Nodes[nodeName]
+	if !ok {
+		return "", false
+	}
+	v, ok := node.Meta.Labels[topologyKey]
+	return v, ok
+}
+
+// Predicate is a hard filter. It returns an error describing why the
+// node is infeasible, or nil if the pod fits.
+type Predicate func(pod *api.Pod, node *api.Node, snap *Snapshot) error
+
+// Priority scores a feasible node; higher is better.
+type Priority func(pod *api.Pod, node *api.Node, snap *Snapshot) int
+
+// Scheduler picks nodes for pods that have no node assigned.
+type Scheduler struct {
+	store      store.Store
+	predicates []Predicate
+	priorities []Priority
+}
+
+// New returns a scheduler with the default predicate and priority chain.
+func New(s store.Store) *Scheduler {
+	return &Scheduler{
+		store: s,
+		predicates: []Predicate{
+			PredicateSchedulable,
+			PredicateResourcesFit,
+			PredicateNodeSelector,
+			PredicateNodeAffinity,
+			PredicatePodAffinity,
+			PredicatePodAntiAffinity,
+			PredicateTopologySpread,
+		},
+		priorities: []Priority{
+			PriorityNodeAffinity,
+			PriorityTopologySpread,
+			PriorityLeastPods,
+		},
+	}
+}
+
+// Schedule picks a node for the pod and returns its name. It does not
+// mutate the pod or the store; the caller records the binding.
+func (sc *Scheduler) Schedule(pod *api.Pod) (string, error) {
+	snap, err := sc.snapshot()
+	if err != nil {
+		return "", err
+	}
+
+	feasible := make([]*api.Node, 0, len(snap.Nodes))
+	reasons := make(map[string]error)
+	for _, node := range snap.Nodes {
+		if err := sc.filter(pod, node, snap); err != nil {
+			reasons[node.Meta.Name] = err
+			continue
+		}
+		feasible = append(feasible, node)
+	}
+	if len(feasible) == 0 {
+		return "", &UnschedulableError{Pod: podName(pod), Reasons: reasons}
+	}
+
+	// Sort for a deterministic winner when scores tie.
+	sort.Slice(feasible, func(i, j int) bool { return feasible[i].Meta.Name < feasible[j].Meta.Name })
+	best := feasible[0]
+	bestScore := sc.score(pod, best, snap)
+	for _, node := range feasible[1:] {
+		if s := sc.score(pod, node, snap); s > bestScore {
+			best, bestScore = node, s
+		}
+	}
+	return best.Meta.Name, nil
+}
+
+func (sc *Scheduler) snapshot() (*Snapshot, error) {
+	nodes, err := sc.store.ListNodes()
+	if err != nil {
+		return nil, fmt.Errorf("listing nodes: %w", err)
+	}
+	pods, err := sc.store.ListPods()
+	if err != nil {
+		return nil, fmt.Errorf("listing pods: %w", err)
+	}
+	snap := &Snapshot{Nodes: make(map[string]*api.Node, len(nodes)), Pods: pods}
+	for _, n := range nodes {
+		snap.Nodes[n.Meta.Name] = n
+	}
+	return snap, nil
+}
+
+func (sc *Scheduler) filter(pod *api.Pod, node *api.Node, snap *Snapshot) error {
+	for _, p := range sc.predicates {
+		if err := p(pod, node, snap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sc *Scheduler) score(pod *api.Pod, node *api.Node, snap *Snapshot) int {
+	total := 0
+	for _, p := range sc.priorities {
+		total += p(pod, node, snap)
+	}
+	return total
+}
+
+// UnschedulableError reports that no node could take the pod, with the
+// per-node rejection reasons.
+type UnschedulableError struct {
+	Pod     string
+	Reasons map[string]error
+}
+
+func (e *UnschedulableError) Error() string {
+	if len(e.Reasons) == 0 {
+		return fmt.Sprintf("pod %s is unschedulable: no nodes registered", e.Pod)
+	}
+	names := make([]string, 0, len(e.Reasons))
+	for name := range e.Reasons {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	msg := fmt.Sprintf("pod %s is unschedulable:", e.Pod)
+	for _, name := range names {
+		msg += fmt.Sprintf(" node %s: %v;", name, e.Reasons[name])
+	}
+	return msg
+}
+
+// PriorityLeastPods favors emptier nodes to keep placement balanced when
+// nothing else differentiates them.
+func PriorityLeastPods(pod *api.Pod, node *api.Node, snap *Snapshot) int {
+	return -len(snap.PodsOnNode(node.Meta.Name))
+}
+
+func podName(pod *api.Pod) string {
+	ns := pod.Meta.Namespace
+	if ns == "" {
+		ns = "default"
+	}
+	return ns + "/" + pod.Meta.Name
+}
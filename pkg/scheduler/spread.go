@@ -0,0 +1,97 @@
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// PredicateTopologySpread enforces DoNotSchedule spread constraints: the
+// pod may only land in a domain where the resulting count of matching
+// pods stays within maxSkew of the emptiest domain.
+func PredicateTopologySpread(pod *api.Pod, node *api.Node, snap *Snapshot) error {
+	for _, c := range pod.Spec.TopologySpreadConstraints {
+		if c.WhenUnsatisfiable != api.DoNotSchedule {
+			continue
+		}
+		skew, err := skewAfterPlacement(c, pod, node, snap)
+		if err != nil {
+			return err
+		}
+		if skew > c.MaxSkew {
+			return fmt.Errorf("placing pod would raise %s skew to %d (maxSkew %d)", c.TopologyKey, skew, c.MaxSkew)
+		}
+	}
+	return nil
+}
+
+// PriorityTopologySpread prefers emptier domains for ScheduleAnyway
+// constraints, and gently for DoNotSchedule ones too so feasible nodes
+// in less crowded domains win ties.
+func PriorityTopologySpread(pod *api.Pod, node *api.Node, snap *Snapshot) int {
+	score := 0
+	for _, c := range pod.Spec.TopologySpreadConstraints {
+		counts, domain, ok := domainCounts(c, pod, node, snap)
+		if !ok {
+			continue
+		}
+		score -= counts[domain]
+	}
+	return score
+}
+
+// skewAfterPlacement computes the skew of the constraint's domains were
+// the pod placed on the candidate node.
+func skewAfterPlacement(c api.TopologySpreadConstraint, pod *api.Pod, node *api.Node, snap *Snapshot) (int, error) {
+	counts, domain, ok := domainCounts(c, pod, node, snap)
+	if !ok {
+		return 0, fmt.Errorf("node has no %s label required by topology spread constraint", c.TopologyKey)
+	}
+	counts[domain]++
+	min := counts[domain]
+	max := counts[domain]
+	for _, n := range counts {
+		if n < min {
+			min = n
+		}
+		if n > max {
+			max = n
+		}
+	}
+	return max - min, nil
+}
+
+// domainCounts tallies matching pods per topology domain across all
+// eligible nodes and returns the candidate node's own domain. The third
+// return is false when the candidate node lacks the topology label.
+func domainCounts(c api.TopologySpreadConstraint, pod *api.Pod, node *api.Node, snap *Snapshot) (map[string]int, string, bool) {
+	domain, ok := node.Meta.Labels[c.TopologyKey]
+	if !ok {
+		return nil, "", false
+	}
+	selector := c.Selector
+	if selector == nil {
+		// Default to spreading pods that share all of this pod's labels.
+		selector = &api.LabelSelector{MatchLabels: pod.Meta.Labels}
+	}
+	counts := make(map[string]int)
+	for _, n := range snap.Nodes {
+		if d, ok := n.Meta.Labels[c.TopologyKey]; ok {
+			if _, seen := counts[d]; !seen {
+				counts[d] = 0
+			}
+		}
+	}
+	for _, other := range snap.Pods {
+		if other.Status.NodeName == "" {
+			continue
+		}
+		if !selector.Matches(other.Meta.Labels) {
+			continue
+		}
+		if d, ok := snap.Domain(other.Status.NodeName, c.TopologyKey); ok {
+			counts[d]++
+		}
+	}
+	return counts, domain, true
+}
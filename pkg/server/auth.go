@@ -0,0 +1,193 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// anonymousUser identifies requests that present no credentials.
+const anonymousUser = "system:anonymous"
+
+// EnableRBAC turns on authorization: every request is resolved to a
+// user via its bearer token and checked against the stored roles and
+// bindings. tokens maps bearer tokens to usernames.
+func (s *Server) EnableRBAC(tokens map[string]string) {
+	s.tokens = tokens
+	s.rbac = true
+}
+
+// LoadTokenFile reads a token file with one "token,username" pair per
+// line; blank lines and #-comments are skipped.
+func LoadTokenFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	tokens := make(map[string]string)
+	sc := bufio.NewScanner(f)
+	line := 0
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		token, user, ok := strings.Cut(text, ",")
+		if !ok || token == "" || user == "" {
+			return nil, fmt.Errorf("%s:%d: want token,username", path, line)
+		}
+		tokens[strings.TrimSpace(token)] = strings.TrimSpace(user)
+	}
+	return tokens, sc.Err()
+}
+
+// userFor resolves the request's identity from its bearer token: first
+// the static token table, then signed service account tokens.
+func (s *Server) userFor(r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+	token, ok := strings.CutPrefix(auth, "Bearer ")
+	if !ok {
+		return anonymousUser
+	}
+	if user, ok := s.tokens[token]; ok {
+		return user
+	}
+	if s.signer != nil {
+		if subject, err := s.signer.Verify(token); err == nil {
+			return subject
+		}
+	}
+	return anonymousUser
+}
+
+// attributes are what the authorizer checks a request against.
+type attributes struct {
+	Verb      string
+	Resource  string
+	Namespace string
+}
+
+// requestAttributes derives authorization attributes from the request.
+// Paths look like /api/v1/<resource>[/<name>[/<subresource>]] or
+// /api/v1/namespaces/<ns>/<resource>/<name>. Subresource verbs such as
+// cordon or drain become the verb itself so they can be granted apart
+// from plain updates.
+func requestAttributes(r *http.Request) attributes {
+	segs := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	// Drop the /api/v1 (or /apis/<group>/<version>) prefix.
+	if len(segs) >= 2 && (segs[0] == "api" || segs[0] == "apis") {
+		if segs[0] == "apis" && len(segs) >= 3 {
+			segs = segs[3:]
+		} else {
+			segs = segs[2:]
+		}
+	}
+	attrs := attributes{}
+	if len(segs) >= 3 && segs[0] == "namespaces" {
+		attrs.Namespace = segs[1]
+		segs = segs[2:]
+	}
+	if len(segs) > 0 {
+		attrs.Resource = segs[0]
+	}
+	switch {
+	case len(segs) >= 3: // resource/name/subresource
+		attrs.Verb = segs[2]
+	case r.Method == http.MethodGet:
+		if len(segs) >= 2 {
+			attrs.Verb = "get"
+		} else {
+			attrs.Verb = "list"
+		}
+	case r.Method == http.MethodPost:
+		attrs.Verb = "create"
+	case r.Method == http.MethodPut, r.Method == http.MethodPatch:
+		attrs.Verb = "update"
+	case r.Method == http.MethodDelete:
+		attrs.Verb = "delete"
+	default:
+		attrs.Verb = strings.ToLower(r.Method)
+	}
+	return attrs
+}
+
+// authorize reports whether the user may perform the request. A
+// namespaced request is satisfied by a binding in that namespace or a
+// cluster-wide one; cluster-scoped requests need a cluster-wide grant.
+func (s *Server) authorize(user string, attrs attributes) (bool, error) {
+	bindings, err := s.store.ListRoleBindings()
+	if err != nil {
+		return false, err
+	}
+	for _, binding := range bindings {
+		if binding.Meta.Namespace != "" && binding.Meta.Namespace != attrs.Namespace {
+			continue
+		}
+		if !binding.HasSubject(user) {
+			continue
+		}
+		role, err := s.store.GetRole(binding.Meta.Namespace, binding.RoleName)
+		if err != nil {
+			// Namespaced bindings may also refer to cluster-wide roles.
+			if binding.Meta.Namespace != "" {
+				role, err = s.store.GetRole("", binding.RoleName)
+			}
+			if err != nil {
+				continue
+			}
+		}
+		for _, rule := range role.Rules {
+			if rule.Allows(attrs.Verb, attrs.Resource) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// handleCanI answers whether the calling user may perform the verb on
+// the resource: GET /api/v1/auth/can-i?verb=get&resource=pods[&namespace=x].
+func (s *Server) handleCanI(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	attrs := attributes{
+		Verb:      q.Get("verb"),
+		Resource:  q.Get("resource"),
+		Namespace: q.Get("namespace"),
+	}
+	if attrs.Verb == "" || attrs.Resource == "" {
+		writeError(w, badRequest("verb and resource query parameters are required"))
+		return
+	}
+	allowed := true
+	if s.rbac {
+		var err error
+		allowed, err = s.authorize(s.userFor(r), attrs)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"allowed": allowed})
+}
+
+// handleWhoAmI reports the identity the server resolved from the
+// request's credentials, with the groups the identity implies:
+// GET /api/v1/auth/whoami.
+func (s *Server) handleWhoAmI(w http.ResponseWriter, r *http.Request) {
+	user := s.userFor(r)
+	groups := []string{"system:authenticated"}
+	if user == anonymousUser {
+		groups = []string{"system:unauthenticated"}
+	}
+	if rest, ok := strings.CutPrefix(user, "system:serviceaccount:"); ok {
+		groups = append(groups, "system:serviceaccounts")
+		if ns, _, ok := strings.Cut(rest, ":"); ok && ns != "" {
+			groups = append(groups, "system:serviceaccounts:"+ns)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"user": user, "groups": groups})
+}
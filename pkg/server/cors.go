@@ -0,0 +1,39 @@
+package server
+
+import "net/http"
+
+// SetCORS enables cross-origin requests from the given origins; "*"
+// allows any. Browser dashboards need these headers (or a local proxy)
+// before they can call the API at all.
+func (s *Server) SetCORS(origins []string) {
+	s.corsOrigins = origins
+}
+
+// cors adds the response headers for an allowed cross-origin request
+// and answers preflights itself, reporting whether the request was a
+// preflight and is done.
+func (s *Server) cors(w http.ResponseWriter, r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || !s.corsAllowed(origin) {
+		return false
+	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	w.Header().Add("Vary", "Origin")
+	if r.Method != http.MethodOptions || r.Header.Get("Access-Control-Request-Method") == "" {
+		return false
+	}
+	w.Header().Set("Access-Control-Allow-Methods", "GET, PUT, POST, DELETE")
+	w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+	w.Header().Set("Access-Control-Max-Age", "600")
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
+
+func (s *Server) corsAllowed(origin string) bool {
+	for _, allowed := range s.corsOrigins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+	}
+	return false
+}
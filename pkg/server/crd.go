@@ -0,0 +1,360 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+func (s *Server) handleListCRDs(w http.ResponseWriter, r *http.Request) {
+	crds, err := s.store.ListCRDs()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, crds)
+}
+
+func (s *Server) handleGetCRD(w http.ResponseWriter, r *http.Request) {
+	crd, err := s.store.GetCRD(r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, crd)
+}
+
+func (s *Server) handlePutCRD(w http.ResponseWriter, r *http.Request) {
+	var crd api.CustomResourceDefinition
+	if err := json.NewDecoder(r.Body).Decode(&crd); err != nil {
+		writeError(w, badRequest("decoding custom resource definition: %v", err))
+		return
+	}
+	crd.Meta.Name = r.PathValue("name")
+	if err := validateMeta("custom resource definition", &crd.Meta); err != nil {
+		writeError(w, err)
+		return
+	}
+	for what, v := range map[string]string{
+		"group":   crd.Spec.Group,
+		"version": crd.Spec.Version,
+		"plural":  crd.Spec.Plural,
+	} {
+		if err := validateName(what, v); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+	if crd.Spec.Kind == "" {
+		writeError(w, badRequest("kind must not be empty"))
+		return
+	}
+	var prior time.Time
+	if old, err := s.store.GetCRD(crd.Meta.Name); err == nil {
+		prior = old.Meta.CreatedAt
+	}
+	stampCreated(&crd.Meta, prior)
+	if err := s.store.PutCRD(&crd); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &crd)
+}
+
+func (s *Server) handleDeleteCRD(w http.ResponseWriter, r *http.Request) {
+	if err := s.store.DeleteCRD(r.PathValue("name")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// findCRD resolves a custom resource path to its registered definition.
+func (s *Server) findCRD(group, version, plural string) (*api.CustomResourceDefinition, error) {
+	crds, err := s.store.ListCRDs()
+	if err != nil {
+		return nil, err
+	}
+	for _, crd := range crds {
+		if crd.Spec.Group == group && crd.Spec.Version == version && crd.Spec.Plural == plural {
+			return crd, nil
+		}
+	}
+	return nil, &apiError{
+		status:  http.StatusNotFound,
+		message: fmt.Sprintf("no resource %s.%s/%s registered", plural, group, version),
+		reason:  "NotFound",
+	}
+}
+
+// handleListCustomObjects lists (or, with ?watch=true, streams) a
+// custom resource. ?labelSelector=k=v,... filters by exact label
+// values; the namespaced route narrows to one namespace.
+func (s *Server) handleListCustomObjects(w http.ResponseWriter, r *http.Request) {
+	crd, err := s.findCRD(r.PathValue("group"), r.PathValue("version"), r.PathValue("plural"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	selector, err := parseLabelSelector(r.URL.Query().Get("labelSelector"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	namespace := r.PathValue("namespace")
+	if r.URL.Query().Get("watch") == "true" {
+		s.watchCustomObjects(w, r, crd, namespace, selector)
+		return
+	}
+	objs, err := s.store.ListCustomObjects(crd.Resource())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	out := make([]*api.CustomObject, 0, len(objs))
+	for _, obj := range objs {
+		if namespace != "" && obj.Meta.Namespace != namespace {
+			continue
+		}
+		if !selector.Matches(obj.Meta.Labels) {
+			continue
+		}
+		out = append(out, obj)
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleGetCustomObject(w http.ResponseWriter, r *http.Request) {
+	crd, err := s.findCRD(r.PathValue("group"), r.PathValue("version"), r.PathValue("plural"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	obj, err := s.store.GetCustomObject(crd.Resource(), r.PathValue("namespace"), r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, obj)
+}
+
+func (s *Server) handlePutCustomObject(w http.ResponseWriter, r *http.Request) {
+	crd, err := s.findCRD(r.PathValue("group"), r.PathValue("version"), r.PathValue("plural"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var obj api.CustomObject
+	if err := json.NewDecoder(r.Body).Decode(&obj); err != nil {
+		writeError(w, badRequest("decoding %s: %v", crd.Spec.Kind, err))
+		return
+	}
+	obj.Resource = crd.Resource()
+	obj.Meta.Namespace = r.PathValue("namespace")
+	obj.Meta.Name = r.PathValue("name")
+	if crd.Spec.Namespaced && obj.Meta.Namespace == "" {
+		writeError(w, badRequest("%s is namespaced; use the namespaced route", crd.Spec.Kind))
+		return
+	}
+	if !crd.Spec.Namespaced && obj.Meta.Namespace != "" {
+		writeError(w, badRequest("%s is cluster-scoped", crd.Spec.Kind))
+		return
+	}
+	if err := validateMeta(crd.Spec.Kind, &obj.Meta); err != nil {
+		writeError(w, err)
+		return
+	}
+	if crd.Spec.Schema != nil {
+		var spec any
+		if len(obj.Spec) > 0 {
+			if err := json.Unmarshal(obj.Spec, &spec); err != nil {
+				writeError(w, badRequest("decoding spec: %v", err))
+				return
+			}
+		}
+		if err := validateSchema(crd.Spec.Schema, spec, "spec"); err != nil {
+			writeError(w, badRequest("%v", err))
+			return
+		}
+	}
+	eventType := "MODIFIED"
+	var prior time.Time
+	if old, err := s.store.GetCustomObject(obj.Resource, obj.Meta.Namespace, obj.Meta.Name); err != nil {
+		eventType = "ADDED"
+	} else {
+		prior = old.Meta.CreatedAt
+	}
+	stampCreated(&obj.Meta, prior)
+	if err := s.store.PutCustomObject(&obj); err != nil {
+		writeError(w, err)
+		return
+	}
+	s.watches.notify(obj.Resource, watchEvent{Type: eventType, Object: &obj})
+	writeJSON(w, http.StatusOK, &obj)
+}
+
+func (s *Server) handleDeleteCustomObject(w http.ResponseWriter, r *http.Request) {
+	crd, err := s.findCRD(r.PathValue("group"), r.PathValue("version"), r.PathValue("plural"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	namespace, name := r.PathValue("namespace"), r.PathValue("name")
+	obj, err := s.store.GetCustomObject(crd.Resource(), namespace, name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := s.store.DeleteCustomObject(crd.Resource(), namespace, name); err != nil {
+		writeError(w, err)
+		return
+	}
+	s.watches.notify(crd.Resource(), watchEvent{Type: "DELETED", Object: obj})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// watchCustomObjects streams the current objects as ADDED events and
+// then every subsequent change, one JSON event per line, until the
+// client disconnects.
+func (s *Server) watchCustomObjects(w http.ResponseWriter, r *http.Request, crd *api.CustomResourceDefinition, namespace string, selector *api.LabelSelector) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, badRequest("watch requires a streaming connection"))
+		return
+	}
+	ch := s.watches.subscribe(crd.Resource())
+	defer s.watches.unsubscribe(crd.Resource(), ch)
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	objs, err := s.store.ListCustomObjects(crd.Resource())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	send := func(ev watchEvent) bool {
+		obj := ev.Object.(*api.CustomObject)
+		if namespace != "" && obj.Meta.Namespace != namespace {
+			return true
+		}
+		if !selector.Matches(obj.Meta.Labels) {
+			return true
+		}
+		if err := enc.Encode(ev); err != nil {
+			return false
+		}
+		flusher.Flush()
+		return true
+	}
+	for _, obj := range objs {
+		if !send(watchEvent{Type: "ADDED", Object: obj}) {
+			return
+		}
+	}
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev := <-ch:
+			if !send(ev) {
+				return
+			}
+		}
+	}
+}
+
+// parseLabelSelector parses the equality-only "k=v,k2=v2" query form.
+func parseLabelSelector(raw string) (*api.LabelSelector, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	labels := make(map[string]string)
+	for _, term := range strings.Split(raw, ",") {
+		k, v, ok := strings.Cut(term, "=")
+		if !ok || k == "" {
+			return nil, badRequest("malformed labelSelector term %q, want key=value", term)
+		}
+		labels[k] = v
+	}
+	return &api.LabelSelector{MatchLabels: labels}, nil
+}
+
+// validateSchema checks a decoded JSON value against the schema
+// subset; path names the offending field in errors.
+func validateSchema(schema *api.JSONSchema, value any, path string) error {
+	if schema == nil {
+		return nil
+	}
+	if value == nil {
+		if schema.Type != "" || len(schema.Required) > 0 {
+			return fmt.Errorf("%s: required but missing", path)
+		}
+		return nil
+	}
+	switch schema.Type {
+	case "", "object":
+		obj, ok := value.(map[string]any)
+		if schema.Type == "object" && !ok {
+			return fmt.Errorf("%s: expected object", path)
+		}
+		if !ok {
+			break
+		}
+		for _, req := range schema.Required {
+			if _, present := obj[req]; !present {
+				return fmt.Errorf("%s.%s: required but missing", path, req)
+			}
+		}
+		for name, sub := range schema.Properties {
+			if v, present := obj[name]; present {
+				if err := validateSchema(sub, v, path+"."+name); err != nil {
+					return err
+				}
+			}
+		}
+	case "array":
+		arr, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("%s: expected array", path)
+		}
+		for i, item := range arr {
+			if err := validateSchema(schema.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%s: expected string", path)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("%s: expected number", path)
+		}
+	case "integer":
+		f, ok := value.(float64)
+		if !ok || f != math.Trunc(f) {
+			return fmt.Errorf("%s: expected integer", path)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: expected boolean", path)
+		}
+	default:
+		return fmt.Errorf("%s: unknown schema type %q", path, schema.Type)
+	}
+	if len(schema.Enum) > 0 {
+		for _, allowed := range schema.Enum {
+			if reflect.DeepEqual(value, allowed) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: %v is not one of the allowed values", path, value)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ptfpinho23/synthesis/pkg/logging"
+)
+
+// handleGetLogLevels reports the effective level per component; the
+// empty component is the default.
+func (s *Server) handleGetLogLevels(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, logging.Levels())
+}
+
+// handleSetLogLevel adjusts logging at runtime:
+// PUT /debug/loglevel {"component": "scheduler", "level": "debug"}.
+// An empty component changes the default; an empty level clears a
+// component's override.
+func (s *Server) handleSetLogLevel(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Component string `json:"component"`
+		Level     string `json:"level"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, badRequest("decoding request: %v", err))
+		return
+	}
+	if req.Level == "" {
+		if req.Component == "" {
+			writeError(w, badRequest("level is required"))
+			return
+		}
+		logging.ClearLevel(req.Component)
+		writeJSON(w, http.StatusOK, logging.Levels())
+		return
+	}
+	level, err := logging.ParseLevel(req.Level)
+	if err != nil {
+		writeError(w, badRequest("%v", err))
+		return
+	}
+	if req.Component == "" {
+		logging.SetDefaultLevel(level)
+	} else {
+		logging.SetLevel(req.Component, level)
+	}
+	writeJSON(w, http.StatusOK, logging.Levels())
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/scheduler"
+)
+
+// ScheduleDryRun answers "would this pod be accepted, and where would
+// it land?" without creating anything. Admitted reports the admission
+// verdict; when the pod clears admission, Node names the winner the
+// scheduler would pick right now, and Reasons carries the per-node
+// rejections when nothing fits.
+type ScheduleDryRun struct {
+	Admitted bool     `json:"admitted"`
+	Warnings []string `json:"warnings,omitempty"`
+	Denied   string   `json:"denied,omitempty"`
+
+	Schedulable bool              `json:"schedulable"`
+	Node        string            `json:"node,omitempty"`
+	Reasons     map[string]string `json:"reasons,omitempty"`
+}
+
+// handlePodDryRun runs the create path — admission included, mutations
+// and all — against a copy that is never stored, then simulates one
+// scheduling cycle. A rejected or unschedulable pod is still a 200: the
+// question was answered, and the answer is in the body.
+func (s *Server) handlePodDryRun(w http.ResponseWriter, r *http.Request) {
+	var pod api.Pod
+	if err := json.NewDecoder(r.Body).Decode(&pod); err != nil {
+		writeError(w, badRequest("decoding pod: %v", err))
+		return
+	}
+	pod.Meta.Namespace = r.PathValue("namespace")
+	completeName(&pod.Meta)
+	if err := validateMeta("pod", &pod.Meta); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	result := ScheduleDryRun{Admitted: true}
+	warnings, err := s.admission.Admit(&pod)
+	result.Warnings = warnings
+	if err != nil {
+		result.Admitted = false
+		result.Denied = err.Error()
+		writeJSON(w, http.StatusOK, &result)
+		return
+	}
+
+	// Pinned pods bypass the scheduler entirely; the answer is the pin.
+	if pod.Spec.NodeName != "" {
+		result.Schedulable = true
+		result.Node = pod.Spec.NodeName
+		writeJSON(w, http.StatusOK, &result)
+		return
+	}
+	nodeName, err := scheduler.New(s.store).Schedule(&pod)
+	if err != nil {
+		var unsched *scheduler.UnschedulableError
+		if !errors.As(err, &unsched) {
+			writeError(w, err)
+			return
+		}
+		result.Reasons = make(map[string]string, len(unsched.Reasons))
+		for name, reason := range unsched.Reasons {
+			result.Reasons[name] = reason.Error()
+		}
+		writeJSON(w, http.StatusOK, &result)
+		return
+	}
+	result.Schedulable = true
+	result.Node = nodeName
+	writeJSON(w, http.StatusOK, &result)
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/events"
+)
+
+// eventLogSize caps the retained event history; older events age out.
+const eventLogSize = 1000
+
+// eventLog is the in-memory event history served by the events API.
+// Events are operational breadcrumbs, not state, so they live here
+// rather than in the store.
+type eventLog struct {
+	mu     sync.Mutex
+	events []api.Event
+}
+
+func (l *eventLog) record(ev api.Event) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, ev)
+	if len(l.events) > eventLogSize {
+		l.events = append(l.events[:0], l.events[len(l.events)-eventLogSize:]...)
+	}
+}
+
+func (l *eventLog) list(namespace string) []api.Event {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := make([]api.Event, 0, len(l.events))
+	for _, ev := range l.events {
+		if namespace != "" && ev.Regarding.Namespace != namespace {
+			continue
+		}
+		out = append(out, ev)
+	}
+	return out
+}
+
+// SetEventNotifier routes recorded events to external sinks as well as
+// the local history.
+func (s *Server) SetEventNotifier(n *events.Notifier) {
+	s.notifier = n
+}
+
+// SetEventBus mirrors every resource change event — the same stream the
+// watch endpoints serve — onto a message bus, so edge systems can react
+// to deployments without polling.
+func (s *Server) SetEventBus(p *events.BusPublisher) {
+	s.watches.tap = func(resource string, ev watchEvent) {
+		p.Publish(resource, ev.Type, ev.Object)
+	}
+}
+
+// RecordEvent appends an event to the history and fans it out to any
+// configured sinks. Missing Time and Type are defaulted.
+func (s *Server) RecordEvent(ev api.Event) {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	if ev.Type == "" {
+		ev.Type = api.EventNormal
+	}
+	s.events.record(ev)
+	if s.notifier != nil {
+		s.notifier.Publish(ev)
+	}
+}
+
+// handleListEvents serves GET /api/v1/events, oldest first;
+// ?namespace= narrows to one namespace.
+func (s *Server) handleListEvents(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.events.list(r.URL.Query().Get("namespace")))
+}
+
+// handlePostEvent lets agents and external controllers record events
+// through the API.
+func (s *Server) handlePostEvent(w http.ResponseWriter, r *http.Request) {
+	var ev api.Event
+	if err := json.NewDecoder(r.Body).Decode(&ev); err != nil {
+		writeError(w, badRequest("decoding event: %v", err))
+		return
+	}
+	if ev.Reason == "" || ev.Regarding.Kind == "" || ev.Regarding.Name == "" {
+		writeError(w, badRequest("event needs reason and regarding kind and name"))
+		return
+	}
+	// Event times are the server's to set; a timestamp from an agent on
+	// a drifting clock would misorder the history.
+	ev.Time = time.Now()
+	s.RecordEvent(ev)
+	writeJSON(w, http.StatusCreated, &ev)
+}
@@ -0,0 +1,212 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+func (s *Server) handleListGateways(w http.ResponseWriter, r *http.Request) {
+	gws, err := s.store.ListGateways()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, gws)
+}
+
+func (s *Server) handleGetGateway(w http.ResponseWriter, r *http.Request) {
+	gw, err := s.store.GetGateway(r.PathValue("namespace"), r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, gw)
+}
+
+func (s *Server) handlePutGateway(w http.ResponseWriter, r *http.Request) {
+	var gw api.Gateway
+	if err := json.NewDecoder(r.Body).Decode(&gw); err != nil {
+		writeError(w, badRequest("decoding gateway: %v", err))
+		return
+	}
+	gw.Meta.Namespace = r.PathValue("namespace")
+	gw.Meta.Name = r.PathValue("name")
+	if err := validateMeta("gateway", &gw.Meta); err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := validateGatewaySpec(&gw.Spec); err != nil {
+		writeError(w, err)
+		return
+	}
+	var prior time.Time
+	if old, err := s.store.GetGateway(gw.Meta.Namespace, gw.Meta.Name); err == nil {
+		prior = old.Meta.CreatedAt
+	}
+	stampCreated(&gw.Meta, prior)
+	if err := s.store.PutGateway(&gw); err != nil {
+		writeError(w, err)
+		return
+	}
+	s.watches.notify("gateways", watchEvent{Type: "MODIFIED", Object: &gw})
+	writeJSON(w, http.StatusOK, &gw)
+}
+
+func (s *Server) handleDeleteGateway(w http.ResponseWriter, r *http.Request) {
+	namespace, name := r.PathValue("namespace"), r.PathValue("name")
+	gw, err := s.store.GetGateway(namespace, name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := s.store.DeleteGateway(namespace, name); err != nil {
+		writeError(w, err)
+		return
+	}
+	s.watches.notify("gateways", watchEvent{Type: "DELETED", Object: gw})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func validateGatewaySpec(spec *api.GatewaySpec) error {
+	if len(spec.Listeners) == 0 {
+		return badRequest("gateway needs at least one listener")
+	}
+	names := make(map[string]bool, len(spec.Listeners))
+	for _, l := range spec.Listeners {
+		if l.Name == "" {
+			return badRequest("listeners must be named")
+		}
+		if names[l.Name] {
+			return badRequest("duplicate listener name %q", l.Name)
+		}
+		names[l.Name] = true
+		if l.Port < 1 || l.Port > 65535 {
+			return badRequest("listener %q: port %d is out of range", l.Name, l.Port)
+		}
+		switch l.Protocol {
+		case "HTTP":
+			if l.TLS != nil {
+				return badRequest("listener %q: HTTP listeners take no TLS config", l.Name)
+			}
+		case "HTTPS":
+			if l.TLS == nil || l.TLS.SecretName == "" {
+				return badRequest("listener %q: HTTPS listeners must name a TLS secret", l.Name)
+			}
+		default:
+			return badRequest("listener %q: unknown protocol %q", l.Name, l.Protocol)
+		}
+	}
+	return nil
+}
+
+func (s *Server) handleListHTTPRoutes(w http.ResponseWriter, r *http.Request) {
+	routes, err := s.store.ListHTTPRoutes()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, routes)
+}
+
+func (s *Server) handleGetHTTPRoute(w http.ResponseWriter, r *http.Request) {
+	route, err := s.store.GetHTTPRoute(r.PathValue("namespace"), r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, route)
+}
+
+func (s *Server) handlePutHTTPRoute(w http.ResponseWriter, r *http.Request) {
+	var route api.HTTPRoute
+	if err := json.NewDecoder(r.Body).Decode(&route); err != nil {
+		writeError(w, badRequest("decoding httproute: %v", err))
+		return
+	}
+	route.Meta.Namespace = r.PathValue("namespace")
+	route.Meta.Name = r.PathValue("name")
+	if err := validateMeta("httproute", &route.Meta); err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := validateHTTPRouteSpec(&route.Spec); err != nil {
+		writeError(w, err)
+		return
+	}
+	var prior time.Time
+	if old, err := s.store.GetHTTPRoute(route.Meta.Namespace, route.Meta.Name); err == nil {
+		prior = old.Meta.CreatedAt
+	}
+	stampCreated(&route.Meta, prior)
+	if err := s.store.PutHTTPRoute(&route); err != nil {
+		writeError(w, err)
+		return
+	}
+	s.watches.notify("httproutes", watchEvent{Type: "MODIFIED", Object: &route})
+	writeJSON(w, http.StatusOK, &route)
+}
+
+func (s *Server) handleDeleteHTTPRoute(w http.ResponseWriter, r *http.Request) {
+	namespace, name := r.PathValue("namespace"), r.PathValue("name")
+	route, err := s.store.GetHTTPRoute(namespace, name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := s.store.DeleteHTTPRoute(namespace, name); err != nil {
+		writeError(w, err)
+		return
+	}
+	s.watches.notify("httproutes", watchEvent{Type: "DELETED", Object: route})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func validateHTTPRouteSpec(spec *api.HTTPRouteSpec) error {
+	if len(spec.ParentRefs) == 0 {
+		return badRequest("httproute must reference at least one gateway")
+	}
+	if len(spec.Rules) == 0 {
+		return badRequest("httproute needs at least one rule")
+	}
+	for _, rule := range spec.Rules {
+		if len(rule.BackendRefs) == 0 {
+			return badRequest("rules need at least one backendRef")
+		}
+		for _, ref := range rule.BackendRefs {
+			if ref.Service == "" {
+				return badRequest("backendRefs must name a service")
+			}
+			if ref.Weight < 0 {
+				return badRequest("backend %q: weight must not be negative", ref.Service)
+			}
+			if ref.Port != "" {
+				if n, err := strconv.Atoi(ref.Port); err == nil && (n < 1 || n > 65535) {
+					return badRequest("backend %q: port %d is out of range", ref.Service, n)
+				}
+			}
+		}
+		for _, m := range rule.Matches {
+			if m.Path != nil {
+				switch m.Path.Type {
+				case "", api.PathMatchPrefix, api.PathMatchExact:
+				default:
+					return badRequest("unknown path match type %q", m.Path.Type)
+				}
+				if m.Path.Value != "" && !strings.HasPrefix(m.Path.Value, "/") {
+					return badRequest("path %q must start with /", m.Path.Value)
+				}
+			}
+			for _, h := range m.Headers {
+				if h.Name == "" {
+					return badRequest("header matches must name a header")
+				}
+			}
+		}
+	}
+	return nil
+}
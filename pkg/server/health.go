@@ -0,0 +1,125 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// healthChecks is the registry behind /healthz. Checks return nil when
+// healthy; anything else surfaces in the probe output.
+type healthChecks struct {
+	mu     sync.RWMutex
+	checks map[string]func() error
+}
+
+// AddHealthCheck registers a named component probe reported under
+// /healthz. Registering an existing name replaces the check.
+func (s *Server) AddHealthCheck(name string, check func() error) {
+	s.health.mu.Lock()
+	defer s.health.mu.Unlock()
+	s.health.checks[name] = check
+}
+
+// run evaluates every check, returning results sorted by name.
+func (h *healthChecks) run() (names []string, failures map[string]error) {
+	h.mu.RLock()
+	checks := make(map[string]func() error, len(h.checks))
+	for name, check := range h.checks {
+		checks[name] = check
+	}
+	h.mu.RUnlock()
+	failures = make(map[string]error)
+	for name, check := range checks {
+		names = append(names, name)
+		if err := check(); err != nil {
+			failures[name] = err
+		}
+	}
+	sort.Strings(names)
+	return names, failures
+}
+
+// handleHealthz answers load balancer probes. The plain form returns
+// "ok" or the failing check names; ?verbose lists every check.
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	names, failures := s.health.run()
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	verbose := r.URL.Query().Has("verbose")
+	status := http.StatusOK
+	if len(failures) > 0 {
+		status = http.StatusInternalServerError
+	}
+	w.WriteHeader(status)
+	if !verbose {
+		if len(failures) == 0 {
+			fmt.Fprintln(w, "ok")
+			return
+		}
+		for _, name := range names {
+			if err, ok := failures[name]; ok {
+				fmt.Fprintf(w, "%s: %v\n", name, err)
+			}
+		}
+		return
+	}
+	for _, name := range names {
+		if err, ok := failures[name]; ok {
+			fmt.Fprintf(w, "[-]%s: %v\n", name, err)
+		} else {
+			fmt.Fprintf(w, "[+]%s ok\n", name)
+		}
+	}
+}
+
+// handleHealthzCheck probes a single component: /healthz/storage.
+func (s *Server) handleHealthzCheck(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("check")
+	s.health.mu.RLock()
+	check, ok := s.health.checks[name]
+	s.health.mu.RUnlock()
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, "unknown check %q\n", name)
+		return
+	}
+	if err := check(); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "%s: %v\n", name, err)
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
+
+// builtinHealthChecks covers what the server can see itself: the store
+// answering and node heartbeats being fresh.
+func (s *Server) builtinHealthChecks() {
+	s.AddHealthCheck("storage", func() error {
+		_, err := s.store.ListNodes()
+		return err
+	})
+	s.AddHealthCheck("heartbeats", func() error {
+		nodes, err := s.store.ListNodes()
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		stale := 0
+		for _, node := range nodes {
+			lease, err := s.store.GetLease("", node.Meta.Name)
+			if err != nil {
+				continue // nodes without leases are treated as alive
+			}
+			if lease.Expired(now) {
+				stale++
+			}
+		}
+		if stale > 0 {
+			return fmt.Errorf("%d of %d nodes have stale heartbeats", stale, len(nodes))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// pullQueueSize bounds the pre-pull queue; older requests fall off the
+// front. The queue is a warm-cache hint, not durable state — a server
+// restart drops it and rollouts still pull on demand.
+const pullQueueSize = 100
+
+// pullQueue holds pending image pre-pull requests for agents to poll.
+type pullQueue struct {
+	mu   sync.Mutex
+	next int64
+	reqs []api.ImagePullRequest
+}
+
+func (q *pullQueue) add(req api.ImagePullRequest) api.ImagePullRequest {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.next++
+	req.ID = q.next
+	q.reqs = append(q.reqs, req)
+	if len(q.reqs) > pullQueueSize {
+		q.reqs = q.reqs[len(q.reqs)-pullQueueSize:]
+	}
+	return req
+}
+
+// pending lists what the named node still has to pull: every request
+// after the cursor that names it (or names no nodes at all).
+func (q *pullQueue) pending(node string, after int64) []api.ImagePullRequest {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var out []api.ImagePullRequest
+	for _, req := range q.reqs {
+		if req.ID <= after {
+			continue
+		}
+		if len(req.Nodes) > 0 && !slices.Contains(req.Nodes, node) {
+			continue
+		}
+		out = append(out, req)
+	}
+	return out
+}
+
+// handlePullImage queues an asynchronous image pre-pull on the selected
+// nodes. The 202 only means "queued"; completion shows up as ImagePulled
+// (or ImagePullFailed) events from each node.
+func (s *Server) handlePullImage(w http.ResponseWriter, r *http.Request) {
+	var req api.ImagePullRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, badRequest("decoding pull request: %v", err))
+		return
+	}
+	if req.Image == "" {
+		writeError(w, badRequest("pull request needs an image"))
+		return
+	}
+	req = s.imagePulls.add(req)
+	s.RecordEvent(api.Event{
+		Reason:    "ImagePullRequested",
+		Message:   "queued pre-pull of " + req.Image,
+		Regarding: api.ObjectRef{Kind: "Image", Name: req.Image},
+	})
+	writeJSON(w, http.StatusAccepted, req)
+}
+
+// handleListImagePulls serves the queue to agents, filtered to the
+// caller's node and the requests after its cursor.
+func (s *Server) handleListImagePulls(w http.ResponseWriter, r *http.Request) {
+	after := int64(0)
+	if v := r.URL.Query().Get("after"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			writeError(w, badRequest("invalid after %q", v))
+			return
+		}
+		after = n
+	}
+	writeJSON(w, http.StatusOK, s.imagePulls.pending(r.URL.Query().Get("node"), after))
+}
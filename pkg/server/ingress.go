@@ -0,0 +1,152 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+func (s *Server) handleListIngresses(w http.ResponseWriter, r *http.Request) {
+	ings, err := s.store.ListIngresses()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, ings)
+}
+
+func (s *Server) handleGetIngress(w http.ResponseWriter, r *http.Request) {
+	ing, err := s.store.GetIngress(r.PathValue("namespace"), r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, ing)
+}
+
+func (s *Server) handlePutIngress(w http.ResponseWriter, r *http.Request) {
+	var ing api.Ingress
+	if err := json.NewDecoder(r.Body).Decode(&ing); err != nil {
+		writeError(w, badRequest("decoding ingress: %v", err))
+		return
+	}
+	ing.Meta.Namespace = r.PathValue("namespace")
+	ing.Meta.Name = r.PathValue("name")
+	if err := validateMeta("ingress", &ing.Meta); err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := validateIngressSpec(&ing.Spec); err != nil {
+		writeError(w, err)
+		return
+	}
+	var prior time.Time
+	if old, err := s.store.GetIngress(ing.Meta.Namespace, ing.Meta.Name); err == nil {
+		prior = old.Meta.CreatedAt
+	}
+	stampCreated(&ing.Meta, prior)
+	if err := s.store.PutIngress(&ing); err != nil {
+		writeError(w, err)
+		return
+	}
+	s.watches.notify("ingresses", watchEvent{Type: "MODIFIED", Object: &ing})
+	writeJSON(w, http.StatusOK, &ing)
+}
+
+func (s *Server) handleDeleteIngress(w http.ResponseWriter, r *http.Request) {
+	namespace, name := r.PathValue("namespace"), r.PathValue("name")
+	ing, err := s.store.GetIngress(namespace, name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := s.store.DeleteIngress(namespace, name); err != nil {
+		writeError(w, err)
+		return
+	}
+	s.watches.notify("ingresses", watchEvent{Type: "DELETED", Object: ing})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func validateIngressSpec(spec *api.IngressSpec) error {
+	if len(spec.Rules) == 0 {
+		return badRequest("ingress needs at least one rule")
+	}
+	hosts := make(map[string]bool, len(spec.Rules))
+	for _, rule := range spec.Rules {
+		if hosts[rule.Host] {
+			if rule.Host == "" {
+				return badRequest("only one rule may leave the host empty")
+			}
+			return badRequest("duplicate rule host %q", rule.Host)
+		}
+		hosts[rule.Host] = true
+		if len(rule.Paths) == 0 {
+			return badRequest("rule %q needs at least one path", rule.Host)
+		}
+		for _, p := range rule.Paths {
+			if p.Service == "" {
+				return badRequest("path %q names no service", p.Path)
+			}
+			if p.Path != "" && !strings.HasPrefix(p.Path, "/") {
+				return badRequest("path %q must start with /", p.Path)
+			}
+			if p.Port != "" {
+				if n, err := strconv.Atoi(p.Port); err == nil && (n < 1 || n > 65535) {
+					return badRequest("port %d is out of range", n)
+				}
+			}
+		}
+	}
+	for _, t := range spec.TLS {
+		if t.SecretName == "" {
+			return badRequest("tls entries must name a secret")
+		}
+		if len(t.Hosts) == 0 {
+			return badRequest("tls entry %q lists no hosts", t.SecretName)
+		}
+	}
+	return nil
+}
+
+// PublishACMEChallenge makes an HTTP-01 key authorization available to
+// the ingress proxies until removed.
+func (s *Server) PublishACMEChallenge(token, keyAuth string) {
+	s.acmeMu.Lock()
+	defer s.acmeMu.Unlock()
+	if s.acmeChallenges == nil {
+		s.acmeChallenges = make(map[string]string)
+	}
+	s.acmeChallenges[token] = keyAuth
+}
+
+// RemoveACMEChallenge retires a published key authorization.
+func (s *Server) RemoveACMEChallenge(token string) {
+	s.acmeMu.Lock()
+	defer s.acmeMu.Unlock()
+	delete(s.acmeChallenges, token)
+}
+
+// handleACMEChallenge serves published HTTP-01 key authorizations. The
+// ingress proxies forward /.well-known/acme-challenge/ requests here,
+// so a validation can land on any node while only the leader's
+// controller talks to the certificate authority.
+func (s *Server) handleACMEChallenge(w http.ResponseWriter, r *http.Request) {
+	s.acmeMu.Lock()
+	keyAuth, ok := s.acmeChallenges[r.PathValue("token")]
+	s.acmeMu.Unlock()
+	if !ok {
+		writeError(w, &apiError{
+			status:  http.StatusNotFound,
+			message: "no pending challenge for this token",
+			reason:  "NotFound",
+		})
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Write([]byte(keyAuth))
+}
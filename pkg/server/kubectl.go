@@ -0,0 +1,276 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// This file is the kubectl compatibility surface: the discovery
+// endpoints kubectl probes on startup, namespaced list routes, and
+// Table-shaped list responses when the Accept header asks for them
+// (as=Table), so `kubectl get pods` renders against Synthesis.
+
+// handleAPIVersions serves GET /api.
+func (s *Server) handleAPIVersions(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"kind":     "APIVersions",
+		"versions": []string{"v1"},
+	})
+}
+
+// handleAPIGroups serves GET /apis, listing the registered custom
+// resource groups.
+func (s *Server) handleAPIGroups(w http.ResponseWriter, r *http.Request) {
+	crds, err := s.store.ListCRDs()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	seen := map[string]bool{}
+	groups := []map[string]any{}
+	for _, crd := range crds {
+		if seen[crd.Spec.Group] {
+			continue
+		}
+		seen[crd.Spec.Group] = true
+		version := map[string]string{
+			"groupVersion": crd.Spec.Group + "/" + crd.Spec.Version,
+			"version":      crd.Spec.Version,
+		}
+		groups = append(groups, map[string]any{
+			"name":             crd.Spec.Group,
+			"versions":         []map[string]string{version},
+			"preferredVersion": version,
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"kind": "APIGroupList", "groups": groups})
+}
+
+// handleAPIV1Resources serves GET /api/v1. The short names drive alias
+// resolution in clients, so a new resource type only needs an entry
+// here to become addressable everywhere.
+func (s *Server) handleAPIV1Resources(w http.ResponseWriter, r *http.Request) {
+	resource := func(name, kind string, namespaced bool, shortNames ...string) map[string]any {
+		return map[string]any{
+			"name":       name,
+			"kind":       kind,
+			"namespaced": namespaced,
+			"shortNames": shortNames,
+			"verbs":      []string{"get", "list", "create", "update", "delete"},
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"kind":         "APIResourceList",
+		"groupVersion": "v1",
+		"resources": []map[string]any{
+			resource("pods", "Pod", true, "po"),
+			resource("nodes", "Node", false, "no"),
+			resource("namespaces", "Namespace", false, "ns"),
+			resource("secrets", "Secret", true, "sec"),
+			resource("services", "Service", true, "svc"),
+			resource("ingresses", "Ingress", true, "ing"),
+			resource("gateways", "Gateway", true, "gw"),
+			resource("httproutes", "HTTPRoute", true),
+			resource("leases", "Lease", true),
+			resource("serviceaccounts", "ServiceAccount", true, "sa"),
+			resource("workloads", "Workload", true, "wl"),
+			resource("roles", "Role", true),
+			resource("rolebindings", "RoleBinding", true),
+			resource("priorityclasses", "PriorityClass", false, "pc"),
+			resource("customresourcedefinitions", "CustomResourceDefinition", false, "crd"),
+		},
+	})
+}
+
+// handleGroupResources serves GET /apis/{group}/{version} for custom
+// resource discovery.
+func (s *Server) handleGroupResources(w http.ResponseWriter, r *http.Request) {
+	group, version := r.PathValue("group"), r.PathValue("version")
+	crds, err := s.store.ListCRDs()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	resources := []map[string]any{}
+	for _, crd := range crds {
+		if crd.Spec.Group != group || crd.Spec.Version != version {
+			continue
+		}
+		resources = append(resources, map[string]any{
+			"name":       crd.Spec.Plural,
+			"kind":       crd.Spec.Kind,
+			"namespaced": crd.Spec.Namespaced,
+			"verbs":      []string{"get", "list", "update", "delete", "watch"},
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"kind":         "APIResourceList",
+		"groupVersion": group + "/" + version,
+		"resources":    resources,
+	})
+}
+
+// handleVersion serves GET /version, which kubectl logs on mismatch
+// but otherwise tolerates.
+func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"major":      "1",
+		"minor":      "22",
+		"gitVersion": "v1.22.0-synthesis",
+	})
+}
+
+// handleListNamespacedPods serves the per-namespace list route kubectl
+// uses for `get pods`.
+func (s *Server) handleListNamespacedPods(w http.ResponseWriter, r *http.Request) {
+	pods, err := s.store.ListPods()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	namespace := r.PathValue("namespace")
+	out := make([]*api.Pod, 0, len(pods))
+	for _, pod := range pods {
+		ns := pod.Meta.Namespace
+		if ns == "" {
+			ns = "default"
+		}
+		if ns == namespace {
+			out = append(out, pod)
+		}
+	}
+	if wantsTable(r) {
+		writeJSON(w, http.StatusOK, podTable(out))
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// watchPods streams the current pods as ADDED events and then every
+// pod mutation made through the API, one JSON event per line.
+func (s *Server) watchPods(w http.ResponseWriter, r *http.Request, namespace string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, badRequest("watch requires a streaming connection"))
+		return
+	}
+	ch := s.watches.subscribe("pods")
+	defer s.watches.unsubscribe("pods", ch)
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	pods, err := s.store.ListPods()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	send := func(ev watchEvent) bool {
+		pod := ev.Object.(*api.Pod)
+		if namespace != "" && pod.Meta.Namespace != namespace {
+			return true
+		}
+		if err := enc.Encode(ev); err != nil {
+			return false
+		}
+		flusher.Flush()
+		return true
+	}
+	for _, pod := range pods {
+		if !send(watchEvent{Type: "ADDED", Object: pod}) {
+			return
+		}
+	}
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev := <-ch:
+			if !send(ev) {
+				return
+			}
+		}
+	}
+}
+
+// wantsTable reports whether the client asked for the meta.k8s.io
+// Table transform.
+func wantsTable(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "as=Table")
+}
+
+func table(columns []string, rows []map[string]any) map[string]any {
+	defs := make([]map[string]string, len(columns))
+	for i, col := range columns {
+		defs[i] = map[string]string{"name": col, "type": "string"}
+	}
+	return map[string]any{
+		"kind":              "Table",
+		"apiVersion":        "meta.k8s.io/v1",
+		"columnDefinitions": defs,
+		"rows":              rows,
+	}
+}
+
+func podTable(pods []*api.Pod) map[string]any {
+	rows := make([]map[string]any, 0, len(pods))
+	for _, pod := range pods {
+		phase := string(pod.Status.Phase)
+		if phase == "" {
+			phase = string(api.PodPending)
+		}
+		rows = append(rows, map[string]any{
+			"cells": []any{pod.Meta.Name, phase, pod.Status.Restarts(),
+				pod.Status.NodeName, age(pod.Meta.CreatedAt)},
+			"object": pod,
+		})
+	}
+	return table([]string{"Name", "Status", "Restarts", "Node", "Age"}, rows)
+}
+
+func nodeTable(nodes []*api.Node) map[string]any {
+	rows := make([]map[string]any, 0, len(nodes))
+	for _, node := range nodes {
+		status := "NotReady"
+		if cond := node.Status.Condition(api.NodeReady); cond != nil && cond.Status {
+			status = "Ready"
+		}
+		rows = append(rows, map[string]any{
+			"cells":  []any{node.Meta.Name, status, node.Status.Address, age(node.Meta.CreatedAt)},
+			"object": node,
+		})
+	}
+	return table([]string{"Name", "Status", "Address", "Age"}, rows)
+}
+
+// age renders how long ago t was in its two largest units, kubectl-style
+// (5d3h), rather than a raw truncated duration.
+func age(t time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	d := time.Since(t)
+	if d < 0 {
+		d = 0
+	}
+	days, hours := int(d.Hours())/24, int(d.Hours())%24
+	minutes, seconds := int(d.Minutes())%60, int(d.Seconds())%60
+	switch {
+	case days > 0 && hours > 0:
+		return fmt.Sprintf("%dd%dh", days, hours)
+	case days > 0:
+		return fmt.Sprintf("%dd", days)
+	case hours > 0 && minutes > 0:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh", hours)
+	case minutes > 0:
+		return fmt.Sprintf("%dm", minutes)
+	default:
+		return fmt.Sprintf("%ds", seconds)
+	}
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+func (s *Server) handleListLeases(w http.ResponseWriter, r *http.Request) {
+	leases, err := s.store.ListLeases()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, leases)
+}
+
+func (s *Server) handleGetLease(w http.ResponseWriter, r *http.Request) {
+	lease, err := s.store.GetLease(r.PathValue("namespace"), r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, lease)
+}
+
+// handlePutLease creates or renews a lease. RenewTime is stamped with
+// the server clock so skew on agent hosts cannot fake liveness, and a
+// lease still held by someone else cannot be taken — which is what
+// makes leases safe for leader election.
+func (s *Server) handlePutLease(w http.ResponseWriter, r *http.Request) {
+	var lease api.Lease
+	if err := json.NewDecoder(r.Body).Decode(&lease); err != nil {
+		writeError(w, badRequest("decoding lease: %v", err))
+		return
+	}
+	lease.Meta.Namespace = r.PathValue("namespace")
+	lease.Meta.Name = r.PathValue("name")
+	if err := validateMeta("lease", &lease.Meta); err != nil {
+		writeError(w, err)
+		return
+	}
+	if lease.Spec.DurationSeconds <= 0 {
+		writeError(w, badRequest("lease durationSeconds must be positive"))
+		return
+	}
+	now := time.Now()
+	lease.Spec.RenewTime = now
+	lease.Spec.AcquireTime = now
+	if existing, err := s.store.GetLease(lease.Meta.Namespace, lease.Meta.Name); err == nil {
+		switch {
+		case existing.Spec.HolderIdentity == lease.Spec.HolderIdentity:
+			// A renewal keeps the original acquisition record.
+			lease.Spec.AcquireTime = existing.Spec.AcquireTime
+			lease.Spec.Transitions = existing.Spec.Transitions
+		case !existing.Expired(now):
+			writeError(w, conflict("lease is held by %q", existing.Spec.HolderIdentity))
+			return
+		default:
+			lease.Spec.Transitions = existing.Spec.Transitions + 1
+		}
+	}
+	if err := s.store.PutLease(&lease); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &lease)
+}
+
+// handleDeleteLease releases a lease early, letting the next candidate
+// acquire it without waiting out the duration.
+func (s *Server) handleDeleteLease(w http.ResponseWriter, r *http.Request) {
+	if err := s.store.DeleteLease(r.PathValue("namespace"), r.PathValue("name")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
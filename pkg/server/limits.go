@@ -0,0 +1,122 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Limits caps what any one client, and the server as a whole, may do.
+// Zero values disable the corresponding limit.
+type Limits struct {
+	// PerClientQPS is the sustained request rate allowed per client IP,
+	// with PerClientBurst extra requests absorbed in spikes.
+	PerClientQPS   float64
+	PerClientBurst int
+
+	// MaxBodyBytes rejects request bodies larger than this.
+	MaxBodyBytes int64
+
+	// MaxInFlightReads / MaxInFlightWrites bound concurrent read-only
+	// and mutating requests separately, so a flood of reads cannot
+	// starve the writes reconcilers depend on (or vice versa).
+	MaxInFlightReads  int
+	MaxInFlightWrites int
+}
+
+// SetLimits enables request limiting with the given caps.
+func (s *Server) SetLimits(l Limits) {
+	s.limits = &limiter{
+		conf:    l,
+		buckets: make(map[string]*bucket),
+	}
+	if l.MaxInFlightReads > 0 {
+		s.limits.reads = make(chan struct{}, l.MaxInFlightReads)
+	}
+	if l.MaxInFlightWrites > 0 {
+		s.limits.writes = make(chan struct{}, l.MaxInFlightWrites)
+	}
+}
+
+// limiter enforces Limits; one per server.
+type limiter struct {
+	conf Limits
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+
+	reads  chan struct{}
+	writes chan struct{}
+}
+
+// bucket is a token bucket refilled lazily on use.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// admit applies all limits; when it returns false it has already
+// written the rejection. done must be called when the request finishes.
+func (l *limiter) admit(w http.ResponseWriter, r *http.Request) (done func(), ok bool) {
+	if l.conf.MaxBodyBytes > 0 && r.Body != nil {
+		r.Body = http.MaxBytesReader(w, r.Body, l.conf.MaxBodyBytes)
+	}
+	if l.conf.PerClientQPS > 0 && !l.allow(clientIP(r)) {
+		writeJSON(w, http.StatusTooManyRequests, map[string]string{"error": "client rate limit exceeded"})
+		return nil, false
+	}
+
+	sem := l.reads
+	if mutating(r.Method) {
+		sem = l.writes
+	}
+	if sem == nil {
+		return func() {}, true
+	}
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, true
+	default:
+		writeJSON(w, http.StatusTooManyRequests, map[string]string{"error": "too many requests in flight"})
+		return nil, false
+	}
+}
+
+// allow takes one token from the client's bucket.
+func (l *limiter) allow(client string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.buckets[client]
+	now := time.Now()
+	if !ok {
+		b = &bucket{tokens: float64(l.conf.PerClientBurst), last: now}
+		l.buckets[client] = b
+	}
+	b.tokens += now.Sub(b.last).Seconds() * l.conf.PerClientQPS
+	if max := float64(l.conf.PerClientBurst); b.tokens > max {
+		b.tokens = max
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func mutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
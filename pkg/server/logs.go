@@ -0,0 +1,77 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// handlePodLog proxies a pod log request to the agent on the pod's
+// node, streaming the response through. ?container= picks a container
+// (default: the spec's first); ?follow=true and ?tail=N pass through.
+func (s *Server) handlePodLog(w http.ResponseWriter, r *http.Request) {
+	namespace, name := r.PathValue("namespace"), r.PathValue("name")
+	pod, err := s.store.GetPod(namespace, name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if pod.Status.NodeName == "" {
+		writeError(w, badRequest("pod %q is not scheduled yet", name))
+		return
+	}
+	node, err := s.store.GetNode(pod.Status.NodeName)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if node.Status.Address == "" {
+		writeError(w, badRequest("node %q has not reported an address", node.Meta.Name))
+		return
+	}
+	container := r.URL.Query().Get("container")
+	if container == "" {
+		if len(pod.Spec.Containers) == 0 {
+			writeError(w, badRequest("pod %q has no containers", name))
+			return
+		}
+		container = pod.Spec.Containers[0].Name
+	}
+	target := fmt.Sprintf("http://%s/logs/%s/%s/%s?%s",
+		net.JoinHostPort(node.Status.Address, strconv.Itoa(api.AgentLogPort)),
+		url.PathEscape(namespace), url.PathEscape(name), url.PathEscape(container),
+		r.URL.RawQuery)
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, target, nil)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		writeError(w, fmt.Errorf("reaching agent on %s: %w", pod.Status.NodeName, err))
+		return
+	}
+	defer resp.Body.Close()
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(resp.StatusCode)
+	flusher, _ := w.(http.Flusher)
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := resp.Body.Read(buf)
+		if n > 0 {
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
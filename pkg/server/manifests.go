@@ -0,0 +1,168 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/logging"
+	"github.com/ptfpinho23/synthesis/pkg/manifest"
+	"github.com/ptfpinho23/synthesis/pkg/yaml"
+)
+
+// manifestsSyncInterval is how often the manifests directory is
+// rescanned for new or changed files.
+const manifestsSyncInterval = 15 * time.Second
+
+// RunManifestsDir applies every manifest file under dir and keeps
+// re-applying files as they change, so base workloads can ship with the
+// node image as a directory of YAML (like k3s's auto-deploying
+// manifests folder). Files go through the server's own handlers, so
+// validation, admission and secret encryption all apply. The directory
+// is polled — the standard library has no file watcher — and removing a
+// file does not delete the objects it created.
+func (s *Server) RunManifestsDir(ctx context.Context, dir string) {
+	logger := logging.Component("manifests")
+	applied := make(map[string]time.Time)
+	s.applyManifestsDir(dir, applied, logger)
+	ticker := time.NewTicker(manifestsSyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		s.applyManifestsDir(dir, applied, logger)
+	}
+}
+
+// applyManifestsDir runs one pass: every .yaml/.yml/.json file that is
+// new or has a changed modtime since the last pass is re-applied.
+// Failures are logged and retried next pass, so a manifest referring to
+// a namespace created by a later file converges on its own.
+func (s *Server) applyManifestsDir(dir string, applied map[string]time.Time, logger *slog.Logger) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml", ".json":
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Warn("scanning manifests directory failed", "dir", dir, "err", err)
+		return
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			logger.Warn("reading manifest failed", "file", path, "err", err)
+			continue
+		}
+		if applied[path].Equal(info.ModTime()) {
+			continue
+		}
+		if err := s.applyManifestFile(path); err != nil {
+			logger.Warn("applying manifest failed", "file", path, "err", err)
+			continue
+		}
+		applied[path] = info.ModTime()
+		logger.Info("applied manifest", "file", path)
+	}
+}
+
+// applyManifestFile applies every document of one file in order,
+// stopping at the first failure so the file is retried as a unit.
+func (s *Server) applyManifestFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	for _, doc := range yaml.SplitDocuments(data) {
+		m, err := manifest.Decode(doc)
+		if err != nil {
+			return err
+		}
+		if !m.Supported {
+			return fmt.Errorf("unsupported kind %s (%s)", m.Kind, m.Name)
+		}
+		if err := s.applyManifest(m); err != nil {
+			return fmt.Errorf("%s: %w", m.ID(), err)
+		}
+	}
+	return nil
+}
+
+// applyManifest upserts one object through the server's own handlers,
+// matching the POST/PUT split the CLI's apply uses.
+func (s *Server) applyManifest(m *manifest.Manifest) error {
+	if m.CollectionPath == "" {
+		return s.localRequest(http.MethodPut, m.ObjectPath, m.Object)
+	}
+	if err := s.localRequest(http.MethodGet, m.ObjectPath, nil); err != nil {
+		return s.localRequest(http.MethodPost, m.CollectionPath, m.Object)
+	}
+	return s.localRequest(http.MethodPut, m.ObjectPath, m.Object)
+}
+
+// localRequest dispatches an in-process request straight to the mux,
+// skipping authorization and rate limits: the manifests directory is
+// the operator's own input, not a remote client.
+func (s *Server) localRequest(method, path string, body any) error {
+	var rdr io.Reader
+	if body != nil {
+		buf, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		rdr = bytes.NewReader(buf)
+	}
+	req, err := http.NewRequest(method, path, rdr)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := &localResponse{header: make(http.Header), status: http.StatusOK}
+	s.mux.ServeHTTP(rec, req)
+	if rec.status >= 300 {
+		var e struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(rec.body.Bytes(), &e) == nil && e.Error != "" {
+			return fmt.Errorf("%s", e.Error)
+		}
+		return fmt.Errorf("handler returned status %d", rec.status)
+	}
+	return nil
+}
+
+// localResponse captures an in-process handler's response.
+type localResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *localResponse) Header() http.Header         { return r.header }
+func (r *localResponse) Write(b []byte) (int, error) { return r.body.Write(b) }
+func (r *localResponse) WriteHeader(code int)        { r.status = code }
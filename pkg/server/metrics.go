@@ -0,0 +1,166 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/store"
+)
+
+// metricsStaleAfter is how long a pushed sample stays servable. Agents
+// push every scrape interval, so anything older means the node stopped
+// reporting and its numbers would mislead.
+const metricsStaleAfter = time.Minute
+
+// metricsCache holds the latest usage sample per node and per pod.
+// Samples are ephemeral by design: they are not written to the store
+// and every replica serves whatever its agents have pushed.
+type metricsCache struct {
+	mu    sync.RWMutex
+	nodes map[string]api.NodeMetrics
+	// pods is keyed namespace/name; podNode remembers which node pushed
+	// each entry so a node's next push can supersede its old pods.
+	pods    map[string]api.PodMetrics
+	podNode map[string]string
+
+	// window bounds the history rings below.
+	window      time.Duration
+	nodeHistory map[string][]api.NodeMetrics
+	podHistory  map[string][]api.PodMetrics
+}
+
+// podMetricsKey mirrors the store's pod keying, defaulting the
+// namespace so lookups and pushes agree.
+func podMetricsKey(namespace, name string) string {
+	if namespace == "" {
+		namespace = "default"
+	}
+	return namespace + "/" + name
+}
+
+func newMetricsCache() *metricsCache {
+	return &metricsCache{
+		nodes:       make(map[string]api.NodeMetrics),
+		pods:        make(map[string]api.PodMetrics),
+		podNode:     make(map[string]string),
+		window:      defaultMetricsWindow,
+		nodeHistory: make(map[string][]api.NodeMetrics),
+		podHistory:  make(map[string][]api.PodMetrics),
+	}
+}
+
+// record replaces everything previously pushed by the node.
+func (c *metricsCache) record(node string, stats api.NodeStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nodes[node] = stats.Node
+	for key, owner := range c.podNode {
+		if owner == node {
+			delete(c.pods, key)
+			delete(c.podNode, key)
+		}
+	}
+	for _, pm := range stats.Pods {
+		key := podMetricsKey(pm.Namespace, pm.Name)
+		c.pods[key] = pm
+		c.podNode[key] = node
+	}
+	c.appendHistory(stats)
+}
+
+func (c *metricsCache) nodeMetrics(name string) (api.NodeMetrics, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	m, ok := c.nodes[name]
+	if !ok || time.Since(m.Timestamp) > metricsStaleAfter {
+		return api.NodeMetrics{}, false
+	}
+	return m, true
+}
+
+func (c *metricsCache) listNodeMetrics() []api.NodeMetrics {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make([]api.NodeMetrics, 0, len(c.nodes))
+	for _, m := range c.nodes {
+		if time.Since(m.Timestamp) > metricsStaleAfter {
+			continue
+		}
+		out = append(out, m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func (c *metricsCache) podMetrics(namespace, name string) (api.PodMetrics, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	m, ok := c.pods[podMetricsKey(namespace, name)]
+	if !ok || time.Since(m.Timestamp) > metricsStaleAfter {
+		return api.PodMetrics{}, false
+	}
+	return m, true
+}
+
+func (c *metricsCache) listPodMetrics() []api.PodMetrics {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make([]api.PodMetrics, 0, len(c.pods))
+	for _, m := range c.pods {
+		if time.Since(m.Timestamp) > metricsStaleAfter {
+			continue
+		}
+		out = append(out, m)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Namespace != out[j].Namespace {
+			return out[i].Namespace < out[j].Namespace
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// handleNodeStats ingests an agent's usage push.
+func (s *Server) handleNodeStats(w http.ResponseWriter, r *http.Request) {
+	var stats api.NodeStats
+	if err := json.NewDecoder(r.Body).Decode(&stats); err != nil {
+		writeError(w, badRequest("decoding stats: %v", err))
+		return
+	}
+	name := r.PathValue("name")
+	stats.Node.Name = name
+	s.metrics.record(name, stats)
+	s.usage.record(s.store, name, stats)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleListNodeMetrics(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.metrics.listNodeMetrics())
+}
+
+func (s *Server) handleGetNodeMetrics(w http.ResponseWriter, r *http.Request) {
+	m, ok := s.metrics.nodeMetrics(r.PathValue("name"))
+	if !ok {
+		writeError(w, store.ErrNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, m)
+}
+
+func (s *Server) handleListPodMetrics(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.metrics.listPodMetrics())
+}
+
+func (s *Server) handleGetPodMetrics(w http.ResponseWriter, r *http.Request) {
+	m, ok := s.metrics.podMetrics(r.PathValue("namespace"), r.PathValue("name"))
+	if !ok {
+		writeError(w, store.ErrNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, m)
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// defaultMetricsWindow is how much usage history is kept per node and
+// pod. At the agents' 10s push interval that is ~90 samples each —
+// enough for averages and CLI sparklines without running a real
+// time-series database on edge hardware.
+const defaultMetricsWindow = 15 * time.Minute
+
+// SetMetricsWindow changes how much usage history the server retains.
+func (s *Server) SetMetricsWindow(window time.Duration) {
+	s.metrics.mu.Lock()
+	defer s.metrics.mu.Unlock()
+	s.metrics.window = window
+}
+
+// PodUsageHistory exposes a pod's retained usage samples, oldest
+// first, to in-process consumers like the recommendation controller.
+func (s *Server) PodUsageHistory(namespace, name string) []api.PodMetrics {
+	return s.metrics.podHistoryFor(namespace, name)
+}
+
+// appendHistory records the push into the per-object rings, dropping
+// samples that have aged out of the window. Called with the cache lock
+// held by record.
+func (c *metricsCache) appendHistory(stats api.NodeStats) {
+	cutoff := time.Now().Add(-c.window)
+	c.nodeHistory[stats.Node.Name] = appendTrimmedNode(c.nodeHistory[stats.Node.Name], stats.Node, cutoff)
+	for _, pm := range stats.Pods {
+		key := podMetricsKey(pm.Namespace, pm.Name)
+		c.podHistory[key] = appendTrimmedPod(c.podHistory[key], pm, cutoff)
+	}
+}
+
+func appendTrimmedNode(ring []api.NodeMetrics, m api.NodeMetrics, cutoff time.Time) []api.NodeMetrics {
+	ring = append(ring, m)
+	start := 0
+	for start < len(ring) && ring[start].Timestamp.Before(cutoff) {
+		start++
+	}
+	return append(ring[:0], ring[start:]...)
+}
+
+func appendTrimmedPod(ring []api.PodMetrics, m api.PodMetrics, cutoff time.Time) []api.PodMetrics {
+	ring = append(ring, m)
+	start := 0
+	for start < len(ring) && ring[start].Timestamp.Before(cutoff) {
+		start++
+	}
+	return append(ring[:0], ring[start:]...)
+}
+
+func (c *metricsCache) nodeHistoryFor(name string) []api.NodeMetrics {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	cutoff := time.Now().Add(-c.window)
+	var out []api.NodeMetrics
+	for _, m := range c.nodeHistory[name] {
+		if !m.Timestamp.Before(cutoff) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func (c *metricsCache) podHistoryFor(namespace, name string) []api.PodMetrics {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	cutoff := time.Now().Add(-c.window)
+	var out []api.PodMetrics
+	for _, m := range c.podHistory[podMetricsKey(namespace, name)] {
+		if !m.Timestamp.Before(cutoff) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// handleNodeMetricsHistory returns the retained samples oldest first;
+// an unknown or silent node yields an empty list, not a 404, since
+// history naturally starts empty.
+func (s *Server) handleNodeMetricsHistory(w http.ResponseWriter, r *http.Request) {
+	history := s.metrics.nodeHistoryFor(r.PathValue("name"))
+	if history == nil {
+		history = []api.NodeMetrics{}
+	}
+	writeJSON(w, http.StatusOK, history)
+}
+
+func (s *Server) handlePodMetricsHistory(w http.ResponseWriter, r *http.Request) {
+	history := s.metrics.podHistoryFor(r.PathValue("namespace"), r.PathValue("name"))
+	if history == nil {
+		history = []api.PodMetrics{}
+	}
+	writeJSON(w, http.StatusOK, history)
+}
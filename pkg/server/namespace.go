@@ -0,0 +1,58 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+func (s *Server) handleListNamespaces(w http.ResponseWriter, r *http.Request) {
+	nss, err := s.store.ListNamespaces()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, nss)
+}
+
+func (s *Server) handleGetNamespace(w http.ResponseWriter, r *http.Request) {
+	ns, err := s.store.GetNamespace(r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, ns)
+}
+
+func (s *Server) handlePutNamespace(w http.ResponseWriter, r *http.Request) {
+	var ns api.Namespace
+	if err := json.NewDecoder(r.Body).Decode(&ns); err != nil {
+		writeError(w, badRequest("decoding namespace: %v", err))
+		return
+	}
+	ns.Meta.Name = r.PathValue("name")
+	if err := validateMeta("namespace", &ns.Meta); err != nil {
+		writeError(w, err)
+		return
+	}
+	var prior time.Time
+	if old, err := s.store.GetNamespace(ns.Meta.Name); err == nil {
+		prior = old.Meta.CreatedAt
+	}
+	stampCreated(&ns.Meta, prior)
+	if err := s.store.PutNamespace(&ns); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &ns)
+}
+
+func (s *Server) handleDeleteNamespace(w http.ResponseWriter, r *http.Request) {
+	if err := s.store.DeleteNamespace(r.PathValue("name")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
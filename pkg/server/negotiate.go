@@ -0,0 +1,97 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ptfpinho23/synthesis/pkg/yaml"
+)
+
+// decodeYAMLBody transparently converts a YAML request body to JSON so
+// every handler keeps its one json.Decoder. Bodies are converted when
+// the Content-Type says yaml, and also when it says nothing useful but
+// the payload clearly is not JSON — clients rarely label manifests
+// precisely.
+func decodeYAMLBody(r *http.Request) error {
+	if r.Body == nil || (r.Method != http.MethodPut && r.Method != http.MethodPost) {
+		return nil
+	}
+	ct := r.Header.Get("Content-Type")
+	if strings.Contains(ct, "json") {
+		return nil
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	r.Body.Close()
+	trimmed := bytes.TrimSpace(body)
+	isYAML := strings.Contains(ct, "yaml")
+	if !isYAML && len(trimmed) > 0 && trimmed[0] != '{' && trimmed[0] != '[' {
+		isYAML = true
+	}
+	if isYAML && len(trimmed) > 0 {
+		converted, err := yaml.ToJSON(trimmed)
+		if err != nil {
+			return badRequest("decoding YAML body: %v", err)
+		}
+		body = converted
+		r.Header.Set("Content-Type", "application/json")
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+	return nil
+}
+
+// negotiate inspects the Accept header: YAML responses are re-encoded
+// through a buffering writer, a protobuf-only Accept is refused with
+// 406 (Synthesis does not speak the Kubernetes protobuf framing), and
+// anything else is served as JSON.
+func negotiate(w http.ResponseWriter, r *http.Request) (http.ResponseWriter, func(), bool) {
+	accept := r.Header.Get("Accept")
+	if strings.Contains(accept, "protobuf") &&
+		!strings.Contains(accept, "json") && !strings.Contains(accept, "yaml") &&
+		!strings.Contains(accept, "*/*") {
+		writeJSON(w, http.StatusNotAcceptable,
+			map[string]string{"error": "protobuf encoding is not supported; accept application/json or application/yaml"})
+		return nil, nil, false
+	}
+	if !strings.Contains(accept, "yaml") {
+		return w, func() {}, true
+	}
+	yw := &yamlWriter{ResponseWriter: w}
+	return yw, yw.finish, true
+}
+
+// yamlWriter buffers a JSON response and re-encodes it as YAML when
+// the handler finishes.
+type yamlWriter struct {
+	http.ResponseWriter
+	status int
+	buf    bytes.Buffer
+}
+
+func (w *yamlWriter) WriteHeader(code int) { w.status = code }
+
+func (w *yamlWriter) Write(b []byte) (int, error) { return w.buf.Write(b) }
+
+func (w *yamlWriter) finish() {
+	body := w.buf.Bytes()
+	if strings.Contains(w.Header().Get("Content-Type"), "json") && len(body) > 0 {
+		var v any
+		if json.Unmarshal(body, &v) == nil {
+			if out, err := yaml.Marshal(v); err == nil {
+				w.Header().Set("Content-Type", "application/yaml")
+				body = out
+			}
+		}
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	_, _ = w.ResponseWriter.Write(body)
+}
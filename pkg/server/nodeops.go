@@ -0,0 +1,207 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// defaultGracePeriod applies when a drain request names no grace period.
+const defaultGracePeriod = 30 * time.Second
+
+// handleCordon marks the node unschedulable. New pods stop landing on it
+// but running pods are untouched.
+func (s *Server) handleCordon(w http.ResponseWriter, r *http.Request) {
+	s.setSchedulable(w, r, false)
+}
+
+// handleUncordon makes a cordoned node schedulable again.
+func (s *Server) handleUncordon(w http.ResponseWriter, r *http.Request) {
+	s.setSchedulable(w, r, true)
+}
+
+func (s *Server) setSchedulable(w http.ResponseWriter, r *http.Request, schedulable bool) {
+	node, err := s.store.GetNode(r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	node.Spec.Unschedulable = !schedulable
+	if err := s.store.PutNode(node); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, node)
+}
+
+// DrainResult reports which pods a drain evicted and which it refused.
+type DrainResult struct {
+	Node        string   `json:"node"`
+	Evicted     []string `json:"evicted,omitempty"`
+	Refused     []string `json:"refused,omitempty"`
+	GracePeriod string   `json:"gracePeriod"`
+}
+
+// handleDrain cordons the node, then evicts its pods one by one. A pod
+// whose disruption budget would be violated is left in place and listed
+// in the response so the caller can retry once replacements are up.
+// ?gracePeriodSeconds= overrides the default termination grace period.
+func (s *Server) handleDrain(w http.ResponseWriter, r *http.Request) {
+	node, err := s.store.GetNode(r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	grace := defaultGracePeriod
+	if v := r.URL.Query().Get("gracePeriodSeconds"); v != "" {
+		secs, err := strconv.Atoi(v)
+		if err != nil || secs < 0 {
+			writeError(w, badRequest("invalid gracePeriodSeconds %q", v))
+			return
+		}
+		grace = time.Duration(secs) * time.Second
+	}
+
+	node.Spec.Unschedulable = true
+	if err := s.store.PutNode(node); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	pods, err := s.store.ListPods()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	pdbs, err := s.store.ListPodDisruptionBudgets()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	result := DrainResult{Node: node.Meta.Name, GracePeriod: grace.String()}
+	for _, pod := range pods {
+		if pod.Status.NodeName != node.Meta.Name {
+			continue
+		}
+		// Pods a previous drain already handed over keep terminating.
+		if pod.Meta.Annotations[api.EvictionGracePeriodAnnotation] != "" {
+			continue
+		}
+		name := pod.Meta.Namespace + "/" + pod.Meta.Name
+		// Each eviction stamps its pod, so the budget check naturally
+		// stops counting it and the next pod sees the reduced count.
+		if err := api.CheckDisruptionBudgets(pod, pods, pdbs); err != nil {
+			result.Refused = append(result.Refused, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		if err := s.evictPod(pod, grace); err != nil {
+			writeError(w, err)
+			return
+		}
+		result.Evicted = append(result.Evicted, name)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// evictPod hands the pod to its node for graceful teardown: the grace
+// period is recorded on the pod, the agent stops its containers within
+// that window, then the agent deletes the pod through the API. Pods
+// not bound to a node have nothing running and are deleted directly.
+func (s *Server) evictPod(pod *api.Pod, grace time.Duration) error {
+	if pod.Status.NodeName == "" {
+		return s.store.DeletePod(pod.Meta.Namespace, pod.Meta.Name)
+	}
+	if pod.Meta.Annotations == nil {
+		pod.Meta.Annotations = map[string]string{}
+	}
+	pod.Meta.Annotations[api.EvictionGracePeriodAnnotation] = grace.String()
+	if err := s.store.PutPod(pod); err != nil {
+		return err
+	}
+	s.watches.notify("pods", watchEvent{Type: "MODIFIED", Object: pod})
+	return nil
+}
+
+// NodeResources is the accounting view served for one node: what the
+// host has, what may be handed out, what pods have asked for, and what
+// remains.
+type NodeResources struct {
+	Node        string           `json:"node"`
+	Capacity    api.ResourceList `json:"capacity"`
+	Allocatable api.ResourceList `json:"allocatable"`
+	Requested   api.ResourceList `json:"requested"`
+	Available   api.ResourceList `json:"available"`
+}
+
+// handleNodeResources reports allocatable headroom for one node by
+// subtracting the requests of its bound pods from allocatable.
+func (s *Server) handleNodeResources(w http.ResponseWriter, r *http.Request) {
+	node, err := s.store.GetNode(r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	pods, err := s.store.ListPods()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res := nodeResources(node, pods)
+	writeJSON(w, http.StatusOK, &res)
+}
+
+// nodeResources computes one node's accounting view from the pods bound
+// to it.
+func nodeResources(node *api.Node, pods []*api.Pod) NodeResources {
+	requested := api.ResourceList{}
+	for _, pod := range pods {
+		if pod.Status.NodeName == node.Meta.Name {
+			requested = requested.Add(api.PodRequests(pod))
+		}
+	}
+	return NodeResources{
+		Node:        node.Meta.Name,
+		Capacity:    node.Status.Capacity,
+		Allocatable: node.Status.Allocatable,
+		Requested:   requested,
+		Available:   node.Status.Allocatable.Sub(requested),
+	}
+}
+
+// ClusterCapacity is the per-node accounting view summed for the whole
+// cluster. The Nodes slice answers "where is the headroom"; the totals
+// answer "does the cluster have room at all".
+type ClusterCapacity struct {
+	Nodes       []NodeResources  `json:"nodes"`
+	Allocatable api.ResourceList `json:"allocatable"`
+	Requested   api.ResourceList `json:"requested"`
+	Available   api.ResourceList `json:"available"`
+}
+
+// handleClusterCapacity reports allocatable minus requested for every
+// node, plus cluster totals, for pre-flight capacity checks.
+func (s *Server) handleClusterCapacity(w http.ResponseWriter, r *http.Request) {
+	nodes, err := s.store.ListNodes()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	pods, err := s.store.ListPods()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	summary := ClusterCapacity{Allocatable: api.ResourceList{}, Requested: api.ResourceList{}}
+	for _, node := range nodes {
+		res := nodeResources(node, pods)
+		summary.Nodes = append(summary.Nodes, res)
+		summary.Allocatable = summary.Allocatable.Add(node.Status.Allocatable)
+		summary.Requested = summary.Requested.Add(res.Requested)
+	}
+	summary.Available = summary.Allocatable.Sub(summary.Requested)
+	writeJSON(w, http.StatusOK, &summary)
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/store"
+)
+
+func runningPod(name, node string, labels map[string]string) *api.Pod {
+	return &api.Pod{
+		Meta: api.ObjectMeta{Namespace: "default", Name: name, Labels: labels},
+		Status: api.PodStatus{
+			Phase:    api.PodRunning,
+			NodeName: node,
+		},
+	}
+}
+
+// TestDrainRespectsDisruptionBudget drains a node holding two of a
+// budget's three pods with minAvailable 2: exactly one may go, and the
+// refusal must account for the first eviction — not the stale pod list.
+func TestDrainRespectsDisruptionBudget(t *testing.T) {
+	st := store.NewMemoryStore()
+	srv := New(st)
+
+	web := map[string]string{"app": "web"}
+	st.PutNode(&api.Node{Meta: api.ObjectMeta{Name: "n1"}})
+	st.PutPod(runningPod("web-1", "n1", web))
+	st.PutPod(runningPod("web-2", "n1", web))
+	st.PutPod(runningPod("web-3", "n2", web))
+	st.PutPodDisruptionBudget(&api.PodDisruptionBudget{
+		Meta: api.ObjectMeta{Namespace: "default", Name: "web"},
+		Spec: api.PodDisruptionBudgetSpec{
+			Selector:     &api.LabelSelector{MatchLabels: web},
+			MinAvailable: 2,
+		},
+	})
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest("POST", "/api/v1/nodes/n1/drain?gracePeriodSeconds=5", nil))
+	if rec.Code != 200 {
+		t.Fatalf("drain returned %d: %s", rec.Code, rec.Body)
+	}
+	var result DrainResult
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatalf("decoding result: %v", err)
+	}
+	if len(result.Evicted) != 1 || len(result.Refused) != 1 {
+		t.Fatalf("evicted %v, refused %v; want one of each", result.Evicted, result.Refused)
+	}
+
+	// The evicted pod stays in the store, stamped with the requested
+	// grace period for its node's agent to honor.
+	pods, _ := st.ListPods()
+	stamped := 0
+	for _, pod := range pods {
+		if g := pod.Meta.Annotations[api.EvictionGracePeriodAnnotation]; g != "" {
+			stamped++
+			if g != "5s" {
+				t.Fatalf("recorded grace %q, want 5s", g)
+			}
+		}
+	}
+	if stamped != 1 {
+		t.Fatalf("%d pods stamped for eviction, want 1", stamped)
+	}
+
+	// Retrying while the first eviction is still terminating must not
+	// evict the survivor.
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest("POST", "/api/v1/nodes/n1/drain", nil))
+	result = DrainResult{}
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatalf("decoding result: %v", err)
+	}
+	if len(result.Evicted) != 0 || len(result.Refused) != 1 {
+		t.Fatalf("retry evicted %v, refused %v; want none evicted", result.Evicted, result.Refused)
+	}
+}
+
+// TestDrainWithoutBudgetEvictsEverything checks the plain path: no
+// budgets, every pod on the node is handed over for teardown.
+func TestDrainWithoutBudgetEvictsEverything(t *testing.T) {
+	st := store.NewMemoryStore()
+	srv := New(st)
+
+	st.PutNode(&api.Node{Meta: api.ObjectMeta{Name: "n1"}})
+	st.PutPod(runningPod("a", "n1", nil))
+	st.PutPod(runningPod("b", "n1", nil))
+	st.PutPod(runningPod("c", "n2", nil))
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest("POST", "/api/v1/nodes/n1/drain", nil))
+	if rec.Code != 200 {
+		t.Fatalf("drain returned %d: %s", rec.Code, rec.Body)
+	}
+	var result DrainResult
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatalf("decoding result: %v", err)
+	}
+	if len(result.Evicted) != 2 || len(result.Refused) != 0 {
+		t.Fatalf("evicted %v, refused %v; want both node pods evicted", result.Evicted, result.Refused)
+	}
+
+	node, err := st.GetNode("n1")
+	if err != nil {
+		t.Fatalf("getting node: %v", err)
+	}
+	if !node.Spec.Unschedulable {
+		t.Fatal("drain left the node schedulable")
+	}
+}
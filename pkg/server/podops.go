@@ -0,0 +1,156 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// defaultSchedulingGate is the gate the hold verb adds when the caller
+// names none.
+const defaultSchedulingGate = "synthesis.io/hold"
+
+// gateRequest is the optional body of the hold and unhold verbs.
+type gateRequest struct {
+	// Gate names the scheduling gate to add or remove. Hold defaults
+	// it to defaultSchedulingGate; unhold without one lifts every gate.
+	Gate string `json:"gate,omitempty"`
+}
+
+// handleHoldPod adds a scheduling gate so the pod stays Pending until
+// it is unheld. Only unscheduled pods can be held; once a pod is bound
+// there is nothing left to gate.
+func (s *Server) handleHoldPod(w http.ResponseWriter, r *http.Request) {
+	pod, req, err := s.gateTarget(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if pod.Status.NodeName != "" || pod.Spec.NodeName != "" {
+		writeError(w, badRequest("pod %q is already scheduled", pod.Meta.Name))
+		return
+	}
+	gate := req.Gate
+	if gate == "" {
+		gate = defaultSchedulingGate
+	}
+	for _, g := range pod.Spec.SchedulingGates {
+		if g.Name == gate {
+			writeJSON(w, http.StatusOK, pod)
+			return
+		}
+	}
+	pod.Spec.SchedulingGates = append(pod.Spec.SchedulingGates, api.SchedulingGate{Name: gate})
+	s.putPodUpdate(w, pod)
+}
+
+// handleUnholdPod removes a scheduling gate. With no gate named, every
+// gate is lifted so the pod schedules on the next pass.
+func (s *Server) handleUnholdPod(w http.ResponseWriter, r *http.Request) {
+	pod, req, err := s.gateTarget(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if req.Gate == "" {
+		pod.Spec.SchedulingGates = nil
+	} else {
+		gates := pod.Spec.SchedulingGates[:0]
+		for _, g := range pod.Spec.SchedulingGates {
+			if g.Name != req.Gate {
+				gates = append(gates, g)
+			}
+		}
+		pod.Spec.SchedulingGates = gates
+	}
+	if len(pod.Spec.SchedulingGates) == 0 {
+		pod.Spec.SchedulingGates = nil
+	}
+	s.putPodUpdate(w, pod)
+}
+
+// gateTarget loads the addressed pod and decodes the optional gate
+// body; an empty body is fine.
+func (s *Server) gateTarget(r *http.Request) (*api.Pod, gateRequest, error) {
+	var req gateRequest
+	pod, err := s.store.GetPod(r.PathValue("namespace"), r.PathValue("name"))
+	if err != nil {
+		return nil, req, err
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		return nil, req, badRequest("decoding gate request: %v", err)
+	}
+	return pod, req, nil
+}
+
+func (s *Server) putPodUpdate(w http.ResponseWriter, pod *api.Pod) {
+	if err := s.store.PutPod(pod); err != nil {
+		writeError(w, err)
+		return
+	}
+	s.watches.notify("pods", watchEvent{Type: "MODIFIED", Object: pod})
+	writeJSON(w, http.StatusOK, pod)
+}
+
+// bindRequest is the body of the binding subresource: the node the
+// caller wants the pod placed on.
+type bindRequest struct {
+	Node string `json:"node"`
+}
+
+// handleBindPod binds a pending pod to a node on the caller's say-so,
+// the escape hatch that makes the built-in scheduler replaceable: an
+// external scheduler watches for pending pods and POSTs bindings here.
+// The node must exist, but no predicates run — whoever binds is trusted
+// to have done their own filtering. With the scheduler controller
+// disabled in the config, this is the only way pods get placed.
+func (s *Server) handleBindPod(w http.ResponseWriter, r *http.Request) {
+	pod, err := s.store.GetPod(r.PathValue("namespace"), r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var req bindRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, badRequest("decoding binding: %v", err))
+		return
+	}
+	if req.Node == "" {
+		writeError(w, badRequest("binding names no node"))
+		return
+	}
+	if pod.Status.NodeName != "" {
+		writeError(w, conflict("pod %q is already bound to %q", pod.Meta.Name, pod.Status.NodeName))
+		return
+	}
+	if _, err := s.store.GetNode(req.Node); err != nil {
+		writeError(w, err)
+		return
+	}
+	pod.Status.NodeName = req.Node
+	s.putPodUpdate(w, pod)
+}
+
+// handleRestartPod stamps a restart timestamp onto the pod; the node's
+// agent recreates containers started before it on its next sync, so a
+// restart never needs out-of-band engine access.
+func (s *Server) handleRestartPod(w http.ResponseWriter, r *http.Request) {
+	pod, err := s.store.GetPod(r.PathValue("namespace"), r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if pod.Status.Phase == api.PodSucceeded || pod.Status.Phase == api.PodFailed {
+		writeError(w, badRequest("pod %q has finished; restart its owner instead", pod.Meta.Name))
+		return
+	}
+	if pod.Meta.Annotations == nil {
+		pod.Meta.Annotations = map[string]string{}
+	}
+	pod.Meta.Annotations[api.RestartedAtAnnotation] = time.Now().Format(time.RFC3339Nano)
+	s.putPodUpdate(w, pod)
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+func (s *Server) handleListPriorityClasses(w http.ResponseWriter, r *http.Request) {
+	classes, err := s.store.ListPriorityClasses()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, classes)
+}
+
+func (s *Server) handleGetPriorityClass(w http.ResponseWriter, r *http.Request) {
+	pc, err := s.store.GetPriorityClass(r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, pc)
+}
+
+func (s *Server) handlePutPriorityClass(w http.ResponseWriter, r *http.Request) {
+	var pc api.PriorityClass
+	if err := json.NewDecoder(r.Body).Decode(&pc); err != nil {
+		writeError(w, badRequest("decoding priority class: %v", err))
+		return
+	}
+	pc.Meta.Name = r.PathValue("name")
+	if err := validateMeta("priority class", &pc.Meta); err != nil {
+		writeError(w, err)
+		return
+	}
+	var prior time.Time
+	if old, err := s.store.GetPriorityClass(pc.Meta.Name); err == nil {
+		prior = old.Meta.CreatedAt
+	}
+	stampCreated(&pc.Meta, prior)
+	if err := s.store.PutPriorityClass(&pc); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &pc)
+}
+
+func (s *Server) handleDeletePriorityClass(w http.ResponseWriter, r *http.Request) {
+	if err := s.store.DeletePriorityClass(r.PathValue("name")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+func (s *Server) handleListRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := s.store.ListRoles()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, roles)
+}
+
+func (s *Server) handlePutRole(w http.ResponseWriter, r *http.Request) {
+	var role api.Role
+	if err := json.NewDecoder(r.Body).Decode(&role); err != nil {
+		writeError(w, badRequest("decoding role: %v", err))
+		return
+	}
+	role.Meta.Namespace = r.PathValue("namespace")
+	role.Meta.Name = r.PathValue("name")
+	if err := validateMeta("role", &role.Meta); err != nil {
+		writeError(w, err)
+		return
+	}
+	var prior time.Time
+	if old, err := s.store.GetRole(role.Meta.Namespace, role.Meta.Name); err == nil {
+		prior = old.Meta.CreatedAt
+	}
+	stampCreated(&role.Meta, prior)
+	if err := s.store.PutRole(&role); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &role)
+}
+
+func (s *Server) handleDeleteRole(w http.ResponseWriter, r *http.Request) {
+	if err := s.store.DeleteRole(r.PathValue("namespace"), r.PathValue("name")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleListRoleBindings(w http.ResponseWriter, r *http.Request) {
+	bindings, err := s.store.ListRoleBindings()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, bindings)
+}
+
+func (s *Server) handlePutRoleBinding(w http.ResponseWriter, r *http.Request) {
+	var binding api.RoleBinding
+	if err := json.NewDecoder(r.Body).Decode(&binding); err != nil {
+		writeError(w, badRequest("decoding rolebinding: %v", err))
+		return
+	}
+	binding.Meta.Namespace = r.PathValue("namespace")
+	binding.Meta.Name = r.PathValue("name")
+	if err := validateMeta("role binding", &binding.Meta); err != nil {
+		writeError(w, err)
+		return
+	}
+	if binding.RoleName == "" {
+		writeError(w, badRequest("rolebinding must name a role"))
+		return
+	}
+	var prior time.Time
+	if bindings, err := s.store.ListRoleBindings(); err == nil {
+		for _, old := range bindings {
+			if old.Meta.Namespace == binding.Meta.Namespace && old.Meta.Name == binding.Meta.Name {
+				prior = old.Meta.CreatedAt
+				break
+			}
+		}
+	}
+	stampCreated(&binding.Meta, prior)
+	if err := s.store.PutRoleBinding(&binding); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &binding)
+}
+
+func (s *Server) handleDeleteRoleBinding(w http.ResponseWriter, r *http.Request) {
+	if err := s.store.DeleteRoleBinding(r.PathValue("namespace"), r.PathValue("name")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
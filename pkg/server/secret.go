@@ -0,0 +1,151 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/kms"
+)
+
+// SetKMSProvider enables envelope encryption of secrets at rest.
+// Without a provider secrets are stored as submitted.
+func (s *Server) SetKMSProvider(p kms.Provider) {
+	s.kms = p
+}
+
+// handlePutSecret seals the secret's data before storage when a KMS
+// provider is configured.
+func (s *Server) handlePutSecret(w http.ResponseWriter, r *http.Request) {
+	var secret api.Secret
+	if err := json.NewDecoder(r.Body).Decode(&secret); err != nil {
+		writeError(w, badRequest("decoding secret: %v", err))
+		return
+	}
+	secret.Meta.Namespace = r.PathValue("namespace")
+	secret.Meta.Name = r.PathValue("name")
+	if err := validateMeta("secret", &secret.Meta); err != nil {
+		writeError(w, err)
+		return
+	}
+	var prior time.Time
+	if old, err := s.store.GetSecret(secret.Meta.Namespace, secret.Meta.Name); err == nil {
+		prior = old.Meta.CreatedAt
+	}
+	stampCreated(&secret.Meta, prior)
+	if s.kms != nil && secret.Data != nil {
+		payload, err := json.Marshal(secret.Data)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		env, err := kms.Seal(s.kms, payload)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		secret.Encrypted = env
+		secret.Data = nil
+	}
+	if err := s.store.PutSecret(&secret); err != nil {
+		writeError(w, err)
+		return
+	}
+	// Do not echo plaintext or ciphertext back.
+	writeJSON(w, http.StatusOK, &api.Secret{Meta: secret.Meta})
+}
+
+// handleGetSecret opens the envelope and returns plaintext data.
+func (s *Server) handleGetSecret(w http.ResponseWriter, r *http.Request) {
+	secret, err := s.store.GetSecret(r.PathValue("namespace"), r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	out := *secret
+	if secret.Encrypted != nil {
+		if s.kms == nil {
+			writeError(w, badRequest("secret is encrypted but no KMS provider is configured"))
+			return
+		}
+		payload, err := kms.Open(s.kms, secret.Encrypted)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if err := json.Unmarshal(payload, &out.Data); err != nil {
+			writeError(w, err)
+			return
+		}
+		out.Encrypted = nil
+	}
+	writeJSON(w, http.StatusOK, &out)
+}
+
+func (s *Server) handleDeleteSecret(w http.ResponseWriter, r *http.Request) {
+	if err := s.store.DeleteSecret(r.PathValue("namespace"), r.PathValue("name")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleListSecrets lists secret names only; values require a targeted
+// get.
+func (s *Server) handleListSecrets(w http.ResponseWriter, r *http.Request) {
+	secrets, err := s.store.ListSecrets()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	out := make([]api.Secret, 0, len(secrets))
+	for _, sec := range secrets {
+		out = append(out, api.Secret{Meta: sec.Meta})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// handleRotateEncryption rotates the provider's master key and reseals
+// every stored secret under it, so old master keys can eventually be
+// retired.
+func (s *Server) handleRotateEncryption(w http.ResponseWriter, r *http.Request) {
+	rotator, ok := s.kms.(interface{ Rotate() (string, error) })
+	if !ok {
+		writeError(w, badRequest("the configured KMS provider does not support server-side rotation"))
+		return
+	}
+	keyID, err := rotator.Rotate()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	secrets, err := s.store.ListSecrets()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	resealed := 0
+	for _, secret := range secrets {
+		if secret.Encrypted == nil {
+			continue
+		}
+		payload, err := kms.Open(s.kms, secret.Encrypted)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		env, err := kms.Seal(s.kms, payload)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		secret.Encrypted = env
+		if err := s.store.PutSecret(secret); err != nil {
+			writeError(w, err)
+			return
+		}
+		resealed++
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"keyID": keyID, "resealed": resealed})
+}
@@ -0,0 +1,617 @@
+// Package server exposes the cluster state over a REST API. Handlers are
+// thin: they validate, touch the store, and leave reconciliation to the
+// controllers.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/admission"
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/events"
+	"github.com/ptfpinho23/synthesis/pkg/kms"
+	"github.com/ptfpinho23/synthesis/pkg/logging"
+	"github.com/ptfpinho23/synthesis/pkg/store"
+	"github.com/ptfpinho23/synthesis/pkg/token"
+)
+
+// Server is the Synthesis API server.
+type Server struct {
+	store store.Store
+	mux   *http.ServeMux
+
+	// tokens maps bearer tokens to usernames; rbac enables enforcement.
+	tokens map[string]string
+	rbac   bool
+
+	// signer mints and verifies service account tokens.
+	signer *token.Signer
+
+	// limits guards against runaway clients when set.
+	limits *limiter
+
+	// admission vets pods before they are stored.
+	admission admission.Chain
+
+	// kms seals secrets at rest when configured.
+	kms kms.Provider
+
+	// metrics caches the usage samples agents push.
+	metrics *metricsCache
+
+	// usage integrates those samples into per-namespace chargeback
+	// records in the store.
+	usage *usageMeter
+
+	// accessLog records one line per request.
+	accessLog *slog.Logger
+
+	// health holds the component probes behind /healthz.
+	health healthChecks
+
+	// draining refuses new mutations once shutdown has begun.
+	draining atomic.Bool
+
+	// corsOrigins, when non-empty, are the origins allowed to make
+	// cross-origin requests.
+	corsOrigins []string
+
+	// watches fans custom resource changes out to watch streams.
+	watches *watchHub
+
+	// events is the bounded history behind the events API; notifier,
+	// when set, forwards recorded events to external sinks.
+	events   eventLog
+	notifier *events.Notifier
+
+	// systemConfig is the effective tuning served read-only at
+	// /api/v1/system/config.
+	systemConfig any
+
+	// imagePulls queues image pre-pull requests for agents to pick up.
+	imagePulls pullQueue
+
+	// acmeChallenges holds pending HTTP-01 key authorizations the
+	// ingress proxies serve on the certificate controller's behalf.
+	acmeMu         sync.Mutex
+	acmeChallenges map[string]string
+}
+
+// SetSystemConfig records the tuning in force so operators can read it
+// back from the system config endpoint.
+func (s *Server) SetSystemConfig(cfg any) { s.systemConfig = cfg }
+
+func (s *Server) handleSystemConfig(w http.ResponseWriter, r *http.Request) {
+	if s.systemConfig == nil {
+		writeJSON(w, http.StatusOK, map[string]any{})
+		return
+	}
+	writeJSON(w, http.StatusOK, s.systemConfig)
+}
+
+// handleSystemTime serves the server clock so agents and CLIs on
+// drifting hardware can measure their offset instead of trusting their
+// own wall time.
+func (s *Server) handleSystemTime(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{"now": time.Now().UTC()})
+}
+
+// BeginDrain makes the server reject new mutating requests with 503
+// while in-flight ones finish, so shutdown never half-applies a write.
+func (s *Server) BeginDrain() { s.draining.Store(true) }
+
+// SetAdmission installs the admission chain run on pod writes.
+func (s *Server) SetAdmission(chain admission.Chain) {
+	s.admission = chain
+}
+
+// New returns a server wired to the given store.
+func New(s store.Store) *Server {
+	srv := &Server{
+		store:     s,
+		mux:       http.NewServeMux(),
+		metrics:   newMetricsCache(),
+		usage:     newUsageMeter(),
+		accessLog: logging.Component("http"),
+		health:    healthChecks{checks: make(map[string]func() error)},
+		watches:   newWatchHub(),
+	}
+	srv.builtinHealthChecks()
+	srv.routes()
+	return srv
+}
+
+// ServeHTTP implements http.Handler: assign a request ID, rate-limit,
+// enforce authorization when RBAC is enabled, dispatch, and log the
+// outcome. The /api/v1/auth/ endpoints are reachable by any caller so
+// clients can probe their own identity and permissions.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	id := r.Header.Get(requestIDHeader)
+	if id == "" {
+		id = newRequestID()
+	}
+	w.Header().Set(requestIDHeader, id)
+	r = r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id))
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	user := s.userFor(r)
+	defer func() {
+		s.accessLog.Info("request",
+			"method", r.Method, "path", r.URL.Path, "user", user,
+			"status", rec.status, "duration", time.Since(start), "requestID", id)
+	}()
+
+	if s.cors(rec, r) {
+		return
+	}
+	if s.draining.Load() && r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeJSON(rec, http.StatusServiceUnavailable, map[string]string{"error": "server is shutting down"})
+		return
+	}
+	if s.limits != nil {
+		done, ok := s.limits.admit(rec, r)
+		if !ok {
+			return
+		}
+		defer done()
+	}
+	if s.rbac && !strings.HasPrefix(r.URL.Path, "/api/v1/auth/") {
+		attrs := requestAttributes(r)
+		allowed, err := s.authorize(user, attrs)
+		if err != nil {
+			writeError(rec, err)
+			return
+		}
+		if !allowed {
+			writeJSON(rec, http.StatusForbidden, map[string]string{
+				"error": fmt.Sprintf("user %q cannot %s %s", user, attrs.Verb, attrs.Resource),
+			})
+			return
+		}
+	}
+	if err := decodeYAMLBody(r); err != nil {
+		writeError(rec, err)
+		return
+	}
+	out, finish, ok := negotiate(rec, r)
+	if !ok {
+		return
+	}
+	s.mux.ServeHTTP(out, r)
+	finish()
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /api/v1/nodes", s.handleListNodes)
+	s.mux.HandleFunc("GET /api/v1/nodes/{name}", s.handleGetNode)
+	s.mux.HandleFunc("PUT /api/v1/nodes/{name}", s.handlePutNode)
+	s.mux.HandleFunc("DELETE /api/v1/nodes/{name}", s.handleDeleteNode)
+	s.mux.HandleFunc("POST /api/v1/nodes/{name}/cordon", s.handleCordon)
+	s.mux.HandleFunc("POST /api/v1/nodes/{name}/uncordon", s.handleUncordon)
+	s.mux.HandleFunc("POST /api/v1/nodes/{name}/drain", s.handleDrain)
+	s.mux.HandleFunc("GET /api/v1/nodes/{name}/resources", s.handleNodeResources)
+	s.mux.HandleFunc("PUT /api/v1/nodes/{name}/stats", s.handleNodeStats)
+	s.mux.HandleFunc("GET /api/v1/capacity", s.handleClusterCapacity)
+
+	s.mux.HandleFunc("GET /apis/metrics.k8s.io/v1beta1/nodes", s.handleListNodeMetrics)
+	s.mux.HandleFunc("GET /apis/metrics.k8s.io/v1beta1/nodes/{name}", s.handleGetNodeMetrics)
+	s.mux.HandleFunc("GET /apis/metrics.k8s.io/v1beta1/pods", s.handleListPodMetrics)
+	s.mux.HandleFunc("GET /apis/metrics.k8s.io/v1beta1/namespaces/{namespace}/pods/{name}", s.handleGetPodMetrics)
+	s.mux.HandleFunc("GET /apis/metrics.k8s.io/v1beta1/nodes/{name}/history", s.handleNodeMetricsHistory)
+	s.mux.HandleFunc("GET /apis/metrics.k8s.io/v1beta1/namespaces/{namespace}/pods/{name}/history", s.handlePodMetricsHistory)
+
+	s.mux.HandleFunc("GET /api/v1/usagerecords", s.handleListUsageRecords)
+
+	s.mux.HandleFunc("GET /api/v1/auth/can-i", s.handleCanI)
+	s.mux.HandleFunc("GET /api/v1/auth/whoami", s.handleWhoAmI)
+
+	s.mux.HandleFunc("POST /api/v1/images/pull", s.handlePullImage)
+	s.mux.HandleFunc("GET /api/v1/images/pulls", s.handleListImagePulls)
+
+	s.mux.HandleFunc("GET /api/v1/events", s.handleListEvents)
+	s.mux.HandleFunc("POST /api/v1/events", s.handlePostEvent)
+
+	s.mux.HandleFunc("GET /api/v1/namespaces", s.handleListNamespaces)
+	s.mux.HandleFunc("GET /api/v1/namespaces/{name}", s.handleGetNamespace)
+	s.mux.HandleFunc("PUT /api/v1/namespaces/{name}", s.handlePutNamespace)
+	s.mux.HandleFunc("DELETE /api/v1/namespaces/{name}", s.handleDeleteNamespace)
+
+	s.mux.HandleFunc("GET /api/v1/serviceaccounts", s.handleListServiceAccounts)
+	s.mux.HandleFunc("PUT /api/v1/namespaces/{namespace}/serviceaccounts/{name}", s.handlePutServiceAccount)
+	s.mux.HandleFunc("DELETE /api/v1/namespaces/{namespace}/serviceaccounts/{name}", s.handleDeleteServiceAccount)
+	s.mux.HandleFunc("POST /api/v1/namespaces/{namespace}/serviceaccounts/{name}/token", s.handleServiceAccountToken)
+
+	s.mux.HandleFunc("GET /api/v1/roles", s.handleListRoles)
+	s.mux.HandleFunc("PUT /api/v1/roles/{name}", s.handlePutRole)
+	s.mux.HandleFunc("DELETE /api/v1/roles/{name}", s.handleDeleteRole)
+	s.mux.HandleFunc("PUT /api/v1/namespaces/{namespace}/roles/{name}", s.handlePutRole)
+	s.mux.HandleFunc("DELETE /api/v1/namespaces/{namespace}/roles/{name}", s.handleDeleteRole)
+
+	s.mux.HandleFunc("GET /api/v1/rolebindings", s.handleListRoleBindings)
+	s.mux.HandleFunc("PUT /api/v1/rolebindings/{name}", s.handlePutRoleBinding)
+	s.mux.HandleFunc("DELETE /api/v1/rolebindings/{name}", s.handleDeleteRoleBinding)
+	s.mux.HandleFunc("PUT /api/v1/namespaces/{namespace}/rolebindings/{name}", s.handlePutRoleBinding)
+	s.mux.HandleFunc("DELETE /api/v1/namespaces/{namespace}/rolebindings/{name}", s.handleDeleteRoleBinding)
+
+	s.mux.HandleFunc("GET /api/v1/services", s.handleListServices)
+	s.mux.HandleFunc("GET /api/v1/namespaces/{namespace}/services/{name}", s.handleGetService)
+	s.mux.HandleFunc("PUT /api/v1/namespaces/{namespace}/services/{name}", s.handlePutService)
+	s.mux.HandleFunc("DELETE /api/v1/namespaces/{namespace}/services/{name}", s.handleDeleteService)
+	s.mux.HandleFunc("GET /api/v1/namespaces/{namespace}/services/{name}/endpoints", s.handleServiceEndpoints)
+
+	s.mux.HandleFunc("GET /api/v1/ingresses", s.handleListIngresses)
+	s.mux.HandleFunc("GET /api/v1/namespaces/{namespace}/ingresses/{name}", s.handleGetIngress)
+	s.mux.HandleFunc("PUT /api/v1/namespaces/{namespace}/ingresses/{name}", s.handlePutIngress)
+	s.mux.HandleFunc("DELETE /api/v1/namespaces/{namespace}/ingresses/{name}", s.handleDeleteIngress)
+	s.mux.HandleFunc("GET /api/v1/acme-challenges/{token}", s.handleACMEChallenge)
+
+	s.mux.HandleFunc("GET /api/v1/gateways", s.handleListGateways)
+	s.mux.HandleFunc("GET /api/v1/namespaces/{namespace}/gateways/{name}", s.handleGetGateway)
+	s.mux.HandleFunc("PUT /api/v1/namespaces/{namespace}/gateways/{name}", s.handlePutGateway)
+	s.mux.HandleFunc("DELETE /api/v1/namespaces/{namespace}/gateways/{name}", s.handleDeleteGateway)
+
+	s.mux.HandleFunc("GET /api/v1/httproutes", s.handleListHTTPRoutes)
+	s.mux.HandleFunc("GET /api/v1/namespaces/{namespace}/httproutes/{name}", s.handleGetHTTPRoute)
+	s.mux.HandleFunc("PUT /api/v1/namespaces/{namespace}/httproutes/{name}", s.handlePutHTTPRoute)
+	s.mux.HandleFunc("DELETE /api/v1/namespaces/{namespace}/httproutes/{name}", s.handleDeleteHTTPRoute)
+
+	s.mux.HandleFunc("GET /api/v1/secrets", s.handleListSecrets)
+	s.mux.HandleFunc("GET /api/v1/namespaces/{namespace}/secrets/{name}", s.handleGetSecret)
+	s.mux.HandleFunc("PUT /api/v1/namespaces/{namespace}/secrets/{name}", s.handlePutSecret)
+	s.mux.HandleFunc("DELETE /api/v1/namespaces/{namespace}/secrets/{name}", s.handleDeleteSecret)
+	s.mux.HandleFunc("POST /api/v1/encryption/rotate", s.handleRotateEncryption)
+
+	s.mux.HandleFunc("GET /api/v1/priorityclasses", s.handleListPriorityClasses)
+	s.mux.HandleFunc("GET /api/v1/priorityclasses/{name}", s.handleGetPriorityClass)
+	s.mux.HandleFunc("PUT /api/v1/priorityclasses/{name}", s.handlePutPriorityClass)
+	s.mux.HandleFunc("DELETE /api/v1/priorityclasses/{name}", s.handleDeletePriorityClass)
+
+	s.mux.HandleFunc("GET /api/v1/workloads", s.handleListWorkloads)
+	s.mux.HandleFunc("GET /api/v1/namespaces/{namespace}/workloads/{name}", s.handleGetWorkload)
+	s.mux.HandleFunc("POST /api/v1/namespaces/{namespace}/workloads", s.handleCreateWorkload)
+	s.mux.HandleFunc("PUT /api/v1/namespaces/{namespace}/workloads/{name}", s.handlePutWorkload)
+	s.mux.HandleFunc("DELETE /api/v1/namespaces/{namespace}/workloads/{name}", s.handleDeleteWorkload)
+	s.mux.HandleFunc("POST /api/v1/namespaces/{namespace}/workloads/{name}/restart", s.handleRestartWorkload)
+	s.mux.HandleFunc("POST /api/v1/namespaces/{namespace}/workloads/{name}/pause", s.handlePauseWorkload)
+	s.mux.HandleFunc("POST /api/v1/namespaces/{namespace}/workloads/{name}/resume", s.handleResumeWorkload)
+
+	s.mux.HandleFunc("GET /api/v1/customresourcedefinitions", s.handleListCRDs)
+	s.mux.HandleFunc("GET /api/v1/customresourcedefinitions/{name}", s.handleGetCRD)
+	s.mux.HandleFunc("PUT /api/v1/customresourcedefinitions/{name}", s.handlePutCRD)
+	s.mux.HandleFunc("DELETE /api/v1/customresourcedefinitions/{name}", s.handleDeleteCRD)
+
+	// Custom resources are served under their registered group and
+	// version. The literal metrics routes above are more specific and
+	// keep precedence over these wildcards.
+	s.mux.HandleFunc("GET /apis/{group}/{version}/{plural}", s.handleListCustomObjects)
+	s.mux.HandleFunc("GET /apis/{group}/{version}/{plural}/{name}", s.handleGetCustomObject)
+	s.mux.HandleFunc("PUT /apis/{group}/{version}/{plural}/{name}", s.handlePutCustomObject)
+	s.mux.HandleFunc("DELETE /apis/{group}/{version}/{plural}/{name}", s.handleDeleteCustomObject)
+	s.mux.HandleFunc("GET /apis/{group}/{version}/namespaces/{namespace}/{plural}", s.handleListCustomObjects)
+	s.mux.HandleFunc("GET /apis/{group}/{version}/namespaces/{namespace}/{plural}/{name}", s.handleGetCustomObject)
+	s.mux.HandleFunc("PUT /apis/{group}/{version}/namespaces/{namespace}/{plural}/{name}", s.handlePutCustomObject)
+	s.mux.HandleFunc("DELETE /apis/{group}/{version}/namespaces/{namespace}/{plural}/{name}", s.handleDeleteCustomObject)
+
+	s.mux.HandleFunc("GET /api/v1/leases", s.handleListLeases)
+	s.mux.HandleFunc("GET /api/v1/leases/{name}", s.handleGetLease)
+	s.mux.HandleFunc("PUT /api/v1/leases/{name}", s.handlePutLease)
+	s.mux.HandleFunc("DELETE /api/v1/leases/{name}", s.handleDeleteLease)
+	s.mux.HandleFunc("GET /api/v1/namespaces/{namespace}/leases/{name}", s.handleGetLease)
+	s.mux.HandleFunc("PUT /api/v1/namespaces/{namespace}/leases/{name}", s.handlePutLease)
+	s.mux.HandleFunc("DELETE /api/v1/namespaces/{namespace}/leases/{name}", s.handleDeleteLease)
+
+	s.mux.HandleFunc("GET /api", s.handleAPIVersions)
+	s.mux.HandleFunc("GET /api/v1", s.handleAPIV1Resources)
+	s.mux.HandleFunc("GET /apis", s.handleAPIGroups)
+	s.mux.HandleFunc("GET /apis/{group}/{version}", s.handleGroupResources)
+	s.mux.HandleFunc("GET /version", s.handleVersion)
+	s.mux.HandleFunc("GET /api/v1/system/config", s.handleSystemConfig)
+	s.mux.HandleFunc("GET /api/v1/system/time", s.handleSystemTime)
+
+	s.mux.HandleFunc("GET /healthz", s.handleHealthz)
+	s.mux.HandleFunc("GET /healthz/{check}", s.handleHealthzCheck)
+
+	s.mux.HandleFunc("GET /debug/loglevel", s.handleGetLogLevels)
+	s.mux.HandleFunc("PUT /debug/loglevel", s.handleSetLogLevel)
+
+	s.mux.HandleFunc("GET /api/v1/pods", s.handleListPods)
+	s.mux.HandleFunc("GET /api/v1/namespaces/{namespace}/pods", s.handleListNamespacedPods)
+	s.mux.HandleFunc("POST /api/v1/namespaces/{namespace}/pods", s.handleCreatePod)
+	s.mux.HandleFunc("POST /api/v1/namespaces/{namespace}/pods/dryrun", s.handlePodDryRun)
+	s.mux.HandleFunc("POST /api/v1/namespaces/{namespace}/pods/{name}/ephemeralcontainers", s.handleEphemeralContainers)
+	s.mux.HandleFunc("GET /api/v1/namespaces/{namespace}/pods/{name}", s.handleGetPod)
+	s.mux.HandleFunc("GET /api/v1/namespaces/{namespace}/pods/{name}/log", s.handlePodLog)
+	s.mux.HandleFunc("PUT /api/v1/namespaces/{namespace}/pods/{name}", s.handlePutPod)
+	s.mux.HandleFunc("DELETE /api/v1/namespaces/{namespace}/pods/{name}", s.handleDeletePod)
+	s.mux.HandleFunc("POST /api/v1/namespaces/{namespace}/pods/{name}/restart", s.handleRestartPod)
+	s.mux.HandleFunc("POST /api/v1/namespaces/{namespace}/pods/{name}/binding", s.handleBindPod)
+	s.mux.HandleFunc("POST /api/v1/namespaces/{namespace}/pods/{name}/hold", s.handleHoldPod)
+	s.mux.HandleFunc("POST /api/v1/namespaces/{namespace}/pods/{name}/unhold", s.handleUnholdPod)
+}
+
+func (s *Server) handleListNodes(w http.ResponseWriter, r *http.Request) {
+	nodes, err := s.store.ListNodes()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if wantsTable(r) {
+		writeJSON(w, http.StatusOK, nodeTable(nodes))
+		return
+	}
+	writeJSON(w, http.StatusOK, nodes)
+}
+
+func (s *Server) handleGetNode(w http.ResponseWriter, r *http.Request) {
+	node, err := s.store.GetNode(r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, node)
+}
+
+func (s *Server) handlePutNode(w http.ResponseWriter, r *http.Request) {
+	var node api.Node
+	if err := json.NewDecoder(r.Body).Decode(&node); err != nil {
+		writeError(w, badRequest("decoding node: %v", err))
+		return
+	}
+	node.Meta.Name = r.PathValue("name")
+	if err := validateMeta("node", &node.Meta); err != nil {
+		writeError(w, err)
+		return
+	}
+	old, _ := s.store.GetNode(node.Meta.Name)
+	var prior time.Time
+	if old != nil {
+		prior = old.Meta.CreatedAt
+	}
+	stampCreated(&node.Meta, prior)
+	stampConditionTimes(&node, old)
+	if err := s.store.PutNode(&node); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &node)
+}
+
+// stampConditionTimes keeps condition transition times on the server
+// clock: a condition whose status is unchanged keeps the stored time,
+// one that flipped (or is new) is stamped now. The time the agent
+// reported is ignored.
+func stampConditionTimes(node, old *api.Node) {
+	now := time.Now()
+	for i := range node.Status.Conditions {
+		cond := &node.Status.Conditions[i]
+		var prev *api.NodeCondition
+		if old != nil {
+			prev = old.Status.Condition(cond.Type)
+		}
+		if prev != nil && prev.Status == cond.Status {
+			cond.LastTransitionTime = prev.LastTransitionTime
+			continue
+		}
+		cond.LastTransitionTime = now
+	}
+}
+
+func (s *Server) handleDeleteNode(w http.ResponseWriter, r *http.Request) {
+	if err := s.store.DeleteNode(r.PathValue("name")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleListPods(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("watch") == "true" {
+		s.watchPods(w, r, "")
+		return
+	}
+	pods, err := s.store.ListPods()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if wantsTable(r) {
+		writeJSON(w, http.StatusOK, podTable(pods))
+		return
+	}
+	writeJSON(w, http.StatusOK, pods)
+}
+
+func (s *Server) handleGetPod(w http.ResponseWriter, r *http.Request) {
+	pod, err := s.store.GetPod(r.PathValue("namespace"), r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, pod)
+}
+
+func (s *Server) handlePutPod(w http.ResponseWriter, r *http.Request) {
+	var pod api.Pod
+	if err := json.NewDecoder(r.Body).Decode(&pod); err != nil {
+		writeError(w, badRequest("decoding pod: %v", err))
+		return
+	}
+	pod.Meta.Namespace = r.PathValue("namespace")
+	pod.Meta.Name = r.PathValue("name")
+	if err := validateMeta("pod", &pod.Meta); err != nil {
+		writeError(w, err)
+		return
+	}
+	// PUT is update-only; creating a pod goes through POST on the
+	// collection, so a mistyped name can no longer mint a fresh object.
+	old, err := s.store.GetPod(pod.Meta.Namespace, pod.Meta.Name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	stampCreated(&pod.Meta, old.Meta.CreatedAt)
+	warnings, err := s.admission.Admit(&pod)
+	for _, warning := range warnings {
+		w.Header().Add("Warning", warning)
+	}
+	if err != nil {
+		writeError(w, badRequest("admission denied: %v", err))
+		return
+	}
+	if err := s.store.PutPod(&pod); err != nil {
+		writeError(w, err)
+		return
+	}
+	s.watches.notify("pods", watchEvent{Type: "MODIFIED", Object: &pod})
+	writeJSON(w, http.StatusOK, &pod)
+}
+
+// handleCreatePod creates a pod named in the body, minting the name
+// from metadata.generateName when none is given.
+func (s *Server) handleCreatePod(w http.ResponseWriter, r *http.Request) {
+	var pod api.Pod
+	if err := json.NewDecoder(r.Body).Decode(&pod); err != nil {
+		writeError(w, badRequest("decoding pod: %v", err))
+		return
+	}
+	pod.Meta.Namespace = r.PathValue("namespace")
+	completeName(&pod.Meta)
+	if err := validateMeta("pod", &pod.Meta); err != nil {
+		writeError(w, err)
+		return
+	}
+	if _, err := s.store.GetPod(pod.Meta.Namespace, pod.Meta.Name); err == nil {
+		writeError(w, conflict("pod %q already exists", pod.Meta.Name))
+		return
+	}
+	stampCreated(&pod.Meta, time.Time{})
+	warnings, err := s.admission.Admit(&pod)
+	for _, warning := range warnings {
+		w.Header().Add("Warning", warning)
+	}
+	if err != nil {
+		writeError(w, badRequest("admission denied: %v", err))
+		return
+	}
+	if err := s.store.PutPod(&pod); err != nil {
+		writeError(w, err)
+		return
+	}
+	s.watches.notify("pods", watchEvent{Type: "ADDED", Object: &pod})
+	writeJSON(w, http.StatusCreated, &pod)
+}
+
+func (s *Server) handleDeletePod(w http.ResponseWriter, r *http.Request) {
+	namespace, name := r.PathValue("namespace"), r.PathValue("name")
+	pod, err := s.store.GetPod(namespace, name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := s.store.DeletePod(namespace, name); err != nil {
+		writeError(w, err)
+		return
+	}
+	s.watches.notify("pods", watchEvent{Type: "DELETED", Object: pod})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleEphemeralContainers injects a debug container into a running
+// pod. Ephemeral containers can be added but never changed or removed,
+// so the handler only appends.
+func (s *Server) handleEphemeralContainers(w http.ResponseWriter, r *http.Request) {
+	pod, err := s.store.GetPod(r.PathValue("namespace"), r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var ec api.Container
+	if err := json.NewDecoder(r.Body).Decode(&ec); err != nil {
+		writeError(w, badRequest("decoding ephemeral container: %v", err))
+		return
+	}
+	if ec.Name == "" || ec.Image == "" {
+		writeError(w, badRequest("ephemeral container needs a name and an image"))
+		return
+	}
+	for _, existing := range append(append(append([]api.Container(nil),
+		pod.Spec.InitContainers...), pod.Spec.Containers...), pod.Spec.EphemeralContainers...) {
+		if existing.Name == ec.Name {
+			writeError(w, badRequest("container %q already exists in pod", ec.Name))
+			return
+		}
+	}
+	pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, ec)
+	if err := s.store.PutPod(pod); err != nil {
+		writeError(w, err)
+		return
+	}
+	s.watches.notify("pods", watchEvent{Type: "MODIFIED", Object: pod})
+	writeJSON(w, http.StatusOK, pod)
+}
+
+// apiError carries an HTTP status alongside the message, plus an
+// optional machine-readable reason clients can switch on.
+type apiError struct {
+	status  int
+	message string
+	reason  string
+}
+
+func (e *apiError) Error() string { return e.message }
+
+func badRequest(format string, args ...any) error {
+	return &apiError{status: http.StatusBadRequest, message: fmt.Sprintf(format, args...)}
+}
+
+// conflict is the 409 a create gets when the name is already taken.
+func conflict(format string, args ...any) error {
+	return &apiError{
+		status:  http.StatusConflict,
+		message: fmt.Sprintf(format, args...),
+		reason:  "AlreadyExists",
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	reason := ""
+	var ae *apiError
+	switch {
+	case errors.As(err, &ae):
+		status = ae.status
+		reason = ae.reason
+	case errors.Is(err, store.ErrNotFound):
+		status = http.StatusNotFound
+		reason = "NotFound"
+	}
+	// The body doubles as a metav1.Status so kubectl renders failures;
+	// existing clients keep reading the "error" key.
+	body := map[string]any{
+		"error":      err.Error(),
+		"kind":       "Status",
+		"apiVersion": "v1",
+		"status":     "Failure",
+		"message":    err.Error(),
+		"code":       status,
+	}
+	if reason != "" {
+		body["reason"] = reason
+	}
+	writeJSON(w, status, body)
+}
@@ -0,0 +1,259 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+func (s *Server) handleListServices(w http.ResponseWriter, r *http.Request) {
+	svcs, err := s.store.ListServices()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, svcs)
+}
+
+func (s *Server) handleGetService(w http.ResponseWriter, r *http.Request) {
+	svc, err := s.store.GetService(r.PathValue("namespace"), r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, svc)
+}
+
+func (s *Server) handlePutService(w http.ResponseWriter, r *http.Request) {
+	var svc api.Service
+	if err := json.NewDecoder(r.Body).Decode(&svc); err != nil {
+		writeError(w, badRequest("decoding service: %v", err))
+		return
+	}
+	svc.Meta.Namespace = r.PathValue("namespace")
+	svc.Meta.Name = r.PathValue("name")
+	if err := validateMeta("service", &svc.Meta); err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := validateServiceSpec(&svc.Spec); err != nil {
+		writeError(w, err)
+		return
+	}
+	// Status is controller-owned; a client update must not drop an
+	// allocated load balancer address.
+	var prior time.Time
+	if existing, err := s.store.GetService(svc.Meta.Namespace, svc.Meta.Name); err == nil {
+		svc.Status = existing.Status
+		prior = existing.Meta.CreatedAt
+	}
+	stampCreated(&svc.Meta, prior)
+	if err := s.store.PutService(&svc); err != nil {
+		writeError(w, err)
+		return
+	}
+	s.watches.notify("services", watchEvent{Type: "MODIFIED", Object: &svc})
+	writeJSON(w, http.StatusOK, &svc)
+}
+
+func (s *Server) handleDeleteService(w http.ResponseWriter, r *http.Request) {
+	namespace, name := r.PathValue("namespace"), r.PathValue("name")
+	svc, err := s.store.GetService(namespace, name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := s.store.DeleteService(namespace, name); err != nil {
+		writeError(w, err)
+		return
+	}
+	s.watches.notify("services", watchEvent{Type: "DELETED", Object: svc})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func validateServiceSpec(spec *api.ServiceSpec) error {
+	if len(spec.Ports) == 0 {
+		return badRequest("service needs at least one port")
+	}
+	names := make(map[string]bool, len(spec.Ports))
+	for _, p := range spec.Ports {
+		if p.Port < 1 || p.Port > 65535 {
+			return badRequest("port %d is out of range", p.Port)
+		}
+		if len(spec.Ports) > 1 && p.Name == "" {
+			return badRequest("multi-port services must name every port")
+		}
+		if p.Name != "" && names[p.Name] {
+			return badRequest("duplicate port name %q", p.Name)
+		}
+		names[p.Name] = true
+		switch p.Protocol {
+		case "", "TCP", "UDP":
+		default:
+			return badRequest("unknown protocol %q", p.Protocol)
+		}
+	}
+	switch spec.SessionAffinity {
+	case "", "None", api.AffinityClientIP:
+	default:
+		return badRequest("unknown session affinity %q", spec.SessionAffinity)
+	}
+	switch spec.LoadBalancing {
+	case "", api.BalanceRoundRobin, api.BalanceLeastConnections, api.BalanceRandom:
+	default:
+		return badRequest("unknown load balancing policy %q", spec.LoadBalancing)
+	}
+	switch spec.InternalTrafficPolicy {
+	case "", "Cluster", "Local":
+	default:
+		return badRequest("unknown internal traffic policy %q", spec.InternalTrafficPolicy)
+	}
+	switch spec.TopologyMode {
+	case "", api.TopologyPreferLocal:
+	default:
+		return badRequest("unknown topology mode %q", spec.TopologyMode)
+	}
+	switch spec.Type {
+	case "", api.ServiceLoadBalancer:
+	default:
+		return badRequest("unknown service type %q", spec.Type)
+	}
+	return nil
+}
+
+// handleServiceEndpoints resolves the service's backends: every
+// running pod matching the selector yields one endpoint per service
+// port, addressed at the pod's node. Named target ports resolve
+// against the port names of all the pod's containers, so a service can
+// front a multi-container pod whichever container serves the port.
+func (s *Server) handleServiceEndpoints(w http.ResponseWriter, r *http.Request) {
+	svc, err := s.store.GetService(r.PathValue("namespace"), r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	pods, err := s.store.ListPods()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	nodes, err := s.store.ListNodes()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, serviceEndpoints(svc, pods, nodes))
+}
+
+func serviceEndpoints(svc *api.Service, pods []*api.Pod, nodes []*api.Node) []api.Endpoint {
+	addrs := make(map[string]string, len(nodes))
+	for _, node := range nodes {
+		addrs[node.Meta.Name] = node.Status.Address
+	}
+	endpoints := []api.Endpoint{}
+	for _, pod := range pods {
+		if pod.Status.Phase != api.PodRunning || !selectorMatches(svc, pod) {
+			continue
+		}
+		nodeName := pod.Status.NodeName
+		if nodeName == "" {
+			nodeName = pod.Spec.NodeName
+		}
+		addr := addrs[nodeName]
+		if addr == "" {
+			continue
+		}
+		for _, sp := range svc.Spec.Ports {
+			port := resolveTargetPort(sp, pod)
+			if port == 0 {
+				continue
+			}
+			endpoints = append(endpoints, api.Endpoint{
+				IP:          addr,
+				NodeName:    nodeName,
+				PodName:     pod.Meta.Name,
+				Port:        port,
+				PortName:    sp.Name,
+				Protocol:    sp.Protocol,
+				AppProtocol: sp.AppProtocol,
+			})
+		}
+	}
+	return endpoints
+}
+
+func selectorMatches(svc *api.Service, pod *api.Pod) bool {
+	if len(svc.Spec.Selector) == 0 {
+		return false
+	}
+	ns := svc.Meta.Namespace
+	if ns == "" {
+		ns = "default"
+	}
+	podNS := pod.Meta.Namespace
+	if podNS == "" {
+		podNS = "default"
+	}
+	if ns != podNS {
+		return false
+	}
+	for k, v := range svc.Spec.Selector {
+		if pod.Meta.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveTargetPort turns a service port into the concrete,
+// node-reachable pod port: the published host port of the matching
+// container port, or the container port itself when the pod shares the
+// host network. Named target ports are looked up across every
+// container, init and main; zero means the pod exposes no matching
+// reachable port and contributes no endpoint.
+func resolveTargetPort(sp api.ServicePort, pod *api.Pod) int {
+	target := sp.TargetPort
+	containers := append(append([]api.Container(nil),
+		pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for _, c := range containers {
+		for _, cp := range c.Ports {
+			if cp.Name != "" && cp.Name == target {
+				return reachablePort(cp, pod)
+			}
+		}
+	}
+	want := sp.Port
+	if target != "" {
+		n := 0
+		for _, ch := range target {
+			if ch < '0' || ch > '9' {
+				return 0 // named port no container declares
+			}
+			n = n*10 + int(ch-'0')
+		}
+		want = n
+	}
+	for _, c := range containers {
+		for _, cp := range c.Ports {
+			if cp.ContainerPort == want {
+				return reachablePort(cp, pod)
+			}
+		}
+	}
+	if pod.Spec.HostNetwork {
+		return want
+	}
+	return 0
+}
+
+func reachablePort(cp api.ContainerPort, pod *api.Pod) int {
+	if cp.HostPort != 0 {
+		return cp.HostPort
+	}
+	if pod.Spec.HostNetwork {
+		return cp.ContainerPort
+	}
+	return 0
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/token"
+)
+
+// serviceAccountTokenTTL bounds minted tokens; workloads re-request
+// before expiry.
+const serviceAccountTokenTTL = 24 * time.Hour
+
+// SetTokenSigner installs the signer used to mint and verify service
+// account tokens.
+func (s *Server) SetTokenSigner(signer *token.Signer) {
+	s.signer = signer
+}
+
+func (s *Server) handleListServiceAccounts(w http.ResponseWriter, r *http.Request) {
+	sas, err := s.store.ListServiceAccounts()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, sas)
+}
+
+func (s *Server) handlePutServiceAccount(w http.ResponseWriter, r *http.Request) {
+	var sa api.ServiceAccount
+	if err := json.NewDecoder(r.Body).Decode(&sa); err != nil {
+		writeError(w, badRequest("decoding serviceaccount: %v", err))
+		return
+	}
+	sa.Meta.Namespace = r.PathValue("namespace")
+	sa.Meta.Name = r.PathValue("name")
+	if err := validateMeta("service account", &sa.Meta); err != nil {
+		writeError(w, err)
+		return
+	}
+	var prior time.Time
+	if old, err := s.store.GetServiceAccount(sa.Meta.Namespace, sa.Meta.Name); err == nil {
+		prior = old.Meta.CreatedAt
+	}
+	stampCreated(&sa.Meta, prior)
+	if err := s.store.PutServiceAccount(&sa); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &sa)
+}
+
+func (s *Server) handleDeleteServiceAccount(w http.ResponseWriter, r *http.Request) {
+	if err := s.store.DeleteServiceAccount(r.PathValue("namespace"), r.PathValue("name")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleServiceAccountToken mints a signed bearer token for the service
+// account, which the authenticator resolves back to the account's
+// identity until it expires.
+func (s *Server) handleServiceAccountToken(w http.ResponseWriter, r *http.Request) {
+	if s.signer == nil {
+		writeError(w, badRequest("token signing is not configured on this server"))
+		return
+	}
+	namespace, name := r.PathValue("namespace"), r.PathValue("name")
+	if _, err := s.store.GetServiceAccount(namespace, name); err != nil {
+		writeError(w, err)
+		return
+	}
+	tok, err := s.signer.Mint(api.ServiceAccountUsername(namespace, name), serviceAccountTokenTTL)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"token": tok})
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/logging"
+	"github.com/ptfpinho23/synthesis/pkg/store"
+)
+
+// usagePeriod is the chargeback granularity: one record per namespace
+// per hour. Record names encode the period start, so re-metering the
+// same period upserts rather than duplicates.
+const usagePeriod = time.Hour
+
+// usageMeter integrates the samples agents push into per-namespace
+// usage records and upserts them into the store, where — unlike the
+// ephemeral metrics cache — they survive restarts and accumulate for
+// as long as the operator keeps them.
+type usageMeter struct {
+	mu  sync.Mutex
+	log *slog.Logger
+
+	// open is the record being accumulated per namespace.
+	open map[string]*api.UsageRecord
+
+	// lastNet remembers each pod's cumulative network counters so a
+	// sample contributes only its delta; podNode mirrors the metrics
+	// cache's ownership tracking so deleted pods age out.
+	lastNet map[string]netCounters
+	podNode map[string]string
+}
+
+type netCounters struct{ rx, tx int64 }
+
+func newUsageMeter() *usageMeter {
+	return &usageMeter{
+		log:     logging.Component("usage"),
+		open:    make(map[string]*api.UsageRecord),
+		lastNet: make(map[string]netCounters),
+		podNode: make(map[string]string),
+	}
+}
+
+// usageRecordName encodes the period start, one record per namespace
+// per period.
+func usageRecordName(start time.Time) string {
+	return start.UTC().Format("20060102-1504")
+}
+
+// record folds one agent push into the open records and writes the
+// touched namespaces through to the store.
+func (m *usageMeter) record(st store.Store, node string, stats api.NodeStats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	start := now.Truncate(usagePeriod)
+
+	seen := make(map[string]bool, len(stats.Pods))
+	touched := make(map[string]bool)
+	for _, pm := range stats.Pods {
+		key := podMetricsKey(pm.Namespace, pm.Name)
+		seen[key] = true
+		m.podNode[key] = node
+		namespace := pm.Namespace
+		if namespace == "" {
+			namespace = "default"
+		}
+		rec := m.open[namespace]
+		if rec == nil || !rec.Start.Equal(start) {
+			rec = &api.UsageRecord{
+				Meta:  api.ObjectMeta{Namespace: namespace, Name: usageRecordName(start), CreatedAt: now},
+				Start: start,
+				End:   now,
+			}
+			m.open[namespace] = rec
+		}
+		usage := pm.Usage()
+		if pm.Window > 0 {
+			rec.CPUCoreSeconds += float64(usage[api.ResourceCPU]) / 1000 * pm.Window.Seconds()
+			rec.MemoryByteHours += float64(usage[api.ResourceMemory]) * pm.Window.Hours()
+		}
+		rec.NetworkRxBytes += netDelta(m.lastNet[key].rx, usage[api.ResourceNetworkRx])
+		rec.NetworkTxBytes += netDelta(m.lastNet[key].tx, usage[api.ResourceNetworkTx])
+		m.lastNet[key] = netCounters{rx: usage[api.ResourceNetworkRx], tx: usage[api.ResourceNetworkTx]}
+		rec.End = now
+		touched[namespace] = true
+	}
+	// Pods the node no longer reports are gone; drop their counters so
+	// a reused name starts a fresh delta.
+	for key, owner := range m.podNode {
+		if owner == node && !seen[key] {
+			delete(m.lastNet, key)
+			delete(m.podNode, key)
+		}
+	}
+	for namespace := range touched {
+		if err := st.PutUsageRecord(m.open[namespace]); err != nil {
+			m.log.Warn("writing usage record failed", "namespace", namespace, "err", err)
+		}
+	}
+}
+
+// netDelta is the bytes a cumulative counter advanced since last. A
+// counter that went backwards was reset by a container restart; the new
+// reading is then the whole delta.
+func netDelta(last, current int64) int64 {
+	if current < last {
+		return current
+	}
+	return current - last
+}
+
+// handleListUsageRecords serves every retained accounting record;
+// clients filter and aggregate, the same split as the other list
+// endpoints.
+func (s *Server) handleListUsageRecords(w http.ResponseWriter, r *http.Request) {
+	records, err := s.store.ListUsageRecords()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, records)
+}
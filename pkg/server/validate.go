@@ -0,0 +1,74 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// dns1123 is the DNS-1123 subdomain grammar every object name must
+// follow: lowercase alphanumerics and dashes, dot-separated.
+var dns1123 = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+// maxNameLength bounds names like DNS bounds hostnames.
+const maxNameLength = 253
+
+// validateMeta rejects metadata whose name (or namespace, when set) is
+// empty or not a DNS-1123 subdomain. An empty name would land the
+// object under the empty-string store key, invisible to every per-name
+// route.
+func validateMeta(kind string, meta *api.ObjectMeta) error {
+	if err := validateName(kind+" name", meta.Name); err != nil {
+		return err
+	}
+	if meta.Namespace != "" {
+		if err := validateName("namespace", meta.Namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateName(what, name string) error {
+	switch {
+	case name == "":
+		return badRequest("%s must not be empty", what)
+	case len(name) > maxNameLength:
+		return badRequest("%s %q is longer than %d characters", what, name, maxNameLength)
+	case !dns1123.MatchString(name):
+		return badRequest("%s %q must be a lowercase DNS-1123 subdomain", what, name)
+	}
+	return nil
+}
+
+// stampCreated makes creationTimestamp server-owned: a new object gets
+// the server clock, an update keeps the stored value, and whatever the
+// client sent is discarded. Edge nodes with drifting clocks were minting
+// objects whose age rendered negative and whose events sorted wrongly.
+func stampCreated(meta *api.ObjectMeta, prior time.Time) {
+	if prior.IsZero() {
+		meta.CreatedAt = time.Now()
+		return
+	}
+	meta.CreatedAt = prior
+}
+
+// completeName resolves create-request naming: an explicit name wins,
+// otherwise generateName gets a random suffix appended.
+func completeName(meta *api.ObjectMeta) {
+	if meta.Name != "" || meta.GenerateName == "" {
+		return
+	}
+	meta.Name = meta.GenerateName + generateSuffix()
+}
+
+func generateSuffix() string {
+	var b [3]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "000000"
+	}
+	return hex.EncodeToString(b[:])
+}
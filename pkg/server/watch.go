@@ -0,0 +1,56 @@
+package server
+
+import "sync"
+
+// watchEvent is one line of a watch stream.
+type watchEvent struct {
+	Type   string `json:"type"` // ADDED, MODIFIED or DELETED
+	Object any    `json:"object"`
+}
+
+// watchHub fans mutation events out to the watch streams subscribed to
+// each resource. Slow consumers lose events rather than blocking
+// writes; clients that care re-list and re-watch.
+type watchHub struct {
+	mu   sync.Mutex
+	subs map[string]map[chan watchEvent]bool
+
+	// tap, when set, sees every event regardless of subscribers; it is
+	// how the message bus mirrors resource changes. Must not block.
+	tap func(resource string, ev watchEvent)
+}
+
+func newWatchHub() *watchHub {
+	return &watchHub{subs: make(map[string]map[chan watchEvent]bool)}
+}
+
+func (h *watchHub) subscribe(resource string) chan watchEvent {
+	ch := make(chan watchEvent, 64)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.subs[resource] == nil {
+		h.subs[resource] = make(map[chan watchEvent]bool)
+	}
+	h.subs[resource][ch] = true
+	return ch
+}
+
+func (h *watchHub) unsubscribe(resource string, ch chan watchEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.subs[resource], ch)
+}
+
+func (h *watchHub) notify(resource string, ev watchEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.tap != nil {
+		h.tap(resource, ev)
+	}
+	for ch := range h.subs[resource] {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
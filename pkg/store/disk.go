@@ -0,0 +1,583 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// DiskStore is a MemoryStore that survives restarts: every mutation is
+// applied in memory immediately and queued for a write-behind flush to
+// one JSON file per object. Rapid updates to the same object within a
+// flush window — status touches, lease renewals — coalesce into a
+// single file write. Call Flush before exiting to drain the queue.
+type DiskStore struct {
+	*MemoryStore
+	dir string
+
+	// FlushInterval is how long a write may sit in the queue before it
+	// reaches disk, and therefore the most state a crash can lose.
+	FlushInterval time.Duration
+
+	mu      sync.Mutex
+	pending map[string]json.RawMessage // encoded object; nil means delete
+}
+
+// NewDiskStore opens (or creates) the state directory and loads every
+// persisted object into memory. Call Start to begin the flush loop.
+func NewDiskStore(dir string) (*DiskStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	ds := &DiskStore{
+		MemoryStore:   NewMemoryStore(),
+		dir:           dir,
+		FlushInterval: 250 * time.Millisecond,
+		pending:       make(map[string]json.RawMessage),
+	}
+	if err := ds.load(); err != nil {
+		return nil, fmt.Errorf("loading state from %s: %w", dir, err)
+	}
+	return ds, nil
+}
+
+// Start begins the write-behind flush loop.
+func (ds *DiskStore) Start() { go ds.run() }
+
+func (ds *DiskStore) run() {
+	for range time.Tick(ds.FlushInterval) {
+		if err := ds.Flush(); err != nil {
+			logger.Error("flushing state to disk failed", "err", err)
+		}
+	}
+}
+
+// Flush writes every queued object to disk now. It is what shutdown
+// paths call so nothing acknowledged is lost.
+func (ds *DiskStore) Flush() error {
+	ds.mu.Lock()
+	if len(ds.pending) == 0 {
+		ds.mu.Unlock()
+		return nil
+	}
+	batch := ds.pending
+	ds.pending = make(map[string]json.RawMessage)
+	ds.mu.Unlock()
+
+	var firstErr error
+	for key, data := range batch {
+		if err := ds.writeFile(key, data); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (ds *DiskStore) writeFile(key string, data json.RawMessage) error {
+	name := filepath.Join(ds.dir, filepath.FromSlash(key)+".json")
+	if data == nil {
+		if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o700); err != nil {
+		return err
+	}
+	// Write-then-rename so a crash mid-write never leaves a truncated
+	// object behind.
+	tmp := name + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
+
+// enqueue queues the object (or, with a nil object, its deletion) for
+// the next flush, replacing any write already queued under the key.
+func (ds *DiskStore) enqueue(key string, obj any) {
+	var data json.RawMessage
+	if obj != nil {
+		var err error
+		if data, err = json.MarshalIndent(obj, "", "  "); err != nil {
+			logger.Error("encoding object for disk failed", "key", key, "err", err)
+			return
+		}
+	}
+	ds.mu.Lock()
+	ds.pending[key] = data
+	ds.mu.Unlock()
+}
+
+// objectKey is the object's path below the state directory, without
+// the .json suffix.
+func objectKey(kind, namespace, name string) string {
+	if namespace == "" {
+		return path.Join(kind, name)
+	}
+	return path.Join(kind, namespace, name)
+}
+
+// load replays every persisted object into the in-memory store. The
+// top-level directory names the kind; unknown kinds and undecodable
+// files fail the load rather than silently dropping state.
+func (ds *DiskStore) load() error {
+	return filepath.WalkDir(ds.dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(name, ".json") {
+			return err
+		}
+		rel, err := filepath.Rel(ds.dir, name)
+		if err != nil {
+			return err
+		}
+		kind := filepath.ToSlash(rel)
+		kind, _, _ = strings.Cut(kind, "/")
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		if err := ds.loadObject(kind, data); err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+		return nil
+	})
+}
+
+func (ds *DiskStore) loadObject(kind string, data []byte) error {
+	switch kind {
+	case "nodes":
+		var node api.Node
+		if err := json.Unmarshal(data, &node); err != nil {
+			return err
+		}
+		return ds.MemoryStore.PutNode(&node)
+	case "pods":
+		var pod api.Pod
+		if err := json.Unmarshal(data, &pod); err != nil {
+			return err
+		}
+		return ds.MemoryStore.PutPod(&pod)
+	case "poddisruptionbudgets":
+		var pdb api.PodDisruptionBudget
+		if err := json.Unmarshal(data, &pdb); err != nil {
+			return err
+		}
+		return ds.MemoryStore.PutPodDisruptionBudget(&pdb)
+	case "leases":
+		var lease api.Lease
+		if err := json.Unmarshal(data, &lease); err != nil {
+			return err
+		}
+		return ds.MemoryStore.PutLease(&lease)
+	case "roles":
+		var role api.Role
+		if err := json.Unmarshal(data, &role); err != nil {
+			return err
+		}
+		return ds.MemoryStore.PutRole(&role)
+	case "rolebindings":
+		var binding api.RoleBinding
+		if err := json.Unmarshal(data, &binding); err != nil {
+			return err
+		}
+		return ds.MemoryStore.PutRoleBinding(&binding)
+	case "serviceaccounts":
+		var sa api.ServiceAccount
+		if err := json.Unmarshal(data, &sa); err != nil {
+			return err
+		}
+		return ds.MemoryStore.PutServiceAccount(&sa)
+	case "namespaces":
+		var ns api.Namespace
+		if err := json.Unmarshal(data, &ns); err != nil {
+			return err
+		}
+		return ds.MemoryStore.PutNamespace(&ns)
+	case "priorityclasses":
+		var pc api.PriorityClass
+		if err := json.Unmarshal(data, &pc); err != nil {
+			return err
+		}
+		return ds.MemoryStore.PutPriorityClass(&pc)
+	case "secrets":
+		var secret api.Secret
+		if err := json.Unmarshal(data, &secret); err != nil {
+			return err
+		}
+		return ds.MemoryStore.PutSecret(&secret)
+	case "services":
+		var svc api.Service
+		if err := json.Unmarshal(data, &svc); err != nil {
+			return err
+		}
+		return ds.MemoryStore.PutService(&svc)
+	case "ingresses":
+		var ing api.Ingress
+		if err := json.Unmarshal(data, &ing); err != nil {
+			return err
+		}
+		return ds.MemoryStore.PutIngress(&ing)
+	case "gateways":
+		var gw api.Gateway
+		if err := json.Unmarshal(data, &gw); err != nil {
+			return err
+		}
+		return ds.MemoryStore.PutGateway(&gw)
+	case "httproutes":
+		var route api.HTTPRoute
+		if err := json.Unmarshal(data, &route); err != nil {
+			return err
+		}
+		return ds.MemoryStore.PutHTTPRoute(&route)
+	case "workloads":
+		var wl api.Workload
+		if err := json.Unmarshal(data, &wl); err != nil {
+			return err
+		}
+		return ds.MemoryStore.PutWorkload(&wl)
+	case "usagerecords":
+		var rec api.UsageRecord
+		if err := json.Unmarshal(data, &rec); err != nil {
+			return err
+		}
+		return ds.MemoryStore.PutUsageRecord(&rec)
+	case "customresourcedefinitions":
+		var crd api.CustomResourceDefinition
+		if err := json.Unmarshal(data, &crd); err != nil {
+			return err
+		}
+		return ds.MemoryStore.PutCRD(&crd)
+	case "customobjects":
+		var obj api.CustomObject
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		return ds.MemoryStore.PutCustomObject(&obj)
+	}
+	return fmt.Errorf("unknown kind %q", kind)
+}
+
+// podNamespace mirrors podKey's defaulting so a pod written without a
+// namespace lands in the same file as one written with "default".
+func podNamespace(pod *api.Pod) string {
+	if pod.Meta.Namespace == "" {
+		return "default"
+	}
+	return pod.Meta.Namespace
+}
+
+func (ds *DiskStore) PutNode(node *api.Node) error {
+	if err := ds.MemoryStore.PutNode(node); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("nodes", "", node.Meta.Name), node)
+	return nil
+}
+
+func (ds *DiskStore) DeleteNode(name string) error {
+	if err := ds.MemoryStore.DeleteNode(name); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("nodes", "", name), nil)
+	return nil
+}
+
+func (ds *DiskStore) PutPod(pod *api.Pod) error {
+	if err := ds.MemoryStore.PutPod(pod); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("pods", podNamespace(pod), pod.Meta.Name), pod)
+	return nil
+}
+
+func (ds *DiskStore) DeletePod(namespace, name string) error {
+	if err := ds.MemoryStore.DeletePod(namespace, name); err != nil {
+		return err
+	}
+	if namespace == "" {
+		namespace = "default"
+	}
+	ds.enqueue(objectKey("pods", namespace, name), nil)
+	return nil
+}
+
+func (ds *DiskStore) PutPodDisruptionBudget(pdb *api.PodDisruptionBudget) error {
+	if err := ds.MemoryStore.PutPodDisruptionBudget(pdb); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("poddisruptionbudgets", pdb.Meta.Namespace, pdb.Meta.Name), pdb)
+	return nil
+}
+
+func (ds *DiskStore) DeletePodDisruptionBudget(namespace, name string) error {
+	if err := ds.MemoryStore.DeletePodDisruptionBudget(namespace, name); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("poddisruptionbudgets", namespace, name), nil)
+	return nil
+}
+
+func (ds *DiskStore) PutLease(lease *api.Lease) error {
+	if err := ds.MemoryStore.PutLease(lease); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("leases", lease.Meta.Namespace, lease.Meta.Name), lease)
+	return nil
+}
+
+func (ds *DiskStore) DeleteLease(namespace, name string) error {
+	if err := ds.MemoryStore.DeleteLease(namespace, name); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("leases", namespace, name), nil)
+	return nil
+}
+
+func (ds *DiskStore) PutRole(role *api.Role) error {
+	if err := ds.MemoryStore.PutRole(role); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("roles", role.Meta.Namespace, role.Meta.Name), role)
+	return nil
+}
+
+func (ds *DiskStore) DeleteRole(namespace, name string) error {
+	if err := ds.MemoryStore.DeleteRole(namespace, name); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("roles", namespace, name), nil)
+	return nil
+}
+
+func (ds *DiskStore) PutRoleBinding(binding *api.RoleBinding) error {
+	if err := ds.MemoryStore.PutRoleBinding(binding); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("rolebindings", binding.Meta.Namespace, binding.Meta.Name), binding)
+	return nil
+}
+
+func (ds *DiskStore) DeleteRoleBinding(namespace, name string) error {
+	if err := ds.MemoryStore.DeleteRoleBinding(namespace, name); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("rolebindings", namespace, name), nil)
+	return nil
+}
+
+func (ds *DiskStore) PutServiceAccount(sa *api.ServiceAccount) error {
+	if err := ds.MemoryStore.PutServiceAccount(sa); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("serviceaccounts", sa.Meta.Namespace, sa.Meta.Name), sa)
+	return nil
+}
+
+func (ds *DiskStore) DeleteServiceAccount(namespace, name string) error {
+	if err := ds.MemoryStore.DeleteServiceAccount(namespace, name); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("serviceaccounts", namespace, name), nil)
+	return nil
+}
+
+func (ds *DiskStore) PutNamespace(ns *api.Namespace) error {
+	if err := ds.MemoryStore.PutNamespace(ns); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("namespaces", "", ns.Meta.Name), ns)
+	return nil
+}
+
+func (ds *DiskStore) DeleteNamespace(name string) error {
+	if err := ds.MemoryStore.DeleteNamespace(name); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("namespaces", "", name), nil)
+	return nil
+}
+
+func (ds *DiskStore) PutSecret(secret *api.Secret) error {
+	if err := ds.MemoryStore.PutSecret(secret); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("secrets", secret.Meta.Namespace, secret.Meta.Name), secret)
+	return nil
+}
+
+func (ds *DiskStore) DeleteSecret(namespace, name string) error {
+	if err := ds.MemoryStore.DeleteSecret(namespace, name); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("secrets", namespace, name), nil)
+	return nil
+}
+
+func (ds *DiskStore) PutService(svc *api.Service) error {
+	if err := ds.MemoryStore.PutService(svc); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("services", svc.Meta.Namespace, svc.Meta.Name), svc)
+	return nil
+}
+
+func (ds *DiskStore) DeleteService(namespace, name string) error {
+	if err := ds.MemoryStore.DeleteService(namespace, name); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("services", namespace, name), nil)
+	return nil
+}
+
+func (ds *DiskStore) PutIngress(ing *api.Ingress) error {
+	if err := ds.MemoryStore.PutIngress(ing); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("ingresses", ing.Meta.Namespace, ing.Meta.Name), ing)
+	return nil
+}
+
+func (ds *DiskStore) DeleteIngress(namespace, name string) error {
+	if err := ds.MemoryStore.DeleteIngress(namespace, name); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("ingresses", namespace, name), nil)
+	return nil
+}
+
+func (ds *DiskStore) PutGateway(gw *api.Gateway) error {
+	if err := ds.MemoryStore.PutGateway(gw); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("gateways", gw.Meta.Namespace, gw.Meta.Name), gw)
+	return nil
+}
+
+func (ds *DiskStore) DeleteGateway(namespace, name string) error {
+	if err := ds.MemoryStore.DeleteGateway(namespace, name); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("gateways", namespace, name), nil)
+	return nil
+}
+
+func (ds *DiskStore) PutHTTPRoute(route *api.HTTPRoute) error {
+	if err := ds.MemoryStore.PutHTTPRoute(route); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("httproutes", route.Meta.Namespace, route.Meta.Name), route)
+	return nil
+}
+
+func (ds *DiskStore) DeleteHTTPRoute(namespace, name string) error {
+	if err := ds.MemoryStore.DeleteHTTPRoute(namespace, name); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("httproutes", namespace, name), nil)
+	return nil
+}
+
+func (ds *DiskStore) PutWorkload(w *api.Workload) error {
+	if err := ds.MemoryStore.PutWorkload(w); err != nil {
+		return err
+	}
+	namespace := w.Meta.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+	ds.enqueue(objectKey("workloads", namespace, w.Meta.Name), w)
+	return nil
+}
+
+func (ds *DiskStore) DeleteWorkload(namespace, name string) error {
+	if err := ds.MemoryStore.DeleteWorkload(namespace, name); err != nil {
+		return err
+	}
+	if namespace == "" {
+		namespace = "default"
+	}
+	ds.enqueue(objectKey("workloads", namespace, name), nil)
+	return nil
+}
+
+func (ds *DiskStore) PutUsageRecord(rec *api.UsageRecord) error {
+	if err := ds.MemoryStore.PutUsageRecord(rec); err != nil {
+		return err
+	}
+	namespace := rec.Meta.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+	ds.enqueue(objectKey("usagerecords", namespace, rec.Meta.Name), rec)
+	return nil
+}
+
+func (ds *DiskStore) DeleteUsageRecord(namespace, name string) error {
+	if err := ds.MemoryStore.DeleteUsageRecord(namespace, name); err != nil {
+		return err
+	}
+	if namespace == "" {
+		namespace = "default"
+	}
+	ds.enqueue(objectKey("usagerecords", namespace, name), nil)
+	return nil
+}
+
+func (ds *DiskStore) PutCRD(crd *api.CustomResourceDefinition) error {
+	if err := ds.MemoryStore.PutCRD(crd); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("customresourcedefinitions", "", crd.Meta.Name), crd)
+	return nil
+}
+
+func (ds *DiskStore) DeleteCRD(name string) error {
+	if err := ds.MemoryStore.DeleteCRD(name); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("customresourcedefinitions", "", name), nil)
+	return nil
+}
+
+// Custom object keys embed the group/plural, so each custom type gets
+// its own directory tree under customobjects/.
+func (ds *DiskStore) PutCustomObject(obj *api.CustomObject) error {
+	if err := ds.MemoryStore.PutCustomObject(obj); err != nil {
+		return err
+	}
+	ds.enqueue("customobjects/"+customKey(obj.Resource, obj.Meta.Namespace, obj.Meta.Name), obj)
+	return nil
+}
+
+func (ds *DiskStore) DeleteCustomObject(resource, namespace, name string) error {
+	if err := ds.MemoryStore.DeleteCustomObject(resource, namespace, name); err != nil {
+		return err
+	}
+	ds.enqueue("customobjects/"+customKey(resource, namespace, name), nil)
+	return nil
+}
+
+func (ds *DiskStore) PutPriorityClass(pc *api.PriorityClass) error {
+	if err := ds.MemoryStore.PutPriorityClass(pc); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("priorityclasses", "", pc.Meta.Name), pc)
+	return nil
+}
+
+func (ds *DiskStore) DeletePriorityClass(name string) error {
+	if err := ds.MemoryStore.DeletePriorityClass(name); err != nil {
+		return err
+	}
+	ds.enqueue(objectKey("priorityclasses", "", name), nil)
+	return nil
+}
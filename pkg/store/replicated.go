@@ -0,0 +1,460 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+	"github.com/ptfpinho23/synthesis/pkg/logging"
+	"github.com/ptfpinho23/synthesis/pkg/raft"
+)
+
+// command is one replicated mutation. Op names a handler in apply; Data
+// holds the JSON-encoded object or key.
+type command struct {
+	Op   string          `json:"op"`
+	Data json.RawMessage `json:"data"`
+}
+
+type deleteKey struct {
+	Resource  string `json:"resource,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+var logger = logging.Component("store")
+
+// ReplicatedStore funnels every mutation through a raft log so several
+// synthesis-server instances converge on the same state. Reads are
+// served from the local copy; writes fail on followers with
+// raft.ErrNotLeader so callers can redirect.
+type ReplicatedStore struct {
+	local *MemoryStore
+	node  *raft.Node
+}
+
+// NewReplicatedStore builds a store replicated across the given peers.
+// id is this member's name; peers maps the other members to their raft
+// URLs. Mount Node().Handler() on the raft listener.
+func NewReplicatedStore(id string, peers map[string]string) *ReplicatedStore {
+	rs := &ReplicatedStore{local: NewMemoryStore()}
+	rs.node = raft.NewNode(id, peers, rs.apply)
+	return rs
+}
+
+// Node exposes the underlying raft member for its HTTP handler and
+// leadership queries.
+func (rs *ReplicatedStore) Node() *raft.Node { return rs.node }
+
+// Start begins raft elections and replication.
+func (rs *ReplicatedStore) Start() { rs.node.Run() }
+
+func (rs *ReplicatedStore) propose(op string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	cmd, err := json.Marshal(command{Op: op, Data: data})
+	if err != nil {
+		return err
+	}
+	return rs.node.Propose(cmd)
+}
+
+// apply is the raft FSM: it replays committed commands onto the local
+// store. Decode failures are logged, not returned — the log is already
+// committed and every replica must handle it identically.
+func (rs *ReplicatedStore) apply(cmd []byte) {
+	var c command
+	if err := json.Unmarshal(cmd, &c); err != nil {
+		logger.Error("undecodable command", "err", err)
+		return
+	}
+	var err error
+	switch c.Op {
+	case "putNode":
+		var node api.Node
+		if err = json.Unmarshal(c.Data, &node); err == nil {
+			err = rs.local.PutNode(&node)
+		}
+	case "deleteNode":
+		var k deleteKey
+		if err = json.Unmarshal(c.Data, &k); err == nil {
+			err = rs.local.DeleteNode(k.Name)
+		}
+	case "putPod":
+		var pod api.Pod
+		if err = json.Unmarshal(c.Data, &pod); err == nil {
+			err = rs.local.PutPod(&pod)
+		}
+	case "deletePod":
+		var k deleteKey
+		if err = json.Unmarshal(c.Data, &k); err == nil {
+			err = rs.local.DeletePod(k.Namespace, k.Name)
+		}
+	case "putPDB":
+		var pdb api.PodDisruptionBudget
+		if err = json.Unmarshal(c.Data, &pdb); err == nil {
+			err = rs.local.PutPodDisruptionBudget(&pdb)
+		}
+	case "deletePDB":
+		var k deleteKey
+		if err = json.Unmarshal(c.Data, &k); err == nil {
+			err = rs.local.DeletePodDisruptionBudget(k.Namespace, k.Name)
+		}
+	case "putLease":
+		var lease api.Lease
+		if err = json.Unmarshal(c.Data, &lease); err == nil {
+			err = rs.local.PutLease(&lease)
+		}
+	case "deleteLease":
+		var k deleteKey
+		if err = json.Unmarshal(c.Data, &k); err == nil {
+			err = rs.local.DeleteLease(k.Namespace, k.Name)
+		}
+	case "putRole":
+		var role api.Role
+		if err = json.Unmarshal(c.Data, &role); err == nil {
+			err = rs.local.PutRole(&role)
+		}
+	case "deleteRole":
+		var k deleteKey
+		if err = json.Unmarshal(c.Data, &k); err == nil {
+			err = rs.local.DeleteRole(k.Namespace, k.Name)
+		}
+	case "putRoleBinding":
+		var binding api.RoleBinding
+		if err = json.Unmarshal(c.Data, &binding); err == nil {
+			err = rs.local.PutRoleBinding(&binding)
+		}
+	case "deleteRoleBinding":
+		var k deleteKey
+		if err = json.Unmarshal(c.Data, &k); err == nil {
+			err = rs.local.DeleteRoleBinding(k.Namespace, k.Name)
+		}
+	case "putServiceAccount":
+		var sa api.ServiceAccount
+		if err = json.Unmarshal(c.Data, &sa); err == nil {
+			err = rs.local.PutServiceAccount(&sa)
+		}
+	case "deleteServiceAccount":
+		var k deleteKey
+		if err = json.Unmarshal(c.Data, &k); err == nil {
+			err = rs.local.DeleteServiceAccount(k.Namespace, k.Name)
+		}
+	case "putNamespace":
+		var ns api.Namespace
+		if err = json.Unmarshal(c.Data, &ns); err == nil {
+			err = rs.local.PutNamespace(&ns)
+		}
+	case "deleteNamespace":
+		var k deleteKey
+		if err = json.Unmarshal(c.Data, &k); err == nil {
+			err = rs.local.DeleteNamespace(k.Name)
+		}
+	case "putPriorityClass":
+		var pc api.PriorityClass
+		if err = json.Unmarshal(c.Data, &pc); err == nil {
+			err = rs.local.PutPriorityClass(&pc)
+		}
+	case "deletePriorityClass":
+		var k deleteKey
+		if err = json.Unmarshal(c.Data, &k); err == nil {
+			err = rs.local.DeletePriorityClass(k.Name)
+		}
+	case "putWorkload":
+		var wl api.Workload
+		if err = json.Unmarshal(c.Data, &wl); err == nil {
+			err = rs.local.PutWorkload(&wl)
+		}
+	case "deleteWorkload":
+		var k deleteKey
+		if err = json.Unmarshal(c.Data, &k); err == nil {
+			err = rs.local.DeleteWorkload(k.Namespace, k.Name)
+		}
+	case "putUsageRecord":
+		var rec api.UsageRecord
+		if err = json.Unmarshal(c.Data, &rec); err == nil {
+			err = rs.local.PutUsageRecord(&rec)
+		}
+	case "deleteUsageRecord":
+		var k deleteKey
+		if err = json.Unmarshal(c.Data, &k); err == nil {
+			err = rs.local.DeleteUsageRecord(k.Namespace, k.Name)
+		}
+	case "putCRD":
+		var crd api.CustomResourceDefinition
+		if err = json.Unmarshal(c.Data, &crd); err == nil {
+			err = rs.local.PutCRD(&crd)
+		}
+	case "deleteCRD":
+		var k deleteKey
+		if err = json.Unmarshal(c.Data, &k); err == nil {
+			err = rs.local.DeleteCRD(k.Name)
+		}
+	case "putCustomObject":
+		var obj api.CustomObject
+		if err = json.Unmarshal(c.Data, &obj); err == nil {
+			err = rs.local.PutCustomObject(&obj)
+		}
+	case "deleteCustomObject":
+		var k deleteKey
+		if err = json.Unmarshal(c.Data, &k); err == nil {
+			err = rs.local.DeleteCustomObject(k.Resource, k.Namespace, k.Name)
+		}
+	case "putSecret":
+		var secret api.Secret
+		if err = json.Unmarshal(c.Data, &secret); err == nil {
+			err = rs.local.PutSecret(&secret)
+		}
+	case "deleteSecret":
+		var k deleteKey
+		if err = json.Unmarshal(c.Data, &k); err == nil {
+			err = rs.local.DeleteSecret(k.Namespace, k.Name)
+		}
+	case "putService":
+		var svc api.Service
+		if err = json.Unmarshal(c.Data, &svc); err == nil {
+			err = rs.local.PutService(&svc)
+		}
+	case "deleteService":
+		var k deleteKey
+		if err = json.Unmarshal(c.Data, &k); err == nil {
+			err = rs.local.DeleteService(k.Namespace, k.Name)
+		}
+	case "putIngress":
+		var ing api.Ingress
+		if err = json.Unmarshal(c.Data, &ing); err == nil {
+			err = rs.local.PutIngress(&ing)
+		}
+	case "deleteIngress":
+		var k deleteKey
+		if err = json.Unmarshal(c.Data, &k); err == nil {
+			err = rs.local.DeleteIngress(k.Namespace, k.Name)
+		}
+	case "putGateway":
+		var gw api.Gateway
+		if err = json.Unmarshal(c.Data, &gw); err == nil {
+			err = rs.local.PutGateway(&gw)
+		}
+	case "deleteGateway":
+		var k deleteKey
+		if err = json.Unmarshal(c.Data, &k); err == nil {
+			err = rs.local.DeleteGateway(k.Namespace, k.Name)
+		}
+	case "putHTTPRoute":
+		var route api.HTTPRoute
+		if err = json.Unmarshal(c.Data, &route); err == nil {
+			err = rs.local.PutHTTPRoute(&route)
+		}
+	case "deleteHTTPRoute":
+		var k deleteKey
+		if err = json.Unmarshal(c.Data, &k); err == nil {
+			err = rs.local.DeleteHTTPRoute(k.Namespace, k.Name)
+		}
+	default:
+		err = fmt.Errorf("unknown op %q", c.Op)
+	}
+	if err != nil {
+		logger.Error("applying command failed", "op", c.Op, "err", err)
+	}
+}
+
+func (rs *ReplicatedStore) GetNode(name string) (*api.Node, error) { return rs.local.GetNode(name) }
+func (rs *ReplicatedStore) ListNodes() ([]*api.Node, error)        { return rs.local.ListNodes() }
+func (rs *ReplicatedStore) PutNode(node *api.Node) error           { return rs.propose("putNode", node) }
+func (rs *ReplicatedStore) DeleteNode(name string) error {
+	return rs.propose("deleteNode", deleteKey{Name: name})
+}
+
+func (rs *ReplicatedStore) GetPod(namespace, name string) (*api.Pod, error) {
+	return rs.local.GetPod(namespace, name)
+}
+func (rs *ReplicatedStore) ListPods() ([]*api.Pod, error) { return rs.local.ListPods() }
+func (rs *ReplicatedStore) PutPod(pod *api.Pod) error     { return rs.propose("putPod", pod) }
+func (rs *ReplicatedStore) DeletePod(namespace, name string) error {
+	return rs.propose("deletePod", deleteKey{Namespace: namespace, Name: name})
+}
+
+func (rs *ReplicatedStore) PutPodDisruptionBudget(pdb *api.PodDisruptionBudget) error {
+	return rs.propose("putPDB", pdb)
+}
+func (rs *ReplicatedStore) DeletePodDisruptionBudget(namespace, name string) error {
+	return rs.propose("deletePDB", deleteKey{Namespace: namespace, Name: name})
+}
+func (rs *ReplicatedStore) ListPodDisruptionBudgets() ([]*api.PodDisruptionBudget, error) {
+	return rs.local.ListPodDisruptionBudgets()
+}
+
+func (rs *ReplicatedStore) GetLease(namespace, name string) (*api.Lease, error) {
+	return rs.local.GetLease(namespace, name)
+}
+func (rs *ReplicatedStore) PutLease(lease *api.Lease) error { return rs.propose("putLease", lease) }
+func (rs *ReplicatedStore) DeleteLease(namespace, name string) error {
+	return rs.propose("deleteLease", deleteKey{Namespace: namespace, Name: name})
+}
+func (rs *ReplicatedStore) ListLeases() ([]*api.Lease, error) { return rs.local.ListLeases() }
+
+func (rs *ReplicatedStore) GetRole(namespace, name string) (*api.Role, error) {
+	return rs.local.GetRole(namespace, name)
+}
+func (rs *ReplicatedStore) PutRole(role *api.Role) error { return rs.propose("putRole", role) }
+func (rs *ReplicatedStore) DeleteRole(namespace, name string) error {
+	return rs.propose("deleteRole", deleteKey{Namespace: namespace, Name: name})
+}
+func (rs *ReplicatedStore) ListRoles() ([]*api.Role, error) { return rs.local.ListRoles() }
+
+func (rs *ReplicatedStore) PutRoleBinding(binding *api.RoleBinding) error {
+	return rs.propose("putRoleBinding", binding)
+}
+func (rs *ReplicatedStore) DeleteRoleBinding(namespace, name string) error {
+	return rs.propose("deleteRoleBinding", deleteKey{Namespace: namespace, Name: name})
+}
+func (rs *ReplicatedStore) ListRoleBindings() ([]*api.RoleBinding, error) {
+	return rs.local.ListRoleBindings()
+}
+
+func (rs *ReplicatedStore) GetServiceAccount(namespace, name string) (*api.ServiceAccount, error) {
+	return rs.local.GetServiceAccount(namespace, name)
+}
+func (rs *ReplicatedStore) PutServiceAccount(sa *api.ServiceAccount) error {
+	return rs.propose("putServiceAccount", sa)
+}
+func (rs *ReplicatedStore) DeleteServiceAccount(namespace, name string) error {
+	return rs.propose("deleteServiceAccount", deleteKey{Namespace: namespace, Name: name})
+}
+func (rs *ReplicatedStore) ListServiceAccounts() ([]*api.ServiceAccount, error) {
+	return rs.local.ListServiceAccounts()
+}
+
+func (rs *ReplicatedStore) GetNamespace(name string) (*api.Namespace, error) {
+	return rs.local.GetNamespace(name)
+}
+func (rs *ReplicatedStore) PutNamespace(ns *api.Namespace) error {
+	return rs.propose("putNamespace", ns)
+}
+func (rs *ReplicatedStore) DeleteNamespace(name string) error {
+	return rs.propose("deleteNamespace", deleteKey{Name: name})
+}
+func (rs *ReplicatedStore) ListNamespaces() ([]*api.Namespace, error) {
+	return rs.local.ListNamespaces()
+}
+
+func (rs *ReplicatedStore) GetSecret(namespace, name string) (*api.Secret, error) {
+	return rs.local.GetSecret(namespace, name)
+}
+func (rs *ReplicatedStore) PutSecret(secret *api.Secret) error {
+	return rs.propose("putSecret", secret)
+}
+func (rs *ReplicatedStore) DeleteSecret(namespace, name string) error {
+	return rs.propose("deleteSecret", deleteKey{Namespace: namespace, Name: name})
+}
+func (rs *ReplicatedStore) ListSecrets() ([]*api.Secret, error) { return rs.local.ListSecrets() }
+
+func (rs *ReplicatedStore) GetService(namespace, name string) (*api.Service, error) {
+	return rs.local.GetService(namespace, name)
+}
+func (rs *ReplicatedStore) PutService(svc *api.Service) error {
+	return rs.propose("putService", svc)
+}
+func (rs *ReplicatedStore) DeleteService(namespace, name string) error {
+	return rs.propose("deleteService", deleteKey{Namespace: namespace, Name: name})
+}
+func (rs *ReplicatedStore) ListServices() ([]*api.Service, error) { return rs.local.ListServices() }
+
+func (rs *ReplicatedStore) GetIngress(namespace, name string) (*api.Ingress, error) {
+	return rs.local.GetIngress(namespace, name)
+}
+func (rs *ReplicatedStore) PutIngress(ing *api.Ingress) error {
+	return rs.propose("putIngress", ing)
+}
+func (rs *ReplicatedStore) DeleteIngress(namespace, name string) error {
+	return rs.propose("deleteIngress", deleteKey{Namespace: namespace, Name: name})
+}
+func (rs *ReplicatedStore) ListIngresses() ([]*api.Ingress, error) { return rs.local.ListIngresses() }
+
+func (rs *ReplicatedStore) GetGateway(namespace, name string) (*api.Gateway, error) {
+	return rs.local.GetGateway(namespace, name)
+}
+func (rs *ReplicatedStore) PutGateway(gw *api.Gateway) error {
+	return rs.propose("putGateway", gw)
+}
+func (rs *ReplicatedStore) DeleteGateway(namespace, name string) error {
+	return rs.propose("deleteGateway", deleteKey{Namespace: namespace, Name: name})
+}
+func (rs *ReplicatedStore) ListGateways() ([]*api.Gateway, error) { return rs.local.ListGateways() }
+
+func (rs *ReplicatedStore) GetHTTPRoute(namespace, name string) (*api.HTTPRoute, error) {
+	return rs.local.GetHTTPRoute(namespace, name)
+}
+func (rs *ReplicatedStore) PutHTTPRoute(route *api.HTTPRoute) error {
+	return rs.propose("putHTTPRoute", route)
+}
+func (rs *ReplicatedStore) DeleteHTTPRoute(namespace, name string) error {
+	return rs.propose("deleteHTTPRoute", deleteKey{Namespace: namespace, Name: name})
+}
+func (rs *ReplicatedStore) ListHTTPRoutes() ([]*api.HTTPRoute, error) {
+	return rs.local.ListHTTPRoutes()
+}
+
+func (rs *ReplicatedStore) GetPriorityClass(name string) (*api.PriorityClass, error) {
+	return rs.local.GetPriorityClass(name)
+}
+func (rs *ReplicatedStore) PutPriorityClass(pc *api.PriorityClass) error {
+	return rs.propose("putPriorityClass", pc)
+}
+func (rs *ReplicatedStore) DeletePriorityClass(name string) error {
+	return rs.propose("deletePriorityClass", deleteKey{Name: name})
+}
+func (rs *ReplicatedStore) ListPriorityClasses() ([]*api.PriorityClass, error) {
+	return rs.local.ListPriorityClasses()
+}
+
+func (rs *ReplicatedStore) GetWorkload(namespace, name string) (*api.Workload, error) {
+	return rs.local.GetWorkload(namespace, name)
+}
+func (rs *ReplicatedStore) PutWorkload(w *api.Workload) error {
+	return rs.propose("putWorkload", w)
+}
+func (rs *ReplicatedStore) DeleteWorkload(namespace, name string) error {
+	return rs.propose("deleteWorkload", deleteKey{Namespace: namespace, Name: name})
+}
+func (rs *ReplicatedStore) ListWorkloads() ([]*api.Workload, error) {
+	return rs.local.ListWorkloads()
+}
+
+func (rs *ReplicatedStore) PutUsageRecord(rec *api.UsageRecord) error {
+	return rs.propose("putUsageRecord", rec)
+}
+func (rs *ReplicatedStore) DeleteUsageRecord(namespace, name string) error {
+	return rs.propose("deleteUsageRecord", deleteKey{Namespace: namespace, Name: name})
+}
+func (rs *ReplicatedStore) ListUsageRecords() ([]*api.UsageRecord, error) {
+	return rs.local.ListUsageRecords()
+}
+
+func (rs *ReplicatedStore) GetCRD(name string) (*api.CustomResourceDefinition, error) {
+	return rs.local.GetCRD(name)
+}
+func (rs *ReplicatedStore) PutCRD(crd *api.CustomResourceDefinition) error {
+	return rs.propose("putCRD", crd)
+}
+func (rs *ReplicatedStore) DeleteCRD(name string) error {
+	return rs.propose("deleteCRD", deleteKey{Name: name})
+}
+func (rs *ReplicatedStore) ListCRDs() ([]*api.CustomResourceDefinition, error) {
+	return rs.local.ListCRDs()
+}
+
+func (rs *ReplicatedStore) GetCustomObject(resource, namespace, name string) (*api.CustomObject, error) {
+	return rs.local.GetCustomObject(resource, namespace, name)
+}
+func (rs *ReplicatedStore) PutCustomObject(obj *api.CustomObject) error {
+	return rs.propose("putCustomObject", obj)
+}
+func (rs *ReplicatedStore) DeleteCustomObject(resource, namespace, name string) error {
+	return rs.propose("deleteCustomObject", deleteKey{Resource: resource, Namespace: namespace, Name: name})
+}
+func (rs *ReplicatedStore) ListCustomObjects(resource string) ([]*api.CustomObject, error) {
+	return rs.local.ListCustomObjects(resource)
+}
@@ -0,0 +1,611 @@
+// Package store holds the cluster state. The in-memory implementation is
+// the default; everything else in the control plane goes through the
+// Store interface so the backend can be swapped out.
+package store
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ptfpinho23/synthesis/pkg/api"
+)
+
+// ErrNotFound is returned when a named object does not exist.
+var ErrNotFound = fmt.Errorf("not found")
+
+// Store is the cluster state backend.
+type Store interface {
+	GetNode(name string) (*api.Node, error)
+	PutNode(node *api.Node) error
+	DeleteNode(name string) error
+	ListNodes() ([]*api.Node, error)
+
+	GetPod(namespace, name string) (*api.Pod, error)
+	PutPod(pod *api.Pod) error
+	DeletePod(namespace, name string) error
+	ListPods() ([]*api.Pod, error)
+
+	PutPodDisruptionBudget(pdb *api.PodDisruptionBudget) error
+	DeletePodDisruptionBudget(namespace, name string) error
+	ListPodDisruptionBudgets() ([]*api.PodDisruptionBudget, error)
+
+	GetLease(namespace, name string) (*api.Lease, error)
+	PutLease(lease *api.Lease) error
+	DeleteLease(namespace, name string) error
+	ListLeases() ([]*api.Lease, error)
+
+	GetRole(namespace, name string) (*api.Role, error)
+	PutRole(role *api.Role) error
+	DeleteRole(namespace, name string) error
+	ListRoles() ([]*api.Role, error)
+
+	PutRoleBinding(binding *api.RoleBinding) error
+	DeleteRoleBinding(namespace, name string) error
+	ListRoleBindings() ([]*api.RoleBinding, error)
+
+	GetServiceAccount(namespace, name string) (*api.ServiceAccount, error)
+	PutServiceAccount(sa *api.ServiceAccount) error
+	DeleteServiceAccount(namespace, name string) error
+	ListServiceAccounts() ([]*api.ServiceAccount, error)
+
+	GetNamespace(name string) (*api.Namespace, error)
+	PutNamespace(ns *api.Namespace) error
+	DeleteNamespace(name string) error
+	ListNamespaces() ([]*api.Namespace, error)
+
+	GetSecret(namespace, name string) (*api.Secret, error)
+	PutSecret(secret *api.Secret) error
+	DeleteSecret(namespace, name string) error
+	ListSecrets() ([]*api.Secret, error)
+
+	GetService(namespace, name string) (*api.Service, error)
+	PutService(svc *api.Service) error
+	DeleteService(namespace, name string) error
+	ListServices() ([]*api.Service, error)
+
+	GetIngress(namespace, name string) (*api.Ingress, error)
+	PutIngress(ing *api.Ingress) error
+	DeleteIngress(namespace, name string) error
+	ListIngresses() ([]*api.Ingress, error)
+
+	GetGateway(namespace, name string) (*api.Gateway, error)
+	PutGateway(gw *api.Gateway) error
+	DeleteGateway(namespace, name string) error
+	ListGateways() ([]*api.Gateway, error)
+
+	GetHTTPRoute(namespace, name string) (*api.HTTPRoute, error)
+	PutHTTPRoute(route *api.HTTPRoute) error
+	DeleteHTTPRoute(namespace, name string) error
+	ListHTTPRoutes() ([]*api.HTTPRoute, error)
+
+	GetPriorityClass(name string) (*api.PriorityClass, error)
+	PutPriorityClass(pc *api.PriorityClass) error
+	DeletePriorityClass(name string) error
+	ListPriorityClasses() ([]*api.PriorityClass, error)
+
+	GetWorkload(namespace, name string) (*api.Workload, error)
+	PutWorkload(w *api.Workload) error
+	DeleteWorkload(namespace, name string) error
+	ListWorkloads() ([]*api.Workload, error)
+
+	PutUsageRecord(rec *api.UsageRecord) error
+	DeleteUsageRecord(namespace, name string) error
+	ListUsageRecords() ([]*api.UsageRecord, error)
+
+	GetCRD(name string) (*api.CustomResourceDefinition, error)
+	PutCRD(crd *api.CustomResourceDefinition) error
+	DeleteCRD(name string) error
+	ListCRDs() ([]*api.CustomResourceDefinition, error)
+
+	GetCustomObject(resource, namespace, name string) (*api.CustomObject, error)
+	PutCustomObject(obj *api.CustomObject) error
+	DeleteCustomObject(resource, namespace, name string) error
+	ListCustomObjects(resource string) ([]*api.CustomObject, error)
+}
+
+// table is one resource type's map guarded by its own lock, so a burst
+// of writes on one type (pod binds, lease renewals) never blocks
+// readers of the others.
+type table[T any] struct {
+	kind string
+	mu   sync.RWMutex
+	objs map[string]*T
+}
+
+func newTable[T any](kind string) *table[T] {
+	return &table[T]{kind: kind, objs: make(map[string]*T)}
+}
+
+func (t *table[T]) get(key string) (*T, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	obj, ok := t.objs[key]
+	if !ok {
+		return nil, fmt.Errorf("%s %q: %w", t.kind, key, ErrNotFound)
+	}
+	return obj, nil
+}
+
+func (t *table[T]) put(key string, obj *T) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.objs[key] = obj
+}
+
+func (t *table[T]) delete(key string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.objs[key]; !ok {
+		return fmt.Errorf("%s %q: %w", t.kind, key, ErrNotFound)
+	}
+	delete(t.objs, key)
+	return nil
+}
+
+// list returns the objects sorted by key, which for every resource is
+// the name or namespace/name.
+func (t *table[T]) list() []*T {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	keys := make([]string, 0, len(t.objs))
+	for k := range t.objs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make([]*T, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, t.objs[k])
+	}
+	return out
+}
+
+// MemoryStore keeps all state in process memory. Each resource type
+// has its own lock so controller scans of one type do not stall API
+// traffic on the rest.
+type MemoryStore struct {
+	nodes    *table[api.Node]
+	pods     *table[api.Pod]
+	pdbs     *table[api.PodDisruptionBudget]
+	leases   *table[api.Lease]
+	roles    *table[api.Role]
+	bindings *table[api.RoleBinding]
+	sas      *table[api.ServiceAccount]
+	nss      *table[api.Namespace]
+	secrets  *table[api.Secret]
+	svcs     *table[api.Service]
+	ings     *table[api.Ingress]
+	gws      *table[api.Gateway]
+	routes   *table[api.HTTPRoute]
+	prios    *table[api.PriorityClass]
+	wls      *table[api.Workload]
+	usage    *table[api.UsageRecord]
+	crds     *table[api.CustomResourceDefinition]
+	customs  *table[api.CustomObject]
+}
+
+// NewMemoryStore returns an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		nodes:    newTable[api.Node]("node"),
+		pods:     newTable[api.Pod]("pod"),
+		pdbs:     newTable[api.PodDisruptionBudget]("poddisruptionbudget"),
+		leases:   newTable[api.Lease]("lease"),
+		roles:    newTable[api.Role]("role"),
+		bindings: newTable[api.RoleBinding]("rolebinding"),
+		sas:      newTable[api.ServiceAccount]("serviceaccount"),
+		nss:      newTable[api.Namespace]("namespace"),
+		secrets:  newTable[api.Secret]("secret"),
+		svcs:     newTable[api.Service]("service"),
+		ings:     newTable[api.Ingress]("ingress"),
+		gws:      newTable[api.Gateway]("gateway"),
+		routes:   newTable[api.HTTPRoute]("httproute"),
+		prios:    newTable[api.PriorityClass]("priorityclass"),
+		wls:      newTable[api.Workload]("workload"),
+		usage:    newTable[api.UsageRecord]("usagerecord"),
+		crds:     newTable[api.CustomResourceDefinition]("customresourcedefinition"),
+		customs:  newTable[api.CustomObject]("customobject"),
+	}
+}
+
+func podKey(namespace, name string) string {
+	if namespace == "" {
+		namespace = "default"
+	}
+	return namespace + "/" + name
+}
+
+// GetNode returns the node with the given name.
+func (s *MemoryStore) GetNode(name string) (*api.Node, error) {
+	return s.nodes.get(name)
+}
+
+// PutNode creates or replaces a node.
+func (s *MemoryStore) PutNode(node *api.Node) error {
+	s.nodes.put(node.Meta.Name, node)
+	return nil
+}
+
+// DeleteNode removes a node; deleting an absent node is an error.
+func (s *MemoryStore) DeleteNode(name string) error {
+	return s.nodes.delete(name)
+}
+
+// ListNodes returns all nodes sorted by name.
+func (s *MemoryStore) ListNodes() ([]*api.Node, error) {
+	return s.nodes.list(), nil
+}
+
+// GetPod returns the pod with the given namespace and name.
+func (s *MemoryStore) GetPod(namespace, name string) (*api.Pod, error) {
+	return s.pods.get(podKey(namespace, name))
+}
+
+// PutPod creates or replaces a pod.
+func (s *MemoryStore) PutPod(pod *api.Pod) error {
+	s.pods.put(podKey(pod.Meta.Namespace, pod.Meta.Name), pod)
+	return nil
+}
+
+// DeletePod removes a pod; deleting an absent pod is an error.
+func (s *MemoryStore) DeletePod(namespace, name string) error {
+	return s.pods.delete(podKey(namespace, name))
+}
+
+// ListPods returns all pods sorted by namespace/name.
+func (s *MemoryStore) ListPods() ([]*api.Pod, error) {
+	return s.pods.list(), nil
+}
+
+// PutPodDisruptionBudget creates or replaces a disruption budget.
+func (s *MemoryStore) PutPodDisruptionBudget(pdb *api.PodDisruptionBudget) error {
+	s.pdbs.put(podKey(pdb.Meta.Namespace, pdb.Meta.Name), pdb)
+	return nil
+}
+
+// DeletePodDisruptionBudget removes a disruption budget.
+func (s *MemoryStore) DeletePodDisruptionBudget(namespace, name string) error {
+	return s.pdbs.delete(podKey(namespace, name))
+}
+
+// ListPodDisruptionBudgets returns all disruption budgets sorted by
+// namespace/name.
+func (s *MemoryStore) ListPodDisruptionBudgets() ([]*api.PodDisruptionBudget, error) {
+	return s.pdbs.list(), nil
+}
+
+// leaseKey keeps node heartbeat leases cluster-scoped under their bare
+// name while namespaced coordination leases get their own keyspace.
+func leaseKey(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
+
+// GetLease returns the lease with the given namespace and name; node
+// heartbeat leases live in the empty namespace.
+func (s *MemoryStore) GetLease(namespace, name string) (*api.Lease, error) {
+	return s.leases.get(leaseKey(namespace, name))
+}
+
+// PutLease creates or renews a lease.
+func (s *MemoryStore) PutLease(lease *api.Lease) error {
+	s.leases.put(leaseKey(lease.Meta.Namespace, lease.Meta.Name), lease)
+	return nil
+}
+
+// DeleteLease releases a lease; deleting an absent one is an error.
+func (s *MemoryStore) DeleteLease(namespace, name string) error {
+	return s.leases.delete(leaseKey(namespace, name))
+}
+
+// ListLeases returns all leases sorted by name.
+func (s *MemoryStore) ListLeases() ([]*api.Lease, error) {
+	return s.leases.list(), nil
+}
+
+// GetRole returns the role with the given namespace ("" for
+// cluster-wide) and name.
+func (s *MemoryStore) GetRole(namespace, name string) (*api.Role, error) {
+	return s.roles.get(namespace + "/" + name)
+}
+
+// PutRole creates or replaces a role.
+func (s *MemoryStore) PutRole(role *api.Role) error {
+	s.roles.put(role.Meta.Namespace+"/"+role.Meta.Name, role)
+	return nil
+}
+
+// DeleteRole removes a role.
+func (s *MemoryStore) DeleteRole(namespace, name string) error {
+	return s.roles.delete(namespace + "/" + name)
+}
+
+// ListRoles returns all roles sorted by namespace/name.
+func (s *MemoryStore) ListRoles() ([]*api.Role, error) {
+	return s.roles.list(), nil
+}
+
+// PutRoleBinding creates or replaces a role binding.
+func (s *MemoryStore) PutRoleBinding(binding *api.RoleBinding) error {
+	s.bindings.put(binding.Meta.Namespace+"/"+binding.Meta.Name, binding)
+	return nil
+}
+
+// DeleteRoleBinding removes a role binding.
+func (s *MemoryStore) DeleteRoleBinding(namespace, name string) error {
+	return s.bindings.delete(namespace + "/" + name)
+}
+
+// ListRoleBindings returns all role bindings sorted by namespace/name.
+func (s *MemoryStore) ListRoleBindings() ([]*api.RoleBinding, error) {
+	return s.bindings.list(), nil
+}
+
+// GetServiceAccount returns the service account with the given
+// namespace and name.
+func (s *MemoryStore) GetServiceAccount(namespace, name string) (*api.ServiceAccount, error) {
+	return s.sas.get(podKey(namespace, name))
+}
+
+// PutServiceAccount creates or replaces a service account.
+func (s *MemoryStore) PutServiceAccount(sa *api.ServiceAccount) error {
+	s.sas.put(podKey(sa.Meta.Namespace, sa.Meta.Name), sa)
+	return nil
+}
+
+// DeleteServiceAccount removes a service account.
+func (s *MemoryStore) DeleteServiceAccount(namespace, name string) error {
+	return s.sas.delete(podKey(namespace, name))
+}
+
+// ListServiceAccounts returns all service accounts sorted by
+// namespace/name.
+func (s *MemoryStore) ListServiceAccounts() ([]*api.ServiceAccount, error) {
+	return s.sas.list(), nil
+}
+
+// GetNamespace returns the namespace with the given name.
+func (s *MemoryStore) GetNamespace(name string) (*api.Namespace, error) {
+	return s.nss.get(name)
+}
+
+// PutNamespace creates or replaces a namespace.
+func (s *MemoryStore) PutNamespace(ns *api.Namespace) error {
+	s.nss.put(ns.Meta.Name, ns)
+	return nil
+}
+
+// DeleteNamespace removes a namespace.
+func (s *MemoryStore) DeleteNamespace(name string) error {
+	return s.nss.delete(name)
+}
+
+// ListNamespaces returns all namespaces sorted by name.
+func (s *MemoryStore) ListNamespaces() ([]*api.Namespace, error) {
+	return s.nss.list(), nil
+}
+
+// GetSecret returns the secret with the given namespace and name.
+func (s *MemoryStore) GetSecret(namespace, name string) (*api.Secret, error) {
+	return s.secrets.get(podKey(namespace, name))
+}
+
+// PutSecret creates or replaces a secret.
+func (s *MemoryStore) PutSecret(secret *api.Secret) error {
+	s.secrets.put(podKey(secret.Meta.Namespace, secret.Meta.Name), secret)
+	return nil
+}
+
+// DeleteSecret removes a secret.
+func (s *MemoryStore) DeleteSecret(namespace, name string) error {
+	return s.secrets.delete(podKey(namespace, name))
+}
+
+// ListSecrets returns all secrets sorted by namespace/name.
+func (s *MemoryStore) ListSecrets() ([]*api.Secret, error) {
+	return s.secrets.list(), nil
+}
+
+// GetService returns the service with the given namespace and name.
+func (s *MemoryStore) GetService(namespace, name string) (*api.Service, error) {
+	return s.svcs.get(podKey(namespace, name))
+}
+
+// PutService creates or replaces a service.
+func (s *MemoryStore) PutService(svc *api.Service) error {
+	s.svcs.put(podKey(svc.Meta.Namespace, svc.Meta.Name), svc)
+	return nil
+}
+
+// DeleteService removes a service; deleting an absent one is an error.
+func (s *MemoryStore) DeleteService(namespace, name string) error {
+	return s.svcs.delete(podKey(namespace, name))
+}
+
+// ListServices returns all services sorted by namespace/name.
+func (s *MemoryStore) ListServices() ([]*api.Service, error) {
+	return s.svcs.list(), nil
+}
+
+// GetIngress returns the ingress with the given namespace and name.
+func (s *MemoryStore) GetIngress(namespace, name string) (*api.Ingress, error) {
+	return s.ings.get(podKey(namespace, name))
+}
+
+// PutIngress creates or replaces an ingress.
+func (s *MemoryStore) PutIngress(ing *api.Ingress) error {
+	s.ings.put(podKey(ing.Meta.Namespace, ing.Meta.Name), ing)
+	return nil
+}
+
+// DeleteIngress removes an ingress; deleting an absent one is an error.
+func (s *MemoryStore) DeleteIngress(namespace, name string) error {
+	return s.ings.delete(podKey(namespace, name))
+}
+
+// ListIngresses returns all ingresses sorted by namespace/name.
+func (s *MemoryStore) ListIngresses() ([]*api.Ingress, error) {
+	return s.ings.list(), nil
+}
+
+// GetGateway returns the gateway with the given namespace and name.
+func (s *MemoryStore) GetGateway(namespace, name string) (*api.Gateway, error) {
+	return s.gws.get(podKey(namespace, name))
+}
+
+// PutGateway creates or replaces a gateway.
+func (s *MemoryStore) PutGateway(gw *api.Gateway) error {
+	s.gws.put(podKey(gw.Meta.Namespace, gw.Meta.Name), gw)
+	return nil
+}
+
+// DeleteGateway removes a gateway; deleting an absent one is an error.
+func (s *MemoryStore) DeleteGateway(namespace, name string) error {
+	return s.gws.delete(podKey(namespace, name))
+}
+
+// ListGateways returns all gateways sorted by namespace/name.
+func (s *MemoryStore) ListGateways() ([]*api.Gateway, error) {
+	return s.gws.list(), nil
+}
+
+// GetHTTPRoute returns the route with the given namespace and name.
+func (s *MemoryStore) GetHTTPRoute(namespace, name string) (*api.HTTPRoute, error) {
+	return s.routes.get(podKey(namespace, name))
+}
+
+// PutHTTPRoute creates or replaces an HTTP route.
+func (s *MemoryStore) PutHTTPRoute(route *api.HTTPRoute) error {
+	s.routes.put(podKey(route.Meta.Namespace, route.Meta.Name), route)
+	return nil
+}
+
+// DeleteHTTPRoute removes a route; deleting an absent one is an error.
+func (s *MemoryStore) DeleteHTTPRoute(namespace, name string) error {
+	return s.routes.delete(podKey(namespace, name))
+}
+
+// ListHTTPRoutes returns all HTTP routes sorted by namespace/name.
+func (s *MemoryStore) ListHTTPRoutes() ([]*api.HTTPRoute, error) {
+	return s.routes.list(), nil
+}
+
+// GetPriorityClass returns the class with the given name.
+func (s *MemoryStore) GetPriorityClass(name string) (*api.PriorityClass, error) {
+	return s.prios.get(name)
+}
+
+// PutPriorityClass creates or replaces a priority class.
+func (s *MemoryStore) PutPriorityClass(pc *api.PriorityClass) error {
+	s.prios.put(pc.Meta.Name, pc)
+	return nil
+}
+
+// DeletePriorityClass removes a class; deleting an absent one is an
+// error.
+func (s *MemoryStore) DeletePriorityClass(name string) error {
+	return s.prios.delete(name)
+}
+
+// ListPriorityClasses returns all classes sorted by name.
+func (s *MemoryStore) ListPriorityClasses() ([]*api.PriorityClass, error) {
+	return s.prios.list(), nil
+}
+
+// GetWorkload returns the workload with the given namespace and name.
+func (s *MemoryStore) GetWorkload(namespace, name string) (*api.Workload, error) {
+	return s.wls.get(podKey(namespace, name))
+}
+
+// PutWorkload creates or replaces a workload.
+func (s *MemoryStore) PutWorkload(w *api.Workload) error {
+	s.wls.put(podKey(w.Meta.Namespace, w.Meta.Name), w)
+	return nil
+}
+
+// DeleteWorkload removes a workload; its pods are the workload
+// controller's to clean up.
+func (s *MemoryStore) DeleteWorkload(namespace, name string) error {
+	return s.wls.delete(podKey(namespace, name))
+}
+
+// ListWorkloads returns all workloads sorted by namespace/name.
+func (s *MemoryStore) ListWorkloads() ([]*api.Workload, error) {
+	return s.wls.list(), nil
+}
+
+// PutUsageRecord creates or replaces an accounting record.
+func (s *MemoryStore) PutUsageRecord(rec *api.UsageRecord) error {
+	s.usage.put(podKey(rec.Meta.Namespace, rec.Meta.Name), rec)
+	return nil
+}
+
+// DeleteUsageRecord removes a record, e.g. when pruning old periods.
+func (s *MemoryStore) DeleteUsageRecord(namespace, name string) error {
+	return s.usage.delete(podKey(namespace, name))
+}
+
+// ListUsageRecords returns all records sorted by namespace/name.
+func (s *MemoryStore) ListUsageRecords() ([]*api.UsageRecord, error) {
+	return s.usage.list(), nil
+}
+
+// GetCRD returns the custom resource definition with the given name.
+func (s *MemoryStore) GetCRD(name string) (*api.CustomResourceDefinition, error) {
+	return s.crds.get(name)
+}
+
+// PutCRD creates or replaces a custom resource definition.
+func (s *MemoryStore) PutCRD(crd *api.CustomResourceDefinition) error {
+	s.crds.put(crd.Meta.Name, crd)
+	return nil
+}
+
+// DeleteCRD removes a definition; existing objects of the type are
+// kept and become reachable again if the definition is re-registered.
+func (s *MemoryStore) DeleteCRD(name string) error {
+	return s.crds.delete(name)
+}
+
+// ListCRDs returns all definitions sorted by name.
+func (s *MemoryStore) ListCRDs() ([]*api.CustomResourceDefinition, error) {
+	return s.crds.list(), nil
+}
+
+// customKey prefixes the object key with the owning resource so one
+// table holds every custom type.
+func customKey(resource, namespace, name string) string {
+	if namespace == "" {
+		return resource + "/" + name
+	}
+	return resource + "/" + namespace + "/" + name
+}
+
+// GetCustomObject returns one object of the given group/plural.
+func (s *MemoryStore) GetCustomObject(resource, namespace, name string) (*api.CustomObject, error) {
+	return s.customs.get(customKey(resource, namespace, name))
+}
+
+// PutCustomObject creates or replaces a custom object.
+func (s *MemoryStore) PutCustomObject(obj *api.CustomObject) error {
+	s.customs.put(customKey(obj.Resource, obj.Meta.Namespace, obj.Meta.Name), obj)
+	return nil
+}
+
+// DeleteCustomObject removes a custom object.
+func (s *MemoryStore) DeleteCustomObject(resource, namespace, name string) error {
+	return s.customs.delete(customKey(resource, namespace, name))
+}
+
+// ListCustomObjects returns the given resource's objects sorted by key.
+func (s *MemoryStore) ListCustomObjects(resource string) ([]*api.CustomObject, error) {
+	var out []*api.CustomObject
+	for _, obj := range s.customs.list() {
+		if obj.Resource == resource {
+			out = append(out, obj)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,58 @@
+// Package systemd integrates the daemons with the init system: sd_notify
+// readiness and watchdog pings, plus writing unit files for `install`.
+// Everything is a no-op outside a systemd service, so the same binaries
+// run unchanged under any other supervisor.
+package systemd
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Notify sends one sd_notify state ("READY=1", "STOPPING=1",
+// "WATCHDOG=1") to the socket systemd handed this process. Without
+// $NOTIFY_SOCKET it does nothing.
+func Notify(state string) error {
+	path := os.Getenv("NOTIFY_SOCKET")
+	if path == "" {
+		return nil
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Write([]byte(state))
+	return err
+}
+
+// Ready tells systemd the service is up; with Type=notify units, start
+// jobs and dependents block until this fires.
+func Ready() { _ = Notify("READY=1") }
+
+// Stopping tells systemd an orderly shutdown has begun.
+func Stopping() { _ = Notify("STOPPING=1") }
+
+// Watchdog pings systemd at half the configured WatchdogSec until ctx
+// ends, so a wedged process is killed and restarted rather than sitting
+// dead. It returns immediately when no watchdog is configured.
+func Watchdog(ctx context.Context) {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return
+	}
+	interval := time.Duration(usec) * time.Microsecond / 2
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = Notify("WATCHDOG=1")
+		}
+	}
+}
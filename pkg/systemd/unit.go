@@ -0,0 +1,64 @@
+package systemd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// unitDir is where InstallUnit writes service files.
+const unitDir = "/etc/systemd/system"
+
+// Unit describes one service file to install.
+type Unit struct {
+	// Name is the service name without the .service suffix.
+	Name string
+
+	// Description is the unit's human-readable summary.
+	Description string
+
+	// ExecStart is the full command line the service runs.
+	ExecStart string
+
+	// WatchdogSec enables the systemd watchdog; the daemons ping it
+	// through Watchdog. Zero disables it.
+	WatchdogSec int
+
+	// FullSandbox applies the strict hardening set. The agent cannot
+	// use it — it needs the engine socket and host paths — so it gets
+	// only the safe subset.
+	FullSandbox bool
+}
+
+// Install writes the service file and returns its path. The caller
+// still runs `systemctl daemon-reload` and `systemctl enable --now`;
+// printing those is the command's job, not a side effect here.
+func (u Unit) Install() (string, error) {
+	path := filepath.Join(unitDir, u.Name+".service")
+	if err := os.WriteFile(path, []byte(u.render()), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func (u Unit) render() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[Unit]\nDescription=%s\nAfter=network-online.target\nWants=network-online.target\n\n", u.Description)
+	b.WriteString("[Service]\nType=notify\n")
+	fmt.Fprintf(&b, "ExecStart=%s\n", u.ExecStart)
+	b.WriteString("Restart=always\nRestartSec=5\n")
+	if u.WatchdogSec > 0 {
+		fmt.Fprintf(&b, "WatchdogSec=%d\n", u.WatchdogSec)
+	}
+	// Logs go to the journal; the daemons write structured lines to
+	// stderr, which journald indexes per unit.
+	b.WriteString("StandardOutput=journal\nStandardError=journal\n")
+	b.WriteString("NoNewPrivileges=yes\n")
+	if u.FullSandbox {
+		b.WriteString("ProtectSystem=strict\nProtectHome=yes\nPrivateTmp=yes\n")
+		b.WriteString("ReadWritePaths=/var/lib/synthesis\n")
+	}
+	b.WriteString("\n[Install]\nWantedBy=multi-user.target\n")
+	return b.String()
+}
@@ -0,0 +1,133 @@
+// Package tlsutil generates the self-signed PKI used when the operator
+// does not bring their own certificates.
+package tlsutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Files are the on-disk names inside the PKI directory.
+const (
+	CAFile   = "ca.crt"
+	CertFile = "server.crt"
+	KeyFile  = "server.key"
+)
+
+// certTTL is how long generated certificates stay valid. The server
+// regenerates any certificate inside renewBefore of expiry on startup,
+// so rotation only needs a restart.
+const (
+	certTTL     = 365 * 24 * time.Hour
+	renewBefore = 30 * 24 * time.Hour
+)
+
+// EnsureServerCert returns paths to a CA and a server certificate under
+// dir, generating or rotating them as needed. hosts are the DNS names
+// and IPs the certificate must cover.
+func EnsureServerCert(dir string, hosts []string) (certFile, keyFile, caFile string, err error) {
+	certFile = filepath.Join(dir, CertFile)
+	keyFile = filepath.Join(dir, KeyFile)
+	caFile = filepath.Join(dir, CAFile)
+
+	if valid(certFile) && valid(caFile) {
+		return certFile, keyFile, caFile, nil
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", "", "", err
+	}
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", "", err
+	}
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "synthesis-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(certTTL),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		return "", "", "", fmt.Errorf("creating CA: %w", err)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	srvKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", "", err
+	}
+	srvTmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "synthesis-server"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(certTTL),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			srvTmpl.IPAddresses = append(srvTmpl.IPAddresses, ip)
+		} else {
+			srvTmpl.DNSNames = append(srvTmpl.DNSNames, h)
+		}
+	}
+	srvDER, err := x509.CreateCertificate(rand.Reader, srvTmpl, caCert, &srvKey.PublicKey, caKey)
+	if err != nil {
+		return "", "", "", fmt.Errorf("creating server certificate: %w", err)
+	}
+
+	if err := writePEM(caFile, "CERTIFICATE", caDER, 0o644); err != nil {
+		return "", "", "", err
+	}
+	if err := writePEM(certFile, "CERTIFICATE", srvDER, 0o644); err != nil {
+		return "", "", "", err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(srvKey)
+	if err != nil {
+		return "", "", "", err
+	}
+	if err := writePEM(keyFile, "EC PRIVATE KEY", keyDER, 0o600); err != nil {
+		return "", "", "", err
+	}
+	return certFile, keyFile, caFile, nil
+}
+
+// valid reports whether the PEM certificate at path exists and is not
+// within the rotation window of expiring.
+func valid(path string) bool {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	block, _ := pem.Decode(buf)
+	if block == nil {
+		return false
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return false
+	}
+	return time.Now().Add(renewBefore).Before(cert.NotAfter)
+}
+
+func writePEM(path, blockType string, der []byte, mode os.FileMode) error {
+	buf := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	return os.WriteFile(path, buf, mode)
+}
@@ -0,0 +1,10 @@
+// Package version holds the build version stamped into the binaries.
+package version
+
+// Version identifies this build. Release builds override it with
+//
+//	go build -ldflags "-X github.com/ptfpinho23/synthesis/pkg/version.Version=v0.9.0"
+//
+// so agents can report what they run and upgrades can tell when a node
+// has come back on the new binary.
+var Version = "dev"
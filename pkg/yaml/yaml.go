@@ -0,0 +1,431 @@
+// Package yaml implements the YAML subset Synthesis needs to accept
+// and serve manifests: block mappings and sequences, flow/JSON values,
+// quoted and literal-block scalars, comments and multi-document
+// streams. It is not a full YAML 1.2 parser; anchors, aliases and tags
+// are out of scope.
+package yaml
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ToJSON converts one YAML document to its JSON encoding.
+func ToJSON(data []byte) ([]byte, error) {
+	v, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// Parse decodes one YAML document into the generic shapes
+// encoding/json uses: map[string]any, []any and scalars.
+func Parse(data []byte) (any, error) {
+	p := &parser{}
+	for n, raw := range strings.Split(string(data), "\n") {
+		if strings.Contains(raw, "\t") && strings.TrimLeft(raw, " \t") != "" &&
+			strings.IndexByte(raw, '\t') < len(raw)-len(strings.TrimLeft(raw, " \t")) {
+			return nil, fmt.Errorf("line %d: tabs are not allowed in indentation", n+1)
+		}
+		trimmed := strings.TrimRight(raw, " \r")
+		body := strings.TrimLeft(trimmed, " ")
+		if body == "" || strings.HasPrefix(body, "#") {
+			continue
+		}
+		p.lines = append(p.lines, line{
+			num:    n + 1,
+			indent: len(trimmed) - len(body),
+			text:   body,
+		})
+	}
+	if len(p.lines) == 0 {
+		return nil, nil
+	}
+	v, err := p.parseNode(p.lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, fmt.Errorf("line %d: unexpected content %q", p.lines[p.pos].num, p.lines[p.pos].text)
+	}
+	return v, nil
+}
+
+// SplitDocuments splits a multi-document stream on "---" separators,
+// dropping empty documents.
+func SplitDocuments(data []byte) [][]byte {
+	var docs [][]byte
+	var cur []string
+	flush := func() {
+		doc := strings.TrimSpace(strings.Join(cur, "\n"))
+		cur = nil
+		if doc != "" {
+			docs = append(docs, []byte(doc))
+		}
+	}
+	for _, raw := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(raw) == "---" {
+			flush()
+			continue
+		}
+		cur = append(cur, raw)
+	}
+	flush()
+	return docs
+}
+
+type line struct {
+	num    int
+	indent int
+	text   string
+}
+
+type parser struct {
+	lines []line
+	pos   int
+}
+
+func (p *parser) parseNode(indent int) (any, error) {
+	if p.pos >= len(p.lines) || p.lines[p.pos].indent < indent {
+		return nil, nil
+	}
+	if _, ok := sequenceItem(p.lines[p.pos].text); ok {
+		return p.parseSequence(p.lines[p.pos].indent)
+	}
+	return p.parseMapping(p.lines[p.pos].indent)
+}
+
+func sequenceItem(text string) (string, bool) {
+	if text == "-" {
+		return "", true
+	}
+	if strings.HasPrefix(text, "- ") {
+		return strings.TrimLeft(text[2:], " "), true
+	}
+	return "", false
+}
+
+func (p *parser) parseSequence(indent int) (any, error) {
+	seq := []any{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent {
+		item, ok := sequenceItem(p.lines[p.pos].text)
+		if !ok {
+			break
+		}
+		ln := p.lines[p.pos]
+		if item == "" {
+			p.pos++
+			v, err := p.parseNode(indent + 1)
+			if err != nil {
+				return nil, err
+			}
+			seq = append(seq, v)
+			continue
+		}
+		if _, _, isMap := mappingEntry(item); isMap {
+			// "- key: value" starts a mapping whose keys align with
+			// the position of the first key on the dash line.
+			inner := indent + len(ln.text) - len(item)
+			p.lines[p.pos] = line{num: ln.num, indent: inner, text: item}
+			v, err := p.parseMapping(inner)
+			if err != nil {
+				return nil, err
+			}
+			seq = append(seq, v)
+			continue
+		}
+		p.pos++
+		v, err := scalar(item)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", ln.num, err)
+		}
+		seq = append(seq, v)
+	}
+	return seq, nil
+}
+
+// mappingEntry splits "key: value" at the first colon outside quotes
+// and flow brackets; a bare "key:" yields an empty value.
+func mappingEntry(text string) (key, value string, ok bool) {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		case c == ':' && depth == 0 && (i == len(text)-1 || text[i+1] == ' '):
+			return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+func (p *parser) parseMapping(indent int) (any, error) {
+	obj := map[string]any{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent {
+		ln := p.lines[p.pos]
+		if _, ok := sequenceItem(ln.text); ok {
+			break
+		}
+		key, val, ok := mappingEntry(ln.text)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\", got %q", ln.num, ln.text)
+		}
+		key = unquoteKey(key)
+		p.pos++
+		switch {
+		case val == "":
+			// Nested block, or null when nothing is indented deeper.
+			// A sequence may sit at the same indent as its key.
+			if p.pos < len(p.lines) && p.lines[p.pos].indent == indent {
+				if _, isSeq := sequenceItem(p.lines[p.pos].text); isSeq {
+					v, err := p.parseSequence(indent)
+					if err != nil {
+						return nil, err
+					}
+					obj[key] = v
+					continue
+				}
+			}
+			if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+				v, err := p.parseNode(p.lines[p.pos].indent)
+				if err != nil {
+					return nil, err
+				}
+				obj[key] = v
+			} else {
+				obj[key] = nil
+			}
+		case val == "|" || val == "|-" || val == ">" || val == ">-":
+			obj[key] = p.blockScalar(indent, val)
+		default:
+			v, err := scalar(val)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", ln.num, err)
+			}
+			obj[key] = v
+		}
+	}
+	return obj, nil
+}
+
+// blockScalar joins the deeper-indented lines following a | or >
+// indicator. Folded scalars (>) join with spaces, literal (|) with
+// newlines; the chomping variants both drop the trailing newline here.
+func (p *parser) blockScalar(indent int, indicator string) string {
+	var parts []string
+	blockIndent := -1
+	for p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+		ln := p.lines[p.pos]
+		if blockIndent == -1 {
+			blockIndent = ln.indent
+		}
+		parts = append(parts, strings.Repeat(" ", ln.indent-blockIndent)+ln.text)
+		p.pos++
+	}
+	sep := "\n"
+	if indicator == ">" || indicator == ">-" {
+		sep = " "
+	}
+	return strings.Join(parts, sep)
+}
+
+// scalar interprets a flow value: quoted strings, JSON-compatible
+// literals (numbers, booleans, null, flow collections) and otherwise
+// a plain string. Trailing comments are stripped.
+func scalar(s string) (any, error) {
+	s = strings.TrimSpace(stripComment(s))
+	switch s {
+	case "", "~", "null":
+		return nil, nil
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	if s[0] == '\'' {
+		if len(s) < 2 || s[len(s)-1] != '\'' {
+			return nil, fmt.Errorf("unterminated string %q", s)
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	}
+	if s[0] == '"' {
+		var out string
+		if err := json.Unmarshal([]byte(s), &out); err != nil {
+			return nil, fmt.Errorf("bad string %q", s)
+		}
+		return out, nil
+	}
+	if s[0] == '[' || s[0] == '{' {
+		var out any
+		if err := json.Unmarshal([]byte(s), &out); err != nil {
+			return nil, fmt.Errorf("bad flow value %q", s)
+		}
+		return out, nil
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return float64(n), nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f, nil
+	}
+	return s, nil
+}
+
+func stripComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '#' && i > 0 && s[i-1] == ' ':
+			return s[:i]
+		}
+	}
+	return s
+}
+
+func unquoteKey(key string) string {
+	v, err := scalar(key)
+	if err != nil {
+		return key
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return key
+}
+
+// Marshal renders a value as YAML. The value is first round-tripped
+// through its JSON encoding so struct tags apply.
+func Marshal(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	writeNode(&b, generic, 0, false)
+	return []byte(b.String()), nil
+}
+
+func writeNode(b *strings.Builder, v any, indent int, inline bool) {
+	switch val := v.(type) {
+	case map[string]any:
+		if len(val) == 0 {
+			b.WriteString("{}\n")
+			return
+		}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for i, k := range keys {
+			if !inline || i > 0 {
+				b.WriteString(strings.Repeat("  ", indent))
+			}
+			b.WriteString(quoteIfNeeded(k))
+			b.WriteString(":")
+			writeChild(b, val[k], indent)
+		}
+	case []any:
+		if len(val) == 0 {
+			b.WriteString("[]\n")
+			return
+		}
+		for i, item := range val {
+			if !inline || i > 0 {
+				b.WriteString(strings.Repeat("  ", indent))
+			}
+			b.WriteString("- ")
+			writeNode(b, item, indent+1, true)
+		}
+	case string:
+		b.WriteString(quoteIfNeeded(val))
+		b.WriteString("\n")
+	case nil:
+		b.WriteString("null\n")
+	case float64:
+		b.WriteString(strconv.FormatFloat(val, 'f', -1, 64))
+		b.WriteString("\n")
+	case bool:
+		b.WriteString(strconv.FormatBool(val))
+		b.WriteString("\n")
+	default:
+		fmt.Fprintf(b, "%v\n", val)
+	}
+}
+
+func writeChild(b *strings.Builder, v any, indent int) {
+	switch val := v.(type) {
+	case map[string]any:
+		if len(val) == 0 {
+			b.WriteString(" {}\n")
+			return
+		}
+		b.WriteString("\n")
+		writeNode(b, val, indent+1, false)
+	case []any:
+		if len(val) == 0 {
+			b.WriteString(" []\n")
+			return
+		}
+		b.WriteString("\n")
+		writeNode(b, val, indent+1, false)
+	default:
+		b.WriteString(" ")
+		writeNode(b, v, indent, true)
+	}
+}
+
+// quoteIfNeeded leaves simple strings bare and JSON-quotes anything
+// YAML would reinterpret.
+func quoteIfNeeded(s string) string {
+	if s == "" {
+		return `""`
+	}
+	plain := true
+	for _, c := range s {
+		if c == ':' || c == '#' || c == '\n' || c == '"' || c == '\'' ||
+			c == '[' || c == ']' || c == '{' || c == '}' || c == ',' {
+			plain = false
+			break
+		}
+	}
+	switch s {
+	case "true", "false", "null", "~", "-":
+		plain = false
+	}
+	if plain {
+		if _, err := strconv.ParseFloat(s, 64); err == nil {
+			plain = false
+		}
+	}
+	if plain && s[0] != ' ' && s[len(s)-1] != ' ' && s[0] != '-' {
+		return s
+	}
+	data, _ := json.Marshal(s)
+	return string(data)
+}